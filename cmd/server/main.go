@@ -9,17 +9,26 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
+	"personal-assistant/internal/auth"
 	"personal-assistant/internal/config"
+	"personal-assistant/internal/dedup"
+	"personal-assistant/internal/http/apikeys"
 	"personal-assistant/internal/http/contacts"
+	"personal-assistant/internal/domain"
 	"personal-assistant/internal/http/infobip"
+	"personal-assistant/internal/http/jobs"
+	"personal-assistant/internal/http/tenants"
+	"personal-assistant/internal/http/usage"
 	infobipClient "personal-assistant/internal/infobip"
 	"personal-assistant/internal/log"
 	"personal-assistant/internal/processor"
+	"personal-assistant/internal/queue"
+	"personal-assistant/internal/scheduler"
 	"personal-assistant/internal/tenant"
-	"personal-assistant/internal/tools"
 )
 
 func main() {
@@ -80,14 +89,39 @@ func main() {
 	// Initialize Infobip client
 	infobipCli := infobipClient.NewRetryableClient(&cfg.Infobip, logger, 3, 1*time.Second)
 
-	// Initialize tool registry
-	toolRegistry := tools.NewRegistry()
-
 	// Initialize message processor
-	messageProcessor := processor.NewMessageProcessor(tenantManager, infobipCli, toolRegistry, logger)
+	messageProcessor := processor.NewMessageProcessor(tenantManager, infobipCli, cfg, logger)
+
+	// Initialize inbound job queue and its worker pool
+	var jobQueue domain.JobQueue
+	var workerPool *queue.WorkerPool
+	if cfg.Queue.Enabled {
+		jobQueue, err = newJobQueue(cfg, logger)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Failed to initialize job queue")
+		}
+		defer jobQueue.Close()
+		messageProcessor.SetJobQueue(jobQueue)
+
+		workerPool = queue.NewWorkerPool(jobQueue, messageProcessor, &cfg.Queue, logger)
+		workerPool.Start(context.Background())
+	}
+
+	// Initialize webhook deduplication
+	deduplicator, err := newDeduplicator(cfg, tenantManager, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to initialize deduplicator")
+	}
 
 	// Initialize webhook handler
-	webhookHandler := infobip.NewWebhookHandler(messageProcessor, cfg, logger)
+	webhookVerifier := infobipClient.NewWebhookVerifier(tenantManager, cfg)
+	webhookHandler := infobip.NewWebhookHandler(messageProcessor, jobQueue, deduplicator, webhookVerifier, cfg, logger)
+
+	// Start reminder scheduler
+	reminderScheduler := scheduler.NewReminderScheduler(tenantManager, infobipCli, &cfg.Scheduler, logger)
+	if cfg.Scheduler.Enabled {
+		reminderScheduler.Start(context.Background())
+	}
 
 	// Initialize contacts handler
 	contactsHandler := contacts.NewContactsHandler(tenantManager, logger)
@@ -100,14 +134,69 @@ func main() {
 	e.POST("/webhooks/infobip/status", webhookHandler.HandleStatus)
 	e.GET("/webhooks/infobip/health", webhookHandler.HandleHealth)
 
-	// Contacts management API endpoints
 	api := e.Group("/api/v1")
+
+	// Require an API key on every /api/v1 route
+	if cfg.Auth.Enabled {
+		db, err := pgxpool.New(context.Background(), cfg.DatabaseURL)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Failed to connect to API key database")
+		}
+		keyStore := auth.NewPostgresKeyStore(db)
+		defer keyStore.Close()
+
+		authenticator := auth.NewAuthenticator(keyStore, &cfg.Auth, logger)
+		api.Use(authenticator.Middleware)
+
+		// API key management endpoints, restricted to global keys
+		apiKeysHandler := apikeys.NewAPIKeysHandler(keyStore, tenantManager, logger)
+		api.GET("/api-keys", apiKeysHandler.ListAPIKeys)
+		api.POST("/api-keys", apiKeysHandler.CreateAPIKey)
+		api.DELETE("/api-keys/:key_id", apiKeysHandler.RevokeAPIKey)
+	} else {
+		logger.Warn().Msg("API key authentication disabled, /api/v1 is open to anyone who can reach the server")
+	}
+
+	// Contacts management API endpoints
 	api.GET("/tenants/:tenant_id/contacts", contactsHandler.ListContacts)
 	api.GET("/tenants/:tenant_id/contacts/:phone_number", contactsHandler.GetContact)
 	api.POST("/tenants/:tenant_id/contacts", contactsHandler.CreateContact)
 	api.PUT("/tenants/:tenant_id/contacts/:phone_number", contactsHandler.UpdateContact)
 	api.DELETE("/tenants/:tenant_id/contacts/:contact_id", contactsHandler.DeleteContact)
 	api.GET("/tenants/:tenant_id/contacts/check", contactsHandler.CheckContact)
+	api.GET("/tenants/:tenant_id/contact-requests", contactsHandler.ListContactRequests)
+	api.POST("/tenants/:tenant_id/contact-requests/:phone_number/approve", contactsHandler.ApproveContactRequest)
+	api.POST("/tenants/:tenant_id/contact-requests/:phone_number/deny", contactsHandler.DenyContactRequest)
+
+	// Inbound job queue API endpoints
+	if jobQueue != nil {
+		jobsHandler := jobs.NewJobsHandler(jobQueue, logger)
+		api.GET("/jobs", jobsHandler.ListJobs)
+		api.GET("/jobs/:job_id", jobsHandler.GetJob)
+		api.POST("/jobs/:job_id/retry", jobsHandler.RetryJob)
+	}
+
+	// LLM usage and cost API endpoints
+	usageHandler := usage.NewUsageHandler(tenantManager, logger)
+	api.GET("/tenants/:tenant_id/usage", usageHandler.GetTenantUsage)
+	api.GET("/tenants/:tenant_id/users/:user_id/usage", usageHandler.GetUserUsage)
+
+	// Tenant, LLM provider and external service management API endpoints
+	tenantsHandler := tenants.NewTenantsHandler(tenantManager, logger)
+	api.GET("/tenants", tenantsHandler.ListTenants)
+	api.POST("/tenants", tenantsHandler.CreateTenant)
+	api.GET("/tenants/:tenant_id", tenantsHandler.GetTenant)
+	api.PUT("/tenants/:tenant_id", tenantsHandler.UpdateTenant)
+	api.DELETE("/tenants/:tenant_id", tenantsHandler.DisableTenant)
+	api.GET("/tenants/:tenant_id/llm-providers", tenantsHandler.ListLLMProviders)
+	api.POST("/tenants/:tenant_id/llm-providers", tenantsHandler.CreateLLMProvider)
+	api.GET("/tenants/:tenant_id/llm-providers/:name", tenantsHandler.GetLLMProvider)
+	api.PUT("/tenants/:tenant_id/llm-providers/:name", tenantsHandler.UpdateLLMProvider)
+	api.POST("/tenants/:tenant_id/llm-providers/:name/default", tenantsHandler.SetDefaultLLMProvider)
+	api.GET("/tenants/:tenant_id/external-services", tenantsHandler.ListExternalServices)
+	api.POST("/tenants/:tenant_id/external-services", tenantsHandler.CreateExternalService)
+	api.GET("/tenants/:tenant_id/external-services/:name", tenantsHandler.GetExternalService)
+	api.PUT("/tenants/:tenant_id/external-services/:name", tenantsHandler.UpdateExternalService)
 
 	// Start server in a goroutine
 	go func() {
@@ -135,9 +224,46 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Stop background workers
+	if cfg.Scheduler.Enabled {
+		reminderScheduler.Stop()
+	}
+	if workerPool != nil {
+		workerPool.Stop()
+	}
+
 	logger.Info().Msg("Server stopped")
 }
 
+// newJobQueue creates the inbound job queue for the configured backend
+func newJobQueue(cfg *config.Config, logger *log.Logger) (domain.JobQueue, error) {
+	switch cfg.Queue.Backend {
+	case "memory":
+		logger.Warn().Msg("using in-memory job queue, queued messages are lost on restart")
+		return queue.NewMemoryQueue(), nil
+	case "postgres", "":
+		db, err := pgxpool.New(context.Background(), cfg.DatabaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to queue database: %w", err)
+		}
+		return queue.NewPostgresQueue(db, logger), nil
+	default:
+		return nil, fmt.Errorf("unsupported queue backend: %s", cfg.Queue.Backend)
+	}
+}
+
+// newDeduplicator creates the webhook deduplicator for the configured backend
+func newDeduplicator(cfg *config.Config, tenantManager domain.TenantManager, logger *log.Logger) (domain.Deduplicator, error) {
+	switch cfg.Dedup.Backend {
+	case "memory":
+		return dedup.NewMemoryDeduplicator(cfg.Dedup.TTL, cfg.Dedup.Capacity), nil
+	case "postgres", "":
+		return dedup.NewPostgresDeduplicator(tenantManager, logger), nil
+	default:
+		return nil, fmt.Errorf("unsupported dedup backend: %s", cfg.Dedup.Backend)
+	}
+}
+
 // healthCheck returns the health status of the service
 func healthCheck(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]interface{}{
@@ -0,0 +1,119 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+// maxChoiceOptions is the largest number of options WhatsApp can show in a list message
+const maxChoiceOptions = domain.InfobipMaxListRows
+
+// ChoiceTool lets the assistant offer options the user can answer with a single tap
+type ChoiceTool struct {
+	logger *log.Logger
+}
+
+// NewChoiceTool creates a new choice tool
+func NewChoiceTool(logger *log.Logger) *ChoiceTool {
+	return &ChoiceTool{
+		logger: logger,
+	}
+}
+
+// Name returns the tool name
+func (t *ChoiceTool) Name() string {
+	return "offer_choices"
+}
+
+// Schema returns the JSON schema for the tool parameters
+func (t *ChoiceTool) Schema() *domain.JSONSchema {
+	return &domain.JSONSchema{
+		Type: "object",
+		Properties: map[string]domain.JSONSchemaProperty{
+			"options": {
+				Type:        "array",
+				Description: "Options to show, up to 10. Three short options (20 characters or less) are shown as buttons, anything else as a list",
+				Items: &domain.JSONSchemaProperty{
+					Type: "object",
+					Properties: map[string]domain.JSONSchemaProperty{
+						"id": {
+							Type:        "string",
+							Description: "Identifier returned when the option is selected",
+						},
+						"title": {
+							Type:        "string",
+							Description: "Option label shown to the user",
+						},
+						"description": {
+							Type:        "string",
+							Description: "Optional detail shown under the label in lists",
+						},
+					},
+				},
+			},
+			"button_text": {
+				Type:        "string",
+				Description: "Label of the button that opens the list, e.g. 'Choose a time'",
+			},
+		},
+		Required: []string{"options"},
+	}
+}
+
+// Invoke executes the tool with the given input
+func (t *ChoiceTool) Invoke(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	rawOptions, ok := input["options"].([]interface{})
+	if !ok || len(rawOptions) == 0 {
+		return nil, fmt.Errorf("options are required")
+	}
+	if len(rawOptions) > maxChoiceOptions {
+		return nil, fmt.Errorf("at most %d options can be offered, got %d", maxChoiceOptions, len(rawOptions))
+	}
+
+	choices := &domain.ReplyChoices{
+		Options: make([]domain.ReplyChoice, 0, len(rawOptions)),
+	}
+	choices.ButtonText, _ = input["button_text"].(string)
+
+	seen := make(map[string]bool)
+	for i, raw := range rawOptions {
+		option, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("option %d must be an object", i+1)
+		}
+
+		title, _ := option["title"].(string)
+		title = strings.TrimSpace(title)
+		if title == "" {
+			return nil, fmt.Errorf("option %d is missing a title", i+1)
+		}
+
+		id, _ := option["id"].(string)
+		id = strings.TrimSpace(id)
+		if id == "" {
+			id = fmt.Sprintf("option_%d", i+1)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("duplicate option id: %s", id)
+		}
+		seen[id] = true
+
+		description, _ := option["description"].(string)
+
+		choices.Options = append(choices.Options, domain.ReplyChoice{
+			ID:          id,
+			Title:       title,
+			Description: strings.TrimSpace(description),
+		})
+	}
+
+	t.logger.WithContext(ctx).Debug().
+		Int("options", len(choices.Options)).
+		Msg("choices offered")
+
+	return choices, nil
+}
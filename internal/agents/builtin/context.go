@@ -0,0 +1,27 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"personal-assistant/internal/log"
+)
+
+// toolUserContext extracts the tenant and user a tool is invoked for from the tool context
+func toolUserContext(ctx context.Context) (string, uuid.UUID, error) {
+	tenantID, _ := ctx.Value(log.TenantIDKey).(string)
+	userIDStr, _ := ctx.Value(log.UserIDKey).(string)
+
+	if tenantID == "" || userIDStr == "" {
+		return "", uuid.Nil, fmt.Errorf("missing tenant_id or user_id in context")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return "", uuid.Nil, fmt.Errorf("invalid user_id format: %w", err)
+	}
+
+	return tenantID, userID, nil
+}
@@ -264,7 +264,7 @@ func (t *DBSearchTool) Schema() *domain.JSONSchema {
 						Description: "Filter by memory item types",
 						Items: &domain.JSONSchemaProperty{
 							Type: "string",
-							Enum: []string{"note", "event", "task", "msg"},
+							Enum: []string{"note", "event", "task", "msg", "document"},
 						},
 					},
 					"tags": {
@@ -368,6 +368,11 @@ func (t *DBSearchTool) Invoke(ctx context.Context, input map[string]interface{})
 			"score":    hit.Score,
 			"metadata": hit.Metadata,
 		}
+		
+		// Cite the source document of ingested chunks
+		if source := documentSource(hit.Metadata); source != nil {
+			items[i]["source"] = source
+		}
 	}
 	
 	return map[string]interface{}{
@@ -0,0 +1,217 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+// DocumentListTool lists the documents ingested into the user's memory
+type DocumentListTool struct {
+	repository domain.Repository
+	logger     *log.Logger
+}
+
+// NewDocumentListTool creates a new document listing tool
+func NewDocumentListTool(repository domain.Repository, logger *log.Logger) *DocumentListTool {
+	return &DocumentListTool{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// Name returns the tool name
+func (t *DocumentListTool) Name() string {
+	return "list_documents"
+}
+
+// Schema returns the JSON schema for the tool parameters
+func (t *DocumentListTool) Schema() *domain.JSONSchema {
+	return &domain.JSONSchema{
+		Type:       "object",
+		Properties: map[string]domain.JSONSchemaProperty{},
+	}
+}
+
+// Invoke executes the tool with the given input
+func (t *DocumentListTool) Invoke(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	tenantID, userID, err := toolUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := t.repository.ListDocuments(ctx, tenantID, userID, 50)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	results := make([]map[string]interface{}, 0, len(documents))
+	for i := range documents {
+		results = append(results, formatDocument(&documents[i]))
+	}
+
+	return map[string]interface{}{
+		"documents": results,
+		"count":     len(results),
+	}, nil
+}
+
+// DocumentForgetTool deletes an ingested document and its memory chunks
+type DocumentForgetTool struct {
+	repository domain.Repository
+	logger     *log.Logger
+}
+
+// NewDocumentForgetTool creates a new document removal tool
+func NewDocumentForgetTool(repository domain.Repository, logger *log.Logger) *DocumentForgetTool {
+	return &DocumentForgetTool{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// Name returns the tool name
+func (t *DocumentForgetTool) Name() string {
+	return "forget_document"
+}
+
+// Schema returns the JSON schema for the tool parameters
+func (t *DocumentForgetTool) Schema() *domain.JSONSchema {
+	return &domain.JSONSchema{
+		Type: "object",
+		Properties: map[string]domain.JSONSchemaProperty{
+			"document_id": {
+				Type:        "string",
+				Description: "UUID of the document to forget",
+			},
+			"file_name": {
+				Type:        "string",
+				Description: "File name of the document to forget, used when the ID is not known",
+			},
+		},
+	}
+}
+
+// Invoke executes the tool with the given input
+func (t *DocumentForgetTool) Invoke(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	tenantID, userID, err := toolUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := t.findDocument(ctx, tenantID, userID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.repository.DeleteDocument(ctx, tenantID, document.ID); err != nil {
+		return nil, fmt.Errorf("failed to forget document: %w", err)
+	}
+
+	t.logger.WithContext(ctx).Info().
+		Str("document_id", document.ID.String()).
+		Int("chunks", document.ChunkCount).
+		Msg("document forgotten")
+
+	return map[string]interface{}{
+		"document_id": document.ID.String(),
+		"file_name":   document.FileName,
+		"deleted":     true,
+	}, nil
+}
+
+// findDocument resolves the document referenced by ID or file name for the current user
+func (t *DocumentForgetTool) findDocument(ctx context.Context, tenantID string, userID uuid.UUID, input map[string]interface{}) (*domain.Document, error) {
+	if documentIDStr, _ := input["document_id"].(string); documentIDStr != "" {
+		documentID, err := uuid.Parse(documentIDStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid document_id format: %w", err)
+		}
+
+		document, err := t.repository.GetDocument(ctx, tenantID, documentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get document: %w", err)
+		}
+		if document == nil || document.UserID != userID {
+			return nil, fmt.Errorf("document not found: %s", documentIDStr)
+		}
+		return document, nil
+	}
+
+	fileName, _ := input["file_name"].(string)
+	if fileName == "" {
+		return nil, fmt.Errorf("document_id or file_name is required")
+	}
+
+	documents, err := t.repository.ListDocuments(ctx, tenantID, userID, 50)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	var matches []*domain.Document
+	for i := range documents {
+		if strings.EqualFold(documents[i].FileName, fileName) {
+			matches = append(matches, &documents[i])
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("document not found: %s", fileName)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%d documents are named %s, specify document_id", len(matches), fileName)
+	}
+}
+
+// formatDocument converts a document into a tool result
+func formatDocument(document *domain.Document) map[string]interface{} {
+	result := map[string]interface{}{
+		"document_id": document.ID.String(),
+		"file_name":   document.FileName,
+		"status":      document.Status,
+		"chunks":      document.ChunkCount,
+		"created_at":  document.CreatedAt.Format(time.RFC3339),
+	}
+	if document.PageCount > 0 {
+		result["pages"] = document.PageCount
+	}
+	if document.LastError != "" {
+		result["last_error"] = document.LastError
+	}
+	return result
+}
+
+// documentSource builds the citation of a memory hit that came from a document
+func documentSource(metadata map[string]interface{}) map[string]interface{} {
+	documentID, ok := metadata["document_id"].(string)
+	if !ok || documentID == "" {
+		return nil
+	}
+
+	name, _ := metadata["document_name"].(string)
+	source := map[string]interface{}{
+		"document_id":   documentID,
+		"document_name": name,
+		"citation":      name,
+	}
+
+	// Metadata round-trips through JSON, so numbers come back as float64
+	switch page := metadata["page"].(type) {
+	case float64:
+		source["page"] = int(page)
+		source["citation"] = fmt.Sprintf("%s, p. %d", name, int(page))
+	case int:
+		source["page"] = page
+		source["citation"] = fmt.Sprintf("%s, p. %d", name, page)
+	}
+
+	return source
+}
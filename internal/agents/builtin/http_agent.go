@@ -324,92 +324,6 @@ func (t *HTTPCallTool) Invoke(ctx context.Context, input map[string]interface{})
 	return result, nil
 }
 
-// ReminderScheduleTool schedules reminders (mock implementation)
-type ReminderScheduleTool struct {
-	logger *log.Logger
-}
-
-// NewReminderScheduleTool creates a new reminder scheduling tool
-func NewReminderScheduleTool(logger *log.Logger) *ReminderScheduleTool {
-	return &ReminderScheduleTool{
-		logger: logger,
-	}
-}
-
-// Name returns the tool name
-func (t *ReminderScheduleTool) Name() string {
-	return "schedule_reminder"
-}
-
-// Schema returns the JSON schema for the tool parameters
-func (t *ReminderScheduleTool) Schema() *domain.JSONSchema {
-	return &domain.JSONSchema{
-		Type: "object",
-		Properties: map[string]domain.JSONSchemaProperty{
-			"item_id": {
-				Type:        "string",
-				Description: "UUID of the memory item to remind about",
-			},
-			"when": {
-				Type:        "string",
-				Description: "ISO8601 timestamp when to send the reminder",
-			},
-			"channel": {
-				Type:        "string",
-				Description: "Channel to send reminder through",
-				Enum:        []string{"whatsapp", "email", "sms"},
-			},
-		},
-		Required: []string{"item_id", "when", "channel"},
-	}
-}
-
-// Invoke executes the tool with the given input
-func (t *ReminderScheduleTool) Invoke(ctx context.Context, input map[string]interface{}) (interface{}, error) {
-	// Extract parameters
-	itemID, _ := input["item_id"].(string)
-	whenStr, _ := input["when"].(string)
-	channel, _ := input["channel"].(string)
-	
-	// Validate timestamp
-	scheduledTime, err := time.Parse(time.RFC3339, whenStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid timestamp format, use ISO8601: %w", err)
-	}
-	
-	// Check if time is in the future
-	if scheduledTime.Before(time.Now()) {
-		return nil, fmt.Errorf("scheduled time must be in the future")
-	}
-	
-	// Extract tenant and user info from context for logging
-	tenantID, _ := ctx.Value(log.TenantIDKey).(string)
-	userIDStr, _ := ctx.Value(log.UserIDKey).(string)
-	
-	t.logger.WithContext(ctx).Info().
-		Str("tenant_id", tenantID).
-		Str("user_id", userIDStr).
-		Str("item_id", itemID).
-		Str("scheduled_at", scheduledTime.Format(time.RFC3339)).
-		Str("channel", channel).
-		Msg("reminder scheduled (mock implementation)")
-	
-	// This is a mock implementation
-	// In production, you would:
-	// 1. Store the reminder in a database
-	// 2. Set up a background job/cron to check for due reminders
-	// 3. Send notifications through the specified channel
-	
-	return map[string]interface{}{
-		"scheduled_at": scheduledTime.Format(time.RFC3339),
-		"item_id":      itemID,
-		"channel":      channel,
-		"status":       "scheduled",
-		"reminder_id":  fmt.Sprintf("reminder_%d", time.Now().Unix()),
-		"note":         "This is a mock implementation. In production, actual reminder scheduling would be implemented.",
-	}, nil
-}
-
 // WeatherTool is an example external service tool
 type WeatherTool struct {
 	httpClient *http.Client
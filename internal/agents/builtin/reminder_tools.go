@@ -0,0 +1,513 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+	"personal-assistant/internal/scheduler"
+)
+
+// ReminderScheduleTool schedules reminders that are delivered by the reminder scheduler
+type ReminderScheduleTool struct {
+	repository domain.Repository
+	logger     *log.Logger
+}
+
+// NewReminderScheduleTool creates a new reminder scheduling tool
+func NewReminderScheduleTool(repository domain.Repository, logger *log.Logger) *ReminderScheduleTool {
+	return &ReminderScheduleTool{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// Name returns the tool name
+func (t *ReminderScheduleTool) Name() string {
+	return "schedule_reminder"
+}
+
+// Schema returns the JSON schema for the tool parameters
+func (t *ReminderScheduleTool) Schema() *domain.JSONSchema {
+	return &domain.JSONSchema{
+		Type: "object",
+		Properties: map[string]domain.JSONSchemaProperty{
+			"message": {
+				Type:        "string",
+				Description: "Text of the reminder that will be sent to the user",
+			},
+			"when": {
+				Type:        "string",
+				Description: "ISO8601 timestamp (with time zone offset) of the first delivery",
+			},
+			"item_id": {
+				Type:        "string",
+				Description: "Optional UUID of the memory item to remind about",
+			},
+			"channel": {
+				Type:        "string",
+				Description: "Channel to send reminder through",
+				Enum:        []string{"whatsapp"},
+			},
+			"timezone": {
+				Type:        "string",
+				Description: "IANA time zone of the user, e.g. America/Sao_Paulo (optional)",
+			},
+			"frequency": {
+				Type:        "string",
+				Description: "Repeat the reminder; omit for a one-off reminder",
+				Enum:        []string{"daily", "weekly", "monthly"},
+			},
+			"interval": {
+				Type:        "integer",
+				Description: "Repeat every N days/weeks/months (default 1)",
+			},
+			"by_weekday": {
+				Type:        "array",
+				Description: "Weekdays for weekly reminders (default: weekday of 'when')",
+				Items: &domain.JSONSchemaProperty{
+					Type: "string",
+					Enum: []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"},
+				},
+			},
+			"by_month_day": {
+				Type:        "array",
+				Description: "Days of the month for monthly reminders, -1 for the last day (default: day of 'when')",
+				Items: &domain.JSONSchemaProperty{
+					Type: "integer",
+				},
+			},
+			"until": {
+				Type:        "string",
+				Description: "ISO8601 timestamp after which the reminder stops repeating (optional)",
+			},
+			"count": {
+				Type:        "integer",
+				Description: "Total number of times to send the reminder (optional)",
+			},
+		},
+		Required: []string{"message", "when"},
+	}
+}
+
+// Invoke executes the tool with the given input
+func (t *ReminderScheduleTool) Invoke(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	// Extract parameters
+	text, _ := input["message"].(string)
+	whenStr, _ := input["when"].(string)
+	itemIDStr, _ := input["item_id"].(string)
+	channel, _ := input["channel"].(string)
+	timeZone, _ := input["timezone"].(string)
+
+	if text == "" {
+		return nil, fmt.Errorf("message is required")
+	}
+
+	if channel == "" {
+		channel = "whatsapp"
+	}
+	if channel != "whatsapp" {
+		return nil, fmt.Errorf("unsupported channel: %s", channel)
+	}
+
+	// Validate timestamp
+	scheduledTime, err := time.Parse(time.RFC3339, whenStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp format, use ISO8601: %w", err)
+	}
+
+	// Check if time is in the future
+	if scheduledTime.Before(time.Now()) {
+		return nil, fmt.Errorf("scheduled time must be in the future")
+	}
+
+	tenantID, userID, err := toolUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeZone == "" {
+		timeZone = t.userTimeZone(ctx, tenantID, userID)
+	}
+	loc, err := scheduler.LoadLocation(timeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	reminder := &domain.Reminder{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		UserID:      userID,
+		Text:        text,
+		Channel:     channel,
+		Status:      domain.ReminderStatusScheduled,
+		ScheduledAt: scheduledTime.UTC(),
+		MaxAttempts: domain.DefaultReminderMaxAttempts,
+		TimeZone:    loc.String(),
+		Metadata:    map[string]interface{}{},
+	}
+
+	if itemIDStr != "" {
+		itemID, err := uuid.Parse(itemIDStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid item_id format: %w", err)
+		}
+		reminder.ItemID = &itemID
+	}
+
+	// Build recurrence rule if requested
+	if frequency, _ := input["frequency"].(string); frequency != "" {
+		rule, err := parseRecurrence(input, frequency, scheduledTime)
+		if err != nil {
+			return nil, err
+		}
+
+		// The first delivery is the first occurrence of the rule at or after 'when'
+		first, ok := scheduler.NextOccurrence(rule, loc, scheduledTime.Add(-time.Second))
+		if !ok {
+			return nil, fmt.Errorf("recurrence rule has no occurrences after %s", whenStr)
+		}
+
+		reminder.Recurrence = rule
+		reminder.ScheduledAt = first.UTC()
+	}
+
+	if err := t.repository.CreateReminder(ctx, reminder); err != nil {
+		return nil, fmt.Errorf("failed to schedule reminder: %w", err)
+	}
+
+	t.logger.WithContext(ctx).Info().
+		Str("tenant_id", tenantID).
+		Str("user_id", userID.String()).
+		Str("reminder_id", reminder.ID.String()).
+		Str("scheduled_at", reminder.ScheduledAt.Format(time.RFC3339)).
+		Bool("recurring", reminder.Recurrence != nil).
+		Str("channel", channel).
+		Msg("reminder scheduled")
+
+	return formatReminder(reminder, loc), nil
+}
+
+// userTimeZone returns the time zone stored in the user's profile, if any
+func (t *ReminderScheduleTool) userTimeZone(ctx context.Context, tenantID string, userID uuid.UUID) string {
+	user, err := t.repository.GetUserByID(ctx, tenantID, userID)
+	if err != nil || user == nil {
+		return ""
+	}
+	timeZone, _ := user.Profile["timezone"].(string)
+	return timeZone
+}
+
+// ReminderListTool lists the user's reminders
+type ReminderListTool struct {
+	repository domain.Repository
+	logger     *log.Logger
+}
+
+// NewReminderListTool creates a new reminder listing tool
+func NewReminderListTool(repository domain.Repository, logger *log.Logger) *ReminderListTool {
+	return &ReminderListTool{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// Name returns the tool name
+func (t *ReminderListTool) Name() string {
+	return "list_reminders"
+}
+
+// Schema returns the JSON schema for the tool parameters
+func (t *ReminderListTool) Schema() *domain.JSONSchema {
+	return &domain.JSONSchema{
+		Type: "object",
+		Properties: map[string]domain.JSONSchemaProperty{
+			"status": {
+				Type:        "string",
+				Description: "Only list reminders in this status (default: scheduled)",
+				Enum:        []string{"scheduled", "sent", "failed", "cancelled", "all"},
+			},
+		},
+	}
+}
+
+// Invoke executes the tool with the given input
+func (t *ReminderListTool) Invoke(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	status, _ := input["status"].(string)
+	switch status {
+	case "":
+		status = domain.ReminderStatusScheduled
+	case "all":
+		status = ""
+	}
+
+	tenantID, userID, err := toolUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reminders, err := t.repository.ListReminders(ctx, tenantID, userID, status, 50)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminders: %w", err)
+	}
+
+	results := make([]map[string]interface{}, 0, len(reminders))
+	for i := range reminders {
+		loc, err := scheduler.LoadLocation(reminders[i].TimeZone)
+		if err != nil {
+			loc = time.UTC
+		}
+		results = append(results, formatReminder(&reminders[i], loc))
+	}
+
+	return map[string]interface{}{
+		"reminders": results,
+		"count":     len(results),
+	}, nil
+}
+
+// ReminderCancelTool cancels a scheduled reminder
+type ReminderCancelTool struct {
+	repository domain.Repository
+	logger     *log.Logger
+}
+
+// NewReminderCancelTool creates a new reminder cancellation tool
+func NewReminderCancelTool(repository domain.Repository, logger *log.Logger) *ReminderCancelTool {
+	return &ReminderCancelTool{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// Name returns the tool name
+func (t *ReminderCancelTool) Name() string {
+	return "cancel_reminder"
+}
+
+// Schema returns the JSON schema for the tool parameters
+func (t *ReminderCancelTool) Schema() *domain.JSONSchema {
+	return &domain.JSONSchema{
+		Type: "object",
+		Properties: map[string]domain.JSONSchemaProperty{
+			"reminder_id": {
+				Type:        "string",
+				Description: "UUID of the reminder to cancel (cancels all future repetitions)",
+			},
+		},
+		Required: []string{"reminder_id"},
+	}
+}
+
+// Invoke executes the tool with the given input
+func (t *ReminderCancelTool) Invoke(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	reminder, err := loadUserReminder(ctx, t.repository, input)
+	if err != nil {
+		return nil, err
+	}
+
+	reminder.Status = domain.ReminderStatusCancelled
+	reminder.LockedUntil = nil
+
+	if err := t.repository.UpdateReminder(ctx, reminder); err != nil {
+		return nil, fmt.Errorf("failed to cancel reminder: %w", err)
+	}
+
+	t.logger.WithContext(ctx).Info().
+		Str("reminder_id", reminder.ID.String()).
+		Msg("reminder cancelled")
+
+	return map[string]interface{}{
+		"reminder_id": reminder.ID.String(),
+		"status":      reminder.Status,
+	}, nil
+}
+
+// ReminderSnoozeTool postpones the next delivery of a reminder
+type ReminderSnoozeTool struct {
+	repository domain.Repository
+	logger     *log.Logger
+}
+
+// NewReminderSnoozeTool creates a new reminder snooze tool
+func NewReminderSnoozeTool(repository domain.Repository, logger *log.Logger) *ReminderSnoozeTool {
+	return &ReminderSnoozeTool{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// Name returns the tool name
+func (t *ReminderSnoozeTool) Name() string {
+	return "snooze_reminder"
+}
+
+// Schema returns the JSON schema for the tool parameters
+func (t *ReminderSnoozeTool) Schema() *domain.JSONSchema {
+	return &domain.JSONSchema{
+		Type: "object",
+		Properties: map[string]domain.JSONSchemaProperty{
+			"reminder_id": {
+				Type:        "string",
+				Description: "UUID of the reminder to snooze",
+			},
+			"minutes": {
+				Type:        "integer",
+				Description: "Postpone the next delivery by this many minutes from now",
+			},
+			"until": {
+				Type:        "string",
+				Description: "ISO8601 timestamp to postpone the next delivery to (alternative to minutes)",
+			},
+		},
+		Required: []string{"reminder_id"},
+	}
+}
+
+// Invoke executes the tool with the given input
+func (t *ReminderSnoozeTool) Invoke(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	var snoozeUntil time.Time
+	if untilStr, _ := input["until"].(string); untilStr != "" {
+		parsed, err := time.Parse(time.RFC3339, untilStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid until format, use ISO8601: %w", err)
+		}
+		snoozeUntil = parsed
+	} else if minutes, ok := input["minutes"].(float64); ok && minutes > 0 {
+		snoozeUntil = time.Now().Add(time.Duration(minutes) * time.Minute)
+	} else {
+		return nil, fmt.Errorf("either minutes or until is required")
+	}
+
+	if snoozeUntil.Before(time.Now()) {
+		return nil, fmt.Errorf("snooze time must be in the future")
+	}
+
+	reminder, err := loadUserReminder(ctx, t.repository, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the next delivery moves; a recurring reminder resumes its normal schedule afterwards
+	reminder.Status = domain.ReminderStatusScheduled
+	reminder.ScheduledAt = snoozeUntil.UTC()
+	reminder.Attempts = 0
+	reminder.LockedUntil = nil
+
+	if err := t.repository.UpdateReminder(ctx, reminder); err != nil {
+		return nil, fmt.Errorf("failed to snooze reminder: %w", err)
+	}
+
+	t.logger.WithContext(ctx).Info().
+		Str("reminder_id", reminder.ID.String()).
+		Str("scheduled_at", reminder.ScheduledAt.Format(time.RFC3339)).
+		Msg("reminder snoozed")
+
+	loc, err := scheduler.LoadLocation(reminder.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	return formatReminder(reminder, loc), nil
+}
+
+// loadUserReminder loads the reminder referenced by input["reminder_id"] and
+// makes sure it belongs to the current user and can still be changed
+func loadUserReminder(ctx context.Context, repository domain.Repository, input map[string]interface{}) (*domain.Reminder, error) {
+	tenantID, userID, err := toolUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reminderIDStr, _ := input["reminder_id"].(string)
+	reminderID, err := uuid.Parse(reminderIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reminder_id format: %w", err)
+	}
+
+	reminder, err := repository.GetReminder(ctx, tenantID, reminderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminder: %w", err)
+	}
+
+	if reminder == nil || reminder.UserID != userID {
+		return nil, fmt.Errorf("reminder not found: %s", reminderIDStr)
+	}
+
+	if reminder.Status != domain.ReminderStatusScheduled {
+		return nil, fmt.Errorf("reminder is already %s", reminder.Status)
+	}
+
+	return reminder, nil
+}
+
+// parseRecurrence builds a recurrence rule from the schedule_reminder input
+func parseRecurrence(input map[string]interface{}, frequency string, start time.Time) (*domain.RecurrenceRule, error) {
+	rule := &domain.RecurrenceRule{
+		Frequency: frequency,
+		DTStart:   start.UTC(),
+	}
+
+	if interval, ok := input["interval"].(float64); ok {
+		rule.Interval = int(interval)
+	}
+
+	if count, ok := input["count"].(float64); ok {
+		rule.Count = int(count)
+	}
+
+	if weekdays, ok := input["by_weekday"].([]interface{}); ok {
+		for _, weekday := range weekdays {
+			if code, ok := weekday.(string); ok {
+				rule.ByWeekday = append(rule.ByWeekday, strings.ToUpper(code))
+			}
+		}
+	}
+
+	if monthDays, ok := input["by_month_day"].([]interface{}); ok {
+		for _, monthDay := range monthDays {
+			if day, ok := monthDay.(float64); ok {
+				rule.ByMonthDay = append(rule.ByMonthDay, int(day))
+			}
+		}
+	}
+
+	if untilStr, _ := input["until"].(string); untilStr != "" {
+		until, err := time.Parse(time.RFC3339, untilStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid until format, use ISO8601: %w", err)
+		}
+		until = until.UTC()
+		rule.Until = &until
+	}
+
+	if err := scheduler.ValidateRecurrence(rule); err != nil {
+		return nil, fmt.Errorf("invalid recurrence: %w", err)
+	}
+
+	return rule, nil
+}
+
+// formatReminder converts a reminder into a tool result shown in the user's time zone
+func formatReminder(reminder *domain.Reminder, loc *time.Location) map[string]interface{} {
+	result := map[string]interface{}{
+		"reminder_id":  reminder.ID.String(),
+		"message":      reminder.Text,
+		"status":       reminder.Status,
+		"scheduled_at": reminder.ScheduledAt.In(loc).Format(time.RFC3339),
+		"timezone":     loc.String(),
+		"channel":      reminder.Channel,
+	}
+
+	if reminder.Recurrence != nil {
+		result["recurrence"] = reminder.Recurrence
+		result["occurrences_sent"] = reminder.Occurrences
+	}
+
+	return result
+}
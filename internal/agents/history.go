@@ -0,0 +1,116 @@
+package agents
+
+import (
+	"context"
+	"time"
+
+	"personal-assistant/internal/domain"
+)
+
+// loadHistory loads the most recent conversation turns for the user as chat
+// messages in chronological order, excluding the message being processed and
+// trimmed from the oldest turn so that it fits in ContextTokens. When
+// summarization is enabled, the rolling summary replaces the turns it covers.
+func (o *MainOrchestrator) loadHistory(ctx context.Context, tenant *domain.Tenant, user *domain.User, current *domain.Message) []domain.ChatMessage {
+	if o.repository == nil || o.config.HistoryTurns <= 0 {
+		return nil
+	}
+
+	var summary *domain.ChatMessage
+	var coversUntil time.Time
+	tokens := 0
+	if o.config.SummarizeEnabled {
+		chunk, err := o.repository.GetLatestMemoryChunk(ctx, tenant.ID, user.ID, SummaryMemoryKind)
+		if err != nil {
+			o.logger.WithContext(ctx).Warn().Err(err).Msg("failed to load conversation summary, continuing without it")
+		} else if chunk != nil {
+			coversUntil, _ = SummaryCoversUntil(chunk)
+			summary = &domain.ChatMessage{
+				Role:    "system",
+				Content: "Summary of the earlier conversation:\n" + chunk.Text,
+			}
+			tokens += domain.EstimateTokens(summary.Content)
+		}
+	}
+
+	// Each turn is a user message plus the assistant reply; +1 for the current message
+	stored, err := o.repository.GetMessages(ctx, tenant.ID, user.ID, o.config.HistoryTurns*2+1)
+	if err != nil {
+		o.logger.WithContext(ctx).Warn().Err(err).Msg("failed to load conversation history, continuing without it")
+		return nil
+	}
+
+	// Messages are returned newest first
+	var history []domain.ChatMessage
+	for _, msg := range stored {
+		if current != nil && (msg.ID == current.ID || (current.MessageID != "" && msg.MessageID == current.MessageID)) {
+			continue
+		}
+
+		// Already covered by the summary
+		if !coversUntil.IsZero() && !msg.Timestamp.After(coversUntil) {
+			break
+		}
+
+		chatMsg, ok := historyChatMessage(&msg)
+		if !ok {
+			continue
+		}
+
+		msgTokens := domain.EstimateTokens(chatMsg.Content)
+		if o.config.ContextTokens > 0 && tokens+msgTokens > o.config.ContextTokens {
+			break
+		}
+		tokens += msgTokens
+
+		history = append(history, chatMsg)
+	}
+
+	// Reverse into chronological order
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+
+	if summary != nil {
+		history = append([]domain.ChatMessage{*summary}, history...)
+	}
+
+	o.logger.WithContext(ctx).Debug().
+		Int("history_messages", len(history)).
+		Int("estimated_tokens", tokens).
+		Bool("summary", summary != nil).
+		Msg("conversation history loaded")
+
+	return history
+}
+
+// historyChatMessage maps a stored message to an LLM chat message
+func historyChatMessage(msg *domain.Message) (domain.ChatMessage, bool) {
+	if msg.Text == "" {
+		return domain.ChatMessage{}, false
+	}
+
+	switch msg.Direction {
+	case "inbound":
+		return domain.ChatMessage{Role: "user", Content: msg.Text}, true
+	case "outbound":
+		return domain.ChatMessage{Role: "assistant", Content: msg.Text}, true
+	default:
+		return domain.ChatMessage{}, false
+	}
+}
+
+// buildMessages assembles the system prompt, conversation history and the current user message
+func buildMessages(systemPrompt string, history []domain.ChatMessage, userMessage string) []domain.ChatMessage {
+	messages := make([]domain.ChatMessage, 0, len(history)+2)
+	messages = append(messages, domain.ChatMessage{
+		Role:    "system",
+		Content: systemPrompt,
+	})
+	messages = append(messages, history...)
+	messages = append(messages, domain.ChatMessage{
+		Role:    "user",
+		Content: userMessage,
+	})
+	return messages
+}
@@ -17,8 +17,10 @@ type MainOrchestrator struct {
 	ragPipeline   domain.RAGPipeline
 	toolRegistry  domain.ToolRegistry
 	agentRegistry domain.AgentRegistry
+	repository    domain.Repository
 	logger        *log.Logger
 	config        *OrchestratorConfig
+	replySink     ReplySink
 }
 
 // OrchestratorConfig holds configuration for the orchestrator
@@ -29,7 +31,8 @@ type OrchestratorConfig struct {
 	EnableRAG        bool
 	RAGTopK          int
 	RAGMinScore      float64
-	ContextTokens    int
+	ContextTokens    int // Token budget for conversation history
+	HistoryTurns     int // Number of previous turns loaded into the prompt
 	SummarizeEnabled bool
 }
 
@@ -39,6 +42,7 @@ func NewMainOrchestrator(
 	ragPipeline domain.RAGPipeline,
 	toolRegistry domain.ToolRegistry,
 	agentRegistry domain.AgentRegistry,
+	repository domain.Repository,
 	logger *log.Logger,
 	config *OrchestratorConfig,
 ) *MainOrchestrator {
@@ -51,6 +55,7 @@ func NewMainOrchestrator(
 			RAGTopK:          5,
 			RAGMinScore:      0.7,
 			ContextTokens:    2000,
+			HistoryTurns:     10,
 			SummarizeEnabled: true,
 		}
 	}
@@ -60,6 +65,7 @@ func NewMainOrchestrator(
 		ragPipeline:   ragPipeline,
 		toolRegistry:  toolRegistry,
 		agentRegistry: agentRegistry,
+		repository:    repository,
 		logger:        logger,
 		config:        config,
 	}
@@ -116,9 +122,13 @@ func (o *MainOrchestrator) Route(ctx context.Context, tenant *domain.Tenant, use
 	
 	systemPrompt := BuildContextualPrompt(promptConfig, memoryContext, nil)
 	
-	// Check if this requires tool gating (simple conversational message)
-	if !o.requiresTools(message.Text, intent) {
-		response, err := o.handleConversationalMessage(ctx, systemPrompt, message.Text, memoryContext)
+	// Load previous turns so follow-up messages keep their context
+	history := o.loadHistory(ctx, tenant, user, message)
+	
+	// Check if this requires tool gating (simple conversational message).
+	// Button and list replies answer a question asked during a tool flow.
+	if !o.requiresTools(message.Text, intent) && !isInteractiveReply(message) {
+		response, err := o.handleConversationalMessage(ctx, systemPrompt, message.Text, history, memoryContext)
 		if err != nil {
 			return nil, fmt.Errorf("failed to handle conversational message: %w", err)
 		}
@@ -132,7 +142,7 @@ func (o *MainOrchestrator) Route(ctx context.Context, tenant *domain.Tenant, use
 	}
 	
 	// Handle with tools
-	response, err := o.handleWithTools(ctx, systemPrompt, message.Text, tenant, user)
+	response, err := o.handleWithTools(ctx, systemPrompt, message.Text, history, tenant, user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to handle message with tools: %w", err)
 	}
@@ -145,6 +155,12 @@ func (o *MainOrchestrator) Route(ctx context.Context, tenant *domain.Tenant, use
 	return response, nil
 }
 
+// isInteractiveReply reports whether the message was sent by tapping a button or list option
+func isInteractiveReply(message *domain.Message) bool {
+	_, ok := message.Metadata["reply_id"]
+	return ok
+}
+
 // requiresTools determines if a message needs tool invocation
 func (o *MainOrchestrator) requiresTools(text, intent string) bool {
 	// Skip tools for purely conversational intents
@@ -183,17 +199,8 @@ func (o *MainOrchestrator) requiresTools(text, intent string) bool {
 }
 
 // handleConversationalMessage handles simple conversational messages without tools
-func (o *MainOrchestrator) handleConversationalMessage(ctx context.Context, systemPrompt, userMessage string, memoryContext []MemoryContextItem) (*domain.AgentResponse, error) {
-	messages := []domain.ChatMessage{
-		{
-			Role:    "system",
-			Content: systemPrompt,
-		},
-		{
-			Role:    "user",
-			Content: userMessage,
-		},
-	}
+func (o *MainOrchestrator) handleConversationalMessage(ctx context.Context, systemPrompt, userMessage string, history []domain.ChatMessage, memoryContext []MemoryContextItem) (*domain.AgentResponse, error) {
+	messages := buildMessages(systemPrompt, history, userMessage)
 	
 	// Add memory context if available
 	if len(memoryContext) > 0 {
@@ -214,9 +221,10 @@ func (o *MainOrchestrator) handleConversationalMessage(ctx context.Context, syst
 		Temperature: o.config.Temperature,
 	}
 	
-	resp, err := o.llmProvider.Chat(ctx, req)
+	writer := o.newParagraphWriter(ctx)
+	resp, streamed, err := o.chat(ctx, req, writer)
 	if err != nil {
-		return nil, fmt.Errorf("LLM chat failed: %w", err)
+		return nil, writer.fail(fmt.Errorf("LLM chat failed: %w", err))
 	}
 	
 	if len(resp.Choices) == 0 {
@@ -224,35 +232,35 @@ func (o *MainOrchestrator) handleConversationalMessage(ctx context.Context, syst
 	}
 	
 	return &domain.AgentResponse{
-		Text: resp.Choices[0].Message.Content,
+		Text:     resp.Choices[0].Message.Content,
+		Streamed: streamed,
 		Metadata: map[string]interface{}{
 			"type":           "conversational",
 			"memory_context": len(memoryContext),
+			"history":        len(history),
 			"token_usage":    resp.Usage,
+			"llm_provider":   o.providerName(resp),
 		},
 	}, nil
 }
 
 // handleWithTools handles messages that require tool invocation
-func (o *MainOrchestrator) handleWithTools(ctx context.Context, systemPrompt, userMessage string, tenant *domain.Tenant, user *domain.User) (*domain.AgentResponse, error) {
-	messages := []domain.ChatMessage{
-		{
-			Role:    "system",
-			Content: systemPrompt,
-		},
-		{
-			Role:    "user",
-			Content: userMessage,
-		},
-	}
+func (o *MainOrchestrator) handleWithTools(ctx context.Context, systemPrompt, userMessage string, history []domain.ChatMessage, tenant *domain.Tenant, user *domain.User) (*domain.AgentResponse, error) {
+	messages := buildMessages(systemPrompt, history, userMessage)
 	
 	// Get available tools for the tenant
 	availableTools := o.toolRegistry.GetToolsForTenant(tenant.ID)
 	llmTools := o.convertToolsForLLM(availableTools)
 	
 	var allToolResults []domain.ToolInvocationResult
+	var choices *domain.ReplyChoices
+	var llmProvider string
+	var usage *domain.TokenUsage
 	maxIterations := o.config.MaxToolCalls
 	
+	// Paragraphs streamed during any completion of the turn have reached the user
+	writer := o.newParagraphWriter(ctx)
+	
 	for iteration := 0; iteration < maxIterations; iteration++ {
 		req := &domain.ChatCompletionRequest{
 			Messages:    messages,
@@ -262,30 +270,50 @@ func (o *MainOrchestrator) handleWithTools(ctx context.Context, systemPrompt, us
 			Temperature: o.config.Temperature,
 		}
 		
-		resp, err := o.llmProvider.Chat(ctx, req)
+		// Offered choices are sent together with the final reply, so it is only
+		// streamed when there are none
+		stream := writer
+		if choices != nil {
+			stream = nil
+		}
+		
+		resp, streamed, err := o.chat(ctx, req, stream)
 		if err != nil {
-			return nil, fmt.Errorf("LLM chat failed on iteration %d: %w", iteration, err)
+			return nil, writer.fail(fmt.Errorf("LLM chat failed on iteration %d: %w", iteration, err))
 		}
 		
 		if len(resp.Choices) == 0 {
-			return nil, fmt.Errorf("no response choices from LLM")
+			return nil, writer.fail(fmt.Errorf("no response choices from LLM"))
 		}
 		
 		choice := resp.Choices[0]
+		llmProvider = o.providerName(resp)
+		usage = addTokenUsage(usage, resp.Usage)
 		
 		// Add assistant message to conversation
 		messages = append(messages, *choice.Message)
 		
 		// Check if we have tool calls
 		if len(choice.Message.ToolCalls) == 0 {
-			// No more tool calls, return final response
+			// No more tool calls, return final response. A streamed reply
+			// includes the paragraphs sent before earlier tool calls.
+			text, preface := choice.Message.Content, writer.sentText()
+			if streamed {
+				text, preface = preface, ""
+			}
+			
 			return &domain.AgentResponse{
-				Text: choice.Message.Content,
+				Text:     text,
+				Preface:  preface,
+				Choices:  choices,
+				Streamed: streamed,
 				Metadata: map[string]interface{}{
 					"type":         "tool_assisted",
 					"iterations":   iteration + 1,
+					"history":      len(history),
 					"tool_results": allToolResults,
-					"token_usage":  resp.Usage,
+					"token_usage":  usage,
+					"llm_provider": llmProvider,
 				},
 			}, nil
 		}
@@ -308,6 +336,11 @@ func (o *MainOrchestrator) handleWithTools(ctx context.Context, systemPrompt, us
 			
 			allToolResults = append(allToolResults, *result)
 			
+			// Options offered by offer_choices are sent with the final reply
+			if offered, ok := result.Result.(*domain.ReplyChoices); ok && result.Success {
+				choices = offered
+			}
+			
 			// Add tool result to conversation
 			var resultContent string
 			if result.Success {
@@ -332,16 +365,44 @@ func (o *MainOrchestrator) handleWithTools(ctx context.Context, systemPrompt, us
 	
 	// If we've reached max iterations, return with partial results
 	return &domain.AgentResponse{
-		Text: "I was able to process your request partially, but reached the maximum number of tool calls allowed.",
+		Text:    "I was able to process your request partially, but reached the maximum number of tool calls allowed.",
+		Preface: writer.sentText(),
+		Choices: choices,
 		Metadata: map[string]interface{}{
 			"type":            "tool_assisted_partial",
 			"max_iterations":  maxIterations,
 			"tool_results":    allToolResults,
 			"warning":         "reached_max_tool_calls",
+			"llm_provider":    llmProvider,
+			"token_usage":     usage,
 		},
 	}, nil
 }
 
+// addTokenUsage adds the usage of a chat completion to a running total
+func addTokenUsage(total, usage *domain.TokenUsage) *domain.TokenUsage {
+	if usage == nil {
+		return total
+	}
+	if total == nil {
+		total = &domain.TokenUsage{}
+	}
+	
+	total.PromptTokens += usage.PromptTokens
+	total.CompletionTokens += usage.CompletionTokens
+	total.TotalTokens += usage.TotalTokens
+	return total
+}
+
+// providerName returns the name of the provider that served a response,
+// which differs from the configured one when a fallback chain failed over
+func (o *MainOrchestrator) providerName(resp *domain.ChatCompletionResponse) string {
+	if resp.Provider != "" {
+		return resp.Provider
+	}
+	return o.llmProvider.Name()
+}
+
 // getAvailableToolNames returns the names of tools available for a tenant
 func (o *MainOrchestrator) getAvailableToolNames(tenantID string) []string {
 	tools := o.toolRegistry.GetToolsForTenant(tenantID)
@@ -384,7 +445,19 @@ func (o *MainOrchestrator) getToolDescription(tool domain.Tool) string {
 	case "call_api":
 		return "Make HTTP API calls to external services"
 	case "schedule_reminder":
-		return "Schedule future reminders"
+		return "Schedule one-off or recurring reminders"
+	case "list_reminders":
+		return "List the user's scheduled reminders"
+	case "cancel_reminder":
+		return "Cancel a scheduled reminder"
+	case "snooze_reminder":
+		return "Postpone the next delivery of a reminder"
+	case "list_documents":
+		return "List documents saved to memory from attachments"
+	case "forget_document":
+		return "Delete a saved document and its memory"
+	case "offer_choices":
+		return "Show options the user can answer with a single tap (buttons or a list)"
 	default:
 		return fmt.Sprintf("Tool: %s", tool.Name())
 	}
@@ -450,6 +523,7 @@ func DefaultOrchestratorConfig() *OrchestratorConfig {
 		RAGTopK:          5,
 		RAGMinScore:      0.7,
 		ContextTokens:    2000,
+		HistoryTurns:     10,
 		SummarizeEnabled: true,
 	}
 }
\ No newline at end of file
@@ -2,11 +2,21 @@ package agents_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"personal-assistant/internal/agents"
+	"personal-assistant/internal/agents/builtin"
 	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+	"personal-assistant/internal/tools"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockAgent implements domain.Agent for testing
@@ -73,3 +83,494 @@ func TestAgentRegistry_GetAgent_NotFound(t *testing.T) {
 	_, err := reg.GetAgent("missing-agent")
 	assert.Error(t, err)
 }
+
+// MockLLMProvider implements domain.LLMProvider for testing
+type MockLLMProvider struct {
+	mock.Mock
+}
+
+func (m *MockLLMProvider) Chat(ctx context.Context, req *domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*domain.ChatCompletionResponse), args.Error(1)
+}
+
+func (m *MockLLMProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	args := m.Called(ctx, texts)
+	return args.Get(0).([][]float32), args.Error(1)
+}
+
+func (m *MockLLMProvider) Name() string { return "mock" }
+
+// MockHistoryRepository implements the message history subset of domain.Repository
+type MockHistoryRepository struct {
+	domain.Repository
+	mock.Mock
+}
+
+func (m *MockHistoryRepository) GetMessages(ctx context.Context, tenantID string, userID uuid.UUID, limit int) ([]domain.Message, error) {
+	args := m.Called(ctx, tenantID, userID, limit)
+	return args.Get(0).([]domain.Message), args.Error(1)
+}
+
+func (m *MockHistoryRepository) GetLatestMemoryChunk(ctx context.Context, tenantID string, userID uuid.UUID, kind string) (*domain.MemoryChunk, error) {
+	args := m.Called(ctx, tenantID, userID, kind)
+	return args.Get(0).(*domain.MemoryChunk), args.Error(1)
+}
+
+func (m *MockHistoryRepository) GetMessagesSince(ctx context.Context, tenantID string, userID uuid.UUID, since time.Time, limit int) ([]domain.Message, error) {
+	args := m.Called(ctx, tenantID, userID, since, limit)
+	return args.Get(0).([]domain.Message), args.Error(1)
+}
+
+func TestMainOrchestrator_IncludesConversationHistory(t *testing.T) {
+	tenant := &domain.Tenant{ID: "test-tenant"}
+	user := &domain.User{ID: uuid.New(), Phone: "+15551111111"}
+	current := &domain.Message{ID: uuid.New(), MessageID: "in-3", Direction: "inbound", Text: "ok thanks"}
+
+	repo := &MockHistoryRepository{}
+	repo.On("GetLatestMemoryChunk", mock.Anything, tenant.ID, user.ID, agents.SummaryMemoryKind).Return((*domain.MemoryChunk)(nil), nil)
+	// Newest first, as returned by the repository
+	repo.On("GetMessages", mock.Anything, tenant.ID, user.ID, 5).Return([]domain.Message{
+		*current,
+		{ID: uuid.New(), Direction: "outbound", Text: "Done, dentist on Thursday at 10."},
+		{ID: uuid.New(), Direction: "inbound", Text: "Book the dentist on Thursday"},
+		{ID: uuid.New(), Direction: "outbound", Text: strings.Repeat("old reply ", 100)},
+	}, nil)
+
+	var captured *domain.ChatCompletionRequest
+	provider := &MockLLMProvider{}
+	provider.On("Chat", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { captured = args.Get(1).(*domain.ChatCompletionRequest) }).
+		Return(&domain.ChatCompletionResponse{
+			Choices: []domain.Choice{{Message: &domain.ChatMessage{Role: "assistant", Content: "You're welcome!"}}},
+		}, nil)
+
+	config := agents.DefaultOrchestratorConfig()
+	config.EnableRAG = false
+	config.HistoryTurns = 2
+	config.ContextTokens = 50
+
+	orchestrator := agents.NewMainOrchestrator(provider, nil, tools.NewRegistry(), nil, repo, log.Init("error"), config)
+
+	response, err := orchestrator.Route(context.Background(), tenant, user, current)
+	assert.NoError(t, err)
+	assert.Equal(t, "You're welcome!", response.Text)
+
+	// The oldest reply exceeds the token budget and the current message is not duplicated
+	if assert.NotNil(t, captured) && assert.Len(t, captured.Messages, 4) {
+		assert.Equal(t, "system", captured.Messages[0].Role)
+		assert.Equal(t, domain.ChatMessage{Role: "user", Content: "Book the dentist on Thursday"}, captured.Messages[1])
+		assert.Equal(t, domain.ChatMessage{Role: "assistant", Content: "Done, dentist on Thursday at 10."}, captured.Messages[2])
+		assert.Equal(t, domain.ChatMessage{Role: "user", Content: "ok thanks"}, captured.Messages[3])
+	}
+}
+
+func TestMainOrchestrator_ReturnsOfferedChoices(t *testing.T) {
+	tenant := &domain.Tenant{ID: "test-tenant"}
+	user := &domain.User{ID: uuid.New(), Phone: "+15551111111"}
+	// A button tap is routed with tools even though the text is short
+	current := &domain.Message{
+		ID:        uuid.New(),
+		MessageID: "in-1",
+		Direction: "inbound",
+		Text:      "Groceries",
+		Metadata:  map[string]interface{}{"reply_id": "note_groceries"},
+	}
+
+	repo := &MockHistoryRepository{}
+	repo.On("GetLatestMemoryChunk", mock.Anything, tenant.ID, user.ID, agents.SummaryMemoryKind).Return((*domain.MemoryChunk)(nil), nil)
+	repo.On("GetMessages", mock.Anything, tenant.ID, user.ID, mock.Anything).Return([]domain.Message{*current}, nil)
+
+	registry := tools.NewRegistry()
+	assert.NoError(t, registry.RegisterTool(builtin.NewChoiceTool(log.Init("error"))))
+
+	provider := &MockLLMProvider{}
+	provider.On("Chat", mock.Anything, mock.MatchedBy(func(req *domain.ChatCompletionRequest) bool {
+		return len(req.Tools) == 1 && req.Messages[len(req.Messages)-1].Role == "user"
+	})).Return(&domain.ChatCompletionResponse{
+		Choices: []domain.Choice{{Message: &domain.ChatMessage{
+			Role: "assistant",
+			ToolCalls: []domain.ToolCall{{
+				ID:   "call_1",
+				Type: "function",
+				Function: &domain.FunctionCall{
+					Name:      "offer_choices",
+					Arguments: json.RawMessage(`{"options":[{"id":"delete_yes","title":"Yes"},{"id":"delete_no","title":"No"}]}`),
+				},
+			}},
+		}}},
+	}, nil).Once()
+	provider.On("Chat", mock.Anything, mock.Anything).Return(&domain.ChatCompletionResponse{
+		Choices: []domain.Choice{{Message: &domain.ChatMessage{Role: "assistant", Content: "Delete the groceries note?"}}},
+	}, nil).Once()
+
+	config := agents.DefaultOrchestratorConfig()
+	config.EnableRAG = false
+
+	orchestrator := agents.NewMainOrchestrator(provider, nil, registry, nil, repo, log.Init("error"), config)
+
+	response, err := orchestrator.Route(context.Background(), tenant, user, current)
+	assert.NoError(t, err)
+	assert.Equal(t, "Delete the groceries note?", response.Text)
+	if assert.NotNil(t, response.Choices) {
+		assert.Equal(t, []domain.ReplyChoice{
+			{ID: "delete_yes", Title: "Yes"},
+			{ID: "delete_no", Title: "No"},
+		}, response.Choices.Options)
+	}
+	provider.AssertExpectations(t)
+}
+
+// MockStreamingLLMProvider implements domain.ChatStreamer by replaying chunks,
+// failing with err after the last one when set
+type MockStreamingLLMProvider struct {
+	MockLLMProvider
+	chunks []string
+	err    error
+}
+
+func (m *MockStreamingLLMProvider) ChatStream(ctx context.Context, req *domain.ChatCompletionRequest, onDelta func(*domain.ChatDelta) error) (*domain.ChatCompletionResponse, error) {
+	for _, chunk := range m.chunks {
+		if err := onDelta(&domain.ChatDelta{Content: chunk}); err != nil {
+			return nil, err
+		}
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &domain.ChatCompletionResponse{
+		Choices: []domain.Choice{{Message: &domain.ChatMessage{Role: "assistant", Content: strings.Join(m.chunks, "")}}},
+	}, nil
+}
+
+func TestMainOrchestrator_StreamsParagraphs(t *testing.T) {
+	tenant := &domain.Tenant{ID: "test-tenant"}
+	user := &domain.User{ID: uuid.New(), Phone: "+15551111111"}
+	current := &domain.Message{ID: uuid.New(), MessageID: "in-1", Direction: "inbound", Text: "thanks"}
+
+	config := agents.DefaultOrchestratorConfig()
+	config.EnableRAG = false
+	config.HistoryTurns = 0
+
+	provider := &MockStreamingLLMProvider{chunks: []string{"You're ", "welcome!\n", "\nHere is ", "a tip.\n\n\n", "And a last ", "one."}}
+
+	t.Run("sends paragraphs to the reply sink", func(t *testing.T) {
+		var paragraphs []string
+		orchestrator := agents.NewMainOrchestrator(provider, nil, tools.NewRegistry(), nil, nil, log.Init("error"), config)
+		orchestrator.SetReplySink(func(ctx context.Context, text string) error {
+			paragraphs = append(paragraphs, text)
+			return nil
+		})
+
+		response, err := orchestrator.Route(context.Background(), tenant, user, current)
+		assert.NoError(t, err)
+		assert.True(t, response.Streamed)
+		assert.Equal(t, "You're welcome!\n\nHere is a tip.\n\n\nAnd a last one.", response.Text)
+		assert.Equal(t, []string{"You're welcome!", "Here is a tip.", "And a last one."}, paragraphs)
+	})
+
+	t.Run("reports the paragraphs sent before the stream failed", func(t *testing.T) {
+		failing := &MockStreamingLLMProvider{chunks: []string{"First part.\n\n", "Second ", "part.\n\nThird"}, err: errors.New("connection reset")}
+
+		var paragraphs []string
+		orchestrator := agents.NewMainOrchestrator(failing, nil, tools.NewRegistry(), nil, nil, log.Init("error"), config)
+		orchestrator.SetReplySink(func(ctx context.Context, text string) error {
+			paragraphs = append(paragraphs, text)
+			return nil
+		})
+
+		_, err := orchestrator.Route(context.Background(), tenant, user, current)
+		var partial *agents.PartialReplyError
+		require.ErrorAs(t, err, &partial)
+		assert.Equal(t, "First part.\n\nSecond part.", partial.Text)
+		assert.Equal(t, []string{"First part.", "Second part."}, paragraphs)
+	})
+
+	t.Run("reports a stream failing before any paragraph as a plain error", func(t *testing.T) {
+		failing := &MockStreamingLLMProvider{chunks: []string{"Not a full paragraph"}, err: errors.New("connection reset")}
+
+		orchestrator := agents.NewMainOrchestrator(failing, nil, tools.NewRegistry(), nil, nil, log.Init("error"), config)
+		orchestrator.SetReplySink(func(ctx context.Context, text string) error {
+			t.Errorf("unexpected paragraph %q", text)
+			return nil
+		})
+
+		_, err := orchestrator.Route(context.Background(), tenant, user, current)
+		require.Error(t, err)
+		var partial *agents.PartialReplyError
+		assert.False(t, errors.As(err, &partial))
+	})
+
+	t.Run("uses a blocking completion without a reply sink", func(t *testing.T) {
+		provider.On("Chat", mock.Anything, mock.Anything).Return(&domain.ChatCompletionResponse{
+			Choices: []domain.Choice{{Message: &domain.ChatMessage{Role: "assistant", Content: "You're welcome!"}}},
+		}, nil).Once()
+
+		orchestrator := agents.NewMainOrchestrator(provider, nil, tools.NewRegistry(), nil, nil, log.Init("error"), config)
+
+		response, err := orchestrator.Route(context.Background(), tenant, user, current)
+		assert.NoError(t, err)
+		assert.False(t, response.Streamed)
+		assert.Equal(t, "You're welcome!", response.Text)
+		provider.AssertExpectations(t)
+	})
+}
+
+// streamedCompletion is one completion replayed by MockToolStreamingLLMProvider
+type streamedCompletion struct {
+	chunks    []string
+	toolCalls []domain.ToolCall
+	err       error
+}
+
+// MockToolStreamingLLMProvider implements domain.ChatStreamer by replaying one
+// completion per call, including tool calls after the text
+type MockToolStreamingLLMProvider struct {
+	MockLLMProvider
+	completions []streamedCompletion
+	calls       int
+}
+
+func (m *MockToolStreamingLLMProvider) ChatStream(ctx context.Context, req *domain.ChatCompletionRequest, onDelta func(*domain.ChatDelta) error) (*domain.ChatCompletionResponse, error) {
+	completion := m.completions[m.calls]
+	m.calls++
+
+	for _, chunk := range completion.chunks {
+		if err := onDelta(&domain.ChatDelta{Content: chunk}); err != nil {
+			return nil, err
+		}
+	}
+	for i, call := range completion.toolCalls {
+		if err := onDelta(&domain.ChatDelta{ToolCalls: []domain.ToolCallDelta{{Index: i, ID: call.ID, Name: call.Function.Name}}}); err != nil {
+			return nil, err
+		}
+	}
+	if completion.err != nil {
+		return nil, completion.err
+	}
+	return &domain.ChatCompletionResponse{
+		Choices: []domain.Choice{{Message: &domain.ChatMessage{
+			Role:      "assistant",
+			Content:   strings.Join(completion.chunks, ""),
+			ToolCalls: completion.toolCalls,
+		}}},
+	}, nil
+}
+
+// MockWeatherTool implements domain.Tool with a fixed forecast
+type MockWeatherTool struct{}
+
+func (MockWeatherTool) Name() string { return "get_weather" }
+
+func (MockWeatherTool) Schema() *domain.JSONSchema {
+	return &domain.JSONSchema{Type: "object"}
+}
+
+func (MockWeatherTool) Invoke(ctx context.Context, input map[string]interface{}) (interface{}, error) {
+	return "sunny", nil
+}
+
+func TestMainOrchestrator_StreamsParagraphsAcrossToolCalls(t *testing.T) {
+	tenant := &domain.Tenant{ID: "test-tenant"}
+	user := &domain.User{ID: uuid.New(), Phone: "+15551111111"}
+	current := &domain.Message{ID: uuid.New(), MessageID: "in-1", Direction: "inbound", Text: "What will the weather be like in Lisbon tomorrow morning?"}
+
+	config := agents.DefaultOrchestratorConfig()
+	config.EnableRAG = false
+	config.HistoryTurns = 0
+
+	registry := tools.NewRegistry()
+	require.NoError(t, registry.RegisterTool(MockWeatherTool{}))
+
+	toolCall := domain.ToolCall{
+		ID:       "call_1",
+		Type:     "function",
+		Function: &domain.FunctionCall{Name: "get_weather", Arguments: json.RawMessage(`{}`)},
+	}
+
+	t.Run("keeps paragraphs sent before tool calls in the reply", func(t *testing.T) {
+		provider := &MockToolStreamingLLMProvider{completions: []streamedCompletion{
+			{chunks: []string{"Let me check that for you.\n\n"}, toolCalls: []domain.ToolCall{toolCall}},
+			{chunks: []string{"It will be sunny."}},
+		}}
+
+		var paragraphs []string
+		orchestrator := agents.NewMainOrchestrator(provider, nil, registry, nil, nil, log.Init("error"), config)
+		orchestrator.SetReplySink(func(ctx context.Context, text string) error {
+			paragraphs = append(paragraphs, text)
+			return nil
+		})
+
+		response, err := orchestrator.Route(context.Background(), tenant, user, current)
+		require.NoError(t, err)
+		assert.True(t, response.Streamed)
+		assert.Equal(t, "Let me check that for you.\n\nIt will be sunny.", response.Text)
+		assert.Equal(t, []string{"Let me check that for you.", "It will be sunny."}, paragraphs)
+	})
+
+	t.Run("reports paragraphs sent by an earlier completion when a later one fails", func(t *testing.T) {
+		provider := &MockToolStreamingLLMProvider{completions: []streamedCompletion{
+			{chunks: []string{"Let me check that for you.\n\n"}, toolCalls: []domain.ToolCall{toolCall}},
+			{err: errors.New("connection reset")},
+		}}
+
+		var paragraphs []string
+		orchestrator := agents.NewMainOrchestrator(provider, nil, registry, nil, nil, log.Init("error"), config)
+		orchestrator.SetReplySink(func(ctx context.Context, text string) error {
+			paragraphs = append(paragraphs, text)
+			return nil
+		})
+
+		_, err := orchestrator.Route(context.Background(), tenant, user, current)
+		var partial *agents.PartialReplyError
+		require.ErrorAs(t, err, &partial)
+		assert.Equal(t, "Let me check that for you.", partial.Text)
+		assert.Equal(t, []string{"Let me check that for you."}, paragraphs)
+		assert.Equal(t, 2, provider.calls)
+	})
+}
+
+func TestMainOrchestrator_UsesConversationSummary(t *testing.T) {
+	tenant := &domain.Tenant{ID: "test-tenant"}
+	user := &domain.User{ID: uuid.New(), Phone: "+15551111111"}
+	coversUntil := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	current := &domain.Message{ID: uuid.New(), Direction: "inbound", Text: "and the time?", Timestamp: coversUntil.Add(2 * time.Minute)}
+
+	repo := &MockHistoryRepository{}
+	repo.On("GetLatestMemoryChunk", mock.Anything, tenant.ID, user.ID, agents.SummaryMemoryKind).Return(&domain.MemoryChunk{
+		ID:   uuid.New(),
+		Kind: agents.SummaryMemoryKind,
+		Text: "User booked a dentist appointment for Thursday.",
+		Metadata: map[string]interface{}{
+			"covers_until": coversUntil.Format(time.RFC3339Nano),
+		},
+	}, nil)
+	repo.On("GetMessages", mock.Anything, tenant.ID, user.ID, 5).Return([]domain.Message{
+		*current,
+		{ID: uuid.New(), Direction: "outbound", Text: "It's at Main St.", Timestamp: coversUntil.Add(time.Minute)},
+		{ID: uuid.New(), Direction: "outbound", Text: "Booked for Thursday.", Timestamp: coversUntil},
+	}, nil)
+
+	var captured *domain.ChatCompletionRequest
+	provider := &MockLLMProvider{}
+	provider.On("Chat", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { captured = args.Get(1).(*domain.ChatCompletionRequest) }).
+		Return(&domain.ChatCompletionResponse{
+			Choices: []domain.Choice{{Message: &domain.ChatMessage{Role: "assistant", Content: "10 AM."}}},
+		}, nil)
+
+	config := agents.DefaultOrchestratorConfig()
+	config.EnableRAG = false
+	config.HistoryTurns = 2
+
+	orchestrator := agents.NewMainOrchestrator(provider, nil, tools.NewRegistry(), nil, repo, log.Init("error"), config)
+
+	_, err := orchestrator.Route(context.Background(), tenant, user, current)
+	assert.NoError(t, err)
+
+	// Messages covered by the summary are replaced by it
+	if assert.NotNil(t, captured) && assert.Len(t, captured.Messages, 4) {
+		assert.Equal(t, "system", captured.Messages[1].Role)
+		assert.Contains(t, captured.Messages[1].Content, "dentist appointment for Thursday")
+		assert.Equal(t, domain.ChatMessage{Role: "assistant", Content: "It's at Main St."}, captured.Messages[2])
+		assert.Equal(t, domain.ChatMessage{Role: "user", Content: "and the time?"}, captured.Messages[3])
+	}
+}
+
+// MockVectorStore implements the write subset of domain.VectorStore
+type MockVectorStore struct {
+	domain.VectorStore
+	mock.Mock
+}
+
+func (m *MockVectorStore) Upsert(ctx context.Context, tenantID string, userID uuid.UUID, items []domain.MemoryItem) ([]uuid.UUID, error) {
+	args := m.Called(ctx, tenantID, userID, items)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockVectorStore) DeleteByID(ctx context.Context, tenantID string, userID uuid.UUID, id uuid.UUID) error {
+	args := m.Called(ctx, tenantID, userID, id)
+	return args.Error(0)
+}
+
+func TestSummarizer_MaybeSummarize(t *testing.T) {
+	tenantID := "test-tenant"
+	userID := uuid.New()
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	var messages []domain.Message
+	for i := 0; i < 6; i++ {
+		direction := "inbound"
+		if i%2 == 1 {
+			direction = "outbound"
+		}
+		messages = append(messages, domain.Message{
+			ID: uuid.New(), Direction: direction, Text: strings.Repeat("word ", 20),
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	newSummarizer := func(repo *MockHistoryRepository, provider *MockLLMProvider, store *MockVectorStore, threshold int) *agents.Summarizer {
+		config := agents.DefaultSummarizerConfig()
+		config.Threshold = threshold
+		config.KeepRecentMessages = 2
+		return agents.NewSummarizer(provider, store, repo, log.Init("error"), config)
+	}
+
+	t.Run("below threshold", func(t *testing.T) {
+		repo := &MockHistoryRepository{}
+		repo.On("GetLatestMemoryChunk", mock.Anything, tenantID, userID, agents.SummaryMemoryKind).Return((*domain.MemoryChunk)(nil), nil)
+		repo.On("GetMessagesSince", mock.Anything, tenantID, userID, time.Time{}, 200).Return(messages, nil)
+		provider := &MockLLMProvider{}
+
+		summarized, err := newSummarizer(repo, provider, &MockVectorStore{}, 10000).MaybeSummarize(context.Background(), tenantID, userID)
+		assert.NoError(t, err)
+		assert.False(t, summarized)
+		provider.AssertNotCalled(t, "Chat", mock.Anything, mock.Anything)
+	})
+
+	t.Run("replaces previous summary", func(t *testing.T) {
+		previous := &domain.MemoryChunk{
+			ID:   uuid.New(),
+			Kind: agents.SummaryMemoryKind,
+			Text: "User likes coffee.",
+			Metadata: map[string]interface{}{
+				"covers_until":  start.Add(-time.Minute).Format(time.RFC3339Nano),
+				"message_count": float64(8),
+			},
+		}
+
+		repo := &MockHistoryRepository{}
+		repo.On("GetLatestMemoryChunk", mock.Anything, tenantID, userID, agents.SummaryMemoryKind).Return(previous, nil)
+		repo.On("GetMessagesSince", mock.Anything, tenantID, userID, start.Add(-time.Minute), 200).Return(messages, nil)
+
+		var captured *domain.ChatCompletionRequest
+		provider := &MockLLMProvider{}
+		provider.On("Chat", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { captured = args.Get(1).(*domain.ChatCompletionRequest) }).
+			Return(&domain.ChatCompletionResponse{
+				Choices: []domain.Choice{{Message: &domain.ChatMessage{Role: "assistant", Content: "User likes coffee and talked a lot."}}},
+			}, nil)
+		provider.On("Embed", mock.Anything, []string{"User likes coffee and talked a lot."}).Return([][]float32{{0.1, 0.2}}, nil)
+
+		store := &MockVectorStore{}
+		store.On("Upsert", mock.Anything, tenantID, userID, mock.MatchedBy(func(items []domain.MemoryItem) bool {
+			return len(items) == 1 &&
+				items[0].Kind == agents.SummaryMemoryKind &&
+				items[0].Metadata["covers_until"] == messages[3].Timestamp.Format(time.RFC3339Nano) &&
+				items[0].Metadata["message_count"] == 12
+		})).Return([]uuid.UUID{uuid.New()}, nil)
+		store.On("DeleteByID", mock.Anything, tenantID, userID, previous.ID).Return(nil)
+
+		summarized, err := newSummarizer(repo, provider, store, 50).MaybeSummarize(context.Background(), tenantID, userID)
+		assert.NoError(t, err)
+		assert.True(t, summarized)
+
+		// The previous summary is merged and the most recent messages are kept out
+		if assert.NotNil(t, captured) {
+			assert.Contains(t, captured.Messages[1].Content, "User likes coffee.")
+			assert.Equal(t, 4, strings.Count(captured.Messages[1].Content, ": word"))
+		}
+		store.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,157 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"personal-assistant/internal/domain"
+)
+
+// ReplySink sends part of a reply to the user while the rest is still being generated
+type ReplySink func(ctx context.Context, text string) error
+
+// PartialReplyError is returned when a streamed reply failed after some of its
+// paragraphs were sent to the user. Retrying the turn would send them again.
+type PartialReplyError struct {
+	Text string // Paragraphs that were sent
+	Err  error
+}
+
+func (e *PartialReplyError) Error() string {
+	return fmt.Sprintf("reply failed after part of it was sent: %v", e.Err)
+}
+
+func (e *PartialReplyError) Unwrap() error {
+	return e.Err
+}
+
+// SetReplySink makes the orchestrator stream replies through sink paragraph by
+// paragraph when the LLM provider supports streaming
+func (o *MainOrchestrator) SetReplySink(sink ReplySink) {
+	o.replySink = sink
+}
+
+// newParagraphWriter returns a writer sending the paragraphs of a turn's
+// replies to the reply sink, or nil when replies are not streamed
+func (o *MainOrchestrator) newParagraphWriter(ctx context.Context) *paragraphWriter {
+	if o.replySink == nil {
+		return nil
+	}
+	if _, ok := o.llmProvider.(domain.ChatStreamer); !ok {
+		return nil
+	}
+	return &paragraphWriter{ctx: ctx, sink: o.replySink}
+}
+
+// chat performs a chat completion. When a writer is given, completed
+// paragraphs of the reply are sent through it as they are generated; it
+// reports whether the whole reply text went out that way.
+func (o *MainOrchestrator) chat(ctx context.Context, req *domain.ChatCompletionRequest, writer *paragraphWriter) (*domain.ChatCompletionResponse, bool, error) {
+	if writer == nil {
+		resp, err := o.llmProvider.Chat(ctx, req)
+		return resp, false, err
+	}
+
+	writer.begin()
+	resp, err := o.llmProvider.(domain.ChatStreamer).ChatStream(ctx, req, writer.write)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Paragraphs sent before tool calls started stay part of the turn's reply,
+	// but the completion text is not the final reply
+	if len(resp.Choices) == 0 || resp.Choices[0].Message == nil || len(resp.Choices[0].Message.ToolCalls) > 0 {
+		return resp, false, nil
+	}
+
+	if err := writer.flush(); err != nil {
+		return nil, false, err
+	}
+
+	return resp, writer.sent > 0, nil
+}
+
+// paragraphWriter collects the streamed text of a turn's completions and
+// sends each paragraph to the reply sink once the blank line that ends it has
+// arrived. Sent paragraphs are kept across the completions of the turn.
+type paragraphWriter struct {
+	ctx    context.Context
+	sink   ReplySink
+	buffer string
+	sent   int
+	text   strings.Builder // Paragraphs sent so far
+	held   bool            // Tool calls started, so the rest of the text is not sent
+}
+
+// begin prepares the writer for the next completion of the turn
+func (w *paragraphWriter) begin() {
+	w.buffer = ""
+	w.held = false
+}
+
+// sentText returns the paragraphs sent so far; it is safe to call on a nil writer
+func (w *paragraphWriter) sentText() string {
+	if w == nil {
+		return ""
+	}
+	return w.text.String()
+}
+
+// fail returns err as a PartialReplyError when part of the reply was already
+// sent, so the turn is not retried and those paragraphs are not sent again
+func (w *paragraphWriter) fail(err error) error {
+	if w == nil || w.sent == 0 {
+		return err
+	}
+	return &PartialReplyError{Text: w.text.String(), Err: err}
+}
+
+// write adds a delta to the buffer and sends the paragraphs it completes
+func (w *paragraphWriter) write(delta *domain.ChatDelta) error {
+	if len(delta.ToolCalls) > 0 {
+		w.held = true
+	}
+	if w.held {
+		return nil
+	}
+
+	w.buffer += delta.Content
+	for {
+		end := strings.Index(w.buffer, "\n\n")
+		if end < 0 {
+			return nil
+		}
+
+		paragraph := w.buffer[:end]
+		w.buffer = strings.TrimLeft(w.buffer[end:], "\n")
+		if err := w.send(paragraph); err != nil {
+			return err
+		}
+	}
+}
+
+// flush sends whatever text is left once the stream has ended
+func (w *paragraphWriter) flush() error {
+	paragraph := w.buffer
+	w.buffer = ""
+	return w.send(paragraph)
+}
+
+// send passes a non-empty paragraph to the sink
+func (w *paragraphWriter) send(paragraph string) error {
+	paragraph = strings.TrimSpace(paragraph)
+	if paragraph == "" {
+		return nil
+	}
+
+	if err := w.sink(w.ctx, paragraph); err != nil {
+		return fmt.Errorf("failed to send streamed reply: %w", err)
+	}
+	if w.sent > 0 {
+		w.text.WriteString("\n\n")
+	}
+	w.text.WriteString(paragraph)
+	w.sent++
+	return nil
+}
@@ -0,0 +1,234 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+// SummaryMemoryKind is the memory chunk kind used for conversation summaries
+const SummaryMemoryKind = "summary"
+
+// Summarizer compresses older conversation turns into a rolling summary
+// stored as a memory chunk. Each user has at most one summary chunk; it
+// records in its metadata the timestamp of the last message it covers, so
+// only newer messages are loaded as raw history.
+type Summarizer struct {
+	llmProvider domain.LLMProvider
+	vectorStore domain.VectorStore
+	repository  domain.Repository
+	logger      *log.Logger
+	config      *SummarizerConfig
+}
+
+// SummarizerConfig holds configuration for the summarizer
+type SummarizerConfig struct {
+	Threshold          int // Estimated tokens of unsummarized history that trigger summarization
+	KeepRecentMessages int // Most recent messages left out of the summary
+	MaxMessages        int // Maximum messages loaded per run
+	MaxSummaryTokens   int
+	Temperature        float32
+}
+
+// NewSummarizer creates a new conversation summarizer
+func NewSummarizer(
+	llmProvider domain.LLMProvider,
+	vectorStore domain.VectorStore,
+	repository domain.Repository,
+	logger *log.Logger,
+	config *SummarizerConfig,
+) *Summarizer {
+	if config == nil {
+		config = DefaultSummarizerConfig()
+	}
+
+	return &Summarizer{
+		llmProvider: llmProvider,
+		vectorStore: vectorStore,
+		repository:  repository,
+		logger:      logger,
+		config:      config,
+	}
+}
+
+// DefaultSummarizerConfig returns the default summarizer configuration
+func DefaultSummarizerConfig() *SummarizerConfig {
+	return &SummarizerConfig{
+		Threshold:          10000,
+		KeepRecentMessages: 10,
+		MaxMessages:        200,
+		MaxSummaryTokens:   400,
+		Temperature:        0.2,
+	}
+}
+
+// MaybeSummarize folds the user's unsummarized messages into the rolling
+// summary once they exceed the token threshold. It reports whether a new
+// summary was written.
+func (s *Summarizer) MaybeSummarize(ctx context.Context, tenantID string, userID uuid.UUID) (bool, error) {
+	if s.config.Threshold <= 0 {
+		return false, nil
+	}
+
+	previous, err := s.repository.GetLatestMemoryChunk(ctx, tenantID, userID, SummaryMemoryKind)
+	if err != nil {
+		return false, fmt.Errorf("failed to load previous summary: %w", err)
+	}
+
+	var since time.Time
+	if previous != nil {
+		since, _ = SummaryCoversUntil(previous)
+	}
+
+	messages, err := s.repository.GetMessagesSince(ctx, tenantID, userID, since, s.config.MaxMessages)
+	if err != nil {
+		return false, fmt.Errorf("failed to load messages: %w", err)
+	}
+
+	tokens := 0
+	for _, msg := range messages {
+		tokens += domain.EstimateTokens(msg.Text)
+	}
+	if tokens < s.config.Threshold || len(messages) <= s.config.KeepRecentMessages {
+		return false, nil
+	}
+
+	older := messages[:len(messages)-s.config.KeepRecentMessages]
+
+	summary, err := s.summarize(ctx, previous, older)
+	if err != nil {
+		return false, err
+	}
+
+	embeddings, err := s.llmProvider.Embed(ctx, []string{summary})
+	if err != nil {
+		return false, fmt.Errorf("failed to embed summary: %w", err)
+	}
+	if len(embeddings) == 0 {
+		return false, fmt.Errorf("no embedding returned for summary")
+	}
+
+	messageCount := len(older)
+	if previous != nil {
+		messageCount += summaryMessageCount(previous)
+	}
+
+	item := domain.MemoryItem{
+		Kind: SummaryMemoryKind,
+		Text: summary,
+		Metadata: map[string]interface{}{
+			"embedding":     embeddings[0],
+			"covers_until":  older[len(older)-1].Timestamp.UTC().Format(time.RFC3339Nano),
+			"message_count": messageCount,
+			"source":        "summarizer",
+		},
+	}
+
+	if _, err := s.vectorStore.Upsert(ctx, tenantID, userID, []domain.MemoryItem{item}); err != nil {
+		return false, fmt.Errorf("failed to store summary: %w", err)
+	}
+
+	// The new summary already includes the previous one
+	if previous != nil {
+		if err := s.vectorStore.DeleteByID(ctx, tenantID, userID, previous.ID); err != nil {
+			s.logger.WithContext(ctx).Warn().
+				Err(err).
+				Str("summary_id", previous.ID.String()).
+				Msg("failed to delete previous summary")
+		}
+	}
+
+	s.logger.WithContext(ctx).Info().
+		Int("summarized_messages", len(older)).
+		Int("estimated_tokens", tokens).
+		Int("total_messages", messageCount).
+		Msg("conversation summary updated")
+
+	return true, nil
+}
+
+// summarize asks the LLM to merge the previous summary and the given messages
+func (s *Summarizer) summarize(ctx context.Context, previous *domain.MemoryChunk, messages []domain.Message) (string, error) {
+	var transcript strings.Builder
+	if previous != nil {
+		transcript.WriteString("Previous summary:\n")
+		transcript.WriteString(previous.Text)
+		transcript.WriteString("\n\n")
+	}
+
+	transcript.WriteString("New messages:\n")
+	for i := range messages {
+		chatMsg, ok := historyChatMessage(&messages[i])
+		if !ok {
+			continue
+		}
+		speaker := "User"
+		if chatMsg.Role == "assistant" {
+			speaker = "Assistant"
+		}
+		transcript.WriteString(fmt.Sprintf("%s: %s\n", speaker, chatMsg.Content))
+	}
+
+	req := &domain.ChatCompletionRequest{
+		Messages: []domain.ChatMessage{
+			{Role: "system", Content: summarizerPrompt},
+			{Role: "user", Content: transcript.String()},
+		},
+		MaxTokens:   s.config.MaxSummaryTokens,
+		Temperature: s.config.Temperature,
+	}
+
+	resp, err := s.llmProvider.Chat(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("LLM summarization failed: %w", err)
+	}
+
+	if len(resp.Choices) == 0 || resp.Choices[0].Message == nil {
+		return "", fmt.Errorf("no response choices from LLM")
+	}
+
+	summary := strings.TrimSpace(resp.Choices[0].Message.Content)
+	if summary == "" {
+		return "", fmt.Errorf("LLM returned an empty summary")
+	}
+
+	return summary, nil
+}
+
+const summarizerPrompt = `You maintain a running summary of a WhatsApp conversation between a user and their personal assistant.
+Merge the previous summary (if any) with the new messages into a single updated summary.
+Keep facts, preferences, decisions, commitments, dates and open questions. Drop greetings and small talk.
+Write in the same language as the conversation, in concise plain sentences, without preamble.`
+
+// SummaryCoversUntil returns the timestamp of the last message covered by a summary chunk
+func SummaryCoversUntil(chunk *domain.MemoryChunk) (time.Time, bool) {
+	value, ok := chunk.Metadata["covers_until"].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	coversUntil, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return coversUntil, true
+}
+
+// summaryMessageCount returns the number of messages already folded into a summary chunk
+func summaryMessageCount(chunk *domain.MemoryChunk) int {
+	switch count := chunk.Metadata["message_count"].(type) {
+	case float64:
+		return int(count)
+	case int:
+		return count
+	default:
+		return 0
+	}
+}
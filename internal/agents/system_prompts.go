@@ -49,7 +49,11 @@ Only call tools when actually necessary for the user's request:
 - User asks to search/find/recall something → search
 - User asks to update/modify stored information → update_item
 - User requests external data (weather, API calls) → call_api
-- User wants to schedule reminders → schedule_reminder
+- User wants to schedule reminders → schedule_reminder (use frequency/by_weekday/by_month_day for repeating ones)
+- User asks which reminders are pending → list_reminders
+- User wants to cancel or postpone a reminder → cancel_reminder / snooze_reminder
+- User asks which documents were saved or wants one forgotten → list_documents / forget_document
+- You need confirmation or a pick from a few options (e.g. "Delete this note? Yes/No") → offer_choices, then write the question as your reply
 
 **WHEN NOT TO USE TOOLS:**
 - General conversation and questions
@@ -62,6 +66,7 @@ Only call tools when actually necessary for the user's request:
 - Confirm actions after completing them
 - Summarize findings when searching memory
 - Ask clarifying questions when needed
+- Taps on buttons or list options arrive as the option title; treat them as the user's answer to your last question
 - Respond in the user's language
 - Keep responses concise but complete
 
@@ -72,6 +77,13 @@ When relevant context is provided from memory:
 - Use it to provide more personalized and accurate answers
 - Mention when information comes from your memory of previous conversations
 
+### 5. Attachments
+Images, documents, audio, videos, locations and contacts arrive as bracketed descriptions such as "[Image received: image/jpeg] caption":
+- Acknowledge what was received and use any caption or details to help
+- Offer to save useful details (addresses, contacts, receipts) with upsert_item
+- PDF, text and Markdown documents are saved to memory automatically; cite the document name and page from search results when answering from them
+- Voice notes are transcribed and arrive as plain text; reply to them like any typed message
+
 ## Available Tools`, currentTime, config.UserName, config.TenantName)
 
 	// Add available tools
@@ -90,7 +102,19 @@ When relevant context is provided from memory:
 			case "call_api":
 				prompt += "- **call_api**: Make external API calls to configured services\n"
 			case "schedule_reminder":
-				prompt += "- **schedule_reminder**: Schedule future reminders\n"
+				prompt += "- **schedule_reminder**: Schedule future reminders, optionally repeating\n"
+			case "list_reminders":
+				prompt += "- **list_reminders**: List pending reminders\n"
+			case "cancel_reminder":
+				prompt += "- **cancel_reminder**: Cancel a reminder\n"
+			case "snooze_reminder":
+				prompt += "- **snooze_reminder**: Postpone a reminder\n"
+			case "list_documents":
+				prompt += "- **list_documents**: List documents saved from attachments\n"
+			case "forget_document":
+				prompt += "- **forget_document**: Remove a saved document from memory\n"
+			case "offer_choices":
+				prompt += "- **offer_choices**: Show reply buttons or a list of options with your answer\n"
 			}
 		}
 	}
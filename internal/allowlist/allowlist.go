@@ -0,0 +1,243 @@
+package allowlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"personal-assistant/internal/domain"
+)
+
+// Keys of the sender settings in system_config. A tenant overrides them by
+// setting the same keys in its own config.
+const (
+	RequireAllowlistKey    = "require_contact_allowlist"
+	DefaultPermissionsKey  = "default_contact_permissions"
+	UnknownSenderActionKey = "unknown_sender_action"
+)
+
+var (
+	// ErrRequestNotFound is returned when a sender has no access request
+	ErrRequestNotFound = errors.New("contact request not found")
+	// ErrRequestDecided is returned when an access request was already approved or denied
+	ErrRequestDecided = errors.New("contact request already decided")
+)
+
+// Policy holds a tenant's settings for senders outside the contact allowlist
+type Policy struct {
+	RequireAllowlist    bool
+	UnknownSenderAction string
+	DefaultPermissions  []string // Permissions of contacts added automatically or on approval
+}
+
+// LoadPolicy returns the sender policy of a tenant, reading each setting from
+// the tenant's config first and from system_config otherwise. Missing or
+// unreadable settings keep their defaults: the allowlist is required and
+// unknown senders are ignored.
+func LoadPolicy(ctx context.Context, repo domain.Repository, tenant *domain.Tenant) (*Policy, error) {
+	policy := &Policy{
+		RequireAllowlist:    true,
+		UnknownSenderAction: domain.UnknownSenderIgnore,
+		DefaultPermissions:  domain.DefaultContactPermissions,
+	}
+
+	for _, key := range []string{RequireAllowlistKey, DefaultPermissionsKey, UnknownSenderActionKey} {
+		value, found := tenant.Config[key]
+		if !found {
+			config, err := repo.GetSystemConfig(ctx, key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get system config %s: %w", key, err)
+			}
+			if config == nil {
+				continue
+			}
+			value = config.Value
+		}
+
+		policy.apply(key, value)
+	}
+
+	return policy, nil
+}
+
+// apply sets a policy setting from its configured value
+func (p *Policy) apply(key string, value interface{}) {
+	switch key {
+	case RequireAllowlistKey:
+		switch v := value.(type) {
+		case bool:
+			p.RequireAllowlist = v
+		case string:
+			if parsed, err := strconv.ParseBool(v); err == nil {
+				p.RequireAllowlist = parsed
+			}
+		}
+
+	case UnknownSenderActionKey:
+		switch action, _ := value.(string); action {
+		case domain.UnknownSenderIgnore, domain.UnknownSenderNotifyAdmin, domain.UnknownSenderAutoAdd:
+			p.UnknownSenderAction = action
+		}
+
+	case DefaultPermissionsKey:
+		var permissions []string
+		switch v := value.(type) {
+		case []string:
+			permissions = v
+		case []interface{}:
+			for _, item := range v {
+				if permission, ok := item.(string); ok {
+					permissions = append(permissions, permission)
+				}
+			}
+		default:
+			return
+		}
+
+		valid := make([]string, 0, len(permissions))
+		for _, permission := range permissions {
+			if domain.IsValidPermission(permission) {
+				valid = append(valid, permission)
+			}
+		}
+		p.DefaultPermissions = valid
+	}
+}
+
+// AddContact adds a sender to the allowlist, or enables them again if they
+// were added before
+func AddContact(ctx context.Context, repo domain.Repository, tenantID, phoneNumber, name string, permissions []string, notes string) (*domain.AllowedContact, error) {
+	contact, err := repo.GetAllowedContact(ctx, tenantID, phoneNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allowed contact: %w", err)
+	}
+
+	if contact != nil {
+		if !contact.Enabled {
+			contact.Enabled = true
+			if err := repo.UpdateAllowedContact(ctx, contact); err != nil {
+				return nil, fmt.Errorf("failed to enable allowed contact: %w", err)
+			}
+		}
+		return contact, nil
+	}
+
+	contact = &domain.AllowedContact{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		PhoneNumber: phoneNumber,
+		ContactName: name,
+		Permissions: permissions,
+		Notes:       notes,
+		Enabled:     true,
+	}
+	if err := repo.CreateAllowedContact(ctx, contact); err != nil {
+		return nil, fmt.Errorf("failed to create allowed contact: %w", err)
+	}
+
+	return contact, nil
+}
+
+// RecordRequest records a message from a sender outside the allowlist as an
+// access request. It reports whether the request is new, or was reopened
+// after an approved contact was removed, so that admins should be notified.
+// Messages from denied senders and further messages of a pending request only
+// update the request.
+func RecordRequest(ctx context.Context, repo domain.Repository, tenantID, phoneNumber, name, text string) (*domain.ContactRequest, bool, error) {
+	request, err := repo.GetContactRequest(ctx, tenantID, phoneNumber)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get contact request: %w", err)
+	}
+
+	if request == nil {
+		request = &domain.ContactRequest{
+			TenantID:    tenantID,
+			PhoneNumber: phoneNumber,
+			ContactName: name,
+			LastMessage: text,
+			Status:      domain.ContactRequestPending,
+		}
+		if err := repo.CreateContactRequest(ctx, request); err != nil {
+			return nil, false, err
+		}
+		return request, true, nil
+	}
+
+	reopened := request.Status == domain.ContactRequestApproved
+	if reopened {
+		request.Status = domain.ContactRequestPending
+		request.DecidedBy = ""
+		request.DecidedAt = nil
+	}
+	if name != "" {
+		request.ContactName = name
+	}
+	request.LastMessage = text
+	request.MessageCount++
+
+	if err := repo.UpdateContactRequest(ctx, request); err != nil {
+		return nil, false, err
+	}
+
+	return request, reopened, nil
+}
+
+// Decide approves or denies a sender's pending access request. An approved
+// sender is added to the allowlist with the policy's default permissions.
+func Decide(ctx context.Context, repo domain.Repository, tenant *domain.Tenant, phoneNumber string, approve bool, decidedBy string) (*domain.ContactRequest, error) {
+	request, err := repo.GetContactRequest(ctx, tenant.ID, phoneNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contact request: %w", err)
+	}
+	if request == nil {
+		return nil, ErrRequestNotFound
+	}
+	if request.Status != domain.ContactRequestPending {
+		return request, ErrRequestDecided
+	}
+
+	request.Status = domain.ContactRequestDenied
+	if approve {
+		policy, err := LoadPolicy(ctx, repo, tenant)
+		if err != nil {
+			return nil, err
+		}
+
+		notes := fmt.Sprintf("Approved by %s", decidedBy)
+		if _, err := AddContact(ctx, repo, tenant.ID, phoneNumber, request.ContactName, policy.DefaultPermissions, notes); err != nil {
+			return nil, err
+		}
+		request.Status = domain.ContactRequestApproved
+	}
+
+	now := time.Now().UTC()
+	request.DecidedBy = decidedBy
+	request.DecidedAt = &now
+
+	if err := repo.UpdateContactRequest(ctx, request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// Admins returns the tenant's enabled contacts with the admin permission
+func Admins(ctx context.Context, repo domain.Repository, tenantID string) ([]domain.AllowedContact, error) {
+	contacts, err := repo.GetAllowedContacts(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allowed contacts: %w", err)
+	}
+
+	var admins []domain.AllowedContact
+	for _, contact := range contacts {
+		if contact.Enabled && contact.HasPermission(domain.PermissionAdmin) {
+			admins = append(admins, contact)
+		}
+	}
+
+	return admins, nil
+}
@@ -0,0 +1,200 @@
+package allowlist_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/allowlist"
+	"personal-assistant/internal/domain"
+)
+
+// MockRepository implements the contact-related subset of domain.Repository
+type MockRepository struct {
+	domain.Repository
+	mock.Mock
+}
+
+func (m *MockRepository) GetSystemConfig(ctx context.Context, key string) (*domain.SystemConfig, error) {
+	args := m.Called(ctx, key)
+	config, _ := args.Get(0).(*domain.SystemConfig)
+	return config, args.Error(1)
+}
+
+func (m *MockRepository) GetAllowedContact(ctx context.Context, tenantID, phoneNumber string) (*domain.AllowedContact, error) {
+	args := m.Called(ctx, tenantID, phoneNumber)
+	contact, _ := args.Get(0).(*domain.AllowedContact)
+	return contact, args.Error(1)
+}
+
+func (m *MockRepository) CreateAllowedContact(ctx context.Context, contact *domain.AllowedContact) error {
+	args := m.Called(ctx, contact)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetContactRequest(ctx context.Context, tenantID, phoneNumber string) (*domain.ContactRequest, error) {
+	args := m.Called(ctx, tenantID, phoneNumber)
+	request, _ := args.Get(0).(*domain.ContactRequest)
+	return request, args.Error(1)
+}
+
+func (m *MockRepository) CreateContactRequest(ctx context.Context, request *domain.ContactRequest) error {
+	args := m.Called(ctx, request)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateContactRequest(ctx context.Context, request *domain.ContactRequest) error {
+	args := m.Called(ctx, request)
+	return args.Error(0)
+}
+
+func TestLoadPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("defaults when nothing is configured", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetSystemConfig", ctx, mock.Anything).Return(nil, nil)
+
+		policy, err := allowlist.LoadPolicy(ctx, repo, &domain.Tenant{ID: "tenant-a"})
+		require.NoError(t, err)
+		assert.True(t, policy.RequireAllowlist)
+		assert.Equal(t, domain.UnknownSenderIgnore, policy.UnknownSenderAction)
+		assert.Equal(t, domain.DefaultContactPermissions, policy.DefaultPermissions)
+	})
+
+	t.Run("tenant config overrides system config", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetSystemConfig", ctx, allowlist.RequireAllowlistKey).
+			Return(&domain.SystemConfig{Value: false}, nil)
+		repo.On("GetSystemConfig", ctx, allowlist.DefaultPermissionsKey).
+			Return(&domain.SystemConfig{Value: []interface{}{"chat", "bogus"}}, nil)
+
+		tenant := &domain.Tenant{
+			ID:     "tenant-a",
+			Config: map[string]interface{}{allowlist.UnknownSenderActionKey: domain.UnknownSenderNotifyAdmin},
+		}
+
+		policy, err := allowlist.LoadPolicy(ctx, repo, tenant)
+		require.NoError(t, err)
+		assert.False(t, policy.RequireAllowlist)
+		assert.Equal(t, domain.UnknownSenderNotifyAdmin, policy.UnknownSenderAction)
+		assert.Equal(t, []string{domain.PermissionChat}, policy.DefaultPermissions)
+		repo.AssertNotCalled(t, "GetSystemConfig", ctx, allowlist.UnknownSenderActionKey)
+	})
+
+	t.Run("ignores unknown actions", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetSystemConfig", ctx, mock.Anything).Return(nil, nil)
+
+		tenant := &domain.Tenant{
+			ID:     "tenant-a",
+			Config: map[string]interface{}{allowlist.UnknownSenderActionKey: "welcome_everyone"},
+		}
+
+		policy, err := allowlist.LoadPolicy(ctx, repo, tenant)
+		require.NoError(t, err)
+		assert.Equal(t, domain.UnknownSenderIgnore, policy.UnknownSenderAction)
+	})
+}
+
+func TestRecordRequest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("new sender notifies admins", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetContactRequest", ctx, "tenant-a", "5511999").Return(nil, nil)
+		repo.On("CreateContactRequest", ctx, mock.AnythingOfType("*domain.ContactRequest")).Return(nil)
+
+		request, notify, err := allowlist.RecordRequest(ctx, repo, "tenant-a", "5511999", "Ana", "hello")
+		require.NoError(t, err)
+		assert.True(t, notify)
+		assert.Equal(t, domain.ContactRequestPending, request.Status)
+		assert.Equal(t, "hello", request.LastMessage)
+	})
+
+	t.Run("pending and denied requests are only updated", func(t *testing.T) {
+		for _, status := range []string{domain.ContactRequestPending, domain.ContactRequestDenied} {
+			existing := &domain.ContactRequest{TenantID: "tenant-a", PhoneNumber: "5511999", Status: status, MessageCount: 1}
+
+			repo := new(MockRepository)
+			repo.On("GetContactRequest", ctx, "tenant-a", "5511999").Return(existing, nil)
+			repo.On("UpdateContactRequest", ctx, existing).Return(nil)
+
+			request, notify, err := allowlist.RecordRequest(ctx, repo, "tenant-a", "5511999", "", "again")
+			require.NoError(t, err)
+			assert.False(t, notify)
+			assert.Equal(t, status, request.Status)
+			assert.Equal(t, 2, request.MessageCount)
+		}
+	})
+
+	t.Run("approved request is reopened", func(t *testing.T) {
+		existing := &domain.ContactRequest{TenantID: "tenant-a", PhoneNumber: "5511999", Status: domain.ContactRequestApproved, DecidedBy: "api"}
+
+		repo := new(MockRepository)
+		repo.On("GetContactRequest", ctx, "tenant-a", "5511999").Return(existing, nil)
+		repo.On("UpdateContactRequest", ctx, existing).Return(nil)
+
+		request, notify, err := allowlist.RecordRequest(ctx, repo, "tenant-a", "5511999", "", "back again")
+		require.NoError(t, err)
+		assert.True(t, notify)
+		assert.Equal(t, domain.ContactRequestPending, request.Status)
+		assert.Empty(t, request.DecidedBy)
+	})
+}
+
+func TestDecide(t *testing.T) {
+	ctx := context.Background()
+	tenant := &domain.Tenant{ID: "tenant-a"}
+
+	t.Run("approval adds the contact with default permissions", func(t *testing.T) {
+		pending := &domain.ContactRequest{TenantID: "tenant-a", PhoneNumber: "5511999", ContactName: "Ana", Status: domain.ContactRequestPending}
+
+		repo := new(MockRepository)
+		repo.On("GetContactRequest", ctx, "tenant-a", "5511999").Return(pending, nil)
+		repo.On("GetSystemConfig", ctx, mock.Anything).Return(nil, nil)
+		repo.On("GetAllowedContact", ctx, "tenant-a", "5511999").Return(nil, nil)
+		repo.On("CreateAllowedContact", ctx, mock.MatchedBy(func(contact *domain.AllowedContact) bool {
+			return contact.PhoneNumber == "5511999" && contact.ContactName == "Ana" && contact.Enabled &&
+				assert.ObjectsAreEqual(domain.DefaultContactPermissions, contact.Permissions)
+		})).Return(nil)
+		repo.On("UpdateContactRequest", ctx, pending).Return(nil)
+
+		request, err := allowlist.Decide(ctx, repo, tenant, "5511999", true, "5511000")
+		require.NoError(t, err)
+		assert.Equal(t, domain.ContactRequestApproved, request.Status)
+		assert.Equal(t, "5511000", request.DecidedBy)
+		assert.NotNil(t, request.DecidedAt)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("denial does not add the contact", func(t *testing.T) {
+		pending := &domain.ContactRequest{TenantID: "tenant-a", PhoneNumber: "5511999", Status: domain.ContactRequestPending}
+
+		repo := new(MockRepository)
+		repo.On("GetContactRequest", ctx, "tenant-a", "5511999").Return(pending, nil)
+		repo.On("UpdateContactRequest", ctx, pending).Return(nil)
+
+		request, err := allowlist.Decide(ctx, repo, tenant, "5511999", false, "api")
+		require.NoError(t, err)
+		assert.Equal(t, domain.ContactRequestDenied, request.Status)
+		repo.AssertNotCalled(t, "CreateAllowedContact", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing and decided requests", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetContactRequest", ctx, "tenant-a", "5511000").Return(nil, nil)
+		repo.On("GetContactRequest", ctx, "tenant-a", "5511999").
+			Return(&domain.ContactRequest{Status: domain.ContactRequestDenied}, nil)
+
+		_, err := allowlist.Decide(ctx, repo, tenant, "5511000", true, "api")
+		assert.ErrorIs(t, err, allowlist.ErrRequestNotFound)
+
+		request, err := allowlist.Decide(ctx, repo, tenant, "5511999", true, "api")
+		assert.ErrorIs(t, err, allowlist.ErrRequestDecided)
+		assert.Equal(t, domain.ContactRequestDenied, request.Status)
+	})
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	// keyPrefix starts every generated key, so that leaked keys are easy to search for
+	keyPrefix = "pa_"
+	// keyBytes is the number of random bytes in a key
+	keyBytes = 32
+	// displayLength is the number of leading characters of a key kept for listings
+	displayLength = 11
+)
+
+// GenerateKey returns a new random API key and the prefix stored to recognise it
+func GenerateKey() (key, prefix string, err error) {
+	secret := make([]byte, keyBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	key = keyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:displayLength], nil
+}
+
+// HashKey returns the hex SHA-256 of a key as stored in api_keys. Keys carry
+// 256 bits of randomness, so a fast unsalted hash is enough and lets keys be
+// looked up by hash.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
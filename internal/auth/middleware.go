@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+const (
+	// APIKeyHeader carries the key when the Authorization header is not used
+	APIKeyHeader = "X-API-Key"
+	// contextKey is the echo context key of the authenticated API key
+	contextKey = "api_key"
+)
+
+// Authenticator checks the API key of requests to the /api/v1 routes
+type Authenticator struct {
+	store     domain.APIKeyStore
+	adminHash string
+	logger    *log.Logger
+}
+
+// NewAuthenticator creates a new API key authenticator. The admin key of the
+// config, when set, is accepted as a global key with every scope.
+func NewAuthenticator(store domain.APIKeyStore, cfg *config.AuthConfig, logger *log.Logger) *Authenticator {
+	authenticator := &Authenticator{
+		store:  store,
+		logger: logger,
+	}
+	if cfg.AdminKey != "" {
+		authenticator.adminHash = HashKey(cfg.AdminKey)
+	}
+	return authenticator
+}
+
+// Middleware rejects requests without an active API key, with a key of
+// another tenant, or with a key lacking the scope of the request method.
+// Routes without a tenant_id parameter require a global key.
+func (a *Authenticator) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		secret := requestKey(c.Request())
+		if secret == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": "missing API key",
+			})
+		}
+
+		key, err := a.authenticate(c, secret)
+		if err != nil {
+			a.logger.WithContext(ctx).Error().Err(err).Msg("failed to look up API key")
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to check API key",
+			})
+		}
+
+		now := time.Now().UTC()
+		if key == nil || !key.IsActive(now) {
+			a.logger.WithContext(ctx).Warn().
+				Str("remote_ip", c.RealIP()).
+				Str("uri", c.Request().RequestURI).
+				Msg("invalid API key")
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": "invalid or expired API key",
+			})
+		}
+
+		tenantID := c.Param("tenant_id")
+		if !key.IsGlobal() && key.TenantID != tenantID {
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": "API key is not permitted to access this resource",
+			})
+		}
+
+		scope := requiredScope(c.Request().Method)
+		if !key.HasScope(scope) {
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": "API key lacks the " + scope + " scope",
+			})
+		}
+
+		if key.ID != uuid.Nil {
+			if err := a.store.TouchAPIKey(ctx, key.ID, now); err != nil {
+				a.logger.WithContext(ctx).Warn().Err(err).Str("key_id", key.ID.String()).Msg("failed to record API key use")
+			}
+		}
+
+		c.Set(contextKey, key)
+		return next(c)
+	}
+}
+
+// authenticate returns the key matching a secret, or nil when none does
+func (a *Authenticator) authenticate(c echo.Context, secret string) (*domain.APIKey, error) {
+	hash := HashKey(secret)
+
+	if a.adminHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(a.adminHash)) == 1 {
+		return &domain.APIKey{
+			Name:   "admin",
+			Scopes: []string{domain.APIScopeRead, domain.APIScopeWrite},
+		}, nil
+	}
+
+	return a.store.GetAPIKeyByHash(c.Request().Context(), hash)
+}
+
+// KeyFromContext returns the API key that authenticated the request, if any
+func KeyFromContext(c echo.Context) *domain.APIKey {
+	key, _ := c.Get(contextKey).(*domain.APIKey)
+	return key
+}
+
+// requestKey returns the key of a bearer Authorization header or of the X-API-Key header
+func requestKey(r *http.Request) string {
+	if authorization := r.Header.Get(echo.HeaderAuthorization); authorization != "" {
+		scheme, token, found := strings.Cut(authorization, " ")
+		if found && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return strings.TrimSpace(r.Header.Get(APIKeyHeader))
+}
+
+// requiredScope returns the scope needed for a request method
+func requiredScope(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return domain.APIScopeRead
+	default:
+		return domain.APIScopeWrite
+	}
+}
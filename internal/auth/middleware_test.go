@@ -0,0 +1,142 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/auth"
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+// MockKeyStore implements the key lookups used by the authenticator
+type MockKeyStore struct {
+	domain.APIKeyStore
+	mock.Mock
+}
+
+func (m *MockKeyStore) GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	args := m.Called(ctx, hash)
+	key, _ := args.Get(0).(*domain.APIKey)
+	return key, args.Error(1)
+}
+
+func (m *MockKeyStore) TouchAPIKey(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error {
+	args := m.Called(ctx, keyID, usedAt)
+	return args.Error(0)
+}
+
+// newServer returns an echo server whose /api/v1 routes require an API key
+func newServer(store domain.APIKeyStore, adminKey string) *echo.Echo {
+	e := echo.New()
+	api := e.Group("/api/v1")
+	api.Use(auth.NewAuthenticator(store, &config.AuthConfig{Enabled: true, AdminKey: adminKey}, log.Init("error")).Middleware)
+
+	ok := func(c echo.Context) error {
+		return c.String(http.StatusOK, auth.KeyFromContext(c).Name)
+	}
+	api.GET("/tenants/:tenant_id/contacts", ok)
+	api.POST("/tenants/:tenant_id/contacts", ok)
+	api.GET("/jobs", ok)
+	return e
+}
+
+func serve(e *echo.Echo, method, path, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader("{}"))
+	if key != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+key)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGenerateKey(t *testing.T) {
+	key, prefix, err := auth.GenerateKey()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, prefix))
+	assert.True(t, strings.HasPrefix(key, "pa_"))
+
+	other, _, err := auth.GenerateKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+	assert.Len(t, auth.HashKey(key), 64)
+	assert.NotEqual(t, auth.HashKey(key), auth.HashKey(other))
+}
+
+func TestAuthenticator_Middleware(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	revoked := time.Now().Add(-time.Minute)
+
+	keys := map[string]*domain.APIKey{
+		"tenant-read":  {ID: uuid.New(), Name: "tenant-read", TenantID: "tenant-a", Scopes: []string{domain.APIScopeRead}},
+		"tenant-write": {ID: uuid.New(), Name: "tenant-write", TenantID: "tenant-a", Scopes: []string{domain.APIScopeRead, domain.APIScopeWrite}},
+		"global":       {ID: uuid.New(), Name: "global", Scopes: []string{domain.APIScopeRead}},
+		"expired":      {ID: uuid.New(), Name: "expired", Scopes: []string{domain.APIScopeRead}, ExpiresAt: &expired},
+		"revoked":      {ID: uuid.New(), Name: "revoked", Scopes: []string{domain.APIScopeRead}, RevokedAt: &revoked},
+	}
+
+	store := new(MockKeyStore)
+	for secret, key := range keys {
+		store.On("GetAPIKeyByHash", mock.Anything, auth.HashKey(secret)).Return(key, nil)
+	}
+	store.On("GetAPIKeyByHash", mock.Anything, mock.Anything).Return(nil, nil)
+	store.On("TouchAPIKey", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	e := newServer(store, "bootstrap")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		status int
+	}{
+		{"missing key", http.MethodGet, "/api/v1/tenants/tenant-a/contacts", "", http.StatusUnauthorized},
+		{"unknown key", http.MethodGet, "/api/v1/tenants/tenant-a/contacts", "nope", http.StatusUnauthorized},
+		{"expired key", http.MethodGet, "/api/v1/jobs", "expired", http.StatusUnauthorized},
+		{"revoked key", http.MethodGet, "/api/v1/jobs", "revoked", http.StatusUnauthorized},
+		{"tenant key reads its tenant", http.MethodGet, "/api/v1/tenants/tenant-a/contacts", "tenant-read", http.StatusOK},
+		{"tenant key without write scope", http.MethodPost, "/api/v1/tenants/tenant-a/contacts", "tenant-read", http.StatusForbidden},
+		{"tenant key writes its tenant", http.MethodPost, "/api/v1/tenants/tenant-a/contacts", "tenant-write", http.StatusOK},
+		{"tenant key on another tenant", http.MethodGet, "/api/v1/tenants/tenant-b/contacts", "tenant-write", http.StatusForbidden},
+		{"tenant key on global route", http.MethodGet, "/api/v1/jobs", "tenant-write", http.StatusForbidden},
+		{"global key on any tenant", http.MethodGet, "/api/v1/tenants/tenant-b/contacts", "global", http.StatusOK},
+		{"global key on global route", http.MethodGet, "/api/v1/jobs", "global", http.StatusOK},
+		{"admin key writes", http.MethodPost, "/api/v1/tenants/tenant-b/contacts", "bootstrap", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(e, tt.method, tt.path, tt.key)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+		})
+	}
+
+	store.AssertCalled(t, "TouchAPIKey", mock.Anything, keys["global"].ID, mock.Anything)
+}
+
+func TestAuthenticator_XAPIKeyHeader(t *testing.T) {
+	store := new(MockKeyStore)
+	store.On("GetAPIKeyByHash", mock.Anything, auth.HashKey("secret")).
+		Return(&domain.APIKey{Name: "header", Scopes: []string{domain.APIScopeRead}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs", nil)
+	req.Header.Set(auth.APIKeyHeader, "secret")
+	rec := httptest.NewRecorder()
+	newServer(store, "").ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "header", rec.Body.String())
+}
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"personal-assistant/internal/domain"
+)
+
+const keyColumns = `id, name, key_prefix, key_hash, COALESCE(tenant_id, ''), scopes, expires_at,
+		last_used_at, revoked_at, COALESCE(created_by, ''), created_at`
+
+// touchInterval is how long a key's last use is kept before it is recorded
+// again, so that busy keys do not write on every request
+const touchInterval = time.Minute
+
+// PostgresKeyStore implements the APIKeyStore interface on the api_keys table
+type PostgresKeyStore struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresKeyStore creates a new PostgreSQL API key store
+func NewPostgresKeyStore(db *pgxpool.Pool) *PostgresKeyStore {
+	return &PostgresKeyStore{db: db}
+}
+
+// Close closes the database connection
+func (s *PostgresKeyStore) Close() error {
+	s.db.Close()
+	return nil
+}
+
+// CreateAPIKey stores a new key
+func (s *PostgresKeyStore) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	if key.ID == uuid.Nil {
+		key.ID = uuid.New()
+	}
+	key.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO api_keys (id, name, key_prefix, key_hash, tenant_id, scopes, expires_at,
+			created_by, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, NULLIF($8, ''), $9)
+	`
+
+	_, err := s.db.Exec(ctx, query,
+		key.ID, key.Name, key.Prefix, key.Hash, key.TenantID, key.Scopes, key.ExpiresAt,
+		key.CreatedBy, key.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return nil
+}
+
+// GetAPIKey retrieves a key by ID
+func (s *PostgresKeyStore) GetAPIKey(ctx context.Context, keyID uuid.UUID) (*domain.APIKey, error) {
+	query := `
+		SELECT ` + keyColumns + `
+		FROM api_keys
+		WHERE id = $1
+	`
+
+	return s.get(ctx, query, keyID)
+}
+
+// GetAPIKeyByHash retrieves a key by the hash of its secret
+func (s *PostgresKeyStore) GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	query := `
+		SELECT ` + keyColumns + `
+		FROM api_keys
+		WHERE key_hash = $1
+	`
+
+	return s.get(ctx, query, hash)
+}
+
+// ListAPIKeys lists keys newest first; an empty tenant ID returns keys of every tenant and global keys
+func (s *PostgresKeyStore) ListAPIKeys(ctx context.Context, tenantID string) ([]domain.APIKey, error) {
+	query := `
+		SELECT ` + keyColumns + `
+		FROM api_keys
+		WHERE $1::text = '' OR tenant_id = $1::text
+		ORDER BY created_at DESC
+	`
+
+	rows, err := s.db.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []domain.APIKey
+	for rows.Next() {
+		key, err := scanKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey revokes a key so that it is no longer accepted
+func (s *PostgresKeyStore) RevokeAPIKey(ctx context.Context, keyID uuid.UUID) error {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := s.db.Exec(ctx, query, keyID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("API key not found")
+	}
+
+	return nil
+}
+
+// TouchAPIKey records that a key was used, at most once per touchInterval
+func (s *PostgresKeyStore) TouchAPIKey(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error {
+	query := `
+		UPDATE api_keys
+		SET last_used_at = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $3)
+	`
+
+	if _, err := s.db.Exec(ctx, query, keyID, usedAt, usedAt.Add(-touchInterval)); err != nil {
+		return fmt.Errorf("failed to record API key use: %w", err)
+	}
+
+	return nil
+}
+
+// get retrieves a single key, returning nil when none matches
+func (s *PostgresKeyStore) get(ctx context.Context, query string, args ...interface{}) (*domain.APIKey, error) {
+	key, err := scanKey(s.db.QueryRow(ctx, query, args...))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	return key, nil
+}
+
+// scanKey scans a row selected with keyColumns
+func scanKey(row pgx.Row) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := row.Scan(
+		&key.ID, &key.Name, &key.Prefix, &key.Hash, &key.TenantID, &key.Scopes, &key.ExpiresAt,
+		&key.LastUsedAt, &key.RevokedAt, &key.CreatedBy, &key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"gopkg.in/yaml.v3"
@@ -22,6 +23,9 @@ type Config struct {
 	// LLM configuration
 	LLM LLMConfig
 
+	// LLM provider fallback configuration
+	LLMFallback LLMFallbackConfig
+
 	// Vector store configuration
 	VectorBackend string `envconfig:"VECTOR_BACKEND" default:"pgvector"` // pgvector, qdrant, sql_fallback
 
@@ -37,9 +41,30 @@ type Config struct {
 	// Webhook
 	WebhookVerifyToken string `envconfig:"WEBHOOK_VERIFY_TOKEN" required:"true"`
 
+	// Webhook signature verification
+	WebhookSignature WebhookSignatureConfig
+
 	// RAG configuration
 	RAG RAGConfig
 
+	// Reminder scheduler configuration
+	Scheduler SchedulerConfig
+
+	// Inbound job queue configuration
+	Queue QueueConfig
+
+	// Webhook deduplication configuration
+	Dedup DedupConfig
+
+	// API key authentication of the /api/v1 routes
+	Auth AuthConfig
+
+	// Messages from one user arriving within this window are answered in a single turn (0 disables)
+	CoalesceWindow time.Duration `envconfig:"MESSAGE_COALESCE_WINDOW" default:"0s"`
+
+	// Replies are sent paragraph by paragraph as they are generated when the LLM provider can stream
+	StreamReplies bool `envconfig:"STREAM_REPLIES" default:"true"`
+
 	// Token limits
 	MaxTokensReply      int `envconfig:"MAX_TOKENS_REPLY" default:"500"`
 	SummarizeThreshold int `envconfig:"SUMMARIZE_THRESHOLD" default:"10000"`
@@ -47,8 +72,8 @@ type Config struct {
 
 // LLMConfig holds LLM provider configuration
 type LLMConfig struct {
-	Provider   string `envconfig:"LLM_PROVIDER" default:"openai"` // openai, anthropic, bedrock, mock
-	APIKey     string `envconfig:"LLM_API_KEY" required:"true"`
+	Provider   string `envconfig:"LLM_PROVIDER" default:"openai"` // openai, deepseek, anthropic, bedrock, ollama, mock
+	APIKey     string `envconfig:"LLM_API_KEY"` // Not needed for ollama
 	ModelChat  string `envconfig:"LLM_MODEL_CHAT" default:"gpt-3.5-turbo"`
 	ModelEmbed string `envconfig:"LLM_MODEL_EMBED" default:"text-embedding-ada-002"`
 }
@@ -66,6 +91,63 @@ type RAGConfig struct {
 	MinScore float64 `envconfig:"RAG_MIN_SCORE" default:"0.7"`
 }
 
+// SchedulerConfig holds reminder scheduler configuration
+type SchedulerConfig struct {
+	Enabled      bool          `envconfig:"REMINDER_SCHEDULER_ENABLED" default:"true"`
+	PollInterval time.Duration `envconfig:"REMINDER_POLL_INTERVAL" default:"30s"`
+	BatchSize    int           `envconfig:"REMINDER_BATCH_SIZE" default:"50"`
+	LeaseTimeout time.Duration `envconfig:"REMINDER_LEASE_TIMEOUT" default:"2m"`
+	RetryBackoff time.Duration `envconfig:"REMINDER_RETRY_BACKOFF" default:"1m"`
+}
+
+// LLMFallbackConfig holds failover and circuit breaker settings for tenants
+// with several enabled LLM providers
+type LLMFallbackConfig struct {
+	AttemptTimeout time.Duration `envconfig:"LLM_FALLBACK_TIMEOUT" default:"60s"`      // Per-provider timeout before failing over
+	FailureRate    float64       `envconfig:"LLM_BREAKER_FAILURE_RATE" default:"0.5"`  // Share of failed calls that opens the circuit
+	MinRequests    int           `envconfig:"LLM_BREAKER_MIN_REQUESTS" default:"5"`    // Calls in the window before the rate is evaluated
+	WindowSize     int           `envconfig:"LLM_BREAKER_WINDOW" default:"20"`         // Most recent calls considered
+	OpenDuration   time.Duration `envconfig:"LLM_BREAKER_OPEN_DURATION" default:"30s"` // Time a provider is skipped before it is probed again
+}
+
+// QueueConfig holds inbound job queue and worker pool configuration
+type QueueConfig struct {
+	Enabled      bool          `envconfig:"QUEUE_ENABLED" default:"true"`
+	Backend      string        `envconfig:"QUEUE_BACKEND" default:"postgres"` // postgres, memory
+	Workers      int           `envconfig:"QUEUE_WORKERS" default:"4"`
+	PollInterval time.Duration `envconfig:"QUEUE_POLL_INTERVAL" default:"1s"`
+	LeaseTimeout time.Duration `envconfig:"QUEUE_LEASE_TIMEOUT" default:"5m"`
+	MaxAttempts  int           `envconfig:"QUEUE_MAX_ATTEMPTS" default:"5"`
+	RetryBackoff time.Duration `envconfig:"QUEUE_RETRY_BACKOFF" default:"5s"`
+	MaxBackoff   time.Duration `envconfig:"QUEUE_MAX_BACKOFF" default:"10m"`
+}
+
+// WebhookSignatureConfig holds HMAC verification settings for Infobip webhooks.
+// Tenants override the secrets with the webhook_secret, webhook_secret_previous
+// and webhook_secret_previous_until keys of their config.
+type WebhookSignatureConfig struct {
+	Secret          string        `envconfig:"WEBHOOK_SIGNING_SECRET"`
+	PreviousSecret  string        `envconfig:"WEBHOOK_SIGNING_SECRET_PREVIOUS"`       // Still accepted while rolling over to Secret
+	PreviousUntil   time.Time     `envconfig:"WEBHOOK_SIGNING_SECRET_PREVIOUS_UNTIL"` // RFC 3339; zero accepts the previous secret indefinitely
+	SignatureHeader string        `envconfig:"WEBHOOK_SIGNATURE_HEADER" default:"X-Hub-Signature"`
+	TimestampHeader string        `envconfig:"WEBHOOK_TIMESTAMP_HEADER" default:"X-Hub-Timestamp"`
+	ReplayWindow    time.Duration `envconfig:"WEBHOOK_REPLAY_WINDOW" default:"5m"`
+	Required        bool          `envconfig:"WEBHOOK_SIGNATURE_REQUIRED" default:"false"` // Reject tenants without a secret instead of checking WEBHOOK_VERIFY_TOKEN
+}
+
+// DedupConfig holds webhook deduplication configuration
+type DedupConfig struct {
+	Backend  string        `envconfig:"DEDUP_BACKEND" default:"postgres"` // postgres, memory
+	TTL      time.Duration `envconfig:"DEDUP_TTL" default:"1h"`           // memory backend only
+	Capacity int           `envconfig:"DEDUP_CAPACITY" default:"10000"`   // memory backend only
+}
+
+// AuthConfig holds API key authentication settings for the /api/v1 routes
+type AuthConfig struct {
+	Enabled  bool   `envconfig:"API_AUTH_ENABLED" default:"true"`
+	AdminKey string `envconfig:"API_ADMIN_KEY"` // Global key with every scope, used to mint the first stored keys
+}
+
 // TenantConfig represents a single tenant configuration
 type TenantConfig struct {
 	TenantID       string            `yaml:"tenant_id"`
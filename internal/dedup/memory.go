@@ -0,0 +1,98 @@
+package dedup
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryDeduplicator implements the Deduplicator interface with an in-process
+// LRU cache. Entries expire after the TTL, and the least recently seen entry
+// is evicted once the cache is full. It is not shared across replicas.
+type MemoryDeduplicator struct {
+	ttl      time.Duration
+	capacity int
+
+	entries map[string]*list.Element
+	order   *list.List // Most recently seen first
+	mutex   sync.Mutex
+}
+
+// memoryEntry is a message recorded in the LRU cache
+type memoryEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// NewMemoryDeduplicator creates a new in-memory deduplicator
+func NewMemoryDeduplicator(ttl time.Duration, capacity int) *MemoryDeduplicator {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &MemoryDeduplicator{
+		ttl:      ttl,
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Seen reports whether a message was already received and otherwise records it
+func (d *MemoryDeduplicator) Seen(ctx context.Context, wabaNumber, messageID string) (bool, error) {
+	key := wabaNumber + "|" + messageID
+	now := time.Now()
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if element, exists := d.entries[key]; exists {
+		entry := element.Value.(*memoryEntry)
+		if now.Before(entry.expiresAt) {
+			d.order.MoveToFront(element)
+			return true, nil
+		}
+		d.remove(element)
+	}
+
+	d.entries[key] = d.order.PushFront(&memoryEntry{
+		key:       key,
+		expiresAt: now.Add(d.ttl),
+	})
+
+	// Evict expired entries, then the least recently seen ones above capacity
+	for element := d.order.Back(); element != nil; element = d.order.Back() {
+		if d.order.Len() <= d.capacity && now.Before(element.Value.(*memoryEntry).expiresAt) {
+			break
+		}
+		d.remove(element)
+	}
+
+	return false, nil
+}
+
+// Forget removes a message recorded by Seen so that a redelivery is accepted again
+func (d *MemoryDeduplicator) Forget(ctx context.Context, wabaNumber, messageID string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if element, exists := d.entries[wabaNumber+"|"+messageID]; exists {
+		d.remove(element)
+	}
+	return nil
+}
+
+// Len returns the number of recorded messages, including expired ones not yet evicted
+func (d *MemoryDeduplicator) Len() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.order.Len()
+}
+
+// remove deletes an entry from the cache
+func (d *MemoryDeduplicator) remove(element *list.Element) {
+	d.order.Remove(element)
+	delete(d.entries, element.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,98 @@
+package dedup_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/dedup"
+)
+
+func TestMemoryDeduplicator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("detects repeated messages per WABA number", func(t *testing.T) {
+		d := dedup.NewMemoryDeduplicator(time.Hour, 10)
+
+		seen, err := d.Seen(ctx, "5511888888888", "msg-1")
+		require.NoError(t, err)
+		assert.False(t, seen)
+
+		seen, err = d.Seen(ctx, "5511888888888", "msg-1")
+		require.NoError(t, err)
+		assert.True(t, seen)
+
+		seen, err = d.Seen(ctx, "5511777777777", "msg-1")
+		require.NoError(t, err)
+		assert.False(t, seen)
+	})
+
+	t.Run("expires entries after the TTL", func(t *testing.T) {
+		d := dedup.NewMemoryDeduplicator(10*time.Millisecond, 10)
+
+		_, err := d.Seen(ctx, "5511888888888", "msg-1")
+		require.NoError(t, err)
+		time.Sleep(20 * time.Millisecond)
+
+		seen, err := d.Seen(ctx, "5511888888888", "msg-1")
+		require.NoError(t, err)
+		assert.False(t, seen)
+	})
+
+	t.Run("evicts the least recently seen message", func(t *testing.T) {
+		d := dedup.NewMemoryDeduplicator(time.Hour, 2)
+
+		d.Seen(ctx, "5511888888888", "msg-1")
+		d.Seen(ctx, "5511888888888", "msg-2")
+		d.Seen(ctx, "5511888888888", "msg-1") // msg-2 is now the least recently seen
+		d.Seen(ctx, "5511888888888", "msg-3")
+		assert.Equal(t, 2, d.Len())
+
+		seen, _ := d.Seen(ctx, "5511888888888", "msg-1")
+		assert.True(t, seen)
+		seen, _ = d.Seen(ctx, "5511888888888", "msg-2")
+		assert.False(t, seen)
+	})
+
+	t.Run("forgets messages", func(t *testing.T) {
+		d := dedup.NewMemoryDeduplicator(time.Hour, 10)
+
+		d.Seen(ctx, "5511888888888", "msg-1")
+		require.NoError(t, d.Forget(ctx, "5511888888888", "msg-1"))
+
+		seen, err := d.Seen(ctx, "5511888888888", "msg-1")
+		require.NoError(t, err)
+		assert.False(t, seen)
+	})
+
+	t.Run("lets exactly one concurrent delivery through", func(t *testing.T) {
+		d := dedup.NewMemoryDeduplicator(time.Hour, 100)
+
+		var wg sync.WaitGroup
+		var mutex sync.Mutex
+		firsts := map[string]int{}
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				messageID := fmt.Sprintf("msg-%d", i%5)
+				if seen, _ := d.Seen(ctx, "5511888888888", messageID); !seen {
+					mutex.Lock()
+					firsts[messageID]++
+					mutex.Unlock()
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		require.Len(t, firsts, 5)
+		for messageID, count := range firsts {
+			assert.Equal(t, 1, count, messageID)
+		}
+	})
+}
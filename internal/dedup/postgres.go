@@ -0,0 +1,52 @@
+package dedup
+
+import (
+	"context"
+	"fmt"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+// PostgresDeduplicator implements the Deduplicator interface on the messages
+// table, so it survives restarts and is shared by all replicas. A message
+// counts as seen once the processor has stored it; concurrent deliveries that
+// both pass the check are settled by the (tenant_id, message_id) unique
+// constraint, which makes the second insert fail with ErrDuplicateMessage.
+type PostgresDeduplicator struct {
+	tenantManager domain.TenantManager
+	logger        *log.Logger
+}
+
+// NewPostgresDeduplicator creates a new PostgreSQL deduplicator
+func NewPostgresDeduplicator(tenantManager domain.TenantManager, logger *log.Logger) *PostgresDeduplicator {
+	return &PostgresDeduplicator{
+		tenantManager: tenantManager,
+		logger:        logger,
+	}
+}
+
+// Seen reports whether the message is already stored for the tenant owning the WABA number
+func (d *PostgresDeduplicator) Seen(ctx context.Context, wabaNumber, messageID string) (bool, error) {
+	tenant, err := d.tenantManager.GetTenant(wabaNumber)
+	if err != nil {
+		return false, fmt.Errorf("failed to get tenant for WABA number %s: %w", wabaNumber, err)
+	}
+
+	repo, err := d.tenantManager.GetRepository(tenant.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get repository for tenant %s: %w", tenant.ID, err)
+	}
+
+	message, err := repo.GetMessageByID(ctx, tenant.ID, messageID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for stored message: %w", err)
+	}
+
+	return message != nil, nil
+}
+
+// Forget is a no-op: messages are only recorded when the processor stores them
+func (d *PostgresDeduplicator) Forget(ctx context.Context, wabaNumber, messageID string) error {
+	return nil
+}
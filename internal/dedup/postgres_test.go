@@ -0,0 +1,83 @@
+package dedup_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/dedup"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+// MockRepository implements the message lookup of domain.Repository
+type MockRepository struct {
+	domain.Repository
+	mock.Mock
+}
+
+func (m *MockRepository) GetMessageByID(ctx context.Context, tenantID string, messageID string) (*domain.Message, error) {
+	args := m.Called(ctx, tenantID, messageID)
+	return args.Get(0).(*domain.Message), args.Error(1)
+}
+
+// MockTenantManager implements the subset of domain.TenantManager used by the deduplicator
+type MockTenantManager struct {
+	domain.TenantManager
+	mock.Mock
+}
+
+func (m *MockTenantManager) GetTenant(wabaNumber string) (*domain.Tenant, error) {
+	args := m.Called(wabaNumber)
+	tenant, _ := args.Get(0).(*domain.Tenant)
+	return tenant, args.Error(1)
+}
+
+func (m *MockTenantManager) GetRepository(tenantID string) (domain.Repository, error) {
+	args := m.Called(tenantID)
+	return args.Get(0).(domain.Repository), args.Error(1)
+}
+
+func TestPostgresDeduplicator(t *testing.T) {
+	ctx := context.Background()
+	logger := log.Init("error")
+	tenant := &domain.Tenant{ID: "tenant1", WABANumber: "5511888888888"}
+
+	setup := func() (*MockTenantManager, *MockRepository) {
+		repo := &MockRepository{}
+		tm := &MockTenantManager{}
+		tm.On("GetTenant", tenant.WABANumber).Return(tenant, nil)
+		tm.On("GetRepository", tenant.ID).Return(repo, nil)
+		return tm, repo
+	}
+
+	t.Run("reports stored messages as seen", func(t *testing.T) {
+		tm, repo := setup()
+		repo.On("GetMessageByID", mock.Anything, tenant.ID, "msg-1").Return(&domain.Message{MessageID: "msg-1"}, nil)
+		repo.On("GetMessageByID", mock.Anything, tenant.ID, "msg-2").Return((*domain.Message)(nil), nil)
+
+		d := dedup.NewPostgresDeduplicator(tm, logger)
+
+		seen, err := d.Seen(ctx, tenant.WABANumber, "msg-1")
+		require.NoError(t, err)
+		assert.True(t, seen)
+
+		seen, err = d.Seen(ctx, tenant.WABANumber, "msg-2")
+		require.NoError(t, err)
+		assert.False(t, seen)
+	})
+
+	t.Run("returns lookup errors", func(t *testing.T) {
+		tm := &MockTenantManager{}
+		tm.On("GetTenant", "5511000000000").Return(nil, errors.New("tenant not found"))
+
+		d := dedup.NewPostgresDeduplicator(tm, logger)
+
+		_, err := d.Seen(ctx, "5511000000000", "msg-1")
+		assert.Error(t, err)
+	})
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -18,6 +19,29 @@ type LLMProvider interface {
 	Name() string
 }
 
+// ChatStreamer is implemented by LLM providers that can stream chat completions
+type ChatStreamer interface {
+	// ChatStream performs a chat completion, passing each delta to onDelta as it
+	// arrives, and returns the complete response once the stream has ended.
+	// An error returned by onDelta aborts the stream.
+	ChatStream(ctx context.Context, req *ChatCompletionRequest, onDelta func(*ChatDelta) error) (*ChatCompletionResponse, error)
+}
+
+// UsageRecorder defines the interface for persisting the token usage of LLM calls
+type UsageRecorder interface {
+	// RecordLLMUsage stores the usage of a single call
+	RecordLLMUsage(ctx context.Context, usage *LLMUsage) error
+}
+
+// Transcriber defines the interface for speech-to-text providers
+type Transcriber interface {
+	// Transcribe converts recorded speech into text
+	Transcribe(ctx context.Context, req *TranscriptionRequest) (*Transcription, error)
+	
+	// Name returns the transcriber name
+	Name() string
+}
+
 // VectorStore defines the interface for vector storage backends
 type VectorStore interface {
 	// Upsert inserts or updates memory items
@@ -50,7 +74,34 @@ type Repository interface {
 	// Message operations
 	CreateMessage(ctx context.Context, message *Message) error
 	GetMessages(ctx context.Context, tenantID string, userID uuid.UUID, limit int) ([]Message, error)
+	GetMessagesSince(ctx context.Context, tenantID string, userID uuid.UUID, since time.Time, limit int) ([]Message, error)
 	GetMessageByID(ctx context.Context, tenantID string, messageID string) (*Message, error)
+	GetLastInboundMessage(ctx context.Context, tenantID string, userID uuid.UUID) (*Message, error)
+	GetReplyMessage(ctx context.Context, tenantID string, inboundMessageID string) (*Message, error)
+	
+	// Message status operations
+	RecordMessageStatus(ctx context.Context, event *MessageStatusEvent) error
+	GetMessageStatusTimeline(ctx context.Context, tenantID string, messageID uuid.UUID) ([]MessageStatusEvent, error)
+	
+	// Memory chunk operations
+	GetLatestMemoryChunk(ctx context.Context, tenantID string, userID uuid.UUID, kind string) (*MemoryChunk, error)
+	
+	// Media operations
+	CreateMedia(ctx context.Context, media *Media) error
+	GetMedia(ctx context.Context, tenantID string, mediaID uuid.UUID) (*Media, error)
+	
+	// Document operations
+	CreateDocument(ctx context.Context, document *Document) error
+	UpdateDocument(ctx context.Context, document *Document) error
+	GetDocument(ctx context.Context, tenantID string, documentID uuid.UUID) (*Document, error)
+	GetDocumentByMediaID(ctx context.Context, tenantID string, mediaID uuid.UUID) (*Document, error)
+	ListDocuments(ctx context.Context, tenantID string, userID uuid.UUID, limit int) ([]Document, error)
+	DeleteDocument(ctx context.Context, tenantID string, documentID uuid.UUID) error
+	
+	// Message template operations
+	GetMessageTemplate(ctx context.Context, tenantID, purpose string) (*MessageTemplate, error)
+	ListMessageTemplates(ctx context.Context, tenantID string) ([]MessageTemplate, error)
+	UpsertMessageTemplate(ctx context.Context, template *MessageTemplate) error
 	
 	// Agent operations
 	GetAgents(ctx context.Context) ([]AgentConfig, error)
@@ -71,6 +122,10 @@ type Repository interface {
 	CreateLLMProvider(ctx context.Context, config *LLMProviderConfig) error
 	UpdateLLMProvider(ctx context.Context, config *LLMProviderConfig) error
 	
+	// LLM usage operations
+	RecordLLMUsage(ctx context.Context, usage *LLMUsage) error
+	GetDailyLLMUsage(ctx context.Context, tenantID string, userID *uuid.UUID, from, to time.Time) ([]DailyLLMUsage, error)
+	
 	// Tenant configuration operations
 	GetTenantsConfig(ctx context.Context) ([]TenantConfig, error)
 	GetTenantConfig(ctx context.Context, tenantID string) (*TenantConfig, error)
@@ -90,6 +145,20 @@ type Repository interface {
 	DeleteAllowedContact(ctx context.Context, tenantID string, contactID uuid.UUID) error
 	IsContactAllowed(ctx context.Context, tenantID, phoneNumber string) (bool, error)
 	
+	// Contact request operations
+	GetContactRequest(ctx context.Context, tenantID, phoneNumber string) (*ContactRequest, error)
+	ListContactRequests(ctx context.Context, tenantID, status string) ([]ContactRequest, error)
+	CreateContactRequest(ctx context.Context, request *ContactRequest) error
+	UpdateContactRequest(ctx context.Context, request *ContactRequest) error
+	
+	// Reminder operations
+	CreateReminder(ctx context.Context, reminder *Reminder) error
+	GetReminder(ctx context.Context, tenantID string, reminderID uuid.UUID) (*Reminder, error)
+	UpdateReminder(ctx context.Context, reminder *Reminder) error
+	ReleaseReminder(ctx context.Context, reminder *Reminder, lease time.Time) error
+	ListReminders(ctx context.Context, tenantID string, userID uuid.UUID, status string, limit int) ([]Reminder, error)
+	ClaimDueReminders(ctx context.Context, tenantID string, limit int, lease time.Duration) ([]Reminder, error)
+	
 	// Utility operations
 	Ping(ctx context.Context) error
 	Close() error
@@ -151,6 +220,23 @@ type TenantManager interface {
 	// GetLLMProvider returns an LLM provider instance for the tenant
 	GetLLMProvider(tenantID string) (LLMProvider, error)
 	
+	// GetTranscriber returns a speech-to-text transcriber for the tenant
+	GetTranscriber(tenantID string) (Transcriber, error)
+	
+	// GetToolRegistry returns the tools of the tenant, limited to its enabled agents and tools
+	GetToolRegistry(tenantID string) (ToolRegistry, error)
+	
+	// GetConfigRepository returns the repository holding tenant configurations,
+	// or ErrTenantConfigReadOnly when tenants are configured from a file
+	GetConfigRepository() (Repository, error)
+	
+	// ReloadTenants reloads tenant configurations and drops resources built from the old ones
+	ReloadTenants() error
+	
+	// ReloadTenant reloads tenant configurations after a change to one tenant,
+	// dropping only the resources built for that tenant
+	ReloadTenant(tenantID string) error
+	
 	// Close closes all tenant resources
 	Close() error
 }
@@ -160,8 +246,21 @@ type InfobipClient interface {
 	// SendText sends a text message via WhatsApp
 	SendText(ctx context.Context, from, to, text string, messageIDRef ...string) (*InfobipMessage, error)
 	
+	// SendButtons sends a message with up to three quick reply buttons
+	SendButtons(ctx context.Context, from, to, text string, buttons []InfobipReplyButton, messageIDRef ...string) (*InfobipMessage, error)
+	
+	// SendList sends a message with a list menu of options
+	SendList(ctx context.Context, from, to, text string, list *InfobipList, messageIDRef ...string) (*InfobipMessage, error)
+	
 	// SendMessage sends a structured message
 	SendMessage(ctx context.Context, message *InfobipMessage) (*InfobipMessage, error)
+	
+	// SendTemplate sends a pre-approved template message, which is allowed
+	// outside the 24-hour customer service window
+	SendTemplate(ctx context.Context, from, to string, template *InfobipTemplate, messageIDRef ...string) (*InfobipMessage, error)
+	
+	// DownloadMedia downloads the content of an incoming media message
+	DownloadMedia(ctx context.Context, url string) (*MediaContent, error)
 }
 
 // RAGPipeline defines the interface for RAG operations
@@ -174,6 +273,9 @@ type RAGPipeline interface {
 	
 	// GetContext builds context from search results
 	GetContext(ctx context.Context, memories []MemoryHit, maxTokens int) string
+	
+	// IngestDocument chunks, embeds and stores the pages of a document
+	IngestDocument(ctx context.Context, document *Document, pages []DocumentPage) (int, error)
 }
 
 // MessageProcessor defines the interface for processing incoming messages
@@ -181,8 +283,84 @@ type MessageProcessor interface {
 	// ProcessIncoming processes an incoming webhook message
 	ProcessIncoming(ctx context.Context, webhookMsg *InfobipWebhookMessage) error
 	
+	// ProcessResult processes a single webhook result, returning the error of a failed attempt
+	ProcessResult(ctx context.Context, result *InfobipWebhookResult) error
+	
 	// ProcessMessage processes a single message
 	ProcessMessage(ctx context.Context, tenant *Tenant, user *User, message *Message) error
+	
+	// ProcessStatus records delivery and seen reports of outbound messages
+	ProcessStatus(ctx context.Context, webhook *InfobipStatusWebhook) error
+}
+
+// Deduplicator defines the interface for detecting webhook messages that were already received
+type Deduplicator interface {
+	// Seen reports whether a message was already received and otherwise records it
+	Seen(ctx context.Context, wabaNumber, messageID string) (bool, error)
+	
+	// Forget removes a message recorded by Seen so that a redelivery is accepted again
+	Forget(ctx context.Context, wabaNumber, messageID string) error
+}
+
+// JobQueue defines the interface for the durable queue of inbound webhook results
+type JobQueue interface {
+	// Enqueue adds a job, ignoring results whose message ID is already queued
+	Enqueue(ctx context.Context, job *InboundJob) error
+	
+	// Claim leases up to limit runnable jobs, including jobs whose lease expired.
+	// A job is not claimed while an earlier job of the same sender and WABA
+	// number is pending or another of its jobs is leased.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]InboundJob, error)
+	
+	// HasPending reports whether a message received after the given one from
+	// the same sender to the same WABA number is waiting to be processed
+	HasPending(ctx context.Context, result *InfobipWebhookResult) (bool, error)
+	
+	// Complete marks a claimed job as done. It and Retry and DeadLetter return
+	// ErrJobLeaseLost when the job's lease expired and it was claimed again.
+	Complete(ctx context.Context, job *InboundJob) error
+	
+	// Retry releases a claimed job to run again at runAt
+	Retry(ctx context.Context, job *InboundJob, runAt time.Time, lastError string) error
+	
+	// DeadLetter marks a job as dead after its last failed attempt
+	DeadLetter(ctx context.Context, job *InboundJob, lastError string) error
+	
+	// Requeue moves a dead job back to the queue with its attempts reset
+	Requeue(ctx context.Context, jobID uuid.UUID) error
+	
+	// GetJob retrieves a job by ID
+	GetJob(ctx context.Context, jobID uuid.UUID) (*InboundJob, error)
+	
+	// ListJobs lists jobs newest first; an empty status returns jobs in any status
+	ListJobs(ctx context.Context, status string, limit int) ([]InboundJob, error)
+	
+	// Close releases the queue's resources
+	Close() error
+}
+
+// APIKeyStore defines the interface for storing the API keys of the /api/v1 routes
+type APIKeyStore interface {
+	// CreateAPIKey stores a new key
+	CreateAPIKey(ctx context.Context, key *APIKey) error
+	
+	// GetAPIKey retrieves a key by ID
+	GetAPIKey(ctx context.Context, keyID uuid.UUID) (*APIKey, error)
+	
+	// GetAPIKeyByHash retrieves a key by the hash of its secret
+	GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error)
+	
+	// ListAPIKeys lists keys newest first; an empty tenant ID returns keys of every tenant and global keys
+	ListAPIKeys(ctx context.Context, tenantID string) ([]APIKey, error)
+	
+	// RevokeAPIKey revokes a key so that it is no longer accepted
+	RevokeAPIKey(ctx context.Context, keyID uuid.UUID) error
+	
+	// TouchAPIKey records that a key was used
+	TouchAPIKey(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error
+	
+	// Close releases the store's resources
+	Close() error
 }
 
 // ToolRegistry defines the interface for managing tools
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -30,14 +32,67 @@ type Message struct {
 	TokenUsage *TokenUsage            `json:"token_usage" db:"token_usage"`
 	Metadata   map[string]interface{} `json:"metadata" db:"metadata"`
 	CreatedAt  time.Time              `json:"created_at" db:"created_at"`
+	Status     string                 `json:"status,omitempty" db:"status"` // Latest delivery status of outbound messages
 }
 
+// Delivery status values of outbound messages
+const (
+	MessageStatusPending   = "pending"
+	MessageStatusSent      = "sent"
+	MessageStatusDelivered = "delivered"
+	MessageStatusSeen      = "seen"
+	MessageStatusFailed    = "failed"
+)
+
+// ErrDuplicateMessage is returned when storing a message whose Infobip message
+// ID is already stored for the tenant
+var ErrDuplicateMessage = errors.New("message already stored")
+
+// ErrMessageNotStored is returned for a status report that arrived before the
+// outbound message it belongs to was stored; the report should be redelivered
+var ErrMessageNotStored = errors.New("status report for a message not stored yet")
+
+// MessageStatusEvent represents an entry in the delivery timeline of an outbound message
+type MessageStatusEvent struct {
+	ID           uuid.UUID              `json:"id" db:"id"`
+	TenantID     string                 `json:"tenant_id" db:"tenant_id"`
+	MessageID    uuid.UUID              `json:"message_id" db:"message_id"`   // Message row the report belongs to
+	ExternalID   string                 `json:"external_id" db:"external_id"` // Infobip message ID
+	Status       string                 `json:"status" db:"status"`
+	Detail       string                 `json:"detail,omitempty" db:"detail"` // Infobip status name, e.g. DELIVERED_TO_HANDSET
+	ErrorCode    int                    `json:"error_code,omitempty" db:"error_code"`
+	ErrorMessage string                 `json:"error_message,omitempty" db:"error_message"`
+	OccurredAt   time.Time              `json:"occurred_at" db:"occurred_at"`
+	Payload      map[string]interface{} `json:"payload,omitempty" db:"payload"`
+	CreatedAt    time.Time              `json:"created_at" db:"created_at"`
+}
+
+// Media represents an attachment, location or contact received in a message
+type Media struct {
+	ID        uuid.UUID              `json:"id" db:"id"`
+	TenantID  string                 `json:"tenant_id" db:"tenant_id"`
+	UserID    uuid.UUID              `json:"user_id" db:"user_id"`
+	MessageID uuid.UUID              `json:"message_id" db:"message_id"`
+	Type      string                 `json:"type" db:"type"` // image, document, audio, video, location, contact
+	MimeType  string                 `json:"mime_type" db:"mime_type"`
+	FileName  string                 `json:"file_name" db:"file_name"`
+	Caption   string                 `json:"caption" db:"caption"`
+	URL       string                 `json:"url" db:"url"`
+	SizeBytes int64                  `json:"size_bytes" db:"size_bytes"`
+	Data      []byte                 `json:"-" db:"data"`
+	Metadata  map[string]interface{} `json:"metadata" db:"metadata"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+}
+
+// EmbeddingDimensions is the size of the vectors stored in memory_chunks.embedding
+const EmbeddingDimensions = 1536
+
 // MemoryChunk represents a piece of user memory for RAG
 type MemoryChunk struct {
 	ID        uuid.UUID              `json:"id" db:"id"`
 	TenantID  string                 `json:"tenant_id" db:"tenant_id"`
 	UserID    uuid.UUID              `json:"user_id" db:"user_id"`
-	Kind      string                 `json:"kind" db:"kind"` // note, event, task, msg
+	Kind      string                 `json:"kind" db:"kind"` // note, event, task, msg, summary
 	Text      string                 `json:"text" db:"text"`
 	Embedding pgvector.Vector        `json:"-" db:"embedding"`
 	Metadata  map[string]interface{} `json:"metadata" db:"metadata"`
@@ -73,7 +128,7 @@ type ExternalService struct {
 type LLMProviderConfig struct {
 	ID          uuid.UUID              `json:"id" db:"id"`
 	TenantID    string                 `json:"tenant_id" db:"tenant_id"`
-	Provider    string                 `json:"provider" db:"provider"` // openai, deepseek, anthropic
+	Provider    string                 `json:"provider" db:"provider"` // openai, deepseek, anthropic, bedrock, ollama
 	Name        string                 `json:"name" db:"name"`         // friendly name
 	APIKey      string                 `json:"api_key" db:"api_key"`
 	BaseURL     string                 `json:"base_url" db:"base_url,omitempty"`
@@ -86,6 +141,14 @@ type LLMProviderConfig struct {
 	UpdatedAt   time.Time              `json:"updated_at" db:"updated_at"`
 }
 
+// ConfigString reads a string option from the provider config, falling back to a default
+func (c *LLMProviderConfig) ConfigString(key, fallback string) string {
+	if value, ok := c.Config[key].(string); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
 // TokenUsage represents token usage information
 type TokenUsage struct {
 	PromptTokens     int `json:"prompt_tokens"`
@@ -93,6 +156,46 @@ type TokenUsage struct {
 	TotalTokens      int `json:"total_tokens"`
 }
 
+// EstimateTokens roughly estimates the number of tokens in a text
+func EstimateTokens(text string) int {
+	// Rough approximation: 1 token ≈ 4 characters
+	return (len(text) + 3) / 4
+}
+
+// LLM usage operations
+const (
+	LLMOperationChat  = "chat"
+	LLMOperationEmbed = "embed"
+)
+
+// LLMUsage represents the token usage of a single LLM call
+type LLMUsage struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	TenantID         string     `json:"tenant_id" db:"tenant_id"`
+	UserID           *uuid.UUID `json:"user_id,omitempty" db:"user_id"` // Nil for calls outside a conversation
+	Provider         string     `json:"provider" db:"provider"`          // Name of the llm_providers row
+	Model            string     `json:"model" db:"model"`
+	Operation        string     `json:"operation" db:"operation"` // chat or embed
+	PromptTokens     int        `json:"prompt_tokens" db:"prompt_tokens"`
+	CompletionTokens int        `json:"completion_tokens" db:"completion_tokens"`
+	Estimated        bool       `json:"estimated" db:"estimated"` // Counted from text length because the provider reported no usage
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
+
+// DailyLLMUsage represents the token usage and cost of one day for a user, provider and model
+type DailyLLMUsage struct {
+	Day              time.Time  `json:"day"`
+	UserID           *uuid.UUID `json:"user_id,omitempty"`
+	Provider         string     `json:"provider"`
+	Model            string     `json:"model"`
+	Calls            int        `json:"calls"`
+	PromptTokens     int        `json:"prompt_tokens"`
+	CompletionTokens int        `json:"completion_tokens"`
+	EmbeddingTokens  int        `json:"embedding_tokens"`
+	Cost             float64    `json:"cost"`   // USD at the prices in llm_model_prices
+	Priced           bool       `json:"priced"` // False when the model has no price, leaving the cost at zero
+}
+
 // MemoryItem represents an item to be stored in memory
 type MemoryItem struct {
 	Kind     string                 `json:"kind"`
@@ -145,8 +248,24 @@ type AgentRequest struct {
 type AgentResponse struct {
 	Text      string         `json:"text,omitempty"`
 	ToolCalls []ToolCall     `json:"tool_calls,omitempty"`
+	Choices   *ReplyChoices  `json:"choices,omitempty"` // Options rendered as buttons or a list
 	Metadata  map[string]interface{} `json:"metadata,omitempty"`
 	Error     string         `json:"error,omitempty"`
+	Streamed  bool           `json:"streamed,omitempty"` // Text was already sent paragraph by paragraph while it was generated
+	Preface   string         `json:"preface,omitempty"`  // Paragraphs sent before tool calls, when Text itself was not streamed
+}
+
+// ReplyChoices represents a set of options the user can pick from with a single tap
+type ReplyChoices struct {
+	ButtonText string        `json:"button_text,omitempty"` // Label of the list menu button
+	Options    []ReplyChoice `json:"options"`
+}
+
+// ReplyChoice represents a single option offered to the user
+type ReplyChoice struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
 }
 
 // ChatMessage represents a chat message for LLM communication
@@ -169,12 +288,13 @@ type ChatCompletionRequest struct {
 
 // ChatCompletionResponse represents a chat completion response
 type ChatCompletionResponse struct {
-	ID      string            `json:"id"`
-	Object  string            `json:"object"`
-	Created int64             `json:"created"`
-	Model   string            `json:"model"`
-	Choices []Choice          `json:"choices"`
-	Usage   *TokenUsage       `json:"usage,omitempty"`
+	ID       string      `json:"id"`
+	Object   string      `json:"object"`
+	Created  int64       `json:"created"`
+	Model    string      `json:"model"`
+	Choices  []Choice    `json:"choices"`
+	Usage    *TokenUsage `json:"usage,omitempty"`
+	Provider string      `json:"provider,omitempty"` // Name of the llm_providers row that served the response, when known
 }
 
 // Choice represents a choice in chat completion response
@@ -185,6 +305,23 @@ type Choice struct {
 	FinishReason string       `json:"finish_reason,omitempty"`
 }
 
+// ChatDelta represents an incremental piece of a streamed chat completion
+type ChatDelta struct {
+	Content      string          `json:"content,omitempty"`
+	ToolCalls    []ToolCallDelta `json:"tool_calls,omitempty"`
+	FinishReason string          `json:"finish_reason,omitempty"`
+}
+
+// ToolCallDelta represents an incremental piece of a streamed tool call.
+// Arguments holds the next fragment of the JSON arguments, which only parse
+// once the tool call is complete.
+type ToolCallDelta struct {
+	Index     int    `json:"index"`
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+}
+
 // ToolDefinition represents a tool definition for chat completions
 type ToolDefinition struct {
 	Type     string       `json:"type"`
@@ -228,11 +365,56 @@ type InfobipMessage struct {
 	CallbackData string              `json:"callbackData,omitempty"`
 }
 
-// InfobipMessageContent represents the content of an Infobip message
+// InfobipMessageContent represents the content of an Infobip message.
+// Text is the message body; at most one of Buttons or List is set for
+// interactive messages.
 type InfobipMessageContent struct {
-	Text string `json:"text"`
+	Text    string               `json:"text"`
+	Buttons []InfobipReplyButton `json:"buttons,omitempty"`
+	List    *InfobipList         `json:"list,omitempty"`
+}
+
+// IsInteractive reports whether the content is an interactive message
+func (c *InfobipMessageContent) IsInteractive() bool {
+	return len(c.Buttons) > 0 || c.List != nil
+}
+
+// InfobipReplyButton represents a quick reply button of an interactive message
+type InfobipReplyButton struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// InfobipList represents the menu of an interactive list message
+type InfobipList struct {
+	Title    string               `json:"title"` // Label of the button that opens the list
+	Sections []InfobipListSection `json:"sections"`
+}
+
+// InfobipListSection represents a group of rows in a list message
+type InfobipListSection struct {
+	Title string           `json:"title,omitempty"`
+	Rows  []InfobipListRow `json:"rows"`
+}
+
+// InfobipListRow represents a selectable row in a list message
+type InfobipListRow struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
 }
 
+// WhatsApp limits for interactive messages
+const (
+	InfobipMaxButtons         = 3
+	InfobipMaxButtonTitle     = 20
+	InfobipMaxListRows        = 10
+	InfobipMaxListRowTitle    = 24
+	InfobipMaxListDescription = 72
+	InfobipMaxListButtonTitle = 20
+	InfobipMaxInteractiveText = 1024
+)
+
 // InfobipWebhookMessage represents an incoming webhook message from Infobip
 type InfobipWebhookMessage struct {
 	Results []InfobipWebhookResult `json:"results"`
@@ -250,10 +432,125 @@ type InfobipWebhookResult struct {
 	Price           InfobipPrice          `json:"price,omitempty"`
 }
 
+// Incoming WhatsApp message types
+const (
+	InfobipMessageTypeText     = "TEXT"
+	InfobipMessageTypeImage    = "IMAGE"
+	InfobipMessageTypeDocument = "DOCUMENT"
+	InfobipMessageTypeAudio    = "AUDIO"
+	InfobipMessageTypeVoice    = "VOICE"
+	InfobipMessageTypeVideo    = "VIDEO"
+	InfobipMessageTypeLocation = "LOCATION"
+	InfobipMessageTypeContact  = "CONTACT"
+
+	InfobipMessageTypeButtonReply = "INTERACTIVE_BUTTON_REPLY"
+	InfobipMessageTypeListReply   = "INTERACTIVE_LIST_REPLY"
+)
+
 // InfobipIncomingMessage represents an incoming message content
 type InfobipIncomingMessage struct {
 	Type string                `json:"type"`
 	Text InfobipIncomingText   `json:"text,omitempty"`
+
+	// Media payloads (IMAGE, DOCUMENT, AUDIO, VOICE, VIDEO)
+	URL      string `json:"url,omitempty"`
+	Caption  string `json:"caption,omitempty"`
+	FileName string `json:"fileName,omitempty"`
+
+	// Location payload
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Address   string  `json:"address,omitempty"`
+
+	// Contact payload
+	Contacts []InfobipSharedContact `json:"contacts,omitempty"`
+
+	// Interactive reply payload (INTERACTIVE_BUTTON_REPLY, INTERACTIVE_LIST_REPLY)
+	ID          string `json:"id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// IsInteractiveReply reports whether the message answers a button or list message
+func (m *InfobipIncomingMessage) IsInteractiveReply() bool {
+	return m.Type == InfobipMessageTypeButtonReply || m.Type == InfobipMessageTypeListReply
+}
+
+// IsMedia reports whether the message carries downloadable media
+func (m *InfobipIncomingMessage) IsMedia() bool {
+	switch m.Type {
+	case InfobipMessageTypeImage, InfobipMessageTypeDocument, InfobipMessageTypeAudio,
+		InfobipMessageTypeVoice, InfobipMessageTypeVideo:
+		return m.URL != ""
+	default:
+		return false
+	}
+}
+
+// IsSupported reports whether the message type can be processed
+func (m *InfobipIncomingMessage) IsSupported() bool {
+	switch m.Type {
+	case InfobipMessageTypeText:
+		return m.Text.Text != ""
+	case InfobipMessageTypeLocation:
+		return true
+	case InfobipMessageTypeContact:
+		return len(m.Contacts) > 0
+	case InfobipMessageTypeButtonReply, InfobipMessageTypeListReply:
+		return m.ID != "" || m.Title != ""
+	default:
+		return m.IsMedia()
+	}
+}
+
+// InfobipSharedContact represents a contact card shared in a message
+type InfobipSharedContact struct {
+	Name   InfobipContactName    `json:"name"`
+	Phones []InfobipContactPhone `json:"phones,omitempty"`
+	Emails []InfobipContactEmail `json:"emails,omitempty"`
+}
+
+// InfobipContactName represents the name of a shared contact
+type InfobipContactName struct {
+	FirstName     string `json:"firstName,omitempty"`
+	LastName      string `json:"lastName,omitempty"`
+	FormattedName string `json:"formattedName,omitempty"`
+}
+
+// InfobipContactPhone represents a phone number of a shared contact
+type InfobipContactPhone struct {
+	Phone string `json:"phone"`
+	Type  string `json:"type,omitempty"`
+	WaID  string `json:"waId,omitempty"`
+}
+
+// InfobipContactEmail represents an email address of a shared contact
+type InfobipContactEmail struct {
+	Email string `json:"email"`
+	Type  string `json:"type,omitempty"`
+}
+
+// TranscriptionRequest represents audio to be transcribed
+type TranscriptionRequest struct {
+	Audio    []byte `json:"-"`
+	FileName string `json:"file_name"`
+	MimeType string `json:"mime_type"`
+	Language string `json:"language,omitempty"` // ISO-639-1 hint, empty for auto-detection
+}
+
+// Transcription represents the text recognized in an audio recording
+type Transcription struct {
+	Text     string  `json:"text"`
+	Language string  `json:"language,omitempty"`
+	Duration float64 `json:"duration,omitempty"` // Seconds
+}
+
+// MediaContent represents media downloaded from Infobip
+type MediaContent struct {
+	Data        []byte
+	ContentType string
+	FileName    string
 }
 
 // InfobipIncomingText represents text content
@@ -272,6 +569,157 @@ type InfobipPrice struct {
 	Currency        string  `json:"currency"`
 }
 
+// InfobipStatusWebhook represents a delivery or seen report webhook from Infobip
+type InfobipStatusWebhook struct {
+	Results []InfobipStatusReport `json:"results"`
+}
+
+// InfobipStatusReport represents the delivery or seen report of an outbound message.
+// Seen reports carry SeenAt and no Status.
+type InfobipStatusReport struct {
+	MessageID    string               `json:"messageId"`
+	BulkID       string               `json:"bulkId,omitempty"`
+	From         string               `json:"from,omitempty"`
+	Sender       string               `json:"sender,omitempty"`
+	To           string               `json:"to"`
+	SentAt       *InfobipTime         `json:"sentAt,omitempty"`
+	DoneAt       *InfobipTime         `json:"doneAt,omitempty"`
+	SeenAt       *InfobipTime         `json:"seenAt,omitempty"`
+	Status       *InfobipReportStatus `json:"status,omitempty"`
+	Error        *InfobipReportError  `json:"error,omitempty"`
+	Price        *InfobipPrice        `json:"price,omitempty"`
+	CallbackData string               `json:"callbackData,omitempty"`
+}
+
+// InfobipReportStatus represents the status of a delivery report
+type InfobipReportStatus struct {
+	GroupID     int    `json:"groupId"`
+	GroupName   string `json:"groupName"`
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// InfobipReportError represents the error of a delivery report; ID 0 means no error
+type InfobipReportError struct {
+	GroupID     int    `json:"groupId"`
+	GroupName   string `json:"groupName"`
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Permanent   bool   `json:"permanent"`
+}
+
+// MessageStatus maps the report to a message delivery status
+func (r *InfobipStatusReport) MessageStatus() string {
+	if r.SeenAt != nil && r.Status == nil {
+		return MessageStatusSeen
+	}
+	if r.Status == nil {
+		return ""
+	}
+
+	// Infobip status groups: 1 PENDING, 2 UNDELIVERABLE, 3 DELIVERED, 4 EXPIRED, 5 REJECTED
+	switch r.Status.GroupName {
+	case "PENDING":
+		return MessageStatusPending
+	case "DELIVERED":
+		return MessageStatusDelivered
+	case "UNDELIVERABLE", "EXPIRED", "REJECTED":
+		return MessageStatusFailed
+	}
+
+	switch r.Status.GroupID {
+	case 1:
+		return MessageStatusPending
+	case 3:
+		return MessageStatusDelivered
+	case 2, 4, 5:
+		return MessageStatusFailed
+	default:
+		return ""
+	}
+}
+
+// OccurredAt returns when the reported status happened
+func (r *InfobipStatusReport) OccurredAt() time.Time {
+	for _, t := range []*InfobipTime{r.SeenAt, r.DoneAt, r.SentAt} {
+		if t != nil && !t.IsZero() {
+			return t.Time
+		}
+	}
+	return time.Now().UTC()
+}
+
+// SenderNumber returns the WABA number the reported message was sent from
+func (r *InfobipStatusReport) SenderNumber() string {
+	if r.Sender != "" {
+		return r.Sender
+	}
+	return r.From
+}
+
+// InfobipTime is a timestamp in the format used by Infobip reports
+// (2019-04-09T16:01:56.494-0500), also accepting RFC 3339
+type InfobipTime struct {
+	time.Time
+}
+
+// infobipTimeLayouts lists the timestamp formats seen in Infobip webhooks
+var infobipTimeLayouts = []string{
+	"2006-01-02T15:04:05.000-0700",
+	"2006-01-02T15:04:05-0700",
+	time.RFC3339Nano,
+}
+
+// UnmarshalJSON parses an Infobip timestamp
+func (t *InfobipTime) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == "" {
+		return nil
+	}
+
+	for _, layout := range infobipTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			t.Time = parsed.UTC()
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid Infobip timestamp: %s", value)
+}
+
+// MarshalJSON formats the timestamp as RFC 3339
+func (t InfobipTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Time.Format(time.RFC3339Nano))
+}
+
+// outboundCallbackData is the callback data attached to outbound messages so
+// status reports can be routed back to the sending tenant
+type outboundCallbackData struct {
+	TenantID string `json:"tenant_id"`
+	ReplyTo  string `json:"reply_to,omitempty"`
+}
+
+// EncodeCallbackData builds the callback data of an outbound message
+func EncodeCallbackData(tenantID, replyTo string) string {
+	data, _ := json.Marshal(outboundCallbackData{TenantID: tenantID, ReplyTo: replyTo})
+	return string(data)
+}
+
+// ParseCallbackData returns the tenant ID and replied message ID stored in
+// callback data, or empty strings when it was not set by EncodeCallbackData
+func ParseCallbackData(callbackData string) (tenantID, replyTo string) {
+	var data outboundCallbackData
+	if err := json.Unmarshal([]byte(callbackData), &data); err != nil {
+		return "", ""
+	}
+	return data.TenantID, data.ReplyTo
+}
+
 // JSONSchema represents a JSON schema for tool parameters
 type JSONSchema struct {
 	Type       string                        `json:"type"`
@@ -315,6 +763,10 @@ type TenantConfig struct {
 	UpdatedAt      time.Time         `json:"updated_at" db:"updated_at"`
 }
 
+// ErrTenantConfigReadOnly is returned when changing tenant configurations
+// that are read from the tenants YAML file
+var ErrTenantConfigReadOnly = errors.New("tenant configuration is read from a file")
+
 // SystemConfig represents a system configuration key-value pair
 type SystemConfig struct {
 	ID          uuid.UUID   `json:"id" db:"id"`
@@ -358,4 +810,266 @@ type AllowedContact struct {
 	Enabled     bool      `json:"enabled" db:"enabled"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
-}
\ No newline at end of file
+}
+
+// Contact permissions
+const (
+	PermissionChat     = "chat"     // Conversation, memory and reply choices
+	PermissionSchedule = "schedule" // Reminders
+	PermissionAPI      = "api"      // Calls to external services
+	PermissionAdmin    = "admin"    // Every permission
+)
+
+// DefaultContactPermissions are the permissions of contacts created without any
+var DefaultContactPermissions = []string{PermissionChat, PermissionSchedule}
+
+// IsValidPermission reports whether a contact permission is known
+func IsValidPermission(permission string) bool {
+	switch permission {
+	case PermissionChat, PermissionSchedule, PermissionAPI, PermissionAdmin:
+		return true
+	}
+	return false
+}
+
+// HasPermission reports whether the contact holds a permission; admin holds them all
+func (c *AllowedContact) HasPermission(permission string) bool {
+	for _, granted := range c.Permissions {
+		if granted == permission || granted == PermissionAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Actions taken when a sender outside the contact allowlist writes
+const (
+	UnknownSenderIgnore      = "ignore"       // Drop the message
+	UnknownSenderNotifyAdmin = "notify_admin" // Ask the tenant's admin contacts to approve the sender
+	UnknownSenderAutoAdd     = "auto_add"     // Add the sender with the default permissions
+)
+
+// Contact request status values
+const (
+	ContactRequestPending  = "pending"
+	ContactRequestApproved = "approved"
+	ContactRequestDenied   = "denied"
+)
+
+// ContactRequest represents a request from an unknown sender to be added to the allowlist
+type ContactRequest struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	TenantID     string     `json:"tenant_id" db:"tenant_id"`
+	PhoneNumber  string     `json:"phone_number" db:"phone_number"`
+	ContactName  string     `json:"contact_name" db:"contact_name"`
+	LastMessage  string     `json:"last_message" db:"last_message"`
+	MessageCount int        `json:"message_count" db:"message_count"`
+	Status       string     `json:"status" db:"status"`
+	DecidedBy    string     `json:"decided_by,omitempty" db:"decided_by"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty" db:"decided_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Reminder status values
+const (
+	ReminderStatusScheduled = "scheduled"
+	ReminderStatusSent      = "sent"
+	ReminderStatusFailed    = "failed"
+	ReminderStatusCancelled = "cancelled"
+
+	// DefaultReminderMaxAttempts is the number of delivery attempts before a reminder is marked failed
+	DefaultReminderMaxAttempts = 3
+)
+
+// ErrReminderLeaseLost is returned when the outcome of a delivery is stored for
+// a reminder that was cancelled, snoozed or claimed again while it was leased
+var ErrReminderLeaseLost = errors.New("reminder lease lost")
+
+// Reminder represents a scheduled message to be delivered to a user
+type Reminder struct {
+	ID          uuid.UUID              `json:"id" db:"id"`
+	TenantID    string                 `json:"tenant_id" db:"tenant_id"`
+	UserID      uuid.UUID              `json:"user_id" db:"user_id"`
+	ItemID      *uuid.UUID             `json:"item_id,omitempty" db:"item_id"`
+	Text        string                 `json:"text" db:"text"`
+	Channel     string                 `json:"channel" db:"channel"`
+	Status      string                 `json:"status" db:"status"` // scheduled, sent, failed, cancelled
+	ScheduledAt time.Time              `json:"scheduled_at" db:"scheduled_at"`
+	Attempts    int                    `json:"attempts" db:"attempts"`
+	MaxAttempts int                    `json:"max_attempts" db:"max_attempts"`
+	LastError   string                 `json:"last_error,omitempty" db:"last_error"`
+	LockedUntil *time.Time             `json:"locked_until,omitempty" db:"locked_until"`
+	SentAt      *time.Time             `json:"sent_at,omitempty" db:"sent_at"`
+	Recurrence  *RecurrenceRule        `json:"recurrence,omitempty" db:"recurrence"`
+	TimeZone    string                 `json:"timezone" db:"timezone"`
+	Occurrences int                    `json:"occurrences" db:"occurrences"`
+	Metadata    map[string]interface{} `json:"metadata" db:"metadata"`
+	CreatedAt   time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at" db:"updated_at"`
+}
+
+// Recurrence frequencies
+const (
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+)
+
+// RecurrenceRule describes an RRULE-style repetition of a reminder.
+// Occurrences keep the wall-clock time of DTStart in the reminder's time zone.
+type RecurrenceRule struct {
+	Frequency  string     `json:"frequency"`              // daily, weekly, monthly
+	Interval   int        `json:"interval,omitempty"`     // every N days/weeks/months, defaults to 1
+	ByWeekday  []string   `json:"by_weekday,omitempty"`   // MO, TU, WE, TH, FR, SA, SU
+	ByMonthDay []int      `json:"by_month_day,omitempty"` // 1-31, or -1 for the last day of the month
+	Until      *time.Time `json:"until,omitempty"`        // no occurrences after this instant
+	Count      int        `json:"count,omitempty"`        // total number of occurrences, 0 = unlimited
+	DTStart    time.Time  `json:"dtstart"`                // anchor of the series
+}
+// Document status values
+const (
+	DocumentStatusProcessing = "processing"
+	DocumentStatusReady      = "ready"
+	DocumentStatusFailed     = "failed"
+)
+
+// Document represents a file ingested into a user's memory
+type Document struct {
+	ID         uuid.UUID              `json:"id" db:"id"`
+	TenantID   string                 `json:"tenant_id" db:"tenant_id"`
+	UserID     uuid.UUID              `json:"user_id" db:"user_id"`
+	MediaID    *uuid.UUID             `json:"media_id,omitempty" db:"media_id"`
+	FileName   string                 `json:"file_name" db:"file_name"`
+	MimeType   string                 `json:"mime_type" db:"mime_type"`
+	Status     string                 `json:"status" db:"status"`
+	PageCount  int                    `json:"page_count" db:"page_count"`
+	ChunkCount int                    `json:"chunk_count" db:"chunk_count"`
+	LastError  string                 `json:"last_error,omitempty" db:"last_error"`
+	Metadata   map[string]interface{} `json:"metadata" db:"metadata"`
+	CreatedAt  time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at" db:"updated_at"`
+}
+
+// DocumentPage represents the extracted text of a single document page.
+// Number is 0 for formats without pages.
+type DocumentPage struct {
+	Number int    `json:"number"`
+	Text   string `json:"text"`
+}
+
+// Message template purposes
+const (
+	TemplatePurposeDefault        = "default"
+	TemplatePurposeReminder       = "reminder"
+	TemplatePurposeContactRequest = "contact_request" // Access requests sent to admins
+)
+
+// MessageTemplate represents a WhatsApp template approved for a tenant, used
+// for messages sent outside the 24-hour customer service window
+type MessageTemplate struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	TenantID     string    `json:"tenant_id" db:"tenant_id"`
+	Purpose      string    `json:"purpose" db:"purpose"`           // Outbound flow using the template, e.g. "reminder"
+	Name         string    `json:"name" db:"name"`                 // Template name registered with WhatsApp
+	Language     string    `json:"language" db:"language"`         // Template language code, e.g. "en" or "pt_BR"
+	Placeholders []string  `json:"placeholders" db:"placeholders"` // Variables filling {{1}}, {{2}}, ... in order
+	Body         string    `json:"body,omitempty" db:"body"`       // Approved template text, used to record what was sent
+	Enabled      bool      `json:"enabled" db:"enabled"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// InfobipTemplate represents a template message to send through Infobip
+type InfobipTemplate struct {
+	Name         string   `json:"templateName"`
+	Language     string   `json:"language"`
+	Placeholders []string `json:"placeholders,omitempty"`
+}
+
+
+// Inbound job status values
+const (
+	JobStatusQueued     = "queued"
+	JobStatusProcessing = "processing"
+	JobStatusDone       = "done"
+	JobStatusDead       = "dead"
+
+	// DefaultJobMaxAttempts is the number of processing attempts before a job is dead-lettered
+	DefaultJobMaxAttempts = 5
+)
+
+// ErrJobLeaseLost is returned when recording the outcome of a job whose lease
+// expired and which was claimed again, so the new claim's outcome is kept
+var ErrJobLeaseLost = errors.New("job lease lost")
+
+// InboundJob represents a received webhook result waiting to be processed by
+// the worker pool
+type InboundJob struct {
+	ID          uuid.UUID            `json:"id" db:"id"`
+	MessageID   string               `json:"message_id" db:"message_id"` // Infobip message ID, unique per job
+	From        string               `json:"from" db:"sender"`
+	To          string               `json:"to" db:"recipient"`
+	Result      InfobipWebhookResult `json:"result" db:"payload"`
+	ReceivedAt  time.Time            `json:"received_at" db:"received_at"` // Jobs of a user are claimed in this order
+	Status      string               `json:"status" db:"status"`
+	Attempts    int                  `json:"attempts" db:"attempts"`
+	MaxAttempts int                  `json:"max_attempts" db:"max_attempts"`
+	LastError   string               `json:"last_error,omitempty" db:"last_error"`
+	RunAt       time.Time            `json:"run_at" db:"run_at"`                       // Earliest time of the next attempt
+	LockedUntil *time.Time           `json:"locked_until,omitempty" db:"locked_until"` // Lease held by the worker processing the job
+	CompletedAt *time.Time           `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt   time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// API key scopes
+const (
+	APIScopeRead  = "read"  // GET requests
+	APIScopeWrite = "write" // Requests that change data
+)
+
+// APIKey represents a key granting access to the /api/v1 routes. Only the
+// hash of the key is stored.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"key_prefix"` // First characters of the key, to recognise it in listings
+	Hash       string     `json:"-" db:"key_hash"`
+	TenantID   string     `json:"tenant_id,omitempty" db:"tenant_id"` // Empty for global admin keys
+	Scopes     []string   `json:"scopes" db:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedBy  string     `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsGlobal reports whether the key grants access to every tenant and to the
+// routes that belong to no tenant
+func (k *APIKey) IsGlobal() bool {
+	return k.TenantID == ""
+}
+
+// HasScope reports whether the key was granted a scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, granted := range k.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether the key is neither revoked nor expired at the given time
+func (k *APIKey) IsActive(at time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || at.Before(*k.ExpiresAt)
+}
+
+// IsValidAPIScope reports whether scope is a known API key scope
+func IsValidAPIScope(scope string) bool {
+	return scope == APIScopeRead || scope == APIScopeWrite
+}
@@ -1,6 +1,7 @@
 package domain_test
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -176,4 +177,116 @@ func TestInfobipMessage(t *testing.T) {
 		assert.Equal(t, "Hello from Infobip", msg.Content.Text)
 		assert.Equal(t, "callback-data", msg.CallbackData)
 	})
-}
\ No newline at end of file
+}
+
+func TestInfobipIncomingMessage(t *testing.T) {
+	t.Run("parses media payload", func(t *testing.T) {
+		var msg domain.InfobipIncomingMessage
+		err := json.Unmarshal([]byte(`{"type":"DOCUMENT","url":"https://api.infobip.com/whatsapp/1/senders/123/media/456","caption":"March invoice","fileName":"invoice.pdf"}`), &msg)
+		require.NoError(t, err)
+
+		assert.Equal(t, domain.InfobipMessageTypeDocument, msg.Type)
+		assert.Equal(t, "invoice.pdf", msg.FileName)
+		assert.Equal(t, "March invoice", msg.Caption)
+		assert.True(t, msg.IsMedia())
+		assert.True(t, msg.IsSupported())
+	})
+
+	t.Run("parses location and contact payloads", func(t *testing.T) {
+		var location domain.InfobipIncomingMessage
+		err := json.Unmarshal([]byte(`{"type":"LOCATION","latitude":-23.5505,"longitude":-46.6333,"name":"Office","address":"Av. Paulista"}`), &location)
+		require.NoError(t, err)
+		assert.Equal(t, -23.5505, location.Latitude)
+		assert.Equal(t, "Office", location.Name)
+		assert.False(t, location.IsMedia())
+		assert.True(t, location.IsSupported())
+
+		var contact domain.InfobipIncomingMessage
+		err = json.Unmarshal([]byte(`{"type":"CONTACT","contacts":[{"name":{"firstName":"Ana","formattedName":"Ana Silva"},"phones":[{"phone":"+5511999999999","type":"CELL"}]}]}`), &contact)
+		require.NoError(t, err)
+		require.Len(t, contact.Contacts, 1)
+		assert.Equal(t, "Ana Silva", contact.Contacts[0].Name.FormattedName)
+		assert.Equal(t, "+5511999999999", contact.Contacts[0].Phones[0].Phone)
+		assert.True(t, contact.IsSupported())
+	})
+
+	t.Run("parses interactive replies", func(t *testing.T) {
+		var button domain.InfobipIncomingMessage
+		err := json.Unmarshal([]byte(`{"type":"INTERACTIVE_BUTTON_REPLY","id":"confirm_yes","title":"Yes"}`), &button)
+		require.NoError(t, err)
+		assert.Equal(t, "confirm_yes", button.ID)
+		assert.Equal(t, "Yes", button.Title)
+		assert.True(t, button.IsInteractiveReply())
+		assert.True(t, button.IsSupported())
+
+		var list domain.InfobipIncomingMessage
+		err = json.Unmarshal([]byte(`{"type":"INTERACTIVE_LIST_REPLY","id":"slot_2","title":"Tuesday 10:00","description":"Dr. Lima"}`), &list)
+		require.NoError(t, err)
+		assert.Equal(t, "Dr. Lima", list.Description)
+		assert.True(t, list.IsInteractiveReply())
+		assert.False(t, list.IsMedia())
+		assert.True(t, list.IsSupported())
+	})
+
+	t.Run("rejects unsupported or empty messages", func(t *testing.T) {
+		assert.False(t, (&domain.InfobipIncomingMessage{Type: "STICKER", URL: "https://example.com/s"}).IsSupported())
+		assert.False(t, (&domain.InfobipIncomingMessage{Type: domain.InfobipMessageTypeImage}).IsSupported())
+		assert.False(t, (&domain.InfobipIncomingMessage{Type: domain.InfobipMessageTypeText}).IsSupported())
+		assert.False(t, (&domain.InfobipIncomingMessage{Type: domain.InfobipMessageTypeButtonReply}).IsSupported())
+	})
+}
+
+func TestInfobipStatusReport(t *testing.T) {
+	t.Run("parses delivery report", func(t *testing.T) {
+		var webhook domain.InfobipStatusWebhook
+		err := json.Unmarshal([]byte(`{"results":[{"messageId":"ABEGVUGWh3gEAgo-sM3kdfFCbcEY","to":"5511999999999","sentAt":"2019-04-09T16:01:56.494-0500","doneAt":"2019-04-09T16:01:57.123-0500","status":{"groupId":3,"groupName":"DELIVERED","id":5,"name":"DELIVERED_TO_HANDSET","description":"Message delivered to handset"},"error":{"groupId":0,"groupName":"OK","id":0,"name":"NO_ERROR","description":"No Error","permanent":false},"callbackData":"{\"tenant_id\":\"tenant1\"}"}]}`), &webhook)
+		require.NoError(t, err)
+		require.Len(t, webhook.Results, 1)
+
+		report := webhook.Results[0]
+		assert.Equal(t, domain.MessageStatusDelivered, report.MessageStatus())
+		assert.Equal(t, time.Date(2019, 4, 9, 21, 1, 57, 123000000, time.UTC), report.OccurredAt())
+
+		tenantID, replyTo := domain.ParseCallbackData(report.CallbackData)
+		assert.Equal(t, "tenant1", tenantID)
+		assert.Empty(t, replyTo)
+	})
+
+	t.Run("parses seen report", func(t *testing.T) {
+		var report domain.InfobipStatusReport
+		err := json.Unmarshal([]byte(`{"messageId":"msg-1","from":"5511888888888","to":"5511999999999","sentAt":"2019-04-09T16:01:56.494-0500","seenAt":"2019-04-09T16:05:00.000-0500"}`), &report)
+		require.NoError(t, err)
+
+		assert.Equal(t, domain.MessageStatusSeen, report.MessageStatus())
+		assert.Equal(t, time.Date(2019, 4, 9, 21, 5, 0, 0, time.UTC), report.OccurredAt())
+		assert.Equal(t, "5511888888888", report.SenderNumber())
+	})
+
+	t.Run("maps failed status groups", func(t *testing.T) {
+		for _, group := range []string{"UNDELIVERABLE", "EXPIRED", "REJECTED"} {
+			report := domain.InfobipStatusReport{Status: &domain.InfobipReportStatus{GroupName: group}}
+			assert.Equal(t, domain.MessageStatusFailed, report.MessageStatus(), group)
+		}
+
+		pending := domain.InfobipStatusReport{Status: &domain.InfobipReportStatus{GroupID: 1}}
+		assert.Equal(t, domain.MessageStatusPending, pending.MessageStatus())
+		assert.Empty(t, (&domain.InfobipStatusReport{}).MessageStatus())
+	})
+
+	t.Run("rejects invalid timestamps", func(t *testing.T) {
+		var report domain.InfobipStatusReport
+		err := json.Unmarshal([]byte(`{"messageId":"msg-1","sentAt":"yesterday"}`), &report)
+		assert.Error(t, err)
+	})
+}
+
+func TestCallbackData(t *testing.T) {
+	tenantID, replyTo := domain.ParseCallbackData(domain.EncodeCallbackData("tenant1", "inbound-1"))
+	assert.Equal(t, "tenant1", tenantID)
+	assert.Equal(t, "inbound-1", replyTo)
+
+	// Callback data set by other integrations is ignored
+	tenantID, replyTo = domain.ParseCallbackData("inbound-1")
+	assert.Empty(t, tenantID)
+	assert.Empty(t, replyTo)
+}
@@ -0,0 +1,197 @@
+package apikeys
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"personal-assistant/internal/auth"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+// APIKeysHandler handles HTTP requests for minting and revoking API keys
+type APIKeysHandler struct {
+	store         domain.APIKeyStore
+	tenantManager domain.TenantManager
+	logger        *log.Logger
+}
+
+// NewAPIKeysHandler creates a new API keys handler
+func NewAPIKeysHandler(store domain.APIKeyStore, tenantManager domain.TenantManager, logger *log.Logger) *APIKeysHandler {
+	return &APIKeysHandler{
+		store:         store,
+		tenantManager: tenantManager,
+		logger:        logger,
+	}
+}
+
+// CreateAPIKeyRequest represents a request to mint an API key
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required"`
+	TenantID  string     `json:"tenant_id"` // Empty for a global admin key
+	Scopes    []string   `json:"scopes" validate:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreateAPIKey mints a new API key. The key itself is only returned in this response.
+func (h *APIKeysHandler) CreateAPIKey(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req CreateAPIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	// Validate required fields
+	if req.Name == "" || len(req.Scopes) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "name and scopes are required",
+		})
+	}
+
+	for _, scope := range req.Scopes {
+		if !domain.IsValidAPIScope(scope) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "unknown scope: " + scope,
+			})
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "expires_at must be in the future",
+		})
+	}
+
+	if req.TenantID != "" {
+		if _, err := h.tenantManager.GetTenantByID(req.TenantID); err != nil {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "tenant not found",
+			})
+		}
+	}
+
+	secret, prefix, err := auth.GenerateKey()
+	if err != nil {
+		h.logger.WithContext(ctx).Error().Err(err).Msg("failed to generate API key")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to generate API key",
+		})
+	}
+
+	key := &domain.APIKey{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Prefix:    prefix,
+		Hash:      auth.HashKey(secret),
+		TenantID:  req.TenantID,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if creator := auth.KeyFromContext(c); creator != nil {
+		key.CreatedBy = creator.Name
+	}
+
+	if err := h.store.CreateAPIKey(ctx, key); err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", req.TenantID).
+			Msg("failed to create API key")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to create API key",
+		})
+	}
+
+	h.logger.WithContext(ctx).Info().
+		Str("key_id", key.ID.String()).
+		Str("tenant_id", key.TenantID).
+		Strs("scopes", key.Scopes).
+		Str("created_by", key.CreatedBy).
+		Msg("API key created")
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"key":     secret,
+		"api_key": key,
+	})
+}
+
+// ListAPIKeys returns API keys, filtered by the optional tenant_id query parameter
+func (h *APIKeysHandler) ListAPIKeys(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.QueryParam("tenant_id")
+
+	keys, err := h.store.ListAPIKeys(ctx, tenantID)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Msg("failed to list API keys")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to list API keys",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"api_keys": keys,
+		"count":    len(keys),
+	})
+}
+
+// RevokeAPIKey revokes an API key so that it is no longer accepted
+func (h *APIKeysHandler) RevokeAPIKey(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	keyID, err := uuid.Parse(c.Param("key_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid key_id format",
+		})
+	}
+
+	key, err := h.store.GetAPIKey(ctx, keyID)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("key_id", keyID.String()).
+			Msg("failed to get API key")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to get API key",
+		})
+	}
+
+	if key == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "API key not found",
+		})
+	}
+
+	if key.RevokedAt != nil {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "API key already revoked",
+		})
+	}
+
+	if err := h.store.RevokeAPIKey(ctx, keyID); err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("key_id", keyID.String()).
+			Msg("failed to revoke API key")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to revoke API key",
+		})
+	}
+
+	h.logger.WithContext(ctx).Info().
+		Str("key_id", keyID.String()).
+		Str("tenant_id", key.TenantID).
+		Msg("API key revoked")
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "API key revoked successfully",
+	})
+}
@@ -139,6 +139,15 @@ func (h *ContactsHandler) CreateContact(c echo.Context) error {
 		})
 	}
 
+	// The tenant of the URL is the one the API key was checked against
+	if req.TenantID == "" {
+		req.TenantID = c.Param("tenant_id")
+	} else if req.TenantID != c.Param("tenant_id") {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id does not match the URL",
+		})
+	}
+
 	// Validate required fields
 	if req.TenantID == "" || req.PhoneNumber == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{
@@ -146,6 +155,12 @@ func (h *ContactsHandler) CreateContact(c echo.Context) error {
 		})
 	}
 
+	if permission, ok := unknownPermission(req.Permissions); ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "unknown permission: " + permission,
+		})
+	}
+
 	// Get repository for the tenant
 	repo, err := h.tenantManager.GetRepository(req.TenantID)
 	if err != nil {
@@ -179,7 +194,7 @@ func (h *ContactsHandler) CreateContact(c echo.Context) error {
 
 	// Set default permissions if not provided
 	if len(req.Permissions) == 0 {
-		req.Permissions = []string{"chat", "schedule"}
+		req.Permissions = domain.DefaultContactPermissions
 	}
 
 	// Create the contact
@@ -232,6 +247,12 @@ func (h *ContactsHandler) UpdateContact(c echo.Context) error {
 		})
 	}
 
+	if permission, ok := unknownPermission(req.Permissions); ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "unknown permission: " + permission,
+		})
+	}
+
 	// Get repository for the tenant
 	repo, err := h.tenantManager.GetRepository(tenantID)
 	if err != nil {
@@ -393,4 +414,14 @@ func (h *ContactsHandler) CheckContact(c echo.Context) error {
 		"phone_number":  phoneNumber,
 		"is_allowed":    isAllowed,
 	})
-}
\ No newline at end of file
+}
+
+// unknownPermission returns the first permission that is not a known contact permission
+func unknownPermission(permissions []string) (string, bool) {
+	for _, permission := range permissions {
+		if !domain.IsValidPermission(permission) {
+			return permission, true
+		}
+	}
+	return "", false
+}
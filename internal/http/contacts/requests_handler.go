@@ -0,0 +1,139 @@
+package contacts
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"personal-assistant/internal/allowlist"
+	"personal-assistant/internal/domain"
+)
+
+// apiDecider is recorded as the decider of requests approved or denied through the API
+const apiDecider = "api"
+
+// ListContactRequests returns the access requests of senders outside the
+// allowlist, filtered by the status query parameter (pending by default, or all)
+func (h *ContactsHandler) ListContactRequests(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenant_id")
+
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	status := c.QueryParam("status")
+	switch status {
+	case "":
+		status = domain.ContactRequestPending
+	case "all":
+		status = ""
+	case domain.ContactRequestPending, domain.ContactRequestApproved, domain.ContactRequestDenied:
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "status must be pending, approved, denied or all",
+		})
+	}
+
+	// Get repository for the tenant
+	repo, err := h.tenantManager.GetRepository(tenantID)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Msg("failed to get repository")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to get tenant repository",
+		})
+	}
+
+	requests, err := repo.ListContactRequests(ctx, tenantID, status)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Msg("failed to list contact requests")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to list contact requests",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"requests": requests,
+		"count":    len(requests),
+	})
+}
+
+// ApproveContactRequest adds the sender of a pending access request to the allowlist
+func (h *ContactsHandler) ApproveContactRequest(c echo.Context) error {
+	return h.decideContactRequest(c, true)
+}
+
+// DenyContactRequest denies a pending access request
+func (h *ContactsHandler) DenyContactRequest(c echo.Context) error {
+	return h.decideContactRequest(c, false)
+}
+
+// decideContactRequest approves or denies the access request of the phone_number parameter
+func (h *ContactsHandler) decideContactRequest(c echo.Context, approve bool) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenant_id")
+	phoneNumber := c.Param("phone_number")
+
+	if tenantID == "" || phoneNumber == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id and phone_number are required",
+		})
+	}
+
+	tenant, err := h.tenantManager.GetTenantByID(tenantID)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "tenant not found",
+		})
+	}
+
+	// Get repository for the tenant
+	repo, err := h.tenantManager.GetRepository(tenantID)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Msg("failed to get repository")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to get tenant repository",
+		})
+	}
+
+	request, err := allowlist.Decide(ctx, repo, tenant, phoneNumber, approve, apiDecider)
+	switch {
+	case errors.Is(err, allowlist.ErrRequestNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "contact request not found",
+		})
+	case errors.Is(err, allowlist.ErrRequestDecided):
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "contact request already " + request.Status,
+		})
+	case err != nil:
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Str("phone_number", phoneNumber).
+			Msg("failed to decide contact request")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to decide contact request",
+		})
+	}
+
+	h.logger.WithContext(ctx).Info().
+		Str("tenant_id", tenantID).
+		Str("phone_number", phoneNumber).
+		Str("status", request.Status).
+		Msg("contact request decided")
+
+	return c.JSON(http.StatusOK, request)
+}
@@ -3,6 +3,7 @@ package infobip
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -19,18 +20,23 @@ import (
 // WebhookHandler handles incoming Infobip webhook requests
 type WebhookHandler struct {
 	processor    domain.MessageProcessor
+	queue        domain.JobQueue // Optional; when set, results are queued instead of processed inline
+	deduplicator domain.Deduplicator
+	verifier     *infobip.WebhookVerifier
 	config       *config.Config
 	logger       *log.Logger
-	seenMessages map[string]time.Time // Simple deduplication cache
 }
 
-// NewWebhookHandler creates a new webhook handler
-func NewWebhookHandler(processor domain.MessageProcessor, config *config.Config, logger *log.Logger) *WebhookHandler {
+// NewWebhookHandler creates a new webhook handler. A nil queue processes
+// messages synchronously within the webhook request.
+func NewWebhookHandler(processor domain.MessageProcessor, queue domain.JobQueue, deduplicator domain.Deduplicator, verifier *infobip.WebhookVerifier, config *config.Config, logger *log.Logger) *WebhookHandler {
 	return &WebhookHandler{
 		processor:    processor,
+		queue:        queue,
+		deduplicator: deduplicator,
+		verifier:     verifier,
 		config:       config,
 		logger:       logger,
-		seenMessages: make(map[string]time.Time),
 	}
 }
 
@@ -43,19 +49,6 @@ func (h *WebhookHandler) HandleIncoming(c echo.Context) error {
 	ctx = context.WithValue(ctx, log.RequestIDKey, requestID)
 	logger := h.logger.WithContext(ctx)
 	
-	// Verify webhook token if configured
-	if h.config.WebhookVerifyToken != "" {
-		token := c.Request().Header.Get("X-Hub-Signature")
-		if !infobip.VerifyWebhookToken(token, h.config.WebhookVerifyToken) {
-			logger.Warn().
-				Str("received_token", token).
-				Msg("webhook token verification failed")
-			return c.JSON(http.StatusUnauthorized, map[string]string{
-				"error": "unauthorized",
-			})
-		}
-	}
-	
 	// Read request body
 	body, err := io.ReadAll(c.Request().Body)
 	if err != nil {
@@ -81,6 +74,14 @@ func (h *WebhookHandler) HandleIncoming(c echo.Context) error {
 		})
 	}
 	
+	// Verify the signature against the secrets of the receiving tenants
+	if err := h.verifier.VerifyIncoming(c.Request().Header, body, &webhookMsg); err != nil {
+		logger.Warn().Err(err).Msg("webhook verification failed")
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "unauthorized",
+		})
+	}
+	
 	// Validate webhook structure
 	if len(webhookMsg.Results) == 0 {
 		logger.Warn().Msg("webhook message has no results")
@@ -90,6 +91,7 @@ func (h *WebhookHandler) HandleIncoming(c echo.Context) error {
 	}
 	
 	// Process each result
+	failed := 0
 	for _, result := range webhookMsg.Results {
 		if err := h.processWebhookResult(ctx, &result); err != nil {
 			logger.Error().
@@ -99,17 +101,33 @@ func (h *WebhookHandler) HandleIncoming(c echo.Context) error {
 				Msg("failed to process webhook result")
 			
 			// Continue processing other results even if one fails
+			failed++
 			continue
 		}
 	}
 	
+	status := "processed"
+	if h.queue != nil {
+		// Messages that could not be queued are lost unless Infobip redelivers them;
+		// results that were queued are ignored on redelivery
+		if failed > 0 {
+			return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
+				"error":         "failed to queue messages",
+				"failed_count":  failed,
+				"results_count": len(webhookMsg.Results),
+			})
+		}
+		status = "queued"
+	}
+	
 	logger.Info().
 		Int("results_count", len(webhookMsg.Results)).
+		Str("status", status).
 		Msg("webhook processed successfully")
 	
 	// Return success response
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"status":           "processed",
+		"status":           status,
 		"results_count":    len(webhookMsg.Results),
 		"processed_at":     time.Now().UTC().Format(time.RFC3339),
 		"request_id":       requestID,
@@ -120,32 +138,33 @@ func (h *WebhookHandler) HandleIncoming(c echo.Context) error {
 func (h *WebhookHandler) processWebhookResult(ctx context.Context, result *domain.InfobipWebhookResult) error {
 	logger := h.logger.WithContext(ctx)
 	
-	// Extract message text
-	messageText := ""
-	if result.Message.Type == "TEXT" && result.Message.Text.Text != "" {
-		messageText = result.Message.Text.Text
-		logger.Info().Str("text", messageText).Msg("Processing text message")
-	} else {
+	// Skip message types we cannot process
+	if !result.Message.IsSupported() {
 		logger.Debug().
 			Str("message_type", result.Message.Type).
-			Msg("skipping non-text message")
-		return nil // Skip non-text messages for now
+			Msg("skipping unsupported message")
+		return nil
 	}
+	logger.Info().Str("message_type", result.Message.Type).Msg("Processing message")
 	
 	// Check for message deduplication
-	if h.isDuplicateMessage(result.MessageID) {
+	if h.isDuplicateMessage(ctx, result) {
 		logger.Debug().
 			Str("message_id", result.MessageID).
 			Msg("skipping duplicate message")
 		return nil
 	}
 	
-	// Mark message as seen
-	h.markMessageSeen(result.MessageID)
-	
-	// Create webhook message for processing
-	webhookMsg := &domain.InfobipWebhookMessage{
-		Results: []domain.InfobipWebhookResult{*result},
+	// Acknowledge immediately and let the worker pool process the message
+	if h.queue != nil {
+		if err := h.enqueueResult(ctx, result); err != nil {
+			// Accept Infobip's redelivery of the message
+			if forgetErr := h.deduplicator.Forget(ctx, result.To, result.MessageID); forgetErr != nil {
+				logger.Warn().Err(forgetErr).Str("message_id", result.MessageID).Msg("failed to forget message")
+			}
+			return err
+		}
+		return nil
 	}
 	
 	// Log message processing start
@@ -159,7 +178,7 @@ func (h *WebhookHandler) processWebhookResult(ctx context.Context, result *domai
 	)
 	
 	// Process the message
-	err := h.processor.ProcessIncoming(ctx, webhookMsg)
+	err := h.processor.ProcessResult(ctx, result)
 	duration := time.Since(start)
 	
 	if err != nil {
@@ -183,28 +202,38 @@ func (h *WebhookHandler) processWebhookResult(ctx context.Context, result *domai
 	return nil
 }
 
-// isDuplicateMessage checks if a message has already been processed recently
-func (h *WebhookHandler) isDuplicateMessage(messageID string) bool {
-	if lastSeen, exists := h.seenMessages[messageID]; exists {
-		// Consider it duplicate if seen within the last 5 minutes
-		if time.Since(lastSeen) < 5*time.Minute {
-			return true
-		}
+// enqueueResult stores a webhook result in the job queue for asynchronous processing
+func (h *WebhookHandler) enqueueResult(ctx context.Context, result *domain.InfobipWebhookResult) error {
+	job := &domain.InboundJob{
+		Result:      *result,
+		MaxAttempts: h.config.Queue.MaxAttempts,
 	}
-	return false
+	
+	if err := h.queue.Enqueue(ctx, job); err != nil {
+		return fmt.Errorf("failed to enqueue message %s: %w", result.MessageID, err)
+	}
+	
+	h.logger.WithContext(ctx).Debug().
+		Str("job_id", job.ID.String()).
+		Str("message_id", result.MessageID).
+		Msg("message queued for processing")
+	
+	return nil
 }
 
-// markMessageSeen marks a message as seen and cleans up old entries
-func (h *WebhookHandler) markMessageSeen(messageID string) {
-	h.seenMessages[messageID] = time.Now()
-	
-	// Clean up old entries (keep only last hour)
-	cutoff := time.Now().Add(-1 * time.Hour)
-	for id, timestamp := range h.seenMessages {
-		if timestamp.Before(cutoff) {
-			delete(h.seenMessages, id)
-		}
+// isDuplicateMessage checks whether a message was already received, recording
+// it otherwise. Messages are let through when the check fails, since storing
+// a duplicate is rejected by the messages unique constraint.
+func (h *WebhookHandler) isDuplicateMessage(ctx context.Context, result *domain.InfobipWebhookResult) bool {
+	seen, err := h.deduplicator.Seen(ctx, result.To, result.MessageID)
+	if err != nil {
+		h.logger.WithContext(ctx).Warn().
+			Err(err).
+			Str("message_id", result.MessageID).
+			Msg("failed to check for duplicate message")
+		return false
 	}
+	return seen
 }
 
 // HandleStatus handles message status webhooks (delivery receipts, etc.)
@@ -229,10 +258,9 @@ func (h *WebhookHandler) HandleStatus(c echo.Context) error {
 		Str("body", string(body)).
 		Msg("received status webhook")
 	
-	// For now, just log the status update
-	// In a production system, you might want to update message status in database
-	var statusUpdate map[string]interface{}
-	if err := json.Unmarshal(body, &statusUpdate); err != nil {
+	// Parse delivery and seen reports
+	var webhook domain.InfobipStatusWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
 		logger.Error().
 			Err(err).
 			Str("body", string(body)).
@@ -242,14 +270,49 @@ func (h *WebhookHandler) HandleStatus(c echo.Context) error {
 		})
 	}
 	
+	// Verify the signature against the secrets of the sending tenants
+	if err := h.verifier.VerifyStatus(c.Request().Header, body, &webhook); err != nil {
+		logger.Warn().Err(err).Msg("status webhook verification failed")
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "unauthorized",
+		})
+	}
+	
+	if len(webhook.Results) == 0 {
+		logger.Warn().Msg("status webhook has no results")
+		return c.JSON(http.StatusOK, map[string]string{
+			"status": "no_results",
+		})
+	}
+	
+	// Reports that arrived before their message was stored are redelivered by
+	// Infobip; other failures are logged but still acknowledged
+	if err := h.processor.ProcessStatus(ctx, &webhook); err != nil {
+		if errors.Is(err, domain.ErrMessageNotStored) {
+			logger.Info().
+				Err(err).
+				Int("results_count", len(webhook.Results)).
+				Msg("status webhook has reports for messages not stored yet")
+			return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
+				"error":         "messages not stored yet, retry later",
+				"results_count": len(webhook.Results),
+			})
+		}
+		logger.Error().
+			Err(err).
+			Int("results_count", len(webhook.Results)).
+			Msg("failed to process status webhook")
+	}
+	
 	logger.Info().
-		Interface("status_update", statusUpdate).
-		Msg("received message status update")
+		Int("results_count", len(webhook.Results)).
+		Msg("status webhook processed successfully")
 	
 	return c.JSON(http.StatusOK, map[string]interface{}{
-		"status":       "received",
-		"processed_at": time.Now().UTC().Format(time.RFC3339),
-		"request_id":   requestID,
+		"status":        "processed",
+		"results_count": len(webhook.Results),
+		"processed_at":  time.Now().UTC().Format(time.RFC3339),
+		"request_id":    requestID,
 	})
 }
 
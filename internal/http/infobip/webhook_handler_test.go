@@ -0,0 +1,156 @@
+package infobip_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/dedup"
+	"personal-assistant/internal/domain"
+	httpinfobip "personal-assistant/internal/http/infobip"
+	"personal-assistant/internal/infobip"
+	"personal-assistant/internal/log"
+)
+
+const (
+	verifyToken     = "verify-token"
+	signatureHeader = "X-Hub-Signature"
+)
+
+// MockTenantManager implements the tenant lookups used by the webhook verifier
+type MockTenantManager struct {
+	domain.TenantManager
+}
+
+func (m *MockTenantManager) GetTenant(wabaNumber string) (*domain.Tenant, error) {
+	return nil, errors.New("tenant not found")
+}
+
+// MockProcessor implements the processing used by the webhook handler
+type MockProcessor struct {
+	domain.MessageProcessor
+	mock.Mock
+}
+
+func (m *MockProcessor) ProcessResult(ctx context.Context, result *domain.InfobipWebhookResult) error {
+	args := m.Called(ctx, result.MessageID)
+	return args.Error(0)
+}
+
+// MockJobQueue implements the enqueueing used by the webhook handler
+type MockJobQueue struct {
+	domain.JobQueue
+	mock.Mock
+}
+
+func (m *MockJobQueue) Enqueue(ctx context.Context, job *domain.InboundJob) error {
+	args := m.Called(ctx, job.Result.MessageID)
+	return args.Error(0)
+}
+
+// newHandler returns a webhook handler checking the verify token, without a
+// queue when queue is nil
+func newHandler(processor domain.MessageProcessor, queue domain.JobQueue) *httpinfobip.WebhookHandler {
+	cfg := &config.Config{
+		WebhookVerifyToken: verifyToken,
+		WebhookSignature:   config.WebhookSignatureConfig{SignatureHeader: signatureHeader},
+		Queue:              config.QueueConfig{MaxAttempts: 5},
+	}
+
+	return httpinfobip.NewWebhookHandler(
+		processor,
+		queue,
+		dedup.NewMemoryDeduplicator(time.Hour, 100),
+		infobip.NewWebhookVerifier(&MockTenantManager{}, cfg),
+		cfg,
+		log.Init("error"),
+	)
+}
+
+// deliver posts an inbound webhook with a single text message
+func deliver(t *testing.T, handler *httpinfobip.WebhookHandler, messageID, token string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, err := json.Marshal(domain.InfobipWebhookMessage{Results: []domain.InfobipWebhookResult{{
+		MessageID: messageID,
+		From:      "5511999999999",
+		To:        "15550000000",
+		Message:   domain.InfobipIncomingMessage{Type: domain.InfobipMessageTypeText, Text: domain.InfobipIncomingText{Text: "hello"}},
+	}}})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook/infobip", strings.NewReader(string(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(signatureHeader, token)
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, handler.HandleIncoming(echo.New().NewContext(req, rec)))
+	return rec
+}
+
+func TestWebhookHandler_HandleIncoming(t *testing.T) {
+	t.Run("queues a message once when Infobip delivers it twice", func(t *testing.T) {
+		queue := &MockJobQueue{}
+		queue.On("Enqueue", mock.Anything, "msg-1").Return(nil).Once()
+		handler := newHandler(&MockProcessor{}, queue)
+
+		first := deliver(t, handler, "msg-1", verifyToken)
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Contains(t, first.Body.String(), `"status":"queued"`)
+
+		second := deliver(t, handler, "msg-1", verifyToken)
+		assert.Equal(t, http.StatusOK, second.Code)
+		queue.AssertExpectations(t)
+	})
+
+	t.Run("does not record messages of requests failing verification", func(t *testing.T) {
+		queue := &MockJobQueue{}
+		queue.On("Enqueue", mock.Anything, "msg-1").Return(nil).Once()
+		handler := newHandler(&MockProcessor{}, queue)
+
+		rejected := deliver(t, handler, "msg-1", "wrong-token")
+		assert.Equal(t, http.StatusUnauthorized, rejected.Code)
+		queue.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+
+		accepted := deliver(t, handler, "msg-1", verifyToken)
+		assert.Equal(t, http.StatusOK, accepted.Code)
+		queue.AssertExpectations(t)
+	})
+
+	t.Run("asks for redelivery of a message that could not be queued", func(t *testing.T) {
+		queue := &MockJobQueue{}
+		queue.On("Enqueue", mock.Anything, "msg-1").Return(errors.New("database unavailable")).Once()
+		queue.On("Enqueue", mock.Anything, "msg-1").Return(nil).Once()
+		handler := newHandler(&MockProcessor{}, queue)
+
+		failed := deliver(t, handler, "msg-1", verifyToken)
+		assert.Equal(t, http.StatusServiceUnavailable, failed.Code)
+
+		// The redelivery is not mistaken for a duplicate
+		redelivered := deliver(t, handler, "msg-1", verifyToken)
+		assert.Equal(t, http.StatusOK, redelivered.Code)
+		queue.AssertExpectations(t)
+	})
+
+	t.Run("processes messages within the request without a queue", func(t *testing.T) {
+		processor := &MockProcessor{}
+		processor.On("ProcessResult", mock.Anything, "msg-1").Return(nil).Once()
+		handler := newHandler(processor, nil)
+
+		rec := deliver(t, handler, "msg-1", verifyToken)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"status":"processed"`)
+		processor.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,155 @@
+package jobs
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+// defaultListLimit is the number of jobs returned when no limit is given
+const defaultListLimit = 50
+
+// JobsHandler handles HTTP requests for inspecting the inbound job queue
+type JobsHandler struct {
+	queue  domain.JobQueue
+	logger *log.Logger
+}
+
+// NewJobsHandler creates a new jobs handler
+func NewJobsHandler(queue domain.JobQueue, logger *log.Logger) *JobsHandler {
+	return &JobsHandler{
+		queue:  queue,
+		logger: logger,
+	}
+}
+
+// ListJobs returns queued jobs, filtered by the optional status query parameter
+func (h *JobsHandler) ListJobs(c echo.Context) error {
+	ctx := c.Request().Context()
+	status := c.QueryParam("status")
+
+	switch status {
+	case "", domain.JobStatusQueued, domain.JobStatusProcessing, domain.JobStatusDone, domain.JobStatusDead:
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "status must be one of queued, processing, done or dead",
+		})
+	}
+
+	limit := defaultListLimit
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > 500 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "limit must be between 1 and 500",
+			})
+		}
+		limit = parsed
+	}
+
+	jobs, err := h.queue.ListJobs(ctx, status, limit)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("status", status).
+			Msg("failed to list jobs")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to list jobs",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"jobs":  jobs,
+		"count": len(jobs),
+	})
+}
+
+// GetJob returns a specific job
+func (h *JobsHandler) GetJob(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	jobID, err := uuid.Parse(c.Param("job_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid job_id format",
+		})
+	}
+
+	job, err := h.queue.GetJob(ctx, jobID)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("job_id", jobID.String()).
+			Msg("failed to get job")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to get job",
+		})
+	}
+
+	if job == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "job not found",
+		})
+	}
+
+	return c.JSON(http.StatusOK, job)
+}
+
+// RetryJob moves a dead job back to the queue
+func (h *JobsHandler) RetryJob(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	jobID, err := uuid.Parse(c.Param("job_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid job_id format",
+		})
+	}
+
+	job, err := h.queue.GetJob(ctx, jobID)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("job_id", jobID.String()).
+			Msg("failed to get job")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to get job",
+		})
+	}
+
+	if job == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "job not found",
+		})
+	}
+
+	if job.Status != domain.JobStatusDead {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "only dead jobs can be retried",
+		})
+	}
+
+	if err := h.queue.Requeue(ctx, jobID); err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("job_id", jobID.String()).
+			Msg("failed to requeue job")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to requeue job",
+		})
+	}
+
+	h.logger.WithContext(ctx).Info().
+		Str("job_id", jobID.String()).
+		Str("message_id", job.MessageID).
+		Msg("dead job requeued")
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "job requeued successfully",
+	})
+}
@@ -0,0 +1,368 @@
+package tenants
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"personal-assistant/internal/domain"
+)
+
+var (
+	// knownProviders are the provider types accepted by llm_providers
+	knownProviders = []string{"openai", "deepseek", "anthropic", "bedrock", "ollama", "mock"}
+	// keylessProviders can run without an API key
+	keylessProviders = []string{"bedrock", "ollama", "mock"}
+)
+
+// CreateLLMProviderRequest represents a request to add an LLM provider to a tenant
+type CreateLLMProviderRequest struct {
+	Provider   string                 `json:"provider" validate:"required"`
+	Name       string                 `json:"name" validate:"required"`
+	APIKey     string                 `json:"api_key"`
+	BaseURL    string                 `json:"base_url"`
+	ModelChat  string                 `json:"model_chat" validate:"required"`
+	ModelEmbed string                 `json:"model_embed"`
+	Config     map[string]interface{} `json:"config"`
+	IsDefault  bool                   `json:"is_default"`
+	Enabled    *bool                  `json:"enabled"`
+}
+
+// UpdateLLMProviderRequest represents a request to update an LLM provider; omitted fields are left unchanged
+type UpdateLLMProviderRequest struct {
+	Provider   *string                `json:"provider"`
+	APIKey     *string                `json:"api_key"`
+	BaseURL    *string                `json:"base_url"`
+	ModelChat  *string                `json:"model_chat"`
+	ModelEmbed *string                `json:"model_embed"`
+	Config     map[string]interface{} `json:"config"`
+	IsDefault  *bool                  `json:"is_default"`
+	Enabled    *bool                  `json:"enabled"`
+}
+
+// ListLLMProviders returns the LLM providers of a tenant, default first
+func (h *TenantsHandler) ListLLMProviders(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenant_id")
+
+	repo, ok := h.tenantRepository(c, tenantID)
+	if !ok {
+		return nil
+	}
+
+	providers, err := repo.GetLLMProviders(ctx, tenantID)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Msg("failed to list LLM providers")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to list LLM providers",
+		})
+	}
+
+	masked := make([]domain.LLMProviderConfig, len(providers))
+	for i, provider := range providers {
+		masked[i] = maskProvider(provider)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"llm_providers": masked,
+		"count":         len(masked),
+	})
+}
+
+// GetLLMProvider returns an LLM provider of a tenant
+func (h *TenantsHandler) GetLLMProvider(c echo.Context) error {
+	tenantID := c.Param("tenant_id")
+
+	repo, ok := h.tenantRepository(c, tenantID)
+	if !ok {
+		return nil
+	}
+
+	provider, ok := h.getLLMProvider(c, repo, tenantID, c.Param("name"))
+	if !ok {
+		return nil
+	}
+
+	return c.JSON(http.StatusOK, maskProvider(*provider))
+}
+
+// CreateLLMProvider adds an LLM provider to a tenant. The tenant's first
+// enabled provider becomes its default.
+func (h *TenantsHandler) CreateLLMProvider(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenant_id")
+
+	var req CreateLLMProviderRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	now := time.Now().UTC()
+	provider := &domain.LLMProviderConfig{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		Provider:   req.Provider,
+		Name:       req.Name,
+		APIKey:     req.APIKey,
+		BaseURL:    req.BaseURL,
+		ModelChat:  req.ModelChat,
+		ModelEmbed: req.ModelEmbed,
+		Config:     req.Config,
+		IsDefault:  req.IsDefault,
+		Enabled:    req.Enabled == nil || *req.Enabled,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if provider.Config == nil {
+		provider.Config = map[string]interface{}{}
+	}
+
+	if provider.Name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "name is required",
+		})
+	}
+	if err := validateProvider(provider); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	repo, ok := h.tenantRepository(c, tenantID)
+	if !ok {
+		return nil
+	}
+
+	existing, err := repo.GetLLMProvider(ctx, tenantID, provider.Name)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Str("name", provider.Name).
+			Msg("failed to check existing LLM provider")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to check existing LLM provider",
+		})
+	}
+
+	if existing != nil {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "LLM provider already exists",
+		})
+	}
+
+	if provider.Enabled && !provider.IsDefault {
+		current, err := repo.GetDefaultLLMProvider(ctx, tenantID)
+		if err != nil {
+			h.logger.WithContext(ctx).Error().
+				Err(err).
+				Str("tenant_id", tenantID).
+				Msg("failed to get default LLM provider")
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed to get default LLM provider",
+			})
+		}
+		provider.IsDefault = current == nil
+	}
+
+	if err := repo.CreateLLMProvider(ctx, provider); err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Str("name", provider.Name).
+			Msg("failed to create LLM provider")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to create LLM provider",
+		})
+	}
+
+	h.logger.WithContext(ctx).Info().
+		Str("tenant_id", tenantID).
+		Str("name", provider.Name).
+		Str("provider_type", provider.Provider).
+		Bool("is_default", provider.IsDefault).
+		Msg("LLM provider created")
+
+	if !h.reloadTenant(c, tenantID) {
+		return nil
+	}
+
+	return c.JSON(http.StatusCreated, maskProvider(*provider))
+}
+
+// UpdateLLMProvider updates an LLM provider of a tenant. Masked API keys and
+// config secrets are left unchanged.
+func (h *TenantsHandler) UpdateLLMProvider(c echo.Context) error {
+	tenantID := c.Param("tenant_id")
+
+	var req UpdateLLMProviderRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	repo, ok := h.tenantRepository(c, tenantID)
+	if !ok {
+		return nil
+	}
+
+	provider, ok := h.getLLMProvider(c, repo, tenantID, c.Param("name"))
+	if !ok {
+		return nil
+	}
+	wasDefault := provider.IsDefault
+
+	// Update fields if provided
+	if req.Provider != nil {
+		provider.Provider = *req.Provider
+	}
+	if req.APIKey != nil && !isMasked(*req.APIKey) {
+		provider.APIKey = *req.APIKey
+	}
+	if req.BaseURL != nil {
+		provider.BaseURL = *req.BaseURL
+	}
+	if req.ModelChat != nil {
+		provider.ModelChat = *req.ModelChat
+	}
+	if req.ModelEmbed != nil {
+		provider.ModelEmbed = *req.ModelEmbed
+	}
+	if req.Config != nil {
+		restoreSecrets(req.Config, provider.Config)
+		provider.Config = req.Config
+	}
+	if req.IsDefault != nil {
+		provider.IsDefault = *req.IsDefault
+	}
+	if req.Enabled != nil {
+		provider.Enabled = *req.Enabled
+	}
+
+	if err := validateProvider(provider); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if wasDefault && !provider.IsDefault {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "make another provider the default instead of unsetting the default",
+		})
+	}
+
+	return h.saveLLMProvider(c, repo, provider, "LLM provider updated")
+}
+
+// SetDefaultLLMProvider makes an enabled LLM provider the tenant's default,
+// which is tried first and used for embeddings and transcription
+func (h *TenantsHandler) SetDefaultLLMProvider(c echo.Context) error {
+	tenantID := c.Param("tenant_id")
+
+	repo, ok := h.tenantRepository(c, tenantID)
+	if !ok {
+		return nil
+	}
+
+	provider, ok := h.getLLMProvider(c, repo, tenantID, c.Param("name"))
+	if !ok {
+		return nil
+	}
+
+	if !provider.Enabled {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "a disabled provider cannot be the default",
+		})
+	}
+
+	provider.IsDefault = true
+	return h.saveLLMProvider(c, repo, provider, "default LLM provider changed")
+}
+
+// saveLLMProvider stores an updated LLM provider and reloads its tenant
+func (h *TenantsHandler) saveLLMProvider(c echo.Context, repo domain.Repository, provider *domain.LLMProviderConfig, message string) error {
+	ctx := c.Request().Context()
+
+	if err := repo.UpdateLLMProvider(ctx, provider); err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", provider.TenantID).
+			Str("name", provider.Name).
+			Msg("failed to update LLM provider")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to update LLM provider",
+		})
+	}
+
+	h.logger.WithContext(ctx).Info().
+		Str("tenant_id", provider.TenantID).
+		Str("name", provider.Name).
+		Bool("is_default", provider.IsDefault).
+		Bool("enabled", provider.Enabled).
+		Msg(message)
+
+	if !h.reloadTenant(c, provider.TenantID) {
+		return nil
+	}
+
+	return c.JSON(http.StatusOK, maskProvider(*provider))
+}
+
+// getLLMProvider returns an LLM provider of a tenant, writing the error
+// response and returning false when it cannot be found
+func (h *TenantsHandler) getLLMProvider(c echo.Context, repo domain.Repository, tenantID, name string) (*domain.LLMProviderConfig, bool) {
+	ctx := c.Request().Context()
+
+	provider, err := repo.GetLLMProvider(ctx, tenantID, name)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Str("name", name).
+			Msg("failed to get LLM provider")
+		c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to get LLM provider",
+		})
+		return nil, false
+	}
+
+	if provider == nil {
+		c.JSON(http.StatusNotFound, map[string]string{
+			"error": "LLM provider not found",
+		})
+		return nil, false
+	}
+
+	return provider, true
+}
+
+// validateProvider checks the fields of an LLM provider configuration
+func validateProvider(provider *domain.LLMProviderConfig) error {
+	if !contains(knownProviders, provider.Provider) {
+		return fmt.Errorf("provider must be one of %v", knownProviders)
+	}
+	if provider.ModelChat == "" {
+		return errors.New("model_chat is required")
+	}
+	if provider.APIKey == "" && !contains(keylessProviders, provider.Provider) {
+		return fmt.Errorf("api_key is required for %s providers", provider.Provider)
+	}
+	if provider.BaseURL != "" {
+		if err := validateBaseURL("base_url", provider.BaseURL); err != nil {
+			return err
+		}
+	}
+	if provider.IsDefault && !provider.Enabled {
+		return errors.New("the default provider cannot be disabled; make another provider the default first")
+	}
+	return nil
+}
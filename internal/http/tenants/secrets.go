@@ -0,0 +1,91 @@
+package tenants
+
+import (
+	"strings"
+
+	"personal-assistant/internal/domain"
+)
+
+// maskPrefix replaces all but the last characters of secrets in responses.
+// Masked values sent back in an update keep the stored secret.
+const maskPrefix = "****"
+
+// secretMarkers flag config and auth keys holding secrets
+var secretMarkers = []string{"secret", "password", "token", "api_key", "apikey", "credential"}
+
+// isSecretKey reports whether a config or auth key holds a secret
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, marker := range secretMarkers {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskSecret hides a secret, keeping its last four characters when it is long
+// enough for them not to give it away
+func maskSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) < 12 {
+		return maskPrefix
+	}
+	return maskPrefix + secret[len(secret)-4:]
+}
+
+// isMasked reports whether a value is a secret masked by maskSecret
+func isMasked(value string) bool {
+	return strings.HasPrefix(value, maskPrefix)
+}
+
+// maskMap returns a copy of a config or auth map with its secret values masked
+func maskMap(values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+
+	masked := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if secret, ok := value.(string); ok && isSecretKey(key) {
+			value = maskSecret(secret)
+		}
+		masked[key] = value
+	}
+	return masked
+}
+
+// restoreSecrets replaces masked values of an updated map with the stored secrets
+func restoreSecrets(updated, stored map[string]interface{}) {
+	for key, value := range updated {
+		if masked, ok := value.(string); ok && isSecretKey(key) && isMasked(masked) {
+			if previous, found := stored[key]; found {
+				updated[key] = previous
+			} else {
+				delete(updated, key)
+			}
+		}
+	}
+}
+
+// maskTenant returns a copy of a tenant configuration safe to return
+func maskTenant(config domain.TenantConfig) domain.TenantConfig {
+	config.Config = maskMap(config.Config)
+	return config
+}
+
+// maskProvider returns a copy of an LLM provider configuration safe to return
+func maskProvider(provider domain.LLMProviderConfig) domain.LLMProviderConfig {
+	provider.APIKey = maskSecret(provider.APIKey)
+	provider.Config = maskMap(provider.Config)
+	return provider
+}
+
+// maskService returns a copy of an external service safe to return
+func maskService(service domain.ExternalService) domain.ExternalService {
+	service.Auth = maskMap(service.Auth)
+	service.Config = maskMap(service.Config)
+	return service
+}
@@ -0,0 +1,284 @@
+package tenants
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"personal-assistant/internal/domain"
+)
+
+// CreateExternalServiceRequest represents a request to add an external service to a tenant
+type CreateExternalServiceRequest struct {
+	Name    string                 `json:"name" validate:"required"`
+	BaseURL string                 `json:"base_url" validate:"required"`
+	Auth    map[string]interface{} `json:"auth"`
+	Config  map[string]interface{} `json:"config"`
+}
+
+// UpdateExternalServiceRequest represents a request to update an external service; omitted fields are left unchanged
+type UpdateExternalServiceRequest struct {
+	BaseURL *string                `json:"base_url"`
+	Auth    map[string]interface{} `json:"auth"`
+	Config  map[string]interface{} `json:"config"`
+}
+
+// ListExternalServices returns the external services a tenant's call_api tool can reach
+func (h *TenantsHandler) ListExternalServices(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenant_id")
+
+	repo, ok := h.tenantRepository(c, tenantID)
+	if !ok {
+		return nil
+	}
+
+	services, err := repo.GetExternalServices(ctx, tenantID)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Msg("failed to list external services")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to list external services",
+		})
+	}
+
+	masked := make([]domain.ExternalService, len(services))
+	for i, service := range services {
+		masked[i] = maskService(service)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"external_services": masked,
+		"count":             len(masked),
+	})
+}
+
+// GetExternalService returns an external service of a tenant
+func (h *TenantsHandler) GetExternalService(c echo.Context) error {
+	tenantID := c.Param("tenant_id")
+
+	repo, ok := h.tenantRepository(c, tenantID)
+	if !ok {
+		return nil
+	}
+
+	service, ok := h.getExternalService(c, repo, tenantID, c.Param("name"))
+	if !ok {
+		return nil
+	}
+
+	return c.JSON(http.StatusOK, maskService(*service))
+}
+
+// CreateExternalService adds an external service to a tenant
+func (h *TenantsHandler) CreateExternalService(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenant_id")
+
+	var req CreateExternalServiceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	service := &domain.ExternalService{
+		ID:       uuid.New(),
+		TenantID: tenantID,
+		Name:     req.Name,
+		BaseURL:  req.BaseURL,
+		Auth:     req.Auth,
+		Config:   req.Config,
+	}
+	if service.Auth == nil {
+		service.Auth = map[string]interface{}{}
+	}
+	if service.Config == nil {
+		service.Config = map[string]interface{}{}
+	}
+
+	if service.Name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "name is required",
+		})
+	}
+	if err := validateService(service); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	repo, ok := h.tenantRepository(c, tenantID)
+	if !ok {
+		return nil
+	}
+
+	existing, err := repo.GetExternalService(ctx, tenantID, service.Name)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Str("name", service.Name).
+			Msg("failed to check existing external service")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to check existing external service",
+		})
+	}
+
+	if existing != nil {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "external service already exists",
+		})
+	}
+
+	if err := repo.CreateExternalService(ctx, service); err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Str("name", service.Name).
+			Msg("failed to create external service")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to create external service",
+		})
+	}
+
+	h.logger.WithContext(ctx).Info().
+		Str("tenant_id", tenantID).
+		Str("name", service.Name).
+		Msg("external service created")
+
+	if !h.reloadTenant(c, tenantID) {
+		return nil
+	}
+
+	return c.JSON(http.StatusCreated, maskService(*service))
+}
+
+// UpdateExternalService updates an external service of a tenant. Masked auth
+// and config secrets are left unchanged.
+func (h *TenantsHandler) UpdateExternalService(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenant_id")
+
+	var req UpdateExternalServiceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	repo, ok := h.tenantRepository(c, tenantID)
+	if !ok {
+		return nil
+	}
+
+	service, ok := h.getExternalService(c, repo, tenantID, c.Param("name"))
+	if !ok {
+		return nil
+	}
+
+	// Update fields if provided
+	if req.BaseURL != nil {
+		service.BaseURL = *req.BaseURL
+	}
+	if req.Auth != nil {
+		restoreSecrets(req.Auth, service.Auth)
+		service.Auth = req.Auth
+	}
+	if req.Config != nil {
+		restoreSecrets(req.Config, service.Config)
+		service.Config = req.Config
+	}
+
+	if err := validateService(service); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := repo.UpdateExternalService(ctx, service); err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Str("name", service.Name).
+			Msg("failed to update external service")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to update external service",
+		})
+	}
+
+	h.logger.WithContext(ctx).Info().
+		Str("tenant_id", tenantID).
+		Str("name", service.Name).
+		Msg("external service updated")
+
+	if !h.reloadTenant(c, tenantID) {
+		return nil
+	}
+
+	return c.JSON(http.StatusOK, maskService(*service))
+}
+
+// getExternalService returns an external service of a tenant, writing the
+// error response and returning false when it cannot be found
+func (h *TenantsHandler) getExternalService(c echo.Context, repo domain.Repository, tenantID, name string) (*domain.ExternalService, bool) {
+	ctx := c.Request().Context()
+
+	service, err := repo.GetExternalService(ctx, tenantID, name)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Str("name", name).
+			Msg("failed to get external service")
+		c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to get external service",
+		})
+		return nil, false
+	}
+
+	if service == nil {
+		c.JSON(http.StatusNotFound, map[string]string{
+			"error": "external service not found",
+		})
+		return nil, false
+	}
+
+	return service, true
+}
+
+// validateService checks the base URL and the auth settings read by the call_api tool
+func validateService(service *domain.ExternalService) error {
+	if err := validateBaseURL("base_url", service.BaseURL); err != nil {
+		return err
+	}
+
+	authType, found := service.Auth["type"]
+	if !found {
+		return nil
+	}
+
+	var required []string
+	switch authType {
+	case "bearer":
+		required = []string{"token"}
+	case "api_key":
+		required = []string{"api_key"}
+	case "basic":
+		required = []string{"username", "password"}
+	default:
+		return errors.New("auth type must be bearer, api_key or basic")
+	}
+
+	for _, field := range required {
+		if value, _ := service.Auth[field].(string); value == "" {
+			return fmt.Errorf("auth %s is required for %v auth", field, authType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,541 @@
+package tenants
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"personal-assistant/internal/allowlist"
+	"personal-assistant/internal/auth"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+var (
+	// tenantIDPattern limits tenant IDs to characters that are safe in URLs
+	tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,255}$`)
+	// wabaNumberPattern matches WABA numbers in international format
+	wabaNumberPattern = regexp.MustCompile(`^\+?[0-9]{6,20}$`)
+
+	// knownAgents are the agents a tenant can enable
+	knownAgents = []string{"db_agent", "http_agent", "orchestrator"}
+	// knownVectorStores are the vector stores accepted by tenants_config
+	knownVectorStores = []string{"pgvector", "sql_fallback"}
+)
+
+// TenantsHandler handles HTTP requests for managing tenants, their LLM
+// providers and their external services
+type TenantsHandler struct {
+	tenantManager domain.TenantManager
+	logger        *log.Logger
+}
+
+// NewTenantsHandler creates a new tenants handler
+func NewTenantsHandler(tenantManager domain.TenantManager, logger *log.Logger) *TenantsHandler {
+	return &TenantsHandler{
+		tenantManager: tenantManager,
+		logger:        logger,
+	}
+}
+
+// CreateTenantRequest represents a request to create a tenant
+type CreateTenantRequest struct {
+	TenantID       string                 `json:"tenant_id" validate:"required"`
+	WABANumber     string                 `json:"waba_number" validate:"required"`
+	EmbeddingModel string                 `json:"embedding_model"`
+	VectorStore    string                 `json:"vector_store"`
+	EnabledAgents  []string               `json:"enabled_agents"`
+	Config         map[string]interface{} `json:"config"`
+	Metadata       map[string]interface{} `json:"metadata"`
+}
+
+// UpdateTenantRequest represents a request to update a tenant; omitted fields are left unchanged
+type UpdateTenantRequest struct {
+	WABANumber     *string                `json:"waba_number"`
+	EmbeddingModel *string                `json:"embedding_model"`
+	VectorStore    *string                `json:"vector_store"`
+	EnabledAgents  []string               `json:"enabled_agents"`
+	Config         map[string]interface{} `json:"config"`
+	Metadata       map[string]interface{} `json:"metadata"`
+	Enabled        *bool                  `json:"enabled"`
+}
+
+// ListTenants returns the enabled tenants
+func (h *TenantsHandler) ListTenants(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	configRepo, ok := h.configRepository(c)
+	if !ok {
+		return nil
+	}
+
+	configs, err := configRepo.GetTenantsConfig(ctx)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().Err(err).Msg("failed to list tenants")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to list tenants",
+		})
+	}
+
+	tenants := make([]domain.TenantConfig, len(configs))
+	for i, config := range configs {
+		tenants[i] = maskTenant(config)
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"tenants": tenants,
+		"count":   len(tenants),
+	})
+}
+
+// GetTenant returns a tenant, including a disabled one
+func (h *TenantsHandler) GetTenant(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenant_id")
+
+	configRepo, ok := h.configRepository(c)
+	if !ok {
+		return nil
+	}
+
+	config, err := configRepo.GetTenantConfig(ctx, tenantID)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Msg("failed to get tenant")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to get tenant",
+		})
+	}
+
+	if config == nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "tenant not found",
+		})
+	}
+
+	return c.JSON(http.StatusOK, maskTenant(*config))
+}
+
+// CreateTenant creates a new tenant
+func (h *TenantsHandler) CreateTenant(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if !requireGlobalKey(c) {
+		return nil
+	}
+
+	var req CreateTenantRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	config := &domain.TenantConfig{
+		ID:             uuid.New(),
+		TenantID:       req.TenantID,
+		WABANumber:     req.WABANumber,
+		EmbeddingModel: req.EmbeddingModel,
+		VectorStore:    req.VectorStore,
+		EnabledAgents:  req.EnabledAgents,
+		Config:         req.Config,
+		Metadata:       req.Metadata,
+		Enabled:        true,
+	}
+
+	// Apply the defaults of tenants_config
+	if config.EmbeddingModel == "" {
+		config.EmbeddingModel = "text-embedding-ada-002"
+	}
+	if config.VectorStore == "" {
+		config.VectorStore = "pgvector"
+	}
+	if config.EnabledAgents == nil {
+		config.EnabledAgents = knownAgents
+	}
+	if config.Config == nil {
+		config.Config = map[string]interface{}{}
+	}
+	if config.Metadata == nil {
+		config.Metadata = map[string]interface{}{}
+	}
+
+	if !tenantIDPattern.MatchString(config.TenantID) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required and may only contain letters, digits, _ and -",
+		})
+	}
+	if err := validateTenant(config); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	configRepo, ok := h.configRepository(c)
+	if !ok {
+		return nil
+	}
+
+	existing, err := configRepo.GetTenantConfig(ctx, config.TenantID)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", config.TenantID).
+			Msg("failed to check existing tenant")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to check existing tenant",
+		})
+	}
+
+	if existing != nil {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "tenant already exists",
+		})
+	}
+
+	if !h.wabaNumberAvailable(c, configRepo, config) {
+		return nil
+	}
+
+	if err := configRepo.CreateTenantConfig(ctx, config); err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", config.TenantID).
+			Msg("failed to create tenant")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to create tenant",
+		})
+	}
+
+	h.logger.WithContext(ctx).Info().
+		Str("tenant_id", config.TenantID).
+		Str("waba_number", config.WABANumber).
+		Msg("tenant created")
+
+	if !h.reloadTenant(c, config.TenantID) {
+		return nil
+	}
+
+	return c.JSON(http.StatusCreated, maskTenant(*config))
+}
+
+// UpdateTenant updates a tenant; setting enabled to true enables a disabled tenant again
+func (h *TenantsHandler) UpdateTenant(c echo.Context) error {
+	tenantID := c.Param("tenant_id")
+
+	if !requireGlobalKey(c) {
+		return nil
+	}
+
+	var req UpdateTenantRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request body",
+		})
+	}
+
+	configRepo, ok := h.configRepository(c)
+	if !ok {
+		return nil
+	}
+
+	config, ok := h.getTenantConfig(c, configRepo, tenantID)
+	if !ok {
+		return nil
+	}
+
+	// Update fields if provided
+	if req.WABANumber != nil {
+		config.WABANumber = *req.WABANumber
+	}
+	if req.EmbeddingModel != nil {
+		config.EmbeddingModel = *req.EmbeddingModel
+	}
+	if req.VectorStore != nil {
+		config.VectorStore = *req.VectorStore
+	}
+	if req.EnabledAgents != nil {
+		config.EnabledAgents = req.EnabledAgents
+	}
+	if req.Config != nil {
+		restoreSecrets(req.Config, config.Config)
+		config.Config = req.Config
+	}
+	if req.Metadata != nil {
+		config.Metadata = req.Metadata
+	}
+	if req.Enabled != nil {
+		config.Enabled = *req.Enabled
+	}
+
+	if err := validateTenant(config); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if !h.wabaNumberAvailable(c, configRepo, config) {
+		return nil
+	}
+
+	return h.saveTenant(c, configRepo, config, "tenant updated")
+}
+
+// DisableTenant disables a tenant, keeping its configuration and data
+func (h *TenantsHandler) DisableTenant(c echo.Context) error {
+	tenantID := c.Param("tenant_id")
+
+	if !requireGlobalKey(c) {
+		return nil
+	}
+
+	configRepo, ok := h.configRepository(c)
+	if !ok {
+		return nil
+	}
+
+	config, ok := h.getTenantConfig(c, configRepo, tenantID)
+	if !ok {
+		return nil
+	}
+
+	config.Enabled = false
+	return h.saveTenant(c, configRepo, config, "tenant disabled")
+}
+
+// saveTenant stores an updated tenant configuration and reloads the tenant
+func (h *TenantsHandler) saveTenant(c echo.Context, configRepo domain.Repository, config *domain.TenantConfig, message string) error {
+	ctx := c.Request().Context()
+
+	if err := configRepo.UpdateTenantConfig(ctx, config); err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", config.TenantID).
+			Msg("failed to update tenant")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to update tenant",
+		})
+	}
+
+	h.logger.WithContext(ctx).Info().
+		Str("tenant_id", config.TenantID).
+		Bool("enabled", config.Enabled).
+		Msg(message)
+
+	if !h.reloadTenant(c, config.TenantID) {
+		return nil
+	}
+
+	return c.JSON(http.StatusOK, maskTenant(*config))
+}
+
+// getTenantConfig returns a stored tenant configuration, writing the error
+// response and returning false when it cannot be found
+func (h *TenantsHandler) getTenantConfig(c echo.Context, configRepo domain.Repository, tenantID string) (*domain.TenantConfig, bool) {
+	ctx := c.Request().Context()
+
+	config, err := configRepo.GetTenantConfig(ctx, tenantID)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Msg("failed to get tenant")
+		c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to get tenant",
+		})
+		return nil, false
+	}
+
+	if config == nil {
+		c.JSON(http.StatusNotFound, map[string]string{
+			"error": "tenant not found",
+		})
+		return nil, false
+	}
+
+	return config, true
+}
+
+// wabaNumberAvailable checks that no other enabled tenant uses the WABA
+// number of a tenant, writing the error response and returning false otherwise
+func (h *TenantsHandler) wabaNumberAvailable(c echo.Context, configRepo domain.Repository, config *domain.TenantConfig) bool {
+	ctx := c.Request().Context()
+
+	owner, err := configRepo.GetTenantConfigByWABA(ctx, config.WABANumber)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("waba_number", config.WABANumber).
+			Msg("failed to check WABA number")
+		c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to check WABA number",
+		})
+		return false
+	}
+
+	if owner != nil && owner.TenantID != config.TenantID {
+		c.JSON(http.StatusConflict, map[string]string{
+			"error": "waba_number is used by another tenant",
+		})
+		return false
+	}
+
+	return true
+}
+
+// configRepository returns the repository holding tenant configurations,
+// writing the error response and returning false when there is none
+func (h *TenantsHandler) configRepository(c echo.Context) (domain.Repository, bool) {
+	configRepo, err := h.tenantManager.GetConfigRepository()
+	if errors.Is(err, domain.ErrTenantConfigReadOnly) {
+		c.JSON(http.StatusConflict, map[string]string{
+			"error": "tenants are configured in the tenants YAML file; set TENANT_CONFIG_SOURCE=database to manage them through the API",
+		})
+		return nil, false
+	}
+	if err != nil {
+		h.logger.WithContext(c.Request().Context()).Error().Err(err).Msg("failed to get tenant config repository")
+		c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to get tenant config repository",
+		})
+		return nil, false
+	}
+
+	return configRepo, true
+}
+
+// tenantRepository returns the repository of a loaded tenant, writing the
+// error response and returning false when the tenant is unknown or disabled
+func (h *TenantsHandler) tenantRepository(c echo.Context, tenantID string) (domain.Repository, bool) {
+	if _, err := h.tenantManager.GetTenantByID(tenantID); err != nil {
+		c.JSON(http.StatusNotFound, map[string]string{
+			"error": "tenant not found",
+		})
+		return nil, false
+	}
+
+	repo, err := h.tenantManager.GetRepository(tenantID)
+	if err != nil {
+		h.logger.WithContext(c.Request().Context()).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Msg("failed to get repository")
+		c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to get tenant repository",
+		})
+		return nil, false
+	}
+
+	return repo, true
+}
+
+// reloadTenant applies a saved change of a tenant to the running tenants,
+// writing the error response and returning false when they cannot be reloaded
+func (h *TenantsHandler) reloadTenant(c echo.Context, tenantID string) bool {
+	if err := h.tenantManager.ReloadTenant(tenantID); err != nil {
+		h.logger.WithContext(c.Request().Context()).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Msg("failed to reload tenant")
+		c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "changes were saved but tenants could not be reloaded",
+		})
+		return false
+	}
+	return true
+}
+
+// requireGlobalKey rejects requests authenticated with a tenant API key,
+// writing the error response and returning false
+func requireGlobalKey(c echo.Context) bool {
+	if key := auth.KeyFromContext(c); key != nil && !key.IsGlobal() {
+		c.JSON(http.StatusForbidden, map[string]string{
+			"error": "a global API key is required to manage tenants",
+		})
+		return false
+	}
+	return true
+}
+
+// validateTenant checks the fields of a tenant configuration
+func validateTenant(config *domain.TenantConfig) error {
+	if !wabaNumberPattern.MatchString(config.WABANumber) {
+		return errors.New("waba_number must be a phone number in international format")
+	}
+	if config.EmbeddingModel == "" {
+		return errors.New("embedding_model must not be empty")
+	}
+	if !contains(knownVectorStores, config.VectorStore) {
+		return fmt.Errorf("vector_store must be one of %v", knownVectorStores)
+	}
+	for _, agent := range config.EnabledAgents {
+		if !contains(knownAgents, agent) {
+			return fmt.Errorf("unknown agent: %s", agent)
+		}
+	}
+	return validateTenantSettings(config.Config)
+}
+
+// validateTenantSettings checks the tenant config keys read by the assistant
+func validateTenantSettings(settings map[string]interface{}) error {
+	if value, found := settings[allowlist.UnknownSenderActionKey]; found {
+		switch value {
+		case domain.UnknownSenderIgnore, domain.UnknownSenderNotifyAdmin, domain.UnknownSenderAutoAdd:
+		default:
+			return fmt.Errorf("%s must be ignore, notify_admin or auto_add", allowlist.UnknownSenderActionKey)
+		}
+	}
+
+	if value, found := settings[allowlist.RequireAllowlistKey]; found {
+		switch v := value.(type) {
+		case bool:
+		case string:
+			if _, err := strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("%s must be a boolean", allowlist.RequireAllowlistKey)
+			}
+		default:
+			return fmt.Errorf("%s must be a boolean", allowlist.RequireAllowlistKey)
+		}
+	}
+
+	if value, found := settings[allowlist.DefaultPermissionsKey]; found {
+		permissions, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be a list of permissions", allowlist.DefaultPermissionsKey)
+		}
+		for _, item := range permissions {
+			if permission, _ := item.(string); !domain.IsValidPermission(permission) {
+				return fmt.Errorf("unknown permission in %s: %v", allowlist.DefaultPermissionsKey, item)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateBaseURL checks that a base URL is an absolute HTTP(S) URL
+func validateBaseURL(field, baseURL string) error {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%s must be an absolute http or https URL", field)
+	}
+	return nil
+}
+
+// contains reports whether a list holds a value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
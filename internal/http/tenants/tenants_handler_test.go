@@ -0,0 +1,207 @@
+package tenants
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/auth"
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+// MockRepository implements the tenant configuration lookups used by the handler
+type MockRepository struct {
+	domain.Repository
+	mock.Mock
+}
+
+func (m *MockRepository) GetTenantConfig(ctx context.Context, tenantID string) (*domain.TenantConfig, error) {
+	args := m.Called(ctx, tenantID)
+	config, _ := args.Get(0).(*domain.TenantConfig)
+	return config, args.Error(1)
+}
+
+func (m *MockRepository) GetTenantConfigByWABA(ctx context.Context, wabaNumber string) (*domain.TenantConfig, error) {
+	args := m.Called(ctx, wabaNumber)
+	config, _ := args.Get(0).(*domain.TenantConfig)
+	return config, args.Error(1)
+}
+
+func (m *MockRepository) CreateTenantConfig(ctx context.Context, config *domain.TenantConfig) error {
+	args := m.Called(ctx, config)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateTenantConfig(ctx context.Context, config *domain.TenantConfig) error {
+	args := m.Called(ctx, config)
+	return args.Error(0)
+}
+
+// MockTenantManager implements the tenant manager methods used by the handler
+type MockTenantManager struct {
+	domain.TenantManager
+	mock.Mock
+}
+
+func (m *MockTenantManager) GetConfigRepository() (domain.Repository, error) {
+	args := m.Called()
+	repo, _ := args.Get(0).(domain.Repository)
+	return repo, args.Error(1)
+}
+
+func (m *MockTenantManager) ReloadTenant(tenantID string) error {
+	args := m.Called(tenantID)
+	return args.Error(0)
+}
+
+// MockKeyStore implements the key lookups used by the authenticator
+type MockKeyStore struct {
+	domain.APIKeyStore
+	mock.Mock
+}
+
+func (m *MockKeyStore) GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	args := m.Called(ctx, hash)
+	key, _ := args.Get(0).(*domain.APIKey)
+	return key, args.Error(1)
+}
+
+const (
+	adminKey  = "pa_admin"
+	tenantKey = "pa_tenant"
+)
+
+// newServer returns an echo server serving the tenant routes behind the API
+// key authenticator, accepting the admin key and a key of tenant-a
+func newServer(manager domain.TenantManager) *echo.Echo {
+	store := &MockKeyStore{}
+	store.On("GetAPIKeyByHash", mock.Anything, auth.HashKey(tenantKey)).Return(&domain.APIKey{
+		Name:     "tenant-a",
+		TenantID: "tenant-a",
+		Scopes:   []string{domain.APIScopeRead, domain.APIScopeWrite},
+	}, nil)
+
+	handler := NewTenantsHandler(manager, log.Init("error"))
+
+	e := echo.New()
+	api := e.Group("/api/v1")
+	api.Use(auth.NewAuthenticator(store, &config.AuthConfig{Enabled: true, AdminKey: adminKey}, log.Init("error")).Middleware)
+	api.POST("/tenants", handler.CreateTenant)
+	api.PUT("/tenants/:tenant_id", handler.UpdateTenant)
+	return e
+}
+
+func serve(e *echo.Echo, method, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+key)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMaskMap_RestoreSecrets(t *testing.T) {
+	stored := map[string]interface{}{
+		"api_key":       "sk-0123456789abcdef",
+		"client_secret": "short",
+		"base_url":      "https://api.example.com",
+		"retries":       float64(3),
+	}
+
+	masked := maskMap(stored)
+	assert.Equal(t, "****cdef", masked["api_key"])
+	assert.Equal(t, maskPrefix, masked["client_secret"])
+	assert.Equal(t, "https://api.example.com", masked["base_url"])
+	assert.Equal(t, float64(3), masked["retries"])
+	assert.Equal(t, "sk-0123456789abcdef", stored["api_key"], "the stored map must not be masked")
+
+	t.Run("masked values sent back keep the stored secrets", func(t *testing.T) {
+		updated := maskMap(stored)
+		updated["base_url"] = "https://eu.example.com"
+
+		restoreSecrets(updated, stored)
+		assert.Equal(t, map[string]interface{}{
+			"api_key":       "sk-0123456789abcdef",
+			"client_secret": "short",
+			"base_url":      "https://eu.example.com",
+			"retries":       float64(3),
+		}, updated)
+	})
+
+	t.Run("new secrets replace the stored ones", func(t *testing.T) {
+		updated := maskMap(stored)
+		updated["api_key"] = "sk-new"
+
+		restoreSecrets(updated, stored)
+		assert.Equal(t, "sk-new", updated["api_key"])
+		assert.Equal(t, "short", updated["client_secret"])
+	})
+
+	t.Run("masked values without a stored secret are dropped", func(t *testing.T) {
+		updated := map[string]interface{}{"access_token": "****abcd"}
+
+		restoreSecrets(updated, stored)
+		assert.NotContains(t, updated, "access_token")
+	})
+}
+
+func TestTenantsHandler_RequireGlobalKey(t *testing.T) {
+	manager := &MockTenantManager{}
+	e := newServer(manager)
+
+	rec := serve(e, http.MethodPut, "/api/v1/tenants/tenant-a", tenantKey, `{"enabled": false}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "global API key")
+	manager.AssertNotCalled(t, "GetConfigRepository")
+}
+
+func TestTenantsHandler_WABACollision(t *testing.T) {
+	tenantA := &domain.TenantConfig{
+		TenantID:       "tenant-a",
+		WABANumber:     "15550000001",
+		EmbeddingModel: "text-embedding-ada-002",
+		VectorStore:    "pgvector",
+		Config:         map[string]interface{}{},
+		Enabled:        true,
+	}
+
+	setup := func() (*MockRepository, *MockTenantManager, *echo.Echo) {
+		repo := &MockRepository{}
+		manager := &MockTenantManager{}
+		manager.On("GetConfigRepository").Return(repo, nil)
+		return repo, manager, newServer(manager)
+	}
+
+	t.Run("rejects creating a tenant with a WABA number in use", func(t *testing.T) {
+		repo, manager, e := setup()
+		repo.On("GetTenantConfig", mock.Anything, "tenant-b").Return(nil, nil)
+		repo.On("GetTenantConfigByWABA", mock.Anything, "15550000001").Return(tenantA, nil)
+
+		rec := serve(e, http.MethodPost, "/api/v1/tenants", adminKey, `{"tenant_id": "tenant-b", "waba_number": "15550000001"}`)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		repo.AssertNotCalled(t, "CreateTenantConfig", mock.Anything, mock.Anything)
+		manager.AssertNotCalled(t, "ReloadTenant", mock.Anything)
+	})
+
+	t.Run("lets a tenant keep its own WABA number", func(t *testing.T) {
+		repo, manager, e := setup()
+		stored := *tenantA
+		repo.On("GetTenantConfig", mock.Anything, "tenant-a").Return(&stored, nil)
+		repo.On("GetTenantConfigByWABA", mock.Anything, "15550000001").Return(tenantA, nil)
+		repo.On("UpdateTenantConfig", mock.Anything, mock.Anything).Return(nil)
+		manager.On("ReloadTenant", "tenant-a").Return(nil)
+
+		rec := serve(e, http.MethodPut, "/api/v1/tenants/tenant-a", adminKey, `{"embedding_model": "text-embedding-3-small"}`)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		manager.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,171 @@
+package usage
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+const (
+	// dateLayout is the format of the from and to query parameters
+	dateLayout = "2006-01-02"
+	// defaultDays is the number of days reported when no range is given
+	defaultDays = 30
+	// maxDays is the longest range that can be reported at once
+	maxDays = 366
+)
+
+// UsageHandler handles HTTP requests for LLM token usage and cost reports
+type UsageHandler struct {
+	tenantManager domain.TenantManager
+	logger        *log.Logger
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(tenantManager domain.TenantManager, logger *log.Logger) *UsageHandler {
+	return &UsageHandler{
+		tenantManager: tenantManager,
+		logger:        logger,
+	}
+}
+
+// UsageTotals represents the usage and cost summed over a report
+type UsageTotals struct {
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	EmbeddingTokens  int     `json:"embedding_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// GetTenantUsage returns the daily usage and cost of a tenant per user,
+// provider and model, limited to one user by the optional user_id query parameter
+func (h *UsageHandler) GetTenantUsage(c echo.Context) error {
+	var userID *uuid.UUID
+	if userIDStr := c.QueryParam("user_id"); userIDStr != "" {
+		parsed, err := uuid.Parse(userIDStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "invalid user_id format",
+			})
+		}
+		userID = &parsed
+	}
+
+	return h.report(c, userID)
+}
+
+// GetUserUsage returns the daily usage and cost of a user per provider and model
+func (h *UsageHandler) GetUserUsage(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid user_id format",
+		})
+	}
+
+	return h.report(c, &userID)
+}
+
+// report responds with the usage of the tenant in the requested date range
+func (h *UsageHandler) report(c echo.Context, userID *uuid.UUID) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("tenant_id")
+
+	if tenantID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "tenant_id is required",
+		})
+	}
+
+	from, to, err := parseRange(c.QueryParam("from"), c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Get repository for the tenant
+	repo, err := h.tenantManager.GetRepository(tenantID)
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Msg("failed to get repository")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to get tenant repository",
+		})
+	}
+
+	// The range is inclusive of the to date
+	days, err := repo.GetDailyLLMUsage(ctx, tenantID, userID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		h.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("tenant_id", tenantID).
+			Msg("failed to get LLM usage")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to get LLM usage",
+		})
+	}
+
+	var totals UsageTotals
+	for _, day := range days {
+		totals.Calls += day.Calls
+		totals.PromptTokens += day.PromptTokens
+		totals.CompletionTokens += day.CompletionTokens
+		totals.EmbeddingTokens += day.EmbeddingTokens
+		totals.Cost += day.Cost
+	}
+
+	response := map[string]interface{}{
+		"tenant_id": tenantID,
+		"from":      from.Format(dateLayout),
+		"to":        to.Format(dateLayout),
+		"days":      days,
+		"totals":    totals,
+	}
+	if userID != nil {
+		response["user_id"] = userID
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// parseRange parses the from and to dates of a report, both inclusive.
+// Without dates the report covers the last defaultDays days.
+func parseRange(fromStr, toStr string) (time.Time, time.Time, error) {
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if toStr != "" {
+		parsed, err := time.Parse(dateLayout, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to must be a date in YYYY-MM-DD format")
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -(defaultDays - 1))
+	if fromStr != "" {
+		parsed, err := time.Parse(dateLayout, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be a date in YYYY-MM-DD format")
+		}
+		from = parsed
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("from must not be after to")
+	}
+	if to.Sub(from) >= maxDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("range must not exceed %d days", maxDays)
+	}
+
+	return from, to, nil
+}
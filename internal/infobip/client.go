@@ -3,12 +3,19 @@ package infobip
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"personal-assistant/internal/config"
 	"personal-assistant/internal/domain"
@@ -53,37 +60,139 @@ func (c *Client) SendText(ctx context.Context, from, to, text string, messageIDR
 	return c.SendMessage(ctx, message)
 }
 
+// SendButtons sends a message with quick reply buttons via WhatsApp
+func (c *Client) SendButtons(ctx context.Context, from, to, text string, buttons []domain.InfobipReplyButton, messageIDRef ...string) (*domain.InfobipMessage, error) {
+	return c.SendMessage(ctx, interactiveMessage(from, to, domain.InfobipMessageContent{
+		Text:    text,
+		Buttons: buttons,
+	}, messageIDRef))
+}
+
+// SendList sends a message with a list menu via WhatsApp
+func (c *Client) SendList(ctx context.Context, from, to, text string, list *domain.InfobipList, messageIDRef ...string) (*domain.InfobipMessage, error) {
+	return c.SendMessage(ctx, interactiveMessage(from, to, domain.InfobipMessageContent{
+		Text: text,
+		List: list,
+	}, messageIDRef))
+}
+
+// interactiveMessage builds an outgoing interactive message
+func interactiveMessage(from, to string, content domain.InfobipMessageContent, messageIDRef []string) *domain.InfobipMessage {
+	message := &domain.InfobipMessage{
+		From:    from,
+		To:      to,
+		Content: content,
+	}
+
+	if len(messageIDRef) > 0 && messageIDRef[0] != "" {
+		message.CallbackData = messageIDRef[0]
+	}
+
+	return message
+}
+
 // SendMessage sends a structured message
 func (c *Client) SendMessage(ctx context.Context, message *domain.InfobipMessage) (*domain.InfobipMessage, error) {
-	start := time.Now()
-
 	// Prepare request
-	url := fmt.Sprintf("%s/whatsapp/1/message/text", c.baseURL)
+	path, content, err := buildContent(&message.Content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interactive message: %w", err)
+	}
+	url := fmt.Sprintf("%s/whatsapp/1/message/%s", c.baseURL, path)
 
 	// Create request body
 	requestBody := map[string]interface{}{
-		"from": message.From,
-		"to":   message.To,
-		"content": map[string]interface{}{
-			"text": message.Content.Text,
-		},
+		"from":    message.From,
+		"to":      message.To,
+		"content": content,
 	}
 
 	if message.CallbackData != "" {
 		requestBody["callbackData"] = message.CallbackData
 	}
 
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
-	}
-
 	c.logger.WithContext(ctx).Debug().
 		Str("from", message.From).
 		Str("to", message.To).
 		Str("text", log.SanitizeText(message.Content.Text)).
 		Msg("sending WhatsApp message")
 
+	msgResult, err := c.post(ctx, url, requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create response message
+	return &domain.InfobipMessage{
+		From:      message.From,
+		To:        message.To,
+		MessageID: msgResult.MessageID,
+		Content:   message.Content,
+	}, nil
+}
+
+// SendTemplate sends a pre-approved template message via WhatsApp
+func (c *Client) SendTemplate(ctx context.Context, from, to string, template *domain.InfobipTemplate, messageIDRef ...string) (*domain.InfobipMessage, error) {
+	if template.Name == "" || template.Language == "" {
+		return nil, fmt.Errorf("invalid template message: name and language are required")
+	}
+
+	url := fmt.Sprintf("%s/whatsapp/1/message/template", c.baseURL)
+
+	placeholders := template.Placeholders
+	if placeholders == nil {
+		placeholders = []string{}
+	}
+
+	message := map[string]interface{}{
+		"from": from,
+		"to":   to,
+		"content": map[string]interface{}{
+			"templateName": template.Name,
+			"language":     template.Language,
+			"templateData": map[string]interface{}{
+				"body": map[string]interface{}{
+					"placeholders": placeholders,
+				},
+			},
+		},
+	}
+
+	if len(messageIDRef) > 0 && messageIDRef[0] != "" {
+		message["callbackData"] = messageIDRef[0]
+	}
+
+	c.logger.WithContext(ctx).Debug().
+		Str("from", from).
+		Str("to", to).
+		Str("template", template.Name).
+		Str("language", template.Language).
+		Msg("sending WhatsApp template message")
+
+	// The template endpoint takes a batch of messages
+	msgResult, err := c.post(ctx, url, map[string]interface{}{
+		"messages": []interface{}{message},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.InfobipMessage{
+		From:      from,
+		To:        to,
+		MessageID: msgResult.MessageID,
+	}, nil
+}
+
+// post sends a request to a message endpoint and returns the first message result
+func (c *Client) post(ctx context.Context, url string, requestBody interface{}) (*InfobipMessageResult, error) {
+	start := time.Now()
+
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
 	if err != nil {
@@ -137,21 +246,189 @@ func (c *Client) SendMessage(ctx context.Context, message *domain.InfobipMessage
 	// Get the first message result
 	msgResult := response.Messages[0]
 
-	// Create response message
-	responseMsg := &domain.InfobipMessage{
-		From:      message.From,
-		To:        message.To,
-		MessageID: msgResult.MessageID,
-		Content:   message.Content,
-	}
-
 	c.logger.WithContext(ctx).Debug().
 		Str("message_id", msgResult.MessageID).
 		Str("status", msgResult.Status.Name).
 		Dur("duration", time.Since(start)).
 		Msg("WhatsApp message sent successfully")
 
-	return responseMsg, nil
+	return &msgResult, nil
+}
+
+// buildContent returns the API path and request content for a message
+func buildContent(content *domain.InfobipMessageContent) (string, map[string]interface{}, error) {
+	if !content.IsInteractive() {
+		return "text", map[string]interface{}{
+			"text": content.Text,
+		}, nil
+	}
+
+	if content.Text == "" {
+		return "", nil, fmt.Errorf("interactive message body is required")
+	}
+	if utf8.RuneCountInString(content.Text) > domain.InfobipMaxInteractiveText {
+		return "", nil, fmt.Errorf("interactive message body exceeds %d characters", domain.InfobipMaxInteractiveText)
+	}
+
+	body := map[string]interface{}{
+		"text": content.Text,
+	}
+
+	if len(content.Buttons) > 0 {
+		if len(content.Buttons) > domain.InfobipMaxButtons {
+			return "", nil, fmt.Errorf("interactive message supports at most %d buttons, got %d", domain.InfobipMaxButtons, len(content.Buttons))
+		}
+
+		buttons := make([]map[string]interface{}, 0, len(content.Buttons))
+		for _, button := range content.Buttons {
+			if button.ID == "" || button.Title == "" {
+				return "", nil, fmt.Errorf("reply button id and title are required")
+			}
+			if utf8.RuneCountInString(button.Title) > domain.InfobipMaxButtonTitle {
+				return "", nil, fmt.Errorf("reply button title exceeds %d characters: %s", domain.InfobipMaxButtonTitle, button.Title)
+			}
+			buttons = append(buttons, map[string]interface{}{
+				"type":  "REPLY",
+				"id":    button.ID,
+				"title": button.Title,
+			})
+		}
+
+		return "interactive/buttons", map[string]interface{}{
+			"body":   body,
+			"action": map[string]interface{}{"buttons": buttons},
+		}, nil
+	}
+
+	list := content.List
+	if list.Title == "" {
+		return "", nil, fmt.Errorf("list button title is required")
+	}
+
+	rowCount := 0
+	sections := make([]map[string]interface{}, 0, len(list.Sections))
+	for _, section := range list.Sections {
+		rows := make([]map[string]interface{}, 0, len(section.Rows))
+		for _, row := range section.Rows {
+			if row.ID == "" || row.Title == "" {
+				return "", nil, fmt.Errorf("list row id and title are required")
+			}
+			entry := map[string]interface{}{
+				"id":    row.ID,
+				"title": row.Title,
+			}
+			if row.Description != "" {
+				entry["description"] = row.Description
+			}
+			rows = append(rows, entry)
+		}
+		rowCount += len(rows)
+
+		entry := map[string]interface{}{"rows": rows}
+		if section.Title != "" {
+			entry["title"] = section.Title
+		}
+		sections = append(sections, entry)
+	}
+
+	if rowCount == 0 || rowCount > domain.InfobipMaxListRows {
+		return "", nil, fmt.Errorf("list message needs between 1 and %d rows, got %d", domain.InfobipMaxListRows, rowCount)
+	}
+
+	return "interactive/list", map[string]interface{}{
+		"body": body,
+		"action": map[string]interface{}{
+			"title":    list.Title,
+			"sections": sections,
+		},
+	}, nil
+}
+
+// maxMediaSize caps the size of downloaded media, which is held in memory and
+// stored with the message. It matches WhatsApp's limit for audio and video;
+// larger documents are rejected instead of being ingested.
+const maxMediaSize = 16 << 20
+
+// DownloadMedia downloads the content of an incoming media message. Only URLs
+// on the scheme and host of the configured base URL are fetched, so a forged
+// webhook cannot send the API key, or any request, to another host.
+func (c *Client) DownloadMedia(ctx context.Context, mediaURL string) (*domain.MediaContent, error) {
+	start := time.Now()
+
+	if err := c.checkMediaURL(mediaURL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", mediaURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("App %s", c.apiKey))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.WithContext(ctx).Error().
+			Err(err).
+			Dur("duration", time.Since(start)).
+			Msg("Infobip media download failed")
+		return nil, fmt.Errorf("Infobip media download failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	c.logger.LogAPICall("infobip", "GET", mediaURL, resp.StatusCode, time.Since(start))
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("Infobip API error: %d - %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	if resp.ContentLength > maxMediaSize {
+		return nil, fmt.Errorf("media exceeds maximum size of %d bytes", maxMediaSize)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxMediaSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read media body: %w", err)
+	}
+	if len(data) > maxMediaSize {
+		return nil, fmt.Errorf("media exceeds maximum size of %d bytes", maxMediaSize)
+	}
+
+	content := &domain.MediaContent{
+		Data:        data,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		content.FileName = params["filename"]
+	}
+
+	c.logger.WithContext(ctx).Debug().
+		Str("content_type", content.ContentType).
+		Int("size_bytes", len(data)).
+		Dur("duration", time.Since(start)).
+		Msg("media downloaded successfully")
+
+	return content, nil
+}
+
+// checkMediaURL rejects media URLs outside the configured Infobip base URL
+func (c *Client) checkMediaURL(mediaURL string) error {
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid Infobip base URL: %w", err)
+	}
+
+	target, err := url.Parse(mediaURL)
+	if err != nil {
+		return fmt.Errorf("invalid media URL: %w", err)
+	}
+
+	if !strings.EqualFold(target.Scheme, base.Scheme) || !strings.EqualFold(target.Host, base.Host) {
+		return fmt.Errorf("media URL is not on the Infobip base URL host %s", base.Host)
+	}
+
+	return nil
 }
 
 // InfobipSendResponse represents the response from Infobip send API
@@ -195,6 +472,27 @@ func NewRetryableClient(cfg *config.InfobipConfig, logger *log.Logger, maxRetrie
 
 // SendText sends a text message with retry logic
 func (rc *RetryableClient) SendText(ctx context.Context, from, to, text string, messageIDRef ...string) (*domain.InfobipMessage, error) {
+	return rc.send(ctx, func() (*domain.InfobipMessage, error) {
+		return rc.client.SendText(ctx, from, to, text, messageIDRef...)
+	})
+}
+
+// SendButtons sends a message with quick reply buttons with retry logic
+func (rc *RetryableClient) SendButtons(ctx context.Context, from, to, text string, buttons []domain.InfobipReplyButton, messageIDRef ...string) (*domain.InfobipMessage, error) {
+	return rc.send(ctx, func() (*domain.InfobipMessage, error) {
+		return rc.client.SendButtons(ctx, from, to, text, buttons, messageIDRef...)
+	})
+}
+
+// SendList sends a message with a list menu with retry logic
+func (rc *RetryableClient) SendList(ctx context.Context, from, to, text string, list *domain.InfobipList, messageIDRef ...string) (*domain.InfobipMessage, error) {
+	return rc.send(ctx, func() (*domain.InfobipMessage, error) {
+		return rc.client.SendList(ctx, from, to, text, list, messageIDRef...)
+	})
+}
+
+// send runs a send operation with exponential backoff
+func (rc *RetryableClient) send(ctx context.Context, sendFn func() (*domain.InfobipMessage, error)) (*domain.InfobipMessage, error) {
 	var lastErr error
 
 	for attempt := 0; attempt <= rc.maxRetries; attempt++ {
@@ -215,7 +513,7 @@ func (rc *RetryableClient) SendText(ctx context.Context, from, to, text string,
 			}
 		}
 
-		result, err := rc.client.SendText(ctx, from, to, text, messageIDRef...)
+		result, err := sendFn()
 		if err == nil {
 			if attempt > 0 {
 				rc.logger.WithContext(ctx).Info().
@@ -241,9 +539,53 @@ func (rc *RetryableClient) SendText(ctx context.Context, from, to, text string,
 	return nil, fmt.Errorf("failed after %d retries: %w", rc.maxRetries, lastErr)
 }
 
+// SendTemplate sends a template message with retry logic
+func (rc *RetryableClient) SendTemplate(ctx context.Context, from, to string, template *domain.InfobipTemplate, messageIDRef ...string) (*domain.InfobipMessage, error) {
+	return rc.send(ctx, func() (*domain.InfobipMessage, error) {
+		return rc.client.SendTemplate(ctx, from, to, template, messageIDRef...)
+	})
+}
+
 // SendMessage sends a structured message with retry logic
 func (rc *RetryableClient) SendMessage(ctx context.Context, message *domain.InfobipMessage) (*domain.InfobipMessage, error) {
-	return rc.SendText(ctx, message.From, message.To, message.Content.Text, message.CallbackData)
+	return rc.send(ctx, func() (*domain.InfobipMessage, error) {
+		return rc.client.SendMessage(ctx, message)
+	})
+}
+
+// DownloadMedia downloads media with retry logic
+func (rc *RetryableClient) DownloadMedia(ctx context.Context, mediaURL string) (*domain.MediaContent, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= rc.maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := rc.baseDelay * time.Duration(1<<(attempt-1))
+			rc.logger.WithContext(ctx).Warn().
+				Int("attempt", attempt).
+				Dur("delay", delay).
+				Err(lastErr).
+				Msg("retrying Infobip media download after delay")
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		content, err := rc.client.DownloadMedia(ctx, mediaURL)
+		if err == nil {
+			return content, nil
+		}
+
+		lastErr = err
+
+		if !rc.isRetryableError(err) {
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("failed after %d retries: %w", rc.maxRetries, lastErr)
 }
 
 // isRetryableError determines if an error should trigger a retry
@@ -257,6 +599,19 @@ func (rc *RetryableClient) isRetryableError(err error) bool {
 	if err != nil && strings.Contains(err.Error(), "no messages") {
 		return false
 	}
+	if err != nil && strings.Contains(err.Error(), "exceeds maximum size") {
+		return false
+	}
+	if err != nil && (strings.Contains(err.Error(), "media URL") || strings.Contains(err.Error(), "base URL")) {
+		return false
+	}
+	// Invalid interactive messages fail validation before reaching the API
+	if err != nil && strings.Contains(err.Error(), "invalid interactive message") {
+		return false
+	}
+	if err != nil && strings.Contains(err.Error(), "invalid template message") {
+		return false
+	}
 
 	// Could also check for specific HTTP status codes
 	// e.g., don't retry on 4xx errors except 429 (rate limit)
@@ -269,13 +624,38 @@ func VerifyWebhookToken(receivedToken, expectedToken string) bool {
 	if expectedToken == "" {
 		return true // Skip verification if no token configured
 	}
-	return receivedToken == expectedToken
+	return subtle.ConstantTimeCompare([]byte(receivedToken), []byte(expectedToken)) == 1
 }
 
-// ValidateWebhookSignature validates the webhook signature (if implemented by Infobip)
+// ValidateWebhookSignature validates a hex-encoded HMAC-SHA256 signature of
+// payload, optionally prefixed with "sha256="
 func ValidateWebhookSignature(payload []byte, signature, secret string) bool {
-	// Infobip doesn't typically use HMAC signatures like some other providers
-	// This is a placeholder for custom signature validation if needed
-	// In practice, you might rely on IP whitelisting or the verification token
-	return true
+	if secret == "" || signature == "" {
+		return false
+	}
+
+	received, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(received, mac.Sum(nil))
+}
+
+// SignWebhook returns the signature of a webhook body sent at the given Unix
+// timestamp, in the format checked by WebhookVerifier
+func SignWebhook(body []byte, timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(signedPayload(body, timestamp))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// signedPayload builds the signed content of a webhook: the timestamp, a dot and the raw body
+func signedPayload(body []byte, timestamp string) []byte {
+	payload := make([]byte, 0, len(timestamp)+1+len(body))
+	payload = append(payload, timestamp...)
+	payload = append(payload, '.')
+	return append(payload, body...)
 }
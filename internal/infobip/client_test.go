@@ -0,0 +1,71 @@
+package infobip_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/infobip"
+	"personal-assistant/internal/log"
+)
+
+func TestClient_DownloadMedia(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		if r.URL.Path == "/large" {
+			w.Header().Set("Content-Length", "17000000")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="report.pdf"`)
+		w.Write([]byte("%PDF-1.4"))
+	}))
+	defer server.Close()
+
+	client := infobip.NewClient(&config.InfobipConfig{BaseURL: server.URL, APIKey: "secret"}, log.Init("error"))
+
+	t.Run("downloads media from the base URL host", func(t *testing.T) {
+		content, err := client.DownloadMedia(context.Background(), server.URL+"/whatsapp/1/senders/1/media/abc")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("%PDF-1.4"), content.Data)
+		assert.Equal(t, "report.pdf", content.FileName)
+		assert.Equal(t, "App secret", authorization)
+	})
+
+	t.Run("rejects media on another host", func(t *testing.T) {
+		other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request with Authorization %q", r.Header.Get("Authorization"))
+		}))
+		defer other.Close()
+
+		_, err := client.DownloadMedia(context.Background(), other.URL+"/media/abc")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "media URL is not on the Infobip base URL host")
+	})
+
+	t.Run("rejects media on another scheme", func(t *testing.T) {
+		_, err := client.DownloadMedia(context.Background(), strings.Replace(server.URL, "http://", "https://", 1)+"/media/abc")
+		require.Error(t, err)
+	})
+
+	t.Run("rejects media larger than the limit", func(t *testing.T) {
+		_, err := client.DownloadMedia(context.Background(), server.URL+"/large")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds maximum size")
+	})
+
+	t.Run("does not retry rejected URLs", func(t *testing.T) {
+		retryable := infobip.NewRetryableClient(&config.InfobipConfig{BaseURL: server.URL, APIKey: "secret"}, log.Init("error"), 3, time.Hour)
+		_, err := retryable.DownloadMedia(context.Background(), "http://169.254.169.254/latest/meta-data")
+		require.Error(t, err)
+	})
+}
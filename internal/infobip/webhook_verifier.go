@@ -0,0 +1,187 @@
+package infobip
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/domain"
+)
+
+// Tenant config keys overriding the global webhook signing secrets
+const (
+	tenantWebhookSecret         = "webhook_secret"
+	tenantWebhookPreviousSecret = "webhook_secret_previous"
+	tenantWebhookPreviousUntil  = "webhook_secret_previous_until"
+)
+
+// WebhookVerifier checks the HMAC-SHA256 signatures of Infobip webhooks.
+// The signature covers the timestamp header, a dot and the raw body, so a
+// captured request cannot be replayed once it falls outside the replay window.
+// During a secret rollover both the new and the previous secret are accepted.
+type WebhookVerifier struct {
+	tenantManager domain.TenantManager
+	config        *config.WebhookSignatureConfig
+	verifyToken   string
+	now           func() time.Time
+}
+
+// NewWebhookVerifier creates a new webhook signature verifier
+func NewWebhookVerifier(tenantManager domain.TenantManager, cfg *config.Config) *WebhookVerifier {
+	return &WebhookVerifier{
+		tenantManager: tenantManager,
+		config:        &cfg.WebhookSignature,
+		verifyToken:   cfg.WebhookVerifyToken,
+		now:           time.Now,
+	}
+}
+
+// VerifyIncoming checks an inbound message webhook against the secrets of the
+// tenants owning the receiving WABA numbers
+func (v *WebhookVerifier) VerifyIncoming(header http.Header, body []byte, webhook *domain.InfobipWebhookMessage) error {
+	tenants := make([]*domain.Tenant, 0, len(webhook.Results))
+	for _, result := range webhook.Results {
+		tenant, _ := v.tenantManager.GetTenant(result.To)
+		tenants = append(tenants, tenant)
+	}
+
+	return v.verify(header, body, tenants)
+}
+
+// VerifyStatus checks a delivery report webhook against the secrets of the
+// tenants that sent the reported messages
+func (v *WebhookVerifier) VerifyStatus(header http.Header, body []byte, webhook *domain.InfobipStatusWebhook) error {
+	tenants := make([]*domain.Tenant, 0, len(webhook.Results))
+	for i := range webhook.Results {
+		report := &webhook.Results[i]
+
+		var tenant *domain.Tenant
+		if tenantID, _ := domain.ParseCallbackData(report.CallbackData); tenantID != "" {
+			tenant, _ = v.tenantManager.GetTenantByID(tenantID)
+		} else if sender := report.SenderNumber(); sender != "" {
+			tenant, _ = v.tenantManager.GetTenant(sender)
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	return v.verify(header, body, tenants)
+}
+
+// verify checks the request against every distinct tenant. Results whose
+// tenant is unknown are checked against the global secrets.
+func (v *WebhookVerifier) verify(header http.Header, body []byte, tenants []*domain.Tenant) error {
+	if len(tenants) == 0 {
+		tenants = []*domain.Tenant{nil}
+	}
+
+	checked := make(map[string]bool)
+	for _, tenant := range tenants {
+		tenantID := ""
+		if tenant != nil {
+			tenantID = tenant.ID
+		}
+		if checked[tenantID] {
+			continue
+		}
+		checked[tenantID] = true
+
+		if err := v.verifyTenant(header, body, tenant); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyTenant checks the request against the secrets of a single tenant,
+// falling back to the plain verify token when no secret is configured
+func (v *WebhookVerifier) verifyTenant(header http.Header, body []byte, tenant *domain.Tenant) error {
+	signature := header.Get(v.config.SignatureHeader)
+
+	secrets := v.secrets(tenant)
+	if len(secrets) == 0 {
+		if v.config.Required {
+			return fmt.Errorf("no webhook secret configured")
+		}
+		if !VerifyWebhookToken(signature, v.verifyToken) {
+			return fmt.Errorf("webhook token verification failed")
+		}
+		return nil
+	}
+
+	if signature == "" {
+		return fmt.Errorf("missing webhook signature")
+	}
+
+	timestamp := header.Get(v.config.TimestampHeader)
+	if err := v.checkTimestamp(timestamp); err != nil {
+		return err
+	}
+
+	payload := signedPayload(body, timestamp)
+	for _, secret := range secrets {
+		if ValidateWebhookSignature(payload, signature, secret) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid webhook signature")
+}
+
+// secrets returns the secrets currently accepted for a tenant, newest first.
+// Tenants with their own webhook_secret do not accept the global secrets.
+func (v *WebhookVerifier) secrets(tenant *domain.Tenant) []string {
+	current := v.config.Secret
+	previous := v.config.PreviousSecret
+	previousUntil := v.config.PreviousUntil
+
+	if tenant != nil {
+		if secret, _ := tenant.Config[tenantWebhookSecret].(string); secret != "" {
+			current = secret
+			previous, _ = tenant.Config[tenantWebhookPreviousSecret].(string)
+			previousUntil = time.Time{}
+
+			if until, _ := tenant.Config[tenantWebhookPreviousUntil].(string); until != "" {
+				parsed, err := time.Parse(time.RFC3339, until)
+				if err != nil {
+					// An unreadable deadline ends the rollover rather than extending it
+					previous = ""
+				}
+				previousUntil = parsed
+			}
+		}
+	}
+
+	var secrets []string
+	if current != "" {
+		secrets = append(secrets, current)
+	}
+	if previous != "" && (previousUntil.IsZero() || v.now().Before(previousUntil)) {
+		secrets = append(secrets, previous)
+	}
+	return secrets
+}
+
+// checkTimestamp rejects signatures made outside the replay window
+func (v *WebhookVerifier) checkTimestamp(timestamp string) error {
+	if timestamp == "" {
+		return fmt.Errorf("missing webhook timestamp")
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid webhook timestamp: %s", timestamp)
+	}
+
+	age := v.now().Sub(time.Unix(seconds, 0))
+	if age < 0 {
+		age = -age
+	}
+	if v.config.ReplayWindow > 0 && age > v.config.ReplayWindow {
+		return fmt.Errorf("webhook timestamp outside the replay window")
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package infobip_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/infobip"
+)
+
+// MockTenantManager implements the tenant lookups used by the verifier
+type MockTenantManager struct {
+	domain.TenantManager
+	mock.Mock
+}
+
+func (m *MockTenantManager) GetTenant(wabaNumber string) (*domain.Tenant, error) {
+	args := m.Called(wabaNumber)
+	tenant, _ := args.Get(0).(*domain.Tenant)
+	return tenant, args.Error(1)
+}
+
+func (m *MockTenantManager) GetTenantByID(tenantID string) (*domain.Tenant, error) {
+	args := m.Called(tenantID)
+	tenant, _ := args.Get(0).(*domain.Tenant)
+	return tenant, args.Error(1)
+}
+
+func signedHeader(body []byte, secret string, at time.Time) http.Header {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	header := http.Header{}
+	header.Set("X-Hub-Signature", infobip.SignWebhook(body, timestamp, secret))
+	header.Set("X-Hub-Timestamp", timestamp)
+	return header
+}
+
+func TestValidateWebhookSignature(t *testing.T) {
+	payload := []byte("1700000000.{}")
+	signature := infobip.SignWebhook([]byte("{}"), "1700000000", "secret")
+
+	assert.True(t, infobip.ValidateWebhookSignature(payload, signature, "secret"))
+	assert.False(t, infobip.ValidateWebhookSignature(payload, signature, "other"))
+	assert.False(t, infobip.ValidateWebhookSignature([]byte("1700000001.{}"), signature, "secret"))
+	assert.False(t, infobip.ValidateWebhookSignature(payload, "not-hex", "secret"))
+	assert.False(t, infobip.ValidateWebhookSignature(payload, signature, ""))
+}
+
+func TestVerifyWebhookToken(t *testing.T) {
+	assert.True(t, infobip.VerifyWebhookToken("token", "token"))
+	assert.False(t, infobip.VerifyWebhookToken("other", "token"))
+	assert.False(t, infobip.VerifyWebhookToken("", "token"))
+	assert.True(t, infobip.VerifyWebhookToken("anything", ""))
+}
+
+func TestWebhookVerifier_VerifyIncoming(t *testing.T) {
+	body := []byte(`{"results":[{"to":"5511888888888"}]}`)
+	webhook := &domain.InfobipWebhookMessage{
+		Results: []domain.InfobipWebhookResult{{To: "5511888888888"}},
+	}
+
+	newVerifier := func(tenant *domain.Tenant, signature config.WebhookSignatureConfig) *infobip.WebhookVerifier {
+		signature.SignatureHeader = "X-Hub-Signature"
+		signature.TimestampHeader = "X-Hub-Timestamp"
+		signature.ReplayWindow = 5 * time.Minute
+
+		tm := &MockTenantManager{}
+		tm.On("GetTenant", "5511888888888").Return(tenant, nil)
+		return infobip.NewWebhookVerifier(tm, &config.Config{
+			WebhookVerifyToken: "verify-token",
+			WebhookSignature:   signature,
+		})
+	}
+
+	t.Run("accepts a valid signature", func(t *testing.T) {
+		verifier := newVerifier(&domain.Tenant{ID: "tenant1"}, config.WebhookSignatureConfig{Secret: "global"})
+
+		assert.NoError(t, verifier.VerifyIncoming(signedHeader(body, "global", time.Now()), body, webhook))
+	})
+
+	t.Run("rejects a modified body", func(t *testing.T) {
+		verifier := newVerifier(&domain.Tenant{ID: "tenant1"}, config.WebhookSignatureConfig{Secret: "global"})
+		header := signedHeader(body, "global", time.Now())
+
+		assert.Error(t, verifier.VerifyIncoming(header, []byte(`{"results":[]}`), webhook))
+	})
+
+	t.Run("rejects a timestamp outside the replay window", func(t *testing.T) {
+		verifier := newVerifier(&domain.Tenant{ID: "tenant1"}, config.WebhookSignatureConfig{Secret: "global"})
+
+		assert.Error(t, verifier.VerifyIncoming(signedHeader(body, "global", time.Now().Add(-10*time.Minute)), body, webhook))
+		assert.Error(t, verifier.VerifyIncoming(signedHeader(body, "global", time.Now().Add(10*time.Minute)), body, webhook))
+	})
+
+	t.Run("rejects a missing timestamp", func(t *testing.T) {
+		verifier := newVerifier(&domain.Tenant{ID: "tenant1"}, config.WebhookSignatureConfig{Secret: "global"})
+		header := signedHeader(body, "global", time.Now())
+		header.Del("X-Hub-Timestamp")
+
+		assert.Error(t, verifier.VerifyIncoming(header, body, webhook))
+	})
+
+	t.Run("prefers the tenant secret over the global one", func(t *testing.T) {
+		tenant := &domain.Tenant{ID: "tenant1", Config: map[string]any{"webhook_secret": "tenant"}}
+		verifier := newVerifier(tenant, config.WebhookSignatureConfig{Secret: "global"})
+
+		assert.NoError(t, verifier.VerifyIncoming(signedHeader(body, "tenant", time.Now()), body, webhook))
+		assert.Error(t, verifier.VerifyIncoming(signedHeader(body, "global", time.Now()), body, webhook))
+	})
+
+	t.Run("accepts the previous secret until the rollover ends", func(t *testing.T) {
+		tenant := &domain.Tenant{ID: "tenant1", Config: map[string]any{
+			"webhook_secret":                "new",
+			"webhook_secret_previous":       "old",
+			"webhook_secret_previous_until": time.Now().Add(time.Hour).Format(time.RFC3339),
+		}}
+		verifier := newVerifier(tenant, config.WebhookSignatureConfig{})
+
+		assert.NoError(t, verifier.VerifyIncoming(signedHeader(body, "new", time.Now()), body, webhook))
+		assert.NoError(t, verifier.VerifyIncoming(signedHeader(body, "old", time.Now()), body, webhook))
+
+		tenant.Config["webhook_secret_previous_until"] = time.Now().Add(-time.Hour).Format(time.RFC3339)
+		assert.Error(t, verifier.VerifyIncoming(signedHeader(body, "old", time.Now()), body, webhook))
+	})
+
+	t.Run("rejects the global previous secret after the rollover ends", func(t *testing.T) {
+		verifier := newVerifier(nil, config.WebhookSignatureConfig{
+			Secret:         "new",
+			PreviousSecret: "old",
+			PreviousUntil:  time.Now().Add(-time.Minute),
+		})
+
+		assert.Error(t, verifier.VerifyIncoming(signedHeader(body, "old", time.Now()), body, webhook))
+	})
+
+	t.Run("falls back to the verify token without a secret", func(t *testing.T) {
+		verifier := newVerifier(&domain.Tenant{ID: "tenant1"}, config.WebhookSignatureConfig{})
+
+		header := http.Header{}
+		header.Set("X-Hub-Signature", "verify-token")
+		assert.NoError(t, verifier.VerifyIncoming(header, body, webhook))
+
+		header.Set("X-Hub-Signature", "wrong")
+		assert.Error(t, verifier.VerifyIncoming(header, body, webhook))
+	})
+
+	t.Run("rejects tenants without a secret when signatures are required", func(t *testing.T) {
+		verifier := newVerifier(&domain.Tenant{ID: "tenant1"}, config.WebhookSignatureConfig{Required: true})
+
+		header := http.Header{}
+		header.Set("X-Hub-Signature", "verify-token")
+		assert.Error(t, verifier.VerifyIncoming(header, body, webhook))
+	})
+}
+
+func TestWebhookVerifier_VerifyStatus(t *testing.T) {
+	body := []byte(`{"results":[]}`)
+	webhook := &domain.InfobipStatusWebhook{
+		Results: []domain.InfobipStatusReport{
+			{MessageID: "out-1", CallbackData: domain.EncodeCallbackData("tenant1", "")},
+		},
+	}
+
+	tm := &MockTenantManager{}
+	tm.On("GetTenantByID", "tenant1").Return(&domain.Tenant{ID: "tenant1", Config: map[string]any{"webhook_secret": "tenant"}}, nil)
+	verifier := infobip.NewWebhookVerifier(tm, &config.Config{
+		WebhookSignature: config.WebhookSignatureConfig{
+			Secret:          "global",
+			SignatureHeader: "X-Hub-Signature",
+			TimestampHeader: "X-Hub-Timestamp",
+			ReplayWindow:    5 * time.Minute,
+		},
+	})
+
+	assert.NoError(t, verifier.VerifyStatus(signedHeader(body, "tenant", time.Now()), body, webhook))
+	assert.Error(t, verifier.VerifyStatus(signedHeader(body, "global", time.Now()), body, webhook))
+	tm.AssertExpectations(t)
+}
@@ -0,0 +1,421 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/llm/openai"
+	"personal-assistant/internal/log"
+)
+
+const (
+	// anthropicVersion is the Messages API version sent with every request
+	anthropicVersion = "2023-06-01"
+	// anthropicDefaultMaxTokens is used when the request does not set max_tokens, which the API requires
+	anthropicDefaultMaxTokens = 1024
+)
+
+// AnthropicProvider implements the LLMProvider interface on the Anthropic Messages API.
+// Anthropic has no embeddings endpoint, so Embed is delegated to an
+// OpenAI-compatible service configured with "embedding_base_url".
+type AnthropicProvider struct {
+	client    *http.Client
+	config    *domain.LLMProviderConfig
+	logger    *log.Logger
+	baseURL   string
+	chatModel string
+	embedder  domain.LLMProvider
+}
+
+// NewAnthropicProvider creates a new Anthropic provider
+func NewAnthropicProvider(config *domain.LLMProviderConfig, logger *log.Logger) (*AnthropicProvider, error) {
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("Anthropic API key is required")
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = GetDefaultBaseURL(Anthropic)
+	}
+
+	// Use configured model or default
+	chatModel := config.ModelChat
+	if chatModel == "" {
+		chatModel, _ = GetDefaultModels(Anthropic)
+	}
+
+	provider := &AnthropicProvider{
+		client:    &http.Client{Timeout: 120 * time.Second},
+		config:    config,
+		logger:    logger,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		chatModel: chatModel,
+	}
+
+	// Embeddings go to an OpenAI-compatible endpoint when configured
+	if embedURL := config.ConfigString("embedding_base_url", ""); embedURL != "" {
+		embedder, err := openai.NewProvider(&domain.LLMProviderConfig{
+			Provider:   string(OpenAI),
+			Name:       config.Name,
+			APIKey:     config.ConfigString("embedding_api_key", ""),
+			BaseURL:    embedURL,
+			ModelEmbed: config.ModelEmbed,
+		}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create embedding provider: %w", err)
+		}
+		provider.embedder = embedder
+	}
+
+	return provider, nil
+}
+
+// ChatModel returns the chat model name
+func (p *AnthropicProvider) ChatModel() string {
+	return p.chatModel
+}
+
+// Name returns the provider name
+func (p *AnthropicProvider) Name() string {
+	return fmt.Sprintf("anthropic-%s", p.config.Name)
+}
+
+// Chat performs a chat completion
+func (p *AnthropicProvider) Chat(ctx context.Context, req *domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+	start := time.Now()
+
+	anthropicReq := p.convertChatRequest(req)
+
+	p.logger.WithContext(ctx).Debug().
+		Str("model", anthropicReq.Model).
+		Int("messages", len(anthropicReq.Messages)).
+		Int("max_tokens", anthropicReq.MaxTokens).
+		Msg("making Anthropic chat completion request")
+
+	resp, err := p.createMessage(ctx, anthropicReq)
+	if err != nil {
+		p.logger.WithContext(ctx).Error().
+			Err(err).
+			Dur("duration", time.Since(start)).
+			Msg("Anthropic chat completion failed")
+		return nil, fmt.Errorf("Anthropic chat completion failed: %w", err)
+	}
+
+	// Convert response
+	domainResp := p.convertChatResponse(resp)
+
+	// Log token usage
+	if domainResp.Usage != nil {
+		p.logger.LogTokenUsage("anthropic_chat",
+			domainResp.Usage.PromptTokens,
+			domainResp.Usage.CompletionTokens,
+			domainResp.Usage.TotalTokens)
+	}
+
+	p.logger.WithContext(ctx).Debug().
+		Str("finish_reason", domainResp.Choices[0].FinishReason).
+		Dur("duration", time.Since(start)).
+		Msg("Anthropic chat completion successful")
+
+	return domainResp, nil
+}
+
+// Embed generates embeddings through the configured embedding endpoint
+func (p *AnthropicProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+	if p.embedder == nil {
+		return nil, fmt.Errorf("embeddings not supported by Anthropic; set embedding_base_url in the provider config")
+	}
+	return p.embedder.Embed(ctx, texts)
+}
+
+// anthropicRequest is the body of a Messages API request
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	Tools       []anthropicTool    `json:"tools,omitempty"`
+	ToolChoice  map[string]string  `json:"tool_choice,omitempty"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature *float32           `json:"temperature,omitempty"`
+}
+
+// anthropicMessage is a user or assistant turn made of content blocks
+type anthropicMessage struct {
+	Role    string                  `json:"role"`
+	Content []anthropicContentBlock `json:"content"`
+}
+
+// anthropicContentBlock is a text, tool_use or tool_result block
+type anthropicContentBlock struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+}
+
+// anthropicTool is a tool definition
+type anthropicTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+// anthropicResponse is the body of a Messages API response
+type anthropicResponse struct {
+	ID         string                  `json:"id"`
+	Type       string                  `json:"type"`
+	Role       string                  `json:"role"`
+	Model      string                  `json:"model"`
+	Content    []anthropicContentBlock `json:"content"`
+	StopReason string                  `json:"stop_reason"`
+	Usage      struct {
+		InputTokens              int `json:"input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+	} `json:"usage"`
+}
+
+// anthropicError is the body of a Messages API error response
+type anthropicError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// createMessage sends a request to the Messages API
+func (p *AnthropicProvider) createMessage(ctx context.Context, req *anthropicRequest) (*anthropicResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", p.config.APIKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+
+	httpResp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		var apiErr anthropicError
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, &APIError{StatusCode: httpResp.StatusCode, Type: apiErr.Error.Type, Message: apiErr.Error.Message}
+		}
+		return nil, &APIError{StatusCode: httpResp.StatusCode, Message: string(respBody)}
+	}
+
+	var resp anthropicResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// convertChatRequest converts domain request to a Messages API request.
+// System messages are moved to the system prompt and tool results are sent
+// as tool_result blocks in a user turn.
+func (p *AnthropicProvider) convertChatRequest(req *domain.ChatCompletionRequest) *anthropicRequest {
+	anthropicReq := &anthropicRequest{
+		Model:     p.chatModel,
+		MaxTokens: req.MaxTokens,
+	}
+	if anthropicReq.MaxTokens <= 0 {
+		anthropicReq.MaxTokens = anthropicDefaultMaxTokens
+	}
+	if req.Temperature > 0 {
+		temperature := req.Temperature
+		anthropicReq.Temperature = &temperature
+	}
+
+	var system []string
+	for _, msg := range req.Messages {
+		var role string
+		var blocks []anthropicContentBlock
+
+		switch msg.Role {
+		case "system":
+			if msg.Content != "" {
+				system = append(system, msg.Content)
+			}
+			continue
+		case "tool":
+			role = "user"
+			blocks = append(blocks, anthropicContentBlock{
+				Type:      "tool_result",
+				ToolUseID: msg.ToolCallID,
+				Content:   msg.Content,
+			})
+		case "assistant":
+			role = "assistant"
+			if msg.Content != "" {
+				blocks = append(blocks, anthropicContentBlock{Type: "text", Text: msg.Content})
+			}
+			for _, toolCall := range msg.ToolCalls {
+				if toolCall.Function == nil {
+					continue
+				}
+				input := toolCall.Function.Arguments
+				if len(bytes.TrimSpace(input)) == 0 {
+					input = json.RawMessage("{}")
+				}
+				blocks = append(blocks, anthropicContentBlock{
+					Type:  "tool_use",
+					ID:    toolCall.ID,
+					Name:  toolCall.Function.Name,
+					Input: input,
+				})
+			}
+		default:
+			role = "user"
+			if msg.Content != "" {
+				blocks = append(blocks, anthropicContentBlock{Type: "text", Text: msg.Content})
+			}
+		}
+
+		if len(blocks) == 0 {
+			continue
+		}
+
+		// Consecutive turns of the same role are merged, so the tool results
+		// answering one assistant turn arrive in a single user turn
+		if last := len(anthropicReq.Messages) - 1; last >= 0 && anthropicReq.Messages[last].Role == role {
+			anthropicReq.Messages[last].Content = append(anthropicReq.Messages[last].Content, blocks...)
+			continue
+		}
+		anthropicReq.Messages = append(anthropicReq.Messages, anthropicMessage{Role: role, Content: blocks})
+	}
+	anthropicReq.System = strings.Join(system, "\n\n")
+
+	// Convert tools
+	for _, tool := range req.Tools {
+		if tool.Function == nil {
+			continue
+		}
+		schema := tool.Function.Parameters
+		if schema == nil {
+			schema = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+		}
+		anthropicReq.Tools = append(anthropicReq.Tools, anthropicTool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			InputSchema: schema,
+		})
+	}
+	if len(anthropicReq.Tools) > 0 {
+		anthropicReq.ToolChoice = convertToolChoice(req.ToolChoice)
+	}
+
+	return anthropicReq
+}
+
+// convertToolChoice maps an OpenAI-style tool choice ("auto", "none",
+// "required" or a named function) to its Anthropic equivalent
+func convertToolChoice(choice interface{}) map[string]string {
+	switch value := choice.(type) {
+	case string:
+		switch value {
+		case "none":
+			return map[string]string{"type": "none"}
+		case "required":
+			return map[string]string{"type": "any"}
+		case "auto":
+			return map[string]string{"type": "auto"}
+		}
+	case map[string]interface{}:
+		if function, ok := value["function"].(map[string]interface{}); ok {
+			if name, _ := function["name"].(string); name != "" {
+				return map[string]string{"type": "tool", "name": name}
+			}
+		}
+	}
+	return nil
+}
+
+// convertChatResponse converts a Messages API response to domain response
+func (p *AnthropicProvider) convertChatResponse(resp *anthropicResponse) *domain.ChatCompletionResponse {
+	domainMsg := &domain.ChatMessage{
+		Role: "assistant",
+	}
+
+	var text []string
+	for _, block := range resp.Content {
+		switch block.Type {
+		case "text":
+			text = append(text, block.Text)
+		case "tool_use":
+			arguments := block.Input
+			if len(arguments) == 0 {
+				arguments = json.RawMessage("{}")
+			}
+			domainMsg.ToolCalls = append(domainMsg.ToolCalls, domain.ToolCall{
+				ID:   block.ID,
+				Type: "function",
+				Function: &domain.FunctionCall{
+					Name:      block.Name,
+					Arguments: arguments,
+				},
+			})
+		}
+	}
+	domainMsg.Content = strings.Join(text, "")
+
+	promptTokens := resp.Usage.InputTokens + resp.Usage.CacheCreationInputTokens + resp.Usage.CacheReadInputTokens
+	return &domain.ChatCompletionResponse{
+		ID:      resp.ID,
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   resp.Model,
+		Choices: []domain.Choice{{
+			Index:        0,
+			Message:      domainMsg,
+			FinishReason: convertStopReason(resp.StopReason),
+		}},
+		Usage: &domain.TokenUsage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: resp.Usage.OutputTokens,
+			TotalTokens:      promptTokens + resp.Usage.OutputTokens,
+		},
+	}
+}
+
+// convertStopReason maps Anthropic stop reasons to OpenAI-style finish reasons
+func convertStopReason(reason string) string {
+	switch reason {
+	case "end_turn", "stop_sequence":
+		return "stop"
+	case "max_tokens":
+		return "length"
+	case "tool_use":
+		return "tool_calls"
+	default:
+		return reason
+	}
+}
@@ -0,0 +1,241 @@
+package llm_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/llm"
+	"personal-assistant/internal/log"
+)
+
+// anthropicStub is an httptest stand-in for the Messages API that records the last request
+type anthropicStub struct {
+	*httptest.Server
+	header  http.Header
+	request map[string]interface{}
+}
+
+func newAnthropicStub(t *testing.T, status int, response string) *anthropicStub {
+	stub := &anthropicStub{}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/messages", r.URL.Path)
+		stub.header = r.Header.Clone()
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&stub.request))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func newAnthropicProvider(t *testing.T, baseURL string) *llm.AnthropicProvider {
+	provider, err := llm.NewAnthropicProvider(&domain.LLMProviderConfig{
+		Provider:  "anthropic",
+		Name:      "test",
+		APIKey:    "test-key",
+		BaseURL:   baseURL,
+		ModelChat: "claude-test",
+	}, log.Init("error"))
+	require.NoError(t, err)
+	return provider
+}
+
+func TestNewAnthropicProvider(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		provider, err := llm.NewAnthropicProvider(&domain.LLMProviderConfig{Name: "test", APIKey: "key"}, log.Init("error"))
+		require.NoError(t, err)
+		assert.Equal(t, "anthropic-test", provider.Name())
+		assert.Equal(t, "claude-3-sonnet-20240229", provider.ChatModel())
+	})
+
+	t.Run("requires an API key", func(t *testing.T) {
+		_, err := llm.NewAnthropicProvider(&domain.LLMProviderConfig{Name: "test"}, log.Init("error"))
+		assert.Error(t, err)
+	})
+
+	t.Run("created by the factory", func(t *testing.T) {
+		provider, err := llm.NewFactory(log.Init("error")).CreateProvider(&domain.LLMProviderConfig{
+			Provider: "anthropic",
+			Name:     "test",
+			APIKey:   "key",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "anthropic-test", provider.Name())
+	})
+}
+
+func TestAnthropicProvider_Chat(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sends system prompt and text messages", func(t *testing.T) {
+		stub := newAnthropicStub(t, http.StatusOK, `{
+			"id": "msg_1",
+			"type": "message",
+			"role": "assistant",
+			"model": "claude-test",
+			"content": [{"type": "text", "text": "Hi there!"}],
+			"stop_reason": "end_turn",
+			"usage": {"input_tokens": 12, "output_tokens": 4, "cache_read_input_tokens": 3}
+		}`)
+		provider := newAnthropicProvider(t, stub.URL)
+
+		resp, err := provider.Chat(ctx, &domain.ChatCompletionRequest{
+			Messages: []domain.ChatMessage{
+				{Role: "system", Content: "You are helpful."},
+				{Role: "system", Content: "Be brief."},
+				{Role: "user", Content: "Hello"},
+			},
+			Temperature: 0.5,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "test-key", stub.header.Get("x-api-key"))
+		assert.Equal(t, "2023-06-01", stub.header.Get("anthropic-version"))
+		assert.Equal(t, "claude-test", stub.request["model"])
+		assert.Equal(t, "You are helpful.\n\nBe brief.", stub.request["system"])
+		assert.Equal(t, float64(1024), stub.request["max_tokens"])
+		assert.Equal(t, 0.5, stub.request["temperature"])
+		assert.NotContains(t, stub.request, "tools")
+
+		messages := stub.request["messages"].([]interface{})
+		require.Len(t, messages, 1)
+		assert.Equal(t, map[string]interface{}{
+			"role":    "user",
+			"content": []interface{}{map[string]interface{}{"type": "text", "text": "Hello"}},
+		}, messages[0])
+
+		require.Len(t, resp.Choices, 1)
+		assert.Equal(t, "msg_1", resp.ID)
+		assert.Equal(t, "assistant", resp.Choices[0].Message.Role)
+		assert.Equal(t, "Hi there!", resp.Choices[0].Message.Content)
+		assert.Equal(t, "stop", resp.Choices[0].FinishReason)
+		assert.Equal(t, &domain.TokenUsage{PromptTokens: 15, CompletionTokens: 4, TotalTokens: 19}, resp.Usage)
+	})
+
+	t.Run("maps tool use blocks to tool calls", func(t *testing.T) {
+		stub := newAnthropicStub(t, http.StatusOK, `{
+			"id": "msg_2",
+			"content": [
+				{"type": "text", "text": "Checking the weather."},
+				{"type": "tool_use", "id": "toolu_1", "name": "get_weather", "input": {"location": "NYC"}}
+			],
+			"stop_reason": "tool_use",
+			"usage": {"input_tokens": 20, "output_tokens": 10}
+		}`)
+		provider := newAnthropicProvider(t, stub.URL)
+
+		resp, err := provider.Chat(ctx, &domain.ChatCompletionRequest{
+			Messages: []domain.ChatMessage{{Role: "user", Content: "Weather in NYC?"}},
+			Tools: []domain.ToolDefinition{{
+				Type: "function",
+				Function: &domain.ToolFunction{
+					Name:        "get_weather",
+					Description: "Get weather information",
+					Parameters: map[string]interface{}{
+						"type":       "object",
+						"properties": map[string]interface{}{"location": map[string]interface{}{"type": "string"}},
+					},
+				},
+			}},
+			ToolChoice: "auto",
+			MaxTokens:  200,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, float64(200), stub.request["max_tokens"])
+		assert.Equal(t, map[string]interface{}{"type": "auto"}, stub.request["tool_choice"])
+		tools := stub.request["tools"].([]interface{})
+		require.Len(t, tools, 1)
+		tool := tools[0].(map[string]interface{})
+		assert.Equal(t, "get_weather", tool["name"])
+		assert.Equal(t, "Get weather information", tool["description"])
+		assert.Equal(t, "object", tool["input_schema"].(map[string]interface{})["type"])
+
+		message := resp.Choices[0].Message
+		assert.Equal(t, "tool_calls", resp.Choices[0].FinishReason)
+		assert.Equal(t, "Checking the weather.", message.Content)
+		require.Len(t, message.ToolCalls, 1)
+		assert.Equal(t, "toolu_1", message.ToolCalls[0].ID)
+		assert.Equal(t, "function", message.ToolCalls[0].Type)
+		assert.Equal(t, "get_weather", message.ToolCalls[0].Function.Name)
+		assert.JSONEq(t, `{"location": "NYC"}`, string(message.ToolCalls[0].Function.Arguments))
+	})
+
+	t.Run("sends tool results in a single user turn", func(t *testing.T) {
+		stub := newAnthropicStub(t, http.StatusOK, `{
+			"id": "msg_3",
+			"content": [{"type": "text", "text": "Sunny in both."}],
+			"stop_reason": "end_turn",
+			"usage": {"input_tokens": 30, "output_tokens": 5}
+		}`)
+		provider := newAnthropicProvider(t, stub.URL)
+
+		_, err := provider.Chat(ctx, &domain.ChatCompletionRequest{
+			Messages: []domain.ChatMessage{
+				{Role: "user", Content: "Weather in NYC and LA?"},
+				{Role: "assistant", ToolCalls: []domain.ToolCall{
+					{ID: "toolu_1", Type: "function", Function: &domain.FunctionCall{Name: "get_weather", Arguments: json.RawMessage(`{"location":"NYC"}`)}},
+					{ID: "toolu_2", Type: "function", Function: &domain.FunctionCall{Name: "get_weather", Arguments: json.RawMessage(`{"location":"LA"}`)}},
+				}},
+				{Role: "tool", ToolCallID: "toolu_1", Content: `{"weather":"sunny"}`},
+				{Role: "tool", ToolCallID: "toolu_2", Content: `{"weather":"sunny"}`},
+			},
+		})
+		require.NoError(t, err)
+
+		messages := stub.request["messages"].([]interface{})
+		require.Len(t, messages, 3)
+
+		assistant := messages[1].(map[string]interface{})
+		assert.Equal(t, "assistant", assistant["role"])
+		toolUses := assistant["content"].([]interface{})
+		require.Len(t, toolUses, 2)
+		assert.Equal(t, map[string]interface{}{
+			"type":  "tool_use",
+			"id":    "toolu_1",
+			"name":  "get_weather",
+			"input": map[string]interface{}{"location": "NYC"},
+		}, toolUses[0])
+
+		results := messages[2].(map[string]interface{})
+		assert.Equal(t, "user", results["role"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"type": "tool_result", "tool_use_id": "toolu_1", "content": `{"weather":"sunny"}`},
+			map[string]interface{}{"type": "tool_result", "tool_use_id": "toolu_2", "content": `{"weather":"sunny"}`},
+		}, results["content"])
+	})
+
+	t.Run("returns API errors", func(t *testing.T) {
+		stub := newAnthropicStub(t, http.StatusBadRequest, `{
+			"type": "error",
+			"error": {"type": "invalid_request_error", "message": "max_tokens: too large"}
+		}`)
+		provider := newAnthropicProvider(t, stub.URL)
+
+		_, err := provider.Chat(ctx, &domain.ChatCompletionRequest{
+			Messages: []domain.ChatMessage{{Role: "user", Content: "Hello"}},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "max_tokens: too large")
+	})
+}
+
+func TestAnthropicProvider_Embed(t *testing.T) {
+	provider := newAnthropicProvider(t, "http://localhost")
+
+	result, err := provider.Embed(context.Background(), []string{})
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+
+	_, err = provider.Embed(context.Background(), []string{"text"})
+	assert.Error(t, err)
+}
@@ -4,39 +4,91 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"regexp"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/smithy-go/auth/bearer"
 
 	"personal-assistant/internal/domain"
 	"personal-assistant/internal/log"
 )
 
+const (
+	// bedrockDefaultRegion is used when neither the provider config, the base URL nor AWS_REGION name a region
+	bedrockDefaultRegion = "us-west-2"
+	// bedrockCohereBatchSize is the maximum number of texts per Cohere embedding request
+	bedrockCohereBatchSize = 96
+	// bedrockDefaultEmbedConcurrency bounds parallel requests for models that embed one text per call
+	bedrockDefaultEmbedConcurrency = 4
+)
+
+// reasoningTags matches the reasoning that gpt-oss models prepend to their answers
+var reasoningTags = regexp.MustCompile(`(?s)<reasoning>.*?</reasoning>`)
+
+// BedrockProvider implements the LLMProvider interface on Amazon Bedrock.
+// Chat models are called with the OpenAI chat completions format served by
+// the gpt-oss models; embeddings support Amazon Titan and Cohere models.
+//
+// The provider config may set "region", "access_key_id", "secret_access_key"
+// and "session_token". Without access keys the api_key column is sent as a
+// Bedrock API key, and otherwise the AWS_* environment variables are used.
 type BedrockProvider struct {
-	client     *bedrockruntime.Client
-	config     *domain.LLMProviderConfig
-	logger     *log.Logger
-	chatModel  string
-	embedModel string
+	client           *bedrockruntime.Client
+	config           *domain.LLMProviderConfig
+	logger           *log.Logger
+	chatModel        string
+	embedModel       string
+	embedConcurrency int
 }
 
+// NewBedrockProvider creates a new Bedrock provider
 func NewBedrockProvider(config *domain.LLMProviderConfig, logger *log.Logger) (*BedrockProvider, error) {
-	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
-	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
-	
-	if accessKey == "" || secretKey == "" {
-		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required")
+	cfg := aws.Config{
+		Region: bedrockRegion(config),
 	}
 
-	cfg := aws.Config{
-		Region:      "us-west-2",
-		Credentials: credentials.NewStaticCredentialsProvider(accessKey, secretKey, ""),
+	var optFns []func(*bedrockruntime.Options)
+	accessKey := config.ConfigString("access_key_id", "")
+	secretKey := config.ConfigString("secret_access_key", "")
+
+	switch {
+	case accessKey != "" && secretKey != "":
+		cfg.Credentials = credentials.NewStaticCredentialsProvider(accessKey, secretKey, config.ConfigString("session_token", ""))
+	case config.APIKey != "":
+		apiKey := config.APIKey
+		optFns = append(optFns, func(o *bedrockruntime.Options) {
+			o.BearerAuthTokenProvider = bearer.TokenProviderFunc(func(ctx context.Context) (bearer.Token, error) {
+				return bearer.Token{Value: apiKey}, nil
+			})
+			o.AuthSchemePreference = []string{"httpBearerAuth"}
+		})
+	case os.Getenv("AWS_ACCESS_KEY_ID") != "" && os.Getenv("AWS_SECRET_ACCESS_KEY") != "":
+		cfg.Credentials = credentials.NewStaticCredentialsProvider(
+			os.Getenv("AWS_ACCESS_KEY_ID"),
+			os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			os.Getenv("AWS_SESSION_TOKEN"),
+		)
+	case os.Getenv("AWS_BEARER_TOKEN_BEDROCK") != "":
+		// Picked up by the Bedrock client itself
+	default:
+		return nil, fmt.Errorf("Bedrock credentials are required: set access_key_id and secret_access_key in the provider config, the api_key, or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+
+	// Use custom endpoint if provided
+	if config.BaseURL != "" {
+		optFns = append(optFns, func(o *bedrockruntime.Options) {
+			o.BaseEndpoint = aws.String(config.BaseURL)
+		})
 	}
 
-	client := bedrockruntime.NewFromConfig(cfg)
+	client := bedrockruntime.NewFromConfig(cfg, optFns...)
 
 	chatModel := config.ModelChat
 	if chatModel == "" {
@@ -48,69 +100,266 @@ func NewBedrockProvider(config *domain.LLMProviderConfig, logger *log.Logger) (*
 		embedModel = "amazon.titan-embed-text-v2:0"
 	}
 
+	embedConcurrency := configInt(config, "embed_concurrency", bedrockDefaultEmbedConcurrency)
+	if embedConcurrency < 1 {
+		embedConcurrency = 1
+	}
+
 	return &BedrockProvider{
-		client:     client,
-		config:     config,
-		logger:     logger,
-		chatModel:  chatModel,
-		embedModel: embedModel,
+		client:           client,
+		config:           config,
+		logger:           logger,
+		chatModel:        chatModel,
+		embedModel:       embedModel,
+		embedConcurrency: embedConcurrency,
 	}, nil
 }
 
+// bedrockRegion resolves the AWS region from the provider config, the
+// regional endpoint in the base URL or the environment
+func bedrockRegion(config *domain.LLMProviderConfig) string {
+	if region := config.ConfigString("region", ""); region != "" {
+		return region
+	}
+
+	// https://bedrock-runtime.<region>.amazonaws.com
+	if config.BaseURL != "" {
+		if parsed, err := url.Parse(config.BaseURL); err == nil {
+			parts := strings.Split(parsed.Hostname(), ".")
+			if len(parts) >= 4 && strings.HasPrefix(parts[0], "bedrock-runtime") {
+				return parts[1]
+			}
+		}
+	}
+
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		return region
+	}
+	return bedrockDefaultRegion
+}
+
+// ChatModel returns the chat model name
+func (p *BedrockProvider) ChatModel() string {
+	return p.chatModel
+}
+
+// EmbedModel returns the embedding model name
+func (p *BedrockProvider) EmbedModel() string {
+	return p.embedModel
+}
+
 func (p *BedrockProvider) Name() string {
 	return "bedrock"
 }
 
 func (p *BedrockProvider) Chat(ctx context.Context, req *domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+	start := time.Now()
+
 	body, err := p.buildBedrockRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
+	p.logger.WithContext(ctx).Debug().
+		Str("model", p.chatModel).
+		Int("messages", len(req.Messages)).
+		Int("tools", len(req.Tools)).
+		Msg("making Bedrock chat completion request")
+
 	result, err := p.client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
-		ModelId: aws.String(p.chatModel),
-		Body:    body,
+		ModelId:     aws.String(p.chatModel),
+		Body:        body,
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
 	})
 	if err != nil {
+		p.logger.WithContext(ctx).Error().
+			Err(err).
+			Dur("duration", time.Since(start)).
+			Msg("Bedrock chat completion failed")
 		return nil, fmt.Errorf("bedrock invoke failed: %w", err)
 	}
 
-	return p.parseBedrockResponse(result.Body)
+	domainResp, err := p.parseBedrockResponse(result.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bedrock response: %w", err)
+	}
+
+	// Log token usage
+	if domainResp.Usage != nil {
+		p.logger.LogTokenUsage("bedrock_chat",
+			domainResp.Usage.PromptTokens,
+			domainResp.Usage.CompletionTokens,
+			domainResp.Usage.TotalTokens)
+	}
+
+	return domainResp, nil
 }
 
+// Embed generates embeddings for the given texts. Cohere models embed up to
+// 96 texts per request; Titan models embed one text per request, so those
+// requests run in parallel.
 func (p *BedrockProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
-	var embeddings [][]float32
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+
+	if strings.Contains(p.embedModel, "cohere.") {
+		return p.embedCohere(ctx, texts)
+	}
+	return p.embedTitan(ctx, texts)
+}
+
+// embedCohere embeds texts in batches with a Cohere model
+func (p *BedrockProvider) embedCohere(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
 
-	for _, text := range texts {
-		body, _ := json.Marshal(map[string]string{"inputText": text})
+	for start := 0; start < len(texts); start += bedrockCohereBatchSize {
+		end := start + bedrockCohereBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
 
-		result, err := p.client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
-			ModelId:     aws.String(p.embedModel),
-			Body:        body,
-			ContentType: aws.String("application/json"),
+		body, err := json.Marshal(map[string]interface{}{
+			"texts":      texts[start:end],
+			"input_type": "search_document",
 		})
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to marshal embedding request: %w", err)
 		}
 
 		var resp struct {
-			Embedding []float32 `json:"embedding"`
+			Embeddings [][]float32 `json:"embeddings"`
+		}
+		if err := p.invokeEmbedding(ctx, body, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Embeddings) != end-start {
+			return nil, fmt.Errorf("bedrock returned %d embeddings for %d texts", len(resp.Embeddings), end-start)
 		}
-		json.Unmarshal(result.Body, &resp)
-		embeddings = append(embeddings, resp.Embedding)
+
+		embeddings = append(embeddings, resp.Embeddings...)
 	}
 
 	return embeddings, nil
 }
 
+// embedTitan embeds each text with a Titan model, keeping the input order
+func (p *BedrockProvider) embedTitan(ctx context.Context, texts []string) ([][]float32, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	embeddings := make([][]float32, len(texts))
+	errs := make([]error, len(texts))
+	slots := make(chan struct{}, p.embedConcurrency)
+
+	var wg sync.WaitGroup
+	for i, text := range texts {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func(i int, text string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			body, err := json.Marshal(map[string]string{"inputText": text})
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to marshal embedding request: %w", err)
+				cancel()
+				return
+			}
+
+			var resp struct {
+				Embedding []float32 `json:"embedding"`
+			}
+			if err := p.invokeEmbedding(ctx, body, &resp); err != nil {
+				errs[i] = err
+				cancel()
+				return
+			}
+			embeddings[i] = resp.Embedding
+		}(i, text)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return embeddings, nil
+}
+
+// invokeEmbedding calls the embedding model and decodes its response
+func (p *BedrockProvider) invokeEmbedding(ctx context.Context, body []byte, resp interface{}) error {
+	result, err := p.client.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(p.embedModel),
+		Body:        body,
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("bedrock embedding failed: %w", err)
+	}
+
+	if err := json.Unmarshal(result.Body, resp); err != nil {
+		return fmt.Errorf("failed to parse bedrock embedding response: %w", err)
+	}
+	return nil
+}
+
+// bedrockMessage is a message in the OpenAI chat completions format
+type bedrockMessage struct {
+	Role       string            `json:"role"`
+	Content    string            `json:"content"`
+	ToolCalls  []bedrockToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string            `json:"tool_call_id,omitempty"`
+	Name       string            `json:"name,omitempty"`
+}
+
+// bedrockToolCall is a tool call; arguments are a JSON-encoded string
+type bedrockToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
 func (p *BedrockProvider) buildBedrockRequest(req *domain.ChatCompletionRequest) ([]byte, error) {
-	messages := []map[string]string{}
+	messages := make([]bedrockMessage, 0, len(req.Messages))
 
 	for _, msg := range req.Messages {
-		messages = append(messages, map[string]string{
-			"role":    msg.Role,
-			"content": msg.Content,
-		})
+		bedrockMsg := bedrockMessage{
+			Role:       msg.Role,
+			Content:    msg.Content,
+			ToolCallID: msg.ToolCallID,
+			Name:       msg.Name,
+		}
+
+		for _, toolCall := range msg.ToolCalls {
+			if toolCall.Function == nil {
+				continue
+			}
+
+			call := bedrockToolCall{
+				ID:   toolCall.ID,
+				Type: toolCall.Type,
+			}
+			if call.Type == "" {
+				call.Type = "function"
+			}
+			call.Function.Name = toolCall.Function.Name
+			call.Function.Arguments = string(toolCall.Function.Arguments)
+			if call.Function.Arguments == "" {
+				call.Function.Arguments = "{}"
+			}
+
+			bedrockMsg.ToolCalls = append(bedrockMsg.ToolCalls, call)
+		}
+
+		messages = append(messages, bedrockMsg)
 	}
 
 	body := map[string]interface{}{
@@ -121,19 +370,32 @@ func (p *BedrockProvider) buildBedrockRequest(req *domain.ChatCompletionRequest)
 		"stream":                false,
 	}
 
+	// Tool definitions share the OpenAI format
+	if len(req.Tools) > 0 {
+		body["tools"] = req.Tools
+		if req.ToolChoice != nil {
+			body["tool_choice"] = req.ToolChoice
+		}
+	}
+
 	return json.Marshal(body)
 }
 
 func (p *BedrockProvider) stripReasoningTags(content string) string {
-	re := regexp.MustCompile(`<reasoning>.*?</reasoning>`)
-	return re.ReplaceAllString(content, "")
+	return strings.TrimSpace(reasoningTags.ReplaceAllString(content, ""))
 }
 
 func (p *BedrockProvider) parseBedrockResponse(body []byte) (*domain.ChatCompletionResponse, error) {
 	var resp struct {
+		ID      string `json:"id"`
+		Created int64  `json:"created"`
+		Model   string `json:"model"`
 		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
+			Index        int    `json:"index"`
+			FinishReason string `json:"finish_reason"`
+			Message      struct {
+				Content   string            `json:"content"`
+				ToolCalls []bedrockToolCall `json:"tool_calls"`
 			} `json:"message"`
 		} `json:"choices"`
 		Usage struct {
@@ -147,22 +409,66 @@ func (p *BedrockProvider) parseBedrockResponse(body []byte) (*domain.ChatComplet
 		return nil, err
 	}
 
-	content := ""
-	if len(resp.Choices) > 0 {
-		content = p.stripReasoningTags(resp.Choices[0].Message.Content)
-	}
-
-	return &domain.ChatCompletionResponse{
-		Choices: []domain.Choice{{
-			Message: &domain.ChatMessage{
-				Role:    "assistant",
-				Content: content,
-			},
-		}},
+	domainResp := &domain.ChatCompletionResponse{
+		ID:      resp.ID,
+		Object:  "chat.completion",
+		Created: resp.Created,
+		Model:   resp.Model,
 		Usage: &domain.TokenUsage{
 			PromptTokens:     resp.Usage.PromptTokens,
 			CompletionTokens: resp.Usage.CompletionTokens,
 			TotalTokens:      resp.Usage.TotalTokens,
 		},
-	}, nil
+	}
+
+	for _, choice := range resp.Choices {
+		message := &domain.ChatMessage{
+			Role:    "assistant",
+			Content: p.stripReasoningTags(choice.Message.Content),
+		}
+
+		for _, toolCall := range choice.Message.ToolCalls {
+			arguments := toolCall.Function.Arguments
+			if arguments == "" {
+				arguments = "{}"
+			}
+			message.ToolCalls = append(message.ToolCalls, domain.ToolCall{
+				ID:   toolCall.ID,
+				Type: "function",
+				Function: &domain.FunctionCall{
+					Name:      toolCall.Function.Name,
+					Arguments: json.RawMessage(arguments),
+				},
+			})
+		}
+
+		domainResp.Choices = append(domainResp.Choices, domain.Choice{
+			Index:        choice.Index,
+			Message:      message,
+			FinishReason: choice.FinishReason,
+		})
+	}
+
+	// Callers read the first choice
+	if len(domainResp.Choices) == 0 {
+		domainResp.Choices = []domain.Choice{{
+			Message: &domain.ChatMessage{Role: "assistant"},
+		}}
+	}
+
+	return domainResp, nil
+}
+
+// configInt reads an integer option from the provider config, which holds
+// float64 when decoded from JSON and int when decoded from YAML
+func configInt(config *domain.LLMProviderConfig, key string, fallback int) int {
+	switch value := config.Config[key].(type) {
+	case int:
+		return value
+	case int64:
+		return int(value)
+	case float64:
+		return int(value)
+	}
+	return fallback
 }
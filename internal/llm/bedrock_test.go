@@ -0,0 +1,232 @@
+package llm_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/llm"
+	"personal-assistant/internal/log"
+)
+
+// bedrockStub is an httptest stand-in for the Bedrock InvokeModel API
+type bedrockStub struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	requests map[string][]map[string]interface{} // Request bodies by model ID
+}
+
+func newBedrockStub(t *testing.T, respond func(modelID string, body map[string]interface{}) interface{}) *bedrockStub {
+	stub := &bedrockStub{requests: make(map[string][]map[string]interface{})}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// POST /model/{modelId}/invoke
+		modelID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/model/"), "/invoke")
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		stub.mutex.Lock()
+		stub.requests[modelID] = append(stub.requests[modelID], body)
+		stub.mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(respond(modelID, body))
+	}))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func newBedrockProvider(t *testing.T, config *domain.LLMProviderConfig) *llm.BedrockProvider {
+	provider, err := llm.NewBedrockProvider(config, log.Init("error"))
+	require.NoError(t, err)
+	return provider
+}
+
+func TestNewBedrockProvider(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_BEARER_TOKEN_BEDROCK", "")
+
+	t.Run("requires credentials", func(t *testing.T) {
+		_, err := llm.NewBedrockProvider(&domain.LLMProviderConfig{Name: "test"}, log.Init("error"))
+		assert.Error(t, err)
+	})
+
+	t.Run("accepts access keys from the provider config", func(t *testing.T) {
+		provider := newBedrockProvider(t, &domain.LLMProviderConfig{
+			Name:   "test",
+			Config: map[string]interface{}{"region": "eu-central-1", "access_key_id": "AKID", "secret_access_key": "secret"},
+		})
+		assert.Equal(t, "openai.gpt-oss-120b-1:0", provider.ChatModel())
+		assert.Equal(t, "amazon.titan-embed-text-v2:0", provider.EmbedModel())
+	})
+
+	t.Run("accepts a Bedrock API key", func(t *testing.T) {
+		_, err := llm.NewBedrockProvider(&domain.LLMProviderConfig{Name: "test", APIKey: "bedrock-api-key"}, log.Init("error"))
+		assert.NoError(t, err)
+	})
+}
+
+func TestBedrockProvider_Chat(t *testing.T) {
+	ctx := context.Background()
+	config := func(baseURL string) *domain.LLMProviderConfig {
+		return &domain.LLMProviderConfig{
+			Name:    "test",
+			BaseURL: baseURL,
+			Config:  map[string]interface{}{"region": "us-east-1", "access_key_id": "AKID", "secret_access_key": "secret"},
+		}
+	}
+
+	t.Run("sends tools and parses tool calls", func(t *testing.T) {
+		stub := newBedrockStub(t, func(string, map[string]interface{}) interface{} {
+			return map[string]interface{}{
+				"id": "chatcmpl-1",
+				"choices": []interface{}{map[string]interface{}{
+					"index":         0,
+					"finish_reason": "tool_calls",
+					"message": map[string]interface{}{
+						"role":    "assistant",
+						"content": "<reasoning>The user wants\nthe weather.</reasoning>",
+						"tool_calls": []interface{}{map[string]interface{}{
+							"id":       "call_1",
+							"type":     "function",
+							"function": map[string]interface{}{"name": "get_weather", "arguments": `{"location":"NYC"}`},
+						}},
+					},
+				}},
+				"usage": map[string]interface{}{"prompt_tokens": 20, "completion_tokens": 8, "total_tokens": 28},
+			}
+		})
+		provider := newBedrockProvider(t, config(stub.URL))
+
+		resp, err := provider.Chat(ctx, &domain.ChatCompletionRequest{
+			Messages: []domain.ChatMessage{{Role: "user", Content: "Weather in NYC?"}},
+			Tools: []domain.ToolDefinition{{
+				Type: "function",
+				Function: &domain.ToolFunction{
+					Name:        "get_weather",
+					Description: "Get weather information",
+					Parameters:  map[string]interface{}{"type": "object"},
+				},
+			}},
+			ToolChoice: "auto",
+			MaxTokens:  100,
+		})
+		require.NoError(t, err)
+
+		request := stub.requests["openai.gpt-oss-120b-1:0"][0]
+		assert.Equal(t, "auto", request["tool_choice"])
+		tools := request["tools"].([]interface{})
+		require.Len(t, tools, 1)
+		assert.Equal(t, "get_weather", tools[0].(map[string]interface{})["function"].(map[string]interface{})["name"])
+
+		message := resp.Choices[0].Message
+		assert.Equal(t, "tool_calls", resp.Choices[0].FinishReason)
+		assert.Empty(t, message.Content)
+		require.Len(t, message.ToolCalls, 1)
+		assert.Equal(t, "call_1", message.ToolCalls[0].ID)
+		assert.Equal(t, "get_weather", message.ToolCalls[0].Function.Name)
+		assert.JSONEq(t, `{"location":"NYC"}`, string(message.ToolCalls[0].Function.Arguments))
+		assert.Equal(t, 28, resp.Usage.TotalTokens)
+	})
+
+	t.Run("sends tool calls and tool results", func(t *testing.T) {
+		stub := newBedrockStub(t, func(string, map[string]interface{}) interface{} {
+			return map[string]interface{}{
+				"choices": []interface{}{map[string]interface{}{
+					"finish_reason": "stop",
+					"message":       map[string]interface{}{"role": "assistant", "content": "It's sunny."},
+				}},
+			}
+		})
+		provider := newBedrockProvider(t, config(stub.URL))
+
+		resp, err := provider.Chat(ctx, &domain.ChatCompletionRequest{
+			Messages: []domain.ChatMessage{
+				{Role: "user", Content: "Weather in NYC?"},
+				{Role: "assistant", ToolCalls: []domain.ToolCall{{
+					ID:       "call_1",
+					Type:     "function",
+					Function: &domain.FunctionCall{Name: "get_weather", Arguments: json.RawMessage(`{"location":"NYC"}`)},
+				}}},
+				{Role: "tool", ToolCallID: "call_1", Content: `{"weather":"sunny"}`},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "It's sunny.", resp.Choices[0].Message.Content)
+
+		messages := stub.requests["openai.gpt-oss-120b-1:0"][0]["messages"].([]interface{})
+		require.Len(t, messages, 3)
+		assert.Equal(t, []interface{}{map[string]interface{}{
+			"id":       "call_1",
+			"type":     "function",
+			"function": map[string]interface{}{"name": "get_weather", "arguments": `{"location":"NYC"}`},
+		}}, messages[1].(map[string]interface{})["tool_calls"])
+		assert.Equal(t, "call_1", messages[2].(map[string]interface{})["tool_call_id"])
+		assert.Equal(t, "tool", messages[2].(map[string]interface{})["role"])
+	})
+}
+
+func TestBedrockProvider_Embed(t *testing.T) {
+	ctx := context.Background()
+	credentials := map[string]interface{}{"region": "us-east-1", "access_key_id": "AKID", "secret_access_key": "secret"}
+
+	t.Run("titan embeds each text in order", func(t *testing.T) {
+		stub := newBedrockStub(t, func(_ string, body map[string]interface{}) interface{} {
+			return map[string]interface{}{"embedding": []float32{float32(len(body["inputText"].(string)))}}
+		})
+		provider := newBedrockProvider(t, &domain.LLMProviderConfig{Name: "test", BaseURL: stub.URL, Config: credentials})
+
+		embeddings, err := provider.Embed(ctx, []string{"a", "bb", "ccc", "dddd", "eeeee"})
+		require.NoError(t, err)
+		assert.Equal(t, [][]float32{{1}, {2}, {3}, {4}, {5}}, embeddings)
+		assert.Len(t, stub.requests["amazon.titan-embed-text-v2:0"], 5)
+	})
+
+	t.Run("cohere embeds texts in batches", func(t *testing.T) {
+		stub := newBedrockStub(t, func(_ string, body map[string]interface{}) interface{} {
+			texts := body["texts"].([]interface{})
+			embeddings := make([][]float32, len(texts))
+			for i := range texts {
+				embeddings[i] = []float32{1}
+			}
+			return map[string]interface{}{"embeddings": embeddings}
+		})
+		provider := newBedrockProvider(t, &domain.LLMProviderConfig{
+			Name:       "test",
+			BaseURL:    stub.URL,
+			ModelEmbed: "cohere.embed-multilingual-v3",
+			Config:     credentials,
+		})
+
+		texts := make([]string, 100)
+		for i := range texts {
+			texts[i] = "text"
+		}
+
+		embeddings, err := provider.Embed(ctx, texts)
+		require.NoError(t, err)
+		assert.Len(t, embeddings, 100)
+
+		requests := stub.requests["cohere.embed-multilingual-v3"]
+		require.Len(t, requests, 2)
+		assert.Len(t, requests[0]["texts"], 96)
+		assert.Len(t, requests[1]["texts"], 4)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		provider := newBedrockProvider(t, &domain.LLMProviderConfig{Name: "test", Config: credentials})
+
+		embeddings, err := provider.Embed(ctx, []string{})
+		assert.NoError(t, err)
+		assert.Empty(t, embeddings)
+	})
+}
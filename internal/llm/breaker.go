@@ -0,0 +1,162 @@
+package llm
+
+import (
+	"sync"
+	"time"
+
+	"personal-assistant/internal/config"
+)
+
+// BreakerState is the state of a circuit breaker
+type BreakerState string
+
+const (
+	// BreakerClosed lets every call through
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects calls until the open duration has passed
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single probe call through to test the provider
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// CircuitBreaker tracks the error rate of a provider over its most recent
+// calls. Once the rate reaches the configured threshold the circuit opens and
+// the provider is skipped; after the open duration a single probe decides
+// whether it closes again.
+type CircuitBreaker struct {
+	config *config.LLMFallbackConfig
+
+	mutex    sync.Mutex
+	state    BreakerState
+	outcomes []bool // Ring buffer of recent outcomes, true for failures
+	next     int
+	count    int
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker creates a new closed circuit breaker
+func NewCircuitBreaker(cfg *config.LLMFallbackConfig) *CircuitBreaker {
+	window := cfg.WindowSize
+	if window < 1 {
+		window = 1
+	}
+
+	return &CircuitBreaker{
+		config:   cfg,
+		state:    BreakerClosed,
+		outcomes: make([]bool, window),
+	}
+}
+
+// State returns the current state of the breaker
+func (b *CircuitBreaker) State() BreakerState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.config.OpenDuration {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// Allow reports whether a call may be made. A call allowed while half-open
+// is the probe and must be followed by Success, Failure or Release.
+func (b *CircuitBreaker) Allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.config.OpenDuration {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Success records a successful call
+func (b *CircuitBreaker) Success() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case BreakerHalfOpen:
+		b.state = BreakerClosed
+		b.probing = false
+		b.reset()
+	case BreakerClosed:
+		b.record(false)
+	}
+}
+
+// Failure records a failed call, opening the circuit when the error rate is reached
+func (b *CircuitBreaker) Failure() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case BreakerHalfOpen:
+		b.open()
+	case BreakerClosed:
+		b.record(true)
+		if b.count >= b.config.MinRequests && float64(b.failures) >= b.config.FailureRate*float64(b.count) {
+			b.open()
+		}
+	}
+}
+
+// Release gives up a probe whose outcome says nothing about the provider,
+// such as a call cancelled by the caller
+func (b *CircuitBreaker) Release() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.probing = false
+}
+
+// record adds an outcome to the window, dropping the oldest one when full
+func (b *CircuitBreaker) record(failure bool) {
+	if b.count == len(b.outcomes) {
+		if b.outcomes[b.next] {
+			b.failures--
+		}
+	} else {
+		b.count++
+	}
+
+	b.outcomes[b.next] = failure
+	if failure {
+		b.failures++
+	}
+	b.next = (b.next + 1) % len(b.outcomes)
+}
+
+// open opens the circuit and starts a new window
+func (b *CircuitBreaker) open() {
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+	b.probing = false
+	b.reset()
+}
+
+// reset clears the recorded outcomes
+func (b *CircuitBreaker) reset() {
+	for i := range b.outcomes {
+		b.outcomes[i] = false
+	}
+	b.next = 0
+	b.count = 0
+	b.failures = 0
+}
@@ -0,0 +1,103 @@
+package llm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/llm"
+)
+
+func newBreakerConfig() *config.LLMFallbackConfig {
+	return &config.LLMFallbackConfig{
+		FailureRate:  0.5,
+		MinRequests:  4,
+		WindowSize:   4,
+		OpenDuration: 20 * time.Millisecond,
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("stays closed below the minimum number of calls", func(t *testing.T) {
+		breaker := llm.NewCircuitBreaker(newBreakerConfig())
+
+		for i := 0; i < 3; i++ {
+			assert.True(t, breaker.Allow())
+			breaker.Failure()
+		}
+		assert.Equal(t, llm.BreakerClosed, breaker.State())
+	})
+
+	t.Run("opens when the failure rate is reached", func(t *testing.T) {
+		breaker := llm.NewCircuitBreaker(newBreakerConfig())
+
+		breaker.Success()
+		breaker.Success()
+		breaker.Failure()
+		assert.Equal(t, llm.BreakerClosed, breaker.State())
+
+		breaker.Failure()
+		assert.Equal(t, llm.BreakerOpen, breaker.State())
+		assert.False(t, breaker.Allow())
+	})
+
+	t.Run("forgets outcomes outside the window", func(t *testing.T) {
+		breaker := llm.NewCircuitBreaker(newBreakerConfig())
+
+		breaker.Failure()
+		for i := 0; i < 4; i++ {
+			breaker.Success()
+		}
+		breaker.Failure()
+		assert.Equal(t, llm.BreakerClosed, breaker.State())
+	})
+
+	t.Run("closes after a successful probe", func(t *testing.T) {
+		breaker := llm.NewCircuitBreaker(newBreakerConfig())
+		for i := 0; i < 4; i++ {
+			breaker.Failure()
+		}
+		assert.False(t, breaker.Allow())
+
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, llm.BreakerHalfOpen, breaker.State())
+
+		// Only one probe at a time
+		assert.True(t, breaker.Allow())
+		assert.False(t, breaker.Allow())
+
+		breaker.Success()
+		assert.Equal(t, llm.BreakerClosed, breaker.State())
+		assert.True(t, breaker.Allow())
+	})
+
+	t.Run("reopens after a failed probe", func(t *testing.T) {
+		breaker := llm.NewCircuitBreaker(newBreakerConfig())
+		for i := 0; i < 4; i++ {
+			breaker.Failure()
+		}
+
+		time.Sleep(30 * time.Millisecond)
+		assert.True(t, breaker.Allow())
+		breaker.Failure()
+
+		assert.Equal(t, llm.BreakerOpen, breaker.State())
+		assert.False(t, breaker.Allow())
+	})
+
+	t.Run("releases a probe without an outcome", func(t *testing.T) {
+		breaker := llm.NewCircuitBreaker(newBreakerConfig())
+		for i := 0; i < 4; i++ {
+			breaker.Failure()
+		}
+
+		time.Sleep(30 * time.Millisecond)
+		assert.True(t, breaker.Allow())
+		breaker.Release()
+
+		assert.Equal(t, llm.BreakerHalfOpen, breaker.State())
+		assert.True(t, breaker.Allow())
+	})
+}
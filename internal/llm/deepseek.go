@@ -9,6 +9,7 @@ import (
 	"github.com/sashabaranov/go-openai"
 
 	"personal-assistant/internal/domain"
+	openaiProvider "personal-assistant/internal/llm/openai"
 	"personal-assistant/internal/log"
 )
 
@@ -113,6 +114,48 @@ func (p *DeepSeekProvider) Chat(ctx context.Context, req *domain.ChatCompletionR
 	return domainResp, nil
 }
 
+// ChatStream performs a streaming chat completion
+func (p *DeepSeekProvider) ChatStream(ctx context.Context, req *domain.ChatCompletionRequest, onDelta func(*domain.ChatDelta) error) (*domain.ChatCompletionResponse, error) {
+	start := time.Now()
+
+	openaiReq := p.convertChatRequest(req)
+	openaiReq.Stream = true
+	openaiReq.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+
+	p.logger.WithContext(ctx).Debug().
+		Str("model", openaiReq.Model).
+		Int("messages", len(openaiReq.Messages)).
+		Int("max_tokens", openaiReq.MaxTokens).
+		Msg("making DeepSeek streaming chat completion request")
+
+	stream, err := p.client.CreateChatCompletionStream(ctx, openaiReq)
+	if err != nil {
+		p.logger.WithContext(ctx).Error().
+			Err(err).
+			Dur("duration", time.Since(start)).
+			Msg("DeepSeek streaming chat completion failed")
+		return nil, fmt.Errorf("DeepSeek chat completion stream failed: %w", err)
+	}
+	defer stream.Close()
+
+	// The stream format is the same as OpenAI's
+	domainResp, err := openaiProvider.ReadChatStream(stream, onDelta)
+	if err != nil {
+		p.logger.WithContext(ctx).Error().
+			Err(err).
+			Dur("duration", time.Since(start)).
+			Msg("DeepSeek streaming chat completion failed")
+		return nil, fmt.Errorf("DeepSeek chat completion stream failed: %w", err)
+	}
+
+	p.logger.WithContext(ctx).Debug().
+		Str("finish_reason", domainResp.Choices[0].FinishReason).
+		Dur("duration", time.Since(start)).
+		Msg("DeepSeek streaming chat completion successful")
+
+	return domainResp, nil
+}
+
 // Embed generates embeddings for the given texts
 func (p *DeepSeekProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
 	if len(texts) == 0 {
@@ -211,7 +254,7 @@ func (p *DeepSeekProvider) convertChatRequest(req *domain.ChatCompletionRequest)
 			}
 
 			if tool.Function != nil {
-				openaiTool.Function = openai.FunctionDefinition{
+				openaiTool.Function = &openai.FunctionDefinition{
 					Name:        tool.Function.Name,
 					Description: tool.Function.Description,
 					Parameters:  tool.Function.Parameters,
@@ -2,6 +2,9 @@ package llm_test
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"personal-assistant/internal/domain"
@@ -9,6 +12,7 @@ import (
 	"personal-assistant/internal/log"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewDeepSeekProvider_Defaults(t *testing.T) {
@@ -32,3 +36,30 @@ func TestDeepSeekProvider_Embed_EmptyInput(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Empty(t, result)
 }
+
+func TestDeepSeekProvider_ChatStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"model\":\"deepseek-chat\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"Hello\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"model\":\"deepseek-chat\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\" there\"},\"finish_reason\":\"stop\"}]}\n\n")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"model\":\"deepseek-chat\",\"choices\":[],\"usage\":{\"prompt_tokens\":5,\"completion_tokens\":2,\"total_tokens\":7}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	provider, err := llm.NewDeepSeekProvider(&domain.LLMProviderConfig{APIKey: "dummy-key", Name: "test", BaseURL: server.URL}, log.Init("error"))
+	require.NoError(t, err)
+
+	var content []string
+	resp, err := provider.ChatStream(context.Background(), &domain.ChatCompletionRequest{
+		Messages: []domain.ChatMessage{{Role: "user", Content: "Hi"}},
+	}, func(delta *domain.ChatDelta) error {
+		content = append(content, delta.Content)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Hello", " there"}, content)
+	assert.Equal(t, "Hello there", resp.Choices[0].Message.Content)
+	assert.Equal(t, "stop", resp.Choices[0].FinishReason)
+	assert.Equal(t, &domain.TokenUsage{PromptTokens: 5, CompletionTokens: 2, TotalTokens: 7}, resp.Usage)
+}
@@ -0,0 +1,18 @@
+package llm
+
+import "fmt"
+
+// APIError is an error response from an LLM provider's HTTP API
+type APIError struct {
+	StatusCode int
+	Type       string // Provider-specific error type, if any
+	Message    string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("API error (status %d, %s): %s", e.StatusCode, e.Type, e.Message)
+	}
+	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Message)
+}
@@ -0,0 +1,227 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+// FallbackMember is a provider in a fallback chain with its circuit breaker
+type FallbackMember struct {
+	Name     string // Name of the llm_providers row
+	Provider domain.LLMProvider
+	Breaker  *CircuitBreaker
+}
+
+// FallbackProvider implements the LLMProvider interface over an ordered list
+// of providers. Chat tries each provider whose circuit is closed and moves on
+// to the next one on timeouts, transport errors, rate limits and 5xx
+// responses; other errors are returned as they are. The response names the
+// provider that served it.
+//
+// Embed always uses the first provider: vectors from different embedding
+// models cannot be compared, so they must not be mixed in one vector store.
+type FallbackProvider struct {
+	members []FallbackMember
+	config  *config.LLMFallbackConfig
+	logger  *log.Logger
+}
+
+// NewFallbackProvider creates a new fallback provider
+func NewFallbackProvider(members []FallbackMember, cfg *config.LLMFallbackConfig, logger *log.Logger) (*FallbackProvider, error) {
+	if len(members) == 0 {
+		return nil, fmt.Errorf("fallback provider requires at least one provider")
+	}
+
+	return &FallbackProvider{
+		members: members,
+		config:  cfg,
+		logger:  logger,
+	}, nil
+}
+
+// Name returns the provider name
+func (f *FallbackProvider) Name() string {
+	names := make([]string, len(f.members))
+	for i, member := range f.members {
+		names[i] = member.Provider.Name()
+	}
+	return "fallback(" + strings.Join(names, ",") + ")"
+}
+
+// Chat performs a chat completion with the first available provider
+func (f *FallbackProvider) Chat(ctx context.Context, req *domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+	return f.run(ctx, func(ctx context.Context, provider domain.LLMProvider) (*domain.ChatCompletionResponse, error) {
+		return provider.Chat(ctx, req)
+	}, nil)
+}
+
+// ChatStream performs a streaming chat completion with the first available
+// provider. Providers that cannot stream pass their response on as a single
+// delta. Once a delta has been passed on the caller may have acted on it, so
+// a provider failing after that is not failed over.
+func (f *FallbackProvider) ChatStream(ctx context.Context, req *domain.ChatCompletionRequest, onDelta func(*domain.ChatDelta) error) (*domain.ChatCompletionResponse, error) {
+	started := false
+	forward := func(delta *domain.ChatDelta) error {
+		started = true
+		return onDelta(delta)
+	}
+
+	return f.run(ctx, func(ctx context.Context, provider domain.LLMProvider) (*domain.ChatCompletionResponse, error) {
+		if streamer, ok := provider.(domain.ChatStreamer); ok {
+			return streamer.ChatStream(ctx, req, forward)
+		}
+
+		resp, err := provider.Chat(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Choices) > 0 && resp.Choices[0].Message != nil {
+			if err := forward(responseDelta(&resp.Choices[0])); err != nil {
+				return nil, err
+			}
+		}
+		return resp, nil
+	}, func() bool { return started })
+}
+
+// run calls each provider whose circuit is closed until one succeeds.
+// committed, when set, reports whether output already reached the caller,
+// after which failing over is no longer possible.
+func (f *FallbackProvider) run(ctx context.Context, call func(context.Context, domain.LLMProvider) (*domain.ChatCompletionResponse, error), committed func() bool) (*domain.ChatCompletionResponse, error) {
+	logger := f.logger.WithContext(ctx)
+
+	var lastErr error
+	for i, member := range f.members {
+		if !member.Breaker.Allow() {
+			logger.Debug().
+				Str("provider_name", member.Name).
+				Msg("skipping LLM provider with open circuit")
+			continue
+		}
+
+		resp, err := f.attempt(ctx, member, call)
+		if err == nil {
+			member.Breaker.Success()
+			resp.Provider = member.Name
+			if i > 0 {
+				logger.Info().
+					Str("provider_name", member.Name).
+					Int("position", i).
+					Msg("LLM request served by fallback provider")
+			}
+			return resp, nil
+		}
+
+		// The caller gave up, or the failure may lie with the caller's delta
+		// handling, which says nothing about the provider
+		if ctx.Err() != nil || (committed != nil && committed()) {
+			member.Breaker.Release()
+			return nil, err
+		}
+
+		if !IsRetryableError(err) {
+			member.Breaker.Success()
+			return nil, err
+		}
+
+		member.Breaker.Failure()
+		lastErr = err
+
+		logger.Warn().
+			Err(err).
+			Str("provider_name", member.Name).
+			Str("circuit", string(member.Breaker.State())).
+			Msg("LLM provider failed, trying next provider")
+	}
+
+	if lastErr == nil {
+		return nil, fmt.Errorf("all LLM providers unavailable: circuits open")
+	}
+	return nil, fmt.Errorf("all LLM providers failed: %w", lastErr)
+}
+
+// attempt calls a single provider within the per-provider timeout
+func (f *FallbackProvider) attempt(ctx context.Context, member FallbackMember, call func(context.Context, domain.LLMProvider) (*domain.ChatCompletionResponse, error)) (*domain.ChatCompletionResponse, error) {
+	if f.config.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.config.AttemptTimeout)
+		defer cancel()
+	}
+
+	return call(ctx, member.Provider)
+}
+
+// responseDelta turns a complete choice into a single delta
+func responseDelta(choice *domain.Choice) *domain.ChatDelta {
+	delta := &domain.ChatDelta{
+		Content:      choice.Message.Content,
+		FinishReason: choice.FinishReason,
+	}
+	for i, toolCall := range choice.Message.ToolCalls {
+		toolCallDelta := domain.ToolCallDelta{Index: i, ID: toolCall.ID}
+		if toolCall.Function != nil {
+			toolCallDelta.Name = toolCall.Function.Name
+			toolCallDelta.Arguments = string(toolCall.Function.Arguments)
+		}
+		delta.ToolCalls = append(delta.ToolCalls, toolCallDelta)
+	}
+	return delta
+}
+
+// Embed generates embeddings with the first provider
+func (f *FallbackProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return f.members[0].Provider.Embed(ctx, texts)
+}
+
+// IsRetryableError reports whether another provider may succeed where this
+// error occurred: timeouts, transport errors, rate limits and 5xx responses
+func IsRetryableError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	// Timeouts and refused or reset connections
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	status := statusCode(err)
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// statusCode extracts the HTTP status code from the errors of the provider clients
+func statusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+
+	var openaiErr *openai.APIError
+	if errors.As(err, &openaiErr) {
+		return openaiErr.HTTPStatusCode
+	}
+
+	var requestErr *openai.RequestError
+	if errors.As(err, &requestErr) {
+		return requestErr.HTTPStatusCode
+	}
+
+	// AWS SDK response errors
+	var responseErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &responseErr) {
+		return responseErr.HTTPStatusCode()
+	}
+
+	return 0
+}
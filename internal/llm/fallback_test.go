@@ -0,0 +1,269 @@
+package llm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/llm"
+	"personal-assistant/internal/log"
+)
+
+// scriptedProvider is an LLMProvider that fails with err, or answers with its name
+type scriptedProvider struct {
+	name  string
+	err   error
+	delay time.Duration
+	calls int
+}
+
+func (p *scriptedProvider) Name() string { return p.name }
+
+func (p *scriptedProvider) Chat(ctx context.Context, req *domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+	p.calls++
+
+	if p.delay > 0 {
+		select {
+		case <-time.After(p.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return &domain.ChatCompletionResponse{
+		Choices: []domain.Choice{{Message: &domain.ChatMessage{Role: "assistant", Content: "from " + p.name}}},
+	}, nil
+}
+
+func (p *scriptedProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	return [][]float32{{float32(len(p.name))}}, nil
+}
+
+// streamingProvider streams its chunks and then fails with err, if set
+type streamingProvider struct {
+	scriptedProvider
+	chunks []string
+}
+
+func (p *streamingProvider) ChatStream(ctx context.Context, req *domain.ChatCompletionRequest, onDelta func(*domain.ChatDelta) error) (*domain.ChatCompletionResponse, error) {
+	p.calls++
+
+	for _, chunk := range p.chunks {
+		if err := onDelta(&domain.ChatDelta{Content: chunk}); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return &domain.ChatCompletionResponse{
+		Choices: []domain.Choice{{Message: &domain.ChatMessage{Role: "assistant", Content: "streamed by " + p.name}}},
+	}, nil
+}
+
+func newFallbackConfig() *config.LLMFallbackConfig {
+	return &config.LLMFallbackConfig{
+		AttemptTimeout: time.Second,
+		FailureRate:    0.5,
+		MinRequests:    2,
+		WindowSize:     10,
+		OpenDuration:   time.Minute,
+	}
+}
+
+func newFallback(t *testing.T, cfg *config.LLMFallbackConfig, providers ...domain.LLMProvider) *llm.FallbackProvider {
+	members := make([]llm.FallbackMember, len(providers))
+	for i, provider := range providers {
+		members[i] = llm.FallbackMember{
+			Name:     provider.Name(),
+			Provider: provider,
+			Breaker:  llm.NewCircuitBreaker(cfg),
+		}
+	}
+
+	fallback, err := llm.NewFallbackProvider(members, cfg, log.Init("error"))
+	require.NoError(t, err)
+	return fallback
+}
+
+func chatRequest() *domain.ChatCompletionRequest {
+	return &domain.ChatCompletionRequest{Messages: []domain.ChatMessage{{Role: "user", Content: "Hello"}}}
+}
+
+func TestFallbackProvider_Chat(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("uses the first provider", func(t *testing.T) {
+		primary := &scriptedProvider{name: "primary"}
+		secondary := &scriptedProvider{name: "secondary"}
+		fallback := newFallback(t, newFallbackConfig(), primary, secondary)
+
+		resp, err := fallback.Chat(ctx, chatRequest())
+		require.NoError(t, err)
+		assert.Equal(t, "primary", resp.Provider)
+		assert.Equal(t, "from primary", resp.Choices[0].Message.Content)
+		assert.Equal(t, 0, secondary.calls)
+		assert.Equal(t, "fallback(primary,secondary)", fallback.Name())
+	})
+
+	t.Run("fails over on server errors and rate limits", func(t *testing.T) {
+		for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+			primary := &scriptedProvider{name: "primary", err: &llm.APIError{StatusCode: status, Message: "unavailable"}}
+			secondary := &scriptedProvider{name: "secondary"}
+			fallback := newFallback(t, newFallbackConfig(), primary, secondary)
+
+			resp, err := fallback.Chat(ctx, chatRequest())
+			require.NoError(t, err)
+			assert.Equal(t, "secondary", resp.Provider)
+			assert.Equal(t, 1, primary.calls)
+		}
+	})
+
+	t.Run("fails over on timeouts", func(t *testing.T) {
+		cfg := newFallbackConfig()
+		cfg.AttemptTimeout = 10 * time.Millisecond
+		primary := &scriptedProvider{name: "primary", delay: time.Second}
+		secondary := &scriptedProvider{name: "secondary"}
+		fallback := newFallback(t, cfg, primary, secondary)
+
+		resp, err := fallback.Chat(ctx, chatRequest())
+		require.NoError(t, err)
+		assert.Equal(t, "secondary", resp.Provider)
+	})
+
+	t.Run("returns client errors without failing over", func(t *testing.T) {
+		primary := &scriptedProvider{name: "primary", err: &llm.APIError{StatusCode: http.StatusBadRequest, Message: "invalid request"}}
+		secondary := &scriptedProvider{name: "secondary"}
+		fallback := newFallback(t, newFallbackConfig(), primary, secondary)
+
+		_, err := fallback.Chat(ctx, chatRequest())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid request")
+		assert.Equal(t, 0, secondary.calls)
+	})
+
+	t.Run("skips providers with an open circuit", func(t *testing.T) {
+		primary := &scriptedProvider{name: "primary", err: &llm.APIError{StatusCode: http.StatusBadGateway}}
+		secondary := &scriptedProvider{name: "secondary"}
+		fallback := newFallback(t, newFallbackConfig(), primary, secondary)
+
+		for i := 0; i < 4; i++ {
+			resp, err := fallback.Chat(ctx, chatRequest())
+			require.NoError(t, err)
+			assert.Equal(t, "secondary", resp.Provider)
+		}
+		assert.Equal(t, 2, primary.calls)
+		assert.Equal(t, 4, secondary.calls)
+	})
+
+	t.Run("reports when every provider failed", func(t *testing.T) {
+		primary := &scriptedProvider{name: "primary", err: &llm.APIError{StatusCode: http.StatusInternalServerError}}
+		secondary := &scriptedProvider{name: "secondary", err: &llm.APIError{StatusCode: http.StatusServiceUnavailable, Message: "overloaded"}}
+		fallback := newFallback(t, newFallbackConfig(), primary, secondary)
+
+		_, err := fallback.Chat(ctx, chatRequest())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "all LLM providers failed")
+		assert.Contains(t, err.Error(), "overloaded")
+
+		_, err = fallback.Chat(ctx, chatRequest())
+		require.Error(t, err)
+
+		// Both circuits are now open
+		_, err = fallback.Chat(ctx, chatRequest())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "circuits open")
+		assert.Equal(t, 2, primary.calls)
+	})
+}
+
+func TestFallbackProvider_ChatStream(t *testing.T) {
+	ctx := context.Background()
+
+	collect := func(content *[]string) func(*domain.ChatDelta) error {
+		return func(delta *domain.ChatDelta) error {
+			*content = append(*content, delta.Content)
+			return nil
+		}
+	}
+
+	t.Run("fails over before the first delta", func(t *testing.T) {
+		primary := &streamingProvider{scriptedProvider: scriptedProvider{name: "primary", err: &llm.APIError{StatusCode: http.StatusServiceUnavailable}}}
+		secondary := &streamingProvider{scriptedProvider: scriptedProvider{name: "secondary"}, chunks: []string{"Hello", " there"}}
+		fallback := newFallback(t, newFallbackConfig(), primary, secondary)
+
+		var content []string
+		resp, err := fallback.ChatStream(ctx, chatRequest(), collect(&content))
+		require.NoError(t, err)
+		assert.Equal(t, "secondary", resp.Provider)
+		assert.Equal(t, []string{"Hello", " there"}, content)
+	})
+
+	t.Run("does not fail over once deltas were passed on", func(t *testing.T) {
+		primary := &streamingProvider{scriptedProvider: scriptedProvider{name: "primary", err: &llm.APIError{StatusCode: http.StatusBadGateway}}, chunks: []string{"Hel"}}
+		secondary := &streamingProvider{scriptedProvider: scriptedProvider{name: "secondary"}, chunks: []string{"Hello"}}
+		fallback := newFallback(t, newFallbackConfig(), primary, secondary)
+
+		var content []string
+		_, err := fallback.ChatStream(ctx, chatRequest(), collect(&content))
+		require.Error(t, err)
+		assert.Equal(t, []string{"Hel"}, content)
+		assert.Equal(t, 0, secondary.calls)
+	})
+
+	t.Run("passes on the response of providers that cannot stream", func(t *testing.T) {
+		primary := &scriptedProvider{name: "primary"}
+		fallback := newFallback(t, newFallbackConfig(), primary, &scriptedProvider{name: "secondary"})
+
+		var content []string
+		resp, err := fallback.ChatStream(ctx, chatRequest(), collect(&content))
+		require.NoError(t, err)
+		assert.Equal(t, "primary", resp.Provider)
+		assert.Equal(t, []string{"from primary"}, content)
+	})
+}
+
+func TestFallbackProvider_Embed(t *testing.T) {
+	primary := &scriptedProvider{name: "primary", err: &llm.APIError{StatusCode: http.StatusServiceUnavailable}}
+	secondary := &scriptedProvider{name: "secondary-embedder"}
+	fallback := newFallback(t, newFallbackConfig(), primary, secondary)
+
+	embeddings, err := fallback.Embed(context.Background(), []string{"text"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{float32(len("primary"))}}, embeddings)
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{"deadline exceeded", fmt.Errorf("request failed: %w", context.DeadlineExceeded), true},
+		{"rate limited", &llm.APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"server error", fmt.Errorf("chat failed: %w", &llm.APIError{StatusCode: http.StatusBadGateway}), true},
+		{"unauthorized", &llm.APIError{StatusCode: http.StatusUnauthorized}, false},
+		{"canceled", context.Canceled, false},
+		{"plain error", errors.New("failed to parse response"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.retryable, llm.IsRetryableError(tt.err))
+		})
+	}
+}
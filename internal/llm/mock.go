@@ -106,6 +106,46 @@ func (p *MockProvider) Chat(ctx context.Context, req *domain.ChatCompletionReque
 	return resp, nil
 }
 
+// ChatStream performs a mock chat completion and replays it as a stream,
+// word by word for text and in two fragments for tool call arguments
+func (p *MockProvider) ChatStream(ctx context.Context, req *domain.ChatCompletionRequest, onDelta func(*domain.ChatDelta) error) (*domain.ChatCompletionResponse, error) {
+	resp, err := p.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	choice := resp.Choices[0]
+	for _, word := range strings.SplitAfter(choice.Message.Content, " ") {
+		if word == "" {
+			continue
+		}
+		if err := onDelta(&domain.ChatDelta{Content: word}); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, toolCall := range choice.Message.ToolCalls {
+		arguments := string(toolCall.Function.Arguments)
+		half := len(arguments) / 2
+
+		deltas := []domain.ToolCallDelta{
+			{Index: i, ID: toolCall.ID, Name: toolCall.Function.Name, Arguments: arguments[:half]},
+			{Index: i, Arguments: arguments[half:]},
+		}
+		for _, delta := range deltas {
+			if err := onDelta(&domain.ChatDelta{ToolCalls: []domain.ToolCallDelta{delta}}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := onDelta(&domain.ChatDelta{FinishReason: choice.FinishReason}); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // Embed generates mock embeddings
 func (p *MockProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
 	if len(texts) == 0 {
@@ -221,3 +261,49 @@ func (p *MockProvider) normalize(vec []float32) []float32 {
 	}
 	return normalized
 }
+
+// MockTranscriber implements the Transcriber interface for testing
+type MockTranscriber struct {
+	config *domain.LLMProviderConfig
+	logger *log.Logger
+}
+
+// NewMockTranscriber creates a new mock transcriber. The returned text can be
+// fixed through the "mock_transcript" provider config option.
+func NewMockTranscriber(config *domain.LLMProviderConfig, logger *log.Logger) (*MockTranscriber, error) {
+	return &MockTranscriber{
+		config: config,
+		logger: logger,
+	}, nil
+}
+
+// Name returns the transcriber name
+func (t *MockTranscriber) Name() string {
+	return fmt.Sprintf("mock-%s", t.config.Name)
+}
+
+// Transcribe returns a deterministic transcript for the given audio
+func (t *MockTranscriber) Transcribe(ctx context.Context, req *domain.TranscriptionRequest) (*domain.Transcription, error) {
+	if len(req.Audio) == 0 {
+		return nil, fmt.Errorf("audio is empty")
+	}
+
+	t.logger.WithContext(ctx).Debug().
+		Int("audio_bytes", len(req.Audio)).
+		Msg("mock transcription request")
+
+	text, _ := t.config.Config["mock_transcript"].(string)
+	if text == "" {
+		text = fmt.Sprintf("This is a mock transcription of %d bytes of audio.", len(req.Audio))
+	}
+
+	language := req.Language
+	if language == "" {
+		language = "en"
+	}
+
+	return &domain.Transcription{
+		Text:     text,
+		Language: language,
+	}, nil
+}
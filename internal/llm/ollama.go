@@ -0,0 +1,444 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/llm/openai"
+	"personal-assistant/internal/log"
+)
+
+// OllamaProvider implements the LLMProvider interface on Ollama's native API,
+// so tenants can run against a local model server without a cloud API key.
+//
+// The provider config may set "keep_alive" (how long the model stays loaded,
+// e.g. "10m" or -1) and "num_ctx" (the context window in tokens). The
+// api_key is optional and only sent for servers behind an authenticating proxy.
+//
+// Embeddings must have domain.EmbeddingDimensions to be stored, which the
+// common local embedding models do not produce. The embedding model is
+// rejected at creation when its size is known ("embedding_dimensions" in the
+// config or ollamaEmbeddingDimensions) and differs; embeddings can instead go
+// to an OpenAI-compatible endpoint set with "embedding_base_url".
+type OllamaProvider struct {
+	client     *http.Client
+	config     *domain.LLMProviderConfig
+	logger     *log.Logger
+	baseURL    string
+	chatModel  string
+	embedModel string
+	keepAlive  interface{}
+	numCtx     int
+	embedder   domain.LLMProvider
+}
+
+// ollamaEmbeddingDimensions lists the vector size of common Ollama embedding models
+var ollamaEmbeddingDimensions = map[string]int{
+	"nomic-embed-text":       768,
+	"mxbai-embed-large":      1024,
+	"snowflake-arctic-embed": 1024,
+	"bge-m3":                 1024,
+	"all-minilm":             384,
+}
+
+// NewOllamaProvider creates a new Ollama provider
+func NewOllamaProvider(config *domain.LLMProviderConfig, logger *log.Logger) (*OllamaProvider, error) {
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = GetDefaultBaseURL(Ollama)
+	}
+
+	// Use configured models or defaults
+	defaultChat, defaultEmbed := GetDefaultModels(Ollama)
+	chatModel := config.ModelChat
+	if chatModel == "" {
+		chatModel = defaultChat
+	}
+
+	embedModel := config.ModelEmbed
+	if embedModel == "" {
+		embedModel = defaultEmbed
+	}
+
+	// keep_alive is either a duration string or a number of seconds
+	var keepAlive interface{}
+	switch value := config.Config["keep_alive"].(type) {
+	case string:
+		if value != "" {
+			keepAlive = value
+		}
+	case int, int64, float64:
+		keepAlive = value
+	}
+
+	provider := &OllamaProvider{
+		// Local models can take a while to load and answer
+		client:     &http.Client{Timeout: 5 * time.Minute},
+		config:     config,
+		logger:     logger,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		chatModel:  chatModel,
+		embedModel: embedModel,
+		keepAlive:  keepAlive,
+		numCtx:     configInt(config, "num_ctx", 0),
+	}
+
+	// Embeddings go to an OpenAI-compatible endpoint when configured
+	if embedURL := config.ConfigString("embedding_base_url", ""); embedURL != "" {
+		embedder, err := openai.NewProvider(&domain.LLMProviderConfig{
+			Provider:   string(OpenAI),
+			Name:       config.Name,
+			APIKey:     config.ConfigString("embedding_api_key", ""),
+			BaseURL:    embedURL,
+			ModelEmbed: config.ModelEmbed,
+		}, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create embedding provider: %w", err)
+		}
+		provider.embedder = embedder
+		return provider, nil
+	}
+
+	if embedModel != "" {
+		dimensions := configInt(config, "embedding_dimensions", ollamaEmbeddingDimensions[strings.Split(embedModel, ":")[0]])
+		if dimensions != 0 && dimensions != domain.EmbeddingDimensions {
+			return nil, fmt.Errorf("Ollama embedding model %s produces %d dimensions but memories store %d; use a %d-dimension model or set embedding_base_url",
+				embedModel, dimensions, domain.EmbeddingDimensions, domain.EmbeddingDimensions)
+		}
+	}
+
+	return provider, nil
+}
+
+// ChatModel returns the chat model name
+func (p *OllamaProvider) ChatModel() string {
+	return p.chatModel
+}
+
+// EmbedModel returns the embedding model name
+func (p *OllamaProvider) EmbedModel() string {
+	return p.embedModel
+}
+
+// Name returns the provider name
+func (p *OllamaProvider) Name() string {
+	return fmt.Sprintf("ollama-%s", p.config.Name)
+}
+
+// Chat performs a chat completion
+func (p *OllamaProvider) Chat(ctx context.Context, req *domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+	start := time.Now()
+
+	ollamaReq := p.convertChatRequest(req)
+
+	p.logger.WithContext(ctx).Debug().
+		Str("model", ollamaReq.Model).
+		Int("messages", len(ollamaReq.Messages)).
+		Int("tools", len(ollamaReq.Tools)).
+		Msg("making Ollama chat request")
+
+	var resp ollamaChatResponse
+	if err := p.post(ctx, "/api/chat", ollamaReq, &resp); err != nil {
+		p.logger.WithContext(ctx).Error().
+			Err(err).
+			Dur("duration", time.Since(start)).
+			Msg("Ollama chat failed")
+		return nil, fmt.Errorf("Ollama chat failed: %w", err)
+	}
+
+	// Convert response
+	domainResp := p.convertChatResponse(&resp)
+
+	// Log token usage
+	if domainResp.Usage != nil {
+		p.logger.LogTokenUsage("ollama_chat",
+			domainResp.Usage.PromptTokens,
+			domainResp.Usage.CompletionTokens,
+			domainResp.Usage.TotalTokens)
+	}
+
+	p.logger.WithContext(ctx).Debug().
+		Str("finish_reason", domainResp.Choices[0].FinishReason).
+		Dur("duration", time.Since(start)).
+		Msg("Ollama chat successful")
+
+	return domainResp, nil
+}
+
+// Embed generates embeddings for the given texts in a single request
+func (p *OllamaProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+	if p.embedder != nil {
+		return p.embedder.Embed(ctx, texts)
+	}
+	if p.embedModel == "" {
+		return nil, fmt.Errorf("no Ollama embedding model configured; set model_embed to a %d-dimension model or embedding_base_url in the provider config",
+			domain.EmbeddingDimensions)
+	}
+
+	start := time.Now()
+
+	p.logger.WithContext(ctx).Debug().
+		Str("model", p.embedModel).
+		Int("texts", len(texts)).
+		Msg("making Ollama embedding request")
+
+	req := &ollamaEmbedRequest{
+		Model:     p.embedModel,
+		Input:     texts,
+		KeepAlive: p.keepAlive,
+		Options:   p.options(nil),
+	}
+
+	var resp struct {
+		Embeddings      [][]float32 `json:"embeddings"`
+		PromptEvalCount int         `json:"prompt_eval_count"`
+	}
+	if err := p.post(ctx, "/api/embed", req, &resp); err != nil {
+		p.logger.WithContext(ctx).Error().
+			Err(err).
+			Dur("duration", time.Since(start)).
+			Msg("Ollama embedding failed")
+		return nil, fmt.Errorf("Ollama embedding failed: %w", err)
+	}
+
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("Ollama returned %d embeddings for %d texts", len(resp.Embeddings), len(texts))
+	}
+	for _, embedding := range resp.Embeddings {
+		if len(embedding) != domain.EmbeddingDimensions {
+			return nil, fmt.Errorf("Ollama embedding model %s produced %d dimensions but memories store %d",
+				p.embedModel, len(embedding), domain.EmbeddingDimensions)
+		}
+	}
+
+	// Log token usage
+	if resp.PromptEvalCount > 0 {
+		p.logger.LogTokenUsage("ollama_embed", resp.PromptEvalCount, 0, resp.PromptEvalCount)
+	}
+
+	return resp.Embeddings, nil
+}
+
+// ollamaChatRequest is the body of an /api/chat request
+type ollamaChatRequest struct {
+	Model     string                  `json:"model"`
+	Messages  []ollamaMessage         `json:"messages"`
+	Tools     []domain.ToolDefinition `json:"tools,omitempty"`
+	Stream    bool                    `json:"stream"`
+	KeepAlive interface{}             `json:"keep_alive,omitempty"`
+	Options   map[string]interface{}  `json:"options,omitempty"`
+}
+
+// ollamaEmbedRequest is the body of an /api/embed request
+type ollamaEmbedRequest struct {
+	Model     string                 `json:"model"`
+	Input     []string               `json:"input"`
+	KeepAlive interface{}            `json:"keep_alive,omitempty"`
+	Options   map[string]interface{} `json:"options,omitempty"`
+}
+
+// ollamaMessage is a chat message; tool results name the tool instead of a call ID
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"`
+}
+
+// ollamaToolCall is a tool call; arguments are a JSON object
+type ollamaToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+// ollamaChatResponse is the body of a non-streaming /api/chat response
+type ollamaChatResponse struct {
+	Model           string        `json:"model"`
+	CreatedAt       time.Time     `json:"created_at"`
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	DoneReason      string        `json:"done_reason"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+}
+
+// options returns the model options for a request
+func (p *OllamaProvider) options(req *domain.ChatCompletionRequest) map[string]interface{} {
+	options := make(map[string]interface{})
+	if p.numCtx > 0 {
+		options["num_ctx"] = p.numCtx
+	}
+	if req != nil {
+		if req.MaxTokens > 0 {
+			options["num_predict"] = req.MaxTokens
+		}
+		if req.Temperature > 0 {
+			options["temperature"] = req.Temperature
+		}
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	return options
+}
+
+// convertChatRequest converts domain request to an /api/chat request
+func (p *OllamaProvider) convertChatRequest(req *domain.ChatCompletionRequest) *ollamaChatRequest {
+	ollamaReq := &ollamaChatRequest{
+		Model:     p.chatModel,
+		Stream:    false,
+		KeepAlive: p.keepAlive,
+		Options:   p.options(req),
+	}
+
+	// Tool results only carry the call ID, so remember which tool each call used
+	toolNames := make(map[string]string)
+
+	for _, msg := range req.Messages {
+		ollamaMsg := ollamaMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		}
+
+		for _, toolCall := range msg.ToolCalls {
+			if toolCall.Function == nil {
+				continue
+			}
+			toolNames[toolCall.ID] = toolCall.Function.Name
+
+			var call ollamaToolCall
+			call.Function.Name = toolCall.Function.Name
+			call.Function.Arguments = toolCall.Function.Arguments
+			if len(bytes.TrimSpace(call.Function.Arguments)) == 0 {
+				call.Function.Arguments = json.RawMessage("{}")
+			}
+			ollamaMsg.ToolCalls = append(ollamaMsg.ToolCalls, call)
+		}
+
+		if msg.Role == "tool" {
+			ollamaMsg.ToolName = msg.Name
+			if ollamaMsg.ToolName == "" {
+				ollamaMsg.ToolName = toolNames[msg.ToolCallID]
+			}
+		}
+
+		ollamaReq.Messages = append(ollamaReq.Messages, ollamaMsg)
+	}
+
+	// Tool definitions share the OpenAI format
+	for _, tool := range req.Tools {
+		if tool.Function != nil {
+			ollamaReq.Tools = append(ollamaReq.Tools, tool)
+		}
+	}
+
+	return ollamaReq
+}
+
+// convertChatResponse converts an /api/chat response to domain response.
+// Ollama does not identify tool calls, so IDs are generated from their position.
+func (p *OllamaProvider) convertChatResponse(resp *ollamaChatResponse) *domain.ChatCompletionResponse {
+	domainMsg := &domain.ChatMessage{
+		Role:    "assistant",
+		Content: resp.Message.Content,
+	}
+
+	for i, toolCall := range resp.Message.ToolCalls {
+		arguments := toolCall.Function.Arguments
+		if len(arguments) == 0 || string(arguments) == "null" {
+			arguments = json.RawMessage("{}")
+		}
+		domainMsg.ToolCalls = append(domainMsg.ToolCalls, domain.ToolCall{
+			ID:   fmt.Sprintf("call_%d", i),
+			Type: "function",
+			Function: &domain.FunctionCall{
+				Name:      toolCall.Function.Name,
+				Arguments: arguments,
+			},
+		})
+	}
+
+	finishReason := resp.DoneReason
+	switch {
+	case len(domainMsg.ToolCalls) > 0:
+		finishReason = "tool_calls"
+	case finishReason == "":
+		finishReason = "stop"
+	}
+
+	created := resp.CreatedAt.Unix()
+	if resp.CreatedAt.IsZero() {
+		created = time.Now().Unix()
+	}
+
+	return &domain.ChatCompletionResponse{
+		Object:  "chat.completion",
+		Created: created,
+		Model:   resp.Model,
+		Choices: []domain.Choice{{
+			Index:        0,
+			Message:      domainMsg,
+			FinishReason: finishReason,
+		}},
+		Usage: &domain.TokenUsage{
+			PromptTokens:     resp.PromptEvalCount,
+			CompletionTokens: resp.EvalCount,
+			TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+		},
+	}
+}
+
+// post sends a JSON request to the Ollama API and decodes the response
+func (p *OllamaProvider) post(ctx context.Context, path string, body interface{}, resp interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.config.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	}
+
+	httpResp, err := p.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error != "" {
+			return &APIError{StatusCode: httpResp.StatusCode, Message: apiErr.Error}
+		}
+		return &APIError{StatusCode: httpResp.StatusCode, Message: string(respBody)}
+	}
+
+	if err := json.Unmarshal(respBody, resp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package llm_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/llm"
+	"personal-assistant/internal/log"
+)
+
+// ollamaStub is an httptest stand-in for the Ollama API that records the last request
+type ollamaStub struct {
+	*httptest.Server
+	path    string
+	header  http.Header
+	request map[string]interface{}
+}
+
+func newOllamaStub(t *testing.T, status int, response string) *ollamaStub {
+	stub := &ollamaStub{}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stub.path = r.URL.Path
+		stub.header = r.Header.Clone()
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&stub.request))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func newOllamaProvider(t *testing.T, baseURL string, config map[string]interface{}) *llm.OllamaProvider {
+	provider, err := llm.NewOllamaProvider(&domain.LLMProviderConfig{
+		Provider: "ollama",
+		Name:     "test",
+		BaseURL:  baseURL,
+		Config:   config,
+	}, log.Init("error"))
+	require.NoError(t, err)
+	return provider
+}
+
+func TestNewOllamaProvider(t *testing.T) {
+	t.Run("defaults without an API key", func(t *testing.T) {
+		provider, err := llm.NewFactory(log.Init("error")).CreateProvider(&domain.LLMProviderConfig{Provider: "ollama", Name: "test"})
+		require.NoError(t, err)
+		assert.Equal(t, "ollama-test", provider.Name())
+
+		ollama := provider.(*llm.OllamaProvider)
+		assert.Equal(t, "llama3.1", ollama.ChatModel())
+		assert.Empty(t, ollama.EmbedModel())
+
+		_, err = ollama.Embed(context.Background(), []string{"text"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no Ollama embedding model configured")
+	})
+
+	t.Run("rejects embedding models of another size", func(t *testing.T) {
+		_, err := llm.NewOllamaProvider(&domain.LLMProviderConfig{
+			Provider:   "ollama",
+			Name:       "test",
+			ModelEmbed: "nomic-embed-text:latest",
+		}, log.Init("error"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "produces 768 dimensions")
+
+		_, err = llm.NewOllamaProvider(&domain.LLMProviderConfig{
+			Provider:   "ollama",
+			Name:       "test",
+			ModelEmbed: "custom-embed",
+			Config:     map[string]interface{}{"embedding_dimensions": float64(1024)},
+		}, log.Init("error"))
+		require.Error(t, err)
+	})
+
+	t.Run("accepts any embedding model with an embedding endpoint", func(t *testing.T) {
+		_, err := llm.NewOllamaProvider(&domain.LLMProviderConfig{
+			Provider:   "ollama",
+			Name:       "test",
+			ModelEmbed: "text-embedding-3-small",
+			Config: map[string]interface{}{
+				"embedding_base_url": "https://api.openai.com/v1",
+				"embedding_api_key":  "sk-test",
+			},
+		}, log.Init("error"))
+		require.NoError(t, err)
+	})
+}
+
+func TestOllamaProvider_Chat(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sends options and parses tool calls", func(t *testing.T) {
+		stub := newOllamaStub(t, http.StatusOK, `{
+			"model": "llama3.1",
+			"created_at": "2024-07-22T20:33:28.123648Z",
+			"message": {
+				"role": "assistant",
+				"content": "",
+				"tool_calls": [{"function": {"name": "get_weather", "arguments": {"location": "NYC"}}}]
+			},
+			"done": true,
+			"done_reason": "stop",
+			"prompt_eval_count": 30,
+			"eval_count": 12
+		}`)
+		provider := newOllamaProvider(t, stub.URL, map[string]interface{}{"keep_alive": "10m", "num_ctx": float64(8192)})
+
+		resp, err := provider.Chat(ctx, &domain.ChatCompletionRequest{
+			Messages: []domain.ChatMessage{
+				{Role: "system", Content: "You are helpful."},
+				{Role: "user", Content: "Weather in NYC?"},
+			},
+			Tools: []domain.ToolDefinition{{
+				Type: "function",
+				Function: &domain.ToolFunction{
+					Name:        "get_weather",
+					Description: "Get weather information",
+					Parameters:  map[string]interface{}{"type": "object"},
+				},
+			}},
+			MaxTokens:   200,
+			Temperature: 0.2,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "/api/chat", stub.path)
+		assert.Empty(t, stub.header.Get("Authorization"))
+		assert.Equal(t, "llama3.1", stub.request["model"])
+		assert.Equal(t, false, stub.request["stream"])
+		assert.Equal(t, "10m", stub.request["keep_alive"])
+		options := stub.request["options"].(map[string]interface{})
+		assert.Equal(t, float64(8192), options["num_ctx"])
+		assert.Equal(t, float64(200), options["num_predict"])
+		assert.InDelta(t, 0.2, options["temperature"], 0.0001)
+		assert.Len(t, stub.request["messages"], 2)
+		assert.Len(t, stub.request["tools"], 1)
+
+		message := resp.Choices[0].Message
+		assert.Equal(t, "tool_calls", resp.Choices[0].FinishReason)
+		require.Len(t, message.ToolCalls, 1)
+		assert.Equal(t, "call_0", message.ToolCalls[0].ID)
+		assert.Equal(t, "function", message.ToolCalls[0].Type)
+		assert.Equal(t, "get_weather", message.ToolCalls[0].Function.Name)
+		assert.JSONEq(t, `{"location": "NYC"}`, string(message.ToolCalls[0].Function.Arguments))
+		assert.Equal(t, &domain.TokenUsage{PromptTokens: 30, CompletionTokens: 12, TotalTokens: 42}, resp.Usage)
+	})
+
+	t.Run("sends tool results with the tool name", func(t *testing.T) {
+		stub := newOllamaStub(t, http.StatusOK, `{
+			"model": "llama3.1",
+			"message": {"role": "assistant", "content": "It's sunny."},
+			"done": true,
+			"done_reason": "stop"
+		}`)
+		provider := newOllamaProvider(t, stub.URL, nil)
+
+		resp, err := provider.Chat(ctx, &domain.ChatCompletionRequest{
+			Messages: []domain.ChatMessage{
+				{Role: "user", Content: "Weather in NYC?"},
+				{Role: "assistant", ToolCalls: []domain.ToolCall{{
+					ID:       "call_0",
+					Type:     "function",
+					Function: &domain.FunctionCall{Name: "get_weather", Arguments: json.RawMessage(`{"location":"NYC"}`)},
+				}}},
+				{Role: "tool", ToolCallID: "call_0", Content: `{"weather":"sunny"}`},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "It's sunny.", resp.Choices[0].Message.Content)
+		assert.Equal(t, "stop", resp.Choices[0].FinishReason)
+		assert.NotContains(t, stub.request, "keep_alive")
+		assert.NotContains(t, stub.request, "options")
+
+		messages := stub.request["messages"].([]interface{})
+		require.Len(t, messages, 3)
+		assert.Equal(t, []interface{}{map[string]interface{}{
+			"function": map[string]interface{}{"name": "get_weather", "arguments": map[string]interface{}{"location": "NYC"}},
+		}}, messages[1].(map[string]interface{})["tool_calls"])
+		assert.Equal(t, map[string]interface{}{
+			"role":      "tool",
+			"content":   `{"weather":"sunny"}`,
+			"tool_name": "get_weather",
+		}, messages[2])
+	})
+
+	t.Run("returns API errors", func(t *testing.T) {
+		stub := newOllamaStub(t, http.StatusNotFound, `{"error": "model \"llama3.1\" not found, try pulling it first"}`)
+		provider := newOllamaProvider(t, stub.URL, nil)
+
+		_, err := provider.Chat(ctx, &domain.ChatCompletionRequest{
+			Messages: []domain.ChatMessage{{Role: "user", Content: "Hello"}},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "try pulling it first")
+	})
+}
+
+func TestOllamaProvider_Embed(t *testing.T) {
+	embedding := make([]float32, domain.EmbeddingDimensions)
+	embedding[0] = 0.5
+	body, err := json.Marshal(map[string]interface{}{
+		"model":             "custom-embed",
+		"embeddings":        [][]float32{embedding, embedding},
+		"prompt_eval_count": 8,
+	})
+	require.NoError(t, err)
+
+	stub := newOllamaStub(t, http.StatusOK, string(body))
+	provider, err := llm.NewOllamaProvider(&domain.LLMProviderConfig{
+		Provider:   "ollama",
+		Name:       "test",
+		BaseURL:    stub.URL,
+		ModelEmbed: "custom-embed",
+		Config:     map[string]interface{}{"keep_alive": float64(-1)},
+	}, log.Init("error"))
+	require.NoError(t, err)
+
+	embeddings, err := provider.Embed(context.Background(), []string{"first", "second"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{embedding, embedding}, embeddings)
+
+	assert.Equal(t, "/api/embed", stub.path)
+	assert.Equal(t, "custom-embed", stub.request["model"])
+	assert.Equal(t, []interface{}{"first", "second"}, stub.request["input"])
+	assert.Equal(t, float64(-1), stub.request["keep_alive"])
+
+	empty, err := provider.Embed(context.Background(), []string{})
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+
+	t.Run("rejects embeddings of another size", func(t *testing.T) {
+		stub := newOllamaStub(t, http.StatusOK, `{"embeddings": [[0.1, 0.2]]}`)
+		provider, err := llm.NewOllamaProvider(&domain.LLMProviderConfig{
+			Provider:   "ollama",
+			Name:       "test",
+			BaseURL:    stub.URL,
+			ModelEmbed: "custom-embed",
+		}, log.Init("error"))
+		require.NoError(t, err)
+
+		_, err = provider.Embed(context.Background(), []string{"first"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "produced 2 dimensions")
+	})
+}
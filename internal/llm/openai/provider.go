@@ -3,7 +3,10 @@ package openai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/sashabaranov/go-openai"
@@ -100,6 +103,47 @@ func (p *Provider) Chat(ctx context.Context, req *domain.ChatCompletionRequest)
 	return domainResp, nil
 }
 
+// ChatStream performs a streaming chat completion
+func (p *Provider) ChatStream(ctx context.Context, req *domain.ChatCompletionRequest, onDelta func(*domain.ChatDelta) error) (*domain.ChatCompletionResponse, error) {
+	start := time.Now()
+	
+	openaiReq := p.convertChatRequest(req)
+	openaiReq.Stream = true
+	openaiReq.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+	
+	p.logger.WithContext(ctx).Debug().
+		Str("model", openaiReq.Model).
+		Int("messages", len(openaiReq.Messages)).
+		Int("max_tokens", openaiReq.MaxTokens).
+		Msg("making OpenAI streaming chat completion request")
+	
+	stream, err := p.client.CreateChatCompletionStream(ctx, openaiReq)
+	if err != nil {
+		p.logger.WithContext(ctx).Error().
+			Err(err).
+			Dur("duration", time.Since(start)).
+			Msg("OpenAI streaming chat completion failed")
+		return nil, fmt.Errorf("OpenAI chat completion stream failed: %w", err)
+	}
+	defer stream.Close()
+	
+	domainResp, err := ReadChatStream(stream, onDelta)
+	if err != nil {
+		p.logger.WithContext(ctx).Error().
+			Err(err).
+			Dur("duration", time.Since(start)).
+			Msg("OpenAI streaming chat completion failed")
+		return nil, fmt.Errorf("OpenAI chat completion stream failed: %w", err)
+	}
+	
+	p.logger.WithContext(ctx).Debug().
+		Str("finish_reason", domainResp.Choices[0].FinishReason).
+		Dur("duration", time.Since(start)).
+		Msg("OpenAI streaming chat completion successful")
+	
+	return domainResp, nil
+}
+
 // Embed generates embeddings for the given texts
 func (p *Provider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
 	if len(texts) == 0 {
@@ -198,7 +242,7 @@ func (p *Provider) convertChatRequest(req *domain.ChatCompletionRequest) openai.
 			}
 			
 			if tool.Function != nil {
-				openaiTool.Function = openai.FunctionDefinition{
+				openaiTool.Function = &openai.FunctionDefinition{
 					Name:        tool.Function.Name,
 					Description: tool.Function.Description,
 					Parameters:  tool.Function.Parameters,
@@ -272,4 +316,112 @@ func (p *Provider) convertChatResponse(resp *openai.ChatCompletionResponse) *dom
 	}
 	
 	return domainResp
-}
\ No newline at end of file
+}
+
+// ReadChatStream reads an OpenAI-compatible chat completion stream to its end,
+// passing the deltas of the first choice to onDelta, and assembles them into a
+// complete response. Tool call arguments arrive in fragments and are only
+// joined into JSON here.
+func ReadChatStream(stream *openai.ChatCompletionStream, onDelta func(*domain.ChatDelta) error) (*domain.ChatCompletionResponse, error) {
+	domainResp := &domain.ChatCompletionResponse{Object: "chat.completion"}
+	
+	var content strings.Builder
+	var toolCalls []domain.ToolCall
+	var arguments []string
+	var finishReason string
+	
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stream: %w", err)
+		}
+		
+		if domainResp.ID == "" {
+			domainResp.ID = chunk.ID
+			domainResp.Created = chunk.Created
+			domainResp.Model = chunk.Model
+		}
+		
+		// With include_usage the last chunk has no choices and carries the usage
+		if chunk.Usage != nil {
+			domainResp.Usage = &domain.TokenUsage{
+				PromptTokens:     chunk.Usage.PromptTokens,
+				CompletionTokens: chunk.Usage.CompletionTokens,
+				TotalTokens:      chunk.Usage.TotalTokens,
+			}
+		}
+		
+		for _, choice := range chunk.Choices {
+			if choice.Index != 0 {
+				continue
+			}
+			
+			delta := &domain.ChatDelta{
+				Content:      choice.Delta.Content,
+				FinishReason: string(choice.FinishReason),
+			}
+			content.WriteString(choice.Delta.Content)
+			if choice.FinishReason != "" {
+				finishReason = string(choice.FinishReason)
+			}
+			
+			for i, toolCall := range choice.Delta.ToolCalls {
+				index := i
+				if toolCall.Index != nil {
+					index = *toolCall.Index
+				}
+				for len(toolCalls) <= index {
+					toolCalls = append(toolCalls, domain.ToolCall{Type: string(openai.ToolTypeFunction)})
+					arguments = append(arguments, "")
+				}
+				
+				if toolCall.ID != "" {
+					toolCalls[index].ID = toolCall.ID
+				}
+				if toolCall.Type != "" {
+					toolCalls[index].Type = string(toolCall.Type)
+				}
+				if toolCall.Function.Name != "" {
+					toolCalls[index].Function = &domain.FunctionCall{Name: toolCall.Function.Name}
+				}
+				arguments[index] += toolCall.Function.Arguments
+				
+				delta.ToolCalls = append(delta.ToolCalls, domain.ToolCallDelta{
+					Index:     index,
+					ID:        toolCall.ID,
+					Name:      toolCall.Function.Name,
+					Arguments: toolCall.Function.Arguments,
+				})
+			}
+			
+			if onDelta != nil && (delta.Content != "" || len(delta.ToolCalls) > 0 || delta.FinishReason != "") {
+				if err := onDelta(delta); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	
+	message := &domain.ChatMessage{
+		Role:    openai.ChatMessageRoleAssistant,
+		Content: content.String(),
+	}
+	for i, toolCall := range toolCalls {
+		if toolCall.Function == nil {
+			continue
+		}
+		toolCall.Function.Arguments = json.RawMessage(arguments[i])
+		message.ToolCalls = append(message.ToolCalls, toolCall)
+	}
+	
+	domainResp.Choices = []domain.Choice{{
+		Index:        0,
+		Message:      message,
+		FinishReason: finishReason,
+	}}
+	
+	return domainResp, nil
+}
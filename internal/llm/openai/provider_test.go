@@ -2,6 +2,10 @@ package openai_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"personal-assistant/internal/domain"
@@ -128,4 +132,66 @@ func TestChatRequestConversion(t *testing.T) {
 		assert.Equal(t, "function", message.ToolCalls[0].Type)
 		assert.Equal(t, "get_weather", message.ToolCalls[0].Function.Name)
 	})
-}
\ No newline at end of file
+}
+
+func TestOpenAIProvider_ChatStream(t *testing.T) {
+	chunks := []string{
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1700000000,"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"Let me "}}]}`,
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1700000000,"model":"gpt-4o","choices":[{"index":0,"delta":{"content":"check."}}]}`,
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1700000000,"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"loc"}}]}}]}`,
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1700000000,"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"ation\":\"NYC\"}"}}]}}]}`,
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1700000000,"model":"gpt-4o","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+		`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1700000000,"model":"gpt-4o","choices":[],"usage":{"prompt_tokens":12,"completion_tokens":9,"total_tokens":21}}`,
+	}
+
+	var request map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	provider, err := openaiProvider.NewProvider(&domain.LLMProviderConfig{
+		APIKey:    "test-api-key",
+		Name:      "test",
+		BaseURL:   server.URL,
+		ModelChat: "gpt-4o",
+	}, log.Init("error"))
+	require.NoError(t, err)
+
+	var deltas []*domain.ChatDelta
+	resp, err := provider.ChatStream(context.Background(), &domain.ChatCompletionRequest{
+		Messages: []domain.ChatMessage{{Role: "user", Content: "Weather in NYC?"}},
+	}, func(delta *domain.ChatDelta) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, true, request["stream"])
+	assert.Equal(t, map[string]interface{}{"include_usage": true}, request["stream_options"])
+
+	require.Len(t, deltas, 5)
+	assert.Equal(t, "Let me ", deltas[0].Content)
+	assert.Equal(t, []domain.ToolCallDelta{{Index: 0, ID: "call_1", Name: "get_weather", Arguments: `{"loc`}}, deltas[2].ToolCalls)
+	assert.Equal(t, []domain.ToolCallDelta{{Index: 0, Arguments: `ation":"NYC"}`}}, deltas[3].ToolCalls)
+	assert.Equal(t, "tool_calls", deltas[4].FinishReason)
+
+	assert.Equal(t, "chatcmpl-1", resp.ID)
+	assert.Equal(t, "gpt-4o", resp.Model)
+	require.Len(t, resp.Choices, 1)
+	assert.Equal(t, "tool_calls", resp.Choices[0].FinishReason)
+	assert.Equal(t, &domain.TokenUsage{PromptTokens: 12, CompletionTokens: 9, TotalTokens: 21}, resp.Usage)
+
+	message := resp.Choices[0].Message
+	assert.Equal(t, "Let me check.", message.Content)
+	require.Len(t, message.ToolCalls, 1)
+	assert.Equal(t, "call_1", message.ToolCalls[0].ID)
+	assert.Equal(t, "function", message.ToolCalls[0].Type)
+	assert.Equal(t, "get_weather", message.ToolCalls[0].Function.Name)
+	assert.JSONEq(t, `{"location":"NYC"}`, string(message.ToolCalls[0].Function.Arguments))
+}
@@ -0,0 +1,112 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+// Transcriber implements the Transcriber interface for OpenAI-compatible audio endpoints
+type Transcriber struct {
+	client *openai.Client
+	config *domain.LLMProviderConfig
+	logger *log.Logger
+	model  string
+}
+
+// NewTranscriber creates a new OpenAI transcriber. The provider config may
+// override the audio endpoint with "transcription_base_url",
+// "transcription_api_key" and "transcription_model".
+func NewTranscriber(config *domain.LLMProviderConfig, logger *log.Logger) (*Transcriber, error) {
+	apiKey := config.ConfigString("transcription_api_key", config.APIKey)
+	clientConfig := openai.DefaultConfig(apiKey)
+
+	// Use custom base URL if provided
+	if baseURL := config.ConfigString("transcription_base_url", config.BaseURL); baseURL != "" {
+		clientConfig.BaseURL = baseURL
+	}
+
+	return &Transcriber{
+		client: openai.NewClientWithConfig(clientConfig),
+		config: config,
+		logger: logger,
+		model:  config.ConfigString("transcription_model", openai.Whisper1),
+	}, nil
+}
+
+// Name returns the transcriber name
+func (t *Transcriber) Name() string {
+	return fmt.Sprintf("openai-%s", t.config.Name)
+}
+
+// Transcribe converts recorded speech into text
+func (t *Transcriber) Transcribe(ctx context.Context, req *domain.TranscriptionRequest) (*domain.Transcription, error) {
+	if len(req.Audio) == 0 {
+		return nil, fmt.Errorf("audio is empty")
+	}
+
+	start := time.Now()
+
+	// The file name is only used to tell the API the audio format
+	fileName := req.FileName
+	if fileName == "" {
+		fileName = "audio" + audioExtension(req.MimeType)
+	}
+
+	resp, err := t.client.CreateTranscription(ctx, openai.AudioRequest{
+		Model:    t.model,
+		FilePath: fileName,
+		Reader:   bytes.NewReader(req.Audio),
+		Language: req.Language,
+	})
+	if err != nil {
+		t.logger.WithContext(ctx).Error().
+			Err(err).
+			Str("model", t.model).
+			Dur("duration", time.Since(start)).
+			Msg("transcription failed")
+		return nil, fmt.Errorf("transcription failed: %w", err)
+	}
+
+	t.logger.WithContext(ctx).Debug().
+		Str("model", t.model).
+		Int("audio_bytes", len(req.Audio)).
+		Dur("duration", time.Since(start)).
+		Msg("transcription completed")
+
+	return &domain.Transcription{
+		Text:     strings.TrimSpace(resp.Text),
+		Language: resp.Language,
+		Duration: resp.Duration,
+	}, nil
+}
+
+// audioExtension returns the file extension for the audio formats WhatsApp sends
+func audioExtension(mimeType string) string {
+	mediaType, _, _ := strings.Cut(mimeType, ";")
+	switch strings.TrimSpace(strings.ToLower(mediaType)) {
+	case "audio/ogg", "audio/opus":
+		return ".ogg"
+	case "audio/mpeg", "audio/mp3":
+		return ".mp3"
+	case "audio/mp4", "audio/m4a", "audio/x-m4a":
+		return ".m4a"
+	case "audio/aac":
+		return ".aac"
+	case "audio/amr":
+		return ".amr"
+	case "audio/wav", "audio/x-wav":
+		return ".wav"
+	case "audio/webm":
+		return ".webm"
+	default:
+		return ".ogg"
+	}
+}
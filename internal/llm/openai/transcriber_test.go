@@ -0,0 +1,78 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/domain"
+	openaiProvider "personal-assistant/internal/llm/openai"
+	"personal-assistant/internal/log"
+)
+
+func TestTranscriber(t *testing.T) {
+	var received struct {
+		path     string
+		model    string
+		fileName string
+		language string
+		audio    int64
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		defer file.Close()
+
+		received.path = r.URL.Path
+		received.model = r.FormValue("model")
+		received.language = r.FormValue("language")
+		received.fileName = header.Filename
+		received.audio = header.Size
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"text": "  Remind me to call mom tomorrow.  "})
+	}))
+	defer server.Close()
+
+	config := &domain.LLMProviderConfig{
+		APIKey:   "chat-key",
+		Provider: "deepseek",
+		Name:     "test",
+		Config: map[string]interface{}{
+			"transcription_base_url": server.URL + "/v1",
+			"transcription_model":    "whisper-large-v3",
+		},
+	}
+
+	transcriber, err := openaiProvider.NewTranscriber(config, log.Init("error"))
+	require.NoError(t, err)
+	assert.Equal(t, "openai-test", transcriber.Name())
+
+	t.Run("transcribes audio", func(t *testing.T) {
+		transcription, err := transcriber.Transcribe(context.Background(), &domain.TranscriptionRequest{
+			Audio:    []byte("OggS fake voice note"),
+			MimeType: "audio/ogg; codecs=opus",
+			Language: "en",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Remind me to call mom tomorrow.", transcription.Text)
+		assert.Equal(t, "/v1/audio/transcriptions", received.path)
+		assert.Equal(t, "whisper-large-v3", received.model)
+		assert.Equal(t, "audio.ogg", received.fileName)
+		assert.Equal(t, "en", received.language)
+		assert.Equal(t, int64(20), received.audio)
+	})
+
+	t.Run("rejects empty audio", func(t *testing.T) {
+		_, err := transcriber.Transcribe(context.Background(), &domain.TranscriptionRequest{})
+		assert.Error(t, err)
+	})
+}
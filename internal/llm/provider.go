@@ -2,7 +2,9 @@ package llm
 
 import (
 	"fmt"
+	"sync"
 
+	"personal-assistant/internal/config"
 	"personal-assistant/internal/domain"
 	"personal-assistant/internal/llm/openai"
 	"personal-assistant/internal/log"
@@ -20,6 +22,8 @@ const (
 	Anthropic ProviderType = "anthropic"
 	// Bedrock provider
 	Bedrock ProviderType = "bedrock"
+	// Ollama provider for local model servers
+	Ollama ProviderType = "ollama"
 	// Mock provider for testing
 	Mock ProviderType = "mock"
 )
@@ -45,8 +49,12 @@ func (f *Factory) CreateProvider(config *domain.LLMProviderConfig) (domain.LLMPr
 		return openai.NewProvider(config, f.logger)
 	case DeepSeek:
 		return NewDeepSeekProvider(config, f.logger)
+	case Anthropic:
+		return NewAnthropicProvider(config, f.logger)
 	case Bedrock:
 		return NewBedrockProvider(config, f.logger)
+	case Ollama:
+		return NewOllamaProvider(config, f.logger)
 	case Mock:
 		return NewMockProvider(config, f.logger)
 	default:
@@ -54,6 +62,25 @@ func (f *Factory) CreateProvider(config *domain.LLMProviderConfig) (domain.LLMPr
 	}
 }
 
+// CreateTranscriber creates a speech-to-text transcriber for a provider configuration.
+// Providers without a native audio endpoint can point "transcription_base_url" at
+// any OpenAI-compatible transcription service.
+func (f *Factory) CreateTranscriber(config *domain.LLMProviderConfig) (domain.Transcriber, error) {
+	providerType := GetProviderType(config.Provider)
+
+	switch providerType {
+	case OpenAI:
+		return openai.NewTranscriber(config, f.logger)
+	case Mock:
+		return NewMockTranscriber(config, f.logger)
+	default:
+		if baseURL, _ := config.Config["transcription_base_url"].(string); baseURL != "" {
+			return openai.NewTranscriber(config, f.logger)
+		}
+		return nil, fmt.Errorf("transcription not supported for LLM provider type: %s", config.Provider)
+	}
+}
+
 // GetProviderType parses a string to ProviderType
 func GetProviderType(s string) ProviderType {
 	switch s {
@@ -65,6 +92,8 @@ func GetProviderType(s string) ProviderType {
 		return Anthropic
 	case "bedrock":
 		return Bedrock
+	case "ollama":
+		return Ollama
 	case "mock":
 		return Mock
 	default:
@@ -74,17 +103,27 @@ func GetProviderType(s string) ProviderType {
 
 // ProviderManager manages multiple LLM providers for different tenants
 type ProviderManager struct {
-	providers map[string]domain.LLMProvider // key: tenantID_providerName
-	factory   *Factory
-	logger    *log.Logger
+	providers    map[string]domain.LLMProvider // key: tenantID_providerName
+	transcribers map[string]domain.Transcriber // key: tenantID_providerName
+	breakers     map[string]*CircuitBreaker    // key: tenantID_providerName
+	factory      *Factory
+	config       *config.LLMFallbackConfig
+	recorder     domain.UsageRecorder // Records token usage of the providers, if set
+	logger       *log.Logger
+	mutex        sync.Mutex
 }
 
-// NewProviderManager creates a new provider manager
-func NewProviderManager(logger *log.Logger) *ProviderManager {
+// NewProviderManager creates a new provider manager. Providers record their
+// token usage with recorder unless it is nil.
+func NewProviderManager(logger *log.Logger, cfg *config.LLMFallbackConfig, recorder domain.UsageRecorder) *ProviderManager {
 	return &ProviderManager{
-		providers: make(map[string]domain.LLMProvider),
-		factory:   NewFactory(logger),
-		logger:    logger,
+		providers:    make(map[string]domain.LLMProvider),
+		transcribers: make(map[string]domain.Transcriber),
+		breakers:     make(map[string]*CircuitBreaker),
+		factory:      NewFactory(logger),
+		config:       cfg,
+		recorder:     recorder,
+		logger:       logger,
 	}
 }
 
@@ -92,6 +131,9 @@ func NewProviderManager(logger *log.Logger) *ProviderManager {
 func (pm *ProviderManager) GetProvider(tenantID string, config *domain.LLMProviderConfig) (domain.LLMProvider, error) {
 	key := fmt.Sprintf("%s_%s", tenantID, config.Name)
 
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
 	// Return existing provider if available
 	if provider, exists := pm.providers[key]; exists {
 		return provider, nil
@@ -103,6 +145,10 @@ func (pm *ProviderManager) GetProvider(tenantID string, config *domain.LLMProvid
 		return nil, fmt.Errorf("failed to create provider %s for tenant %s: %w", config.Name, tenantID, err)
 	}
 
+	if pm.recorder != nil {
+		provider = NewMeteredProvider(provider, pm.recorder, tenantID, config, pm.logger)
+	}
+
 	// Cache the provider
 	pm.providers[key] = provider
 
@@ -115,19 +161,116 @@ func (pm *ProviderManager) GetProvider(tenantID string, config *domain.LLMProvid
 	return provider, nil
 }
 
+// GetFallbackProvider returns a provider that fails over between the given
+// provider configs in order. Providers that cannot be created are skipped;
+// a single usable provider is returned as it is.
+func (pm *ProviderManager) GetFallbackProvider(tenantID string, configs []domain.LLMProviderConfig) (domain.LLMProvider, error) {
+	var members []FallbackMember
+	for i := range configs {
+		provider, err := pm.GetProvider(tenantID, &configs[i])
+		if err != nil {
+			pm.logger.Error().
+				Err(err).
+				Str("tenant_id", tenantID).
+				Str("provider_name", configs[i].Name).
+				Msg("skipping LLM provider in fallback chain")
+			continue
+		}
+
+		members = append(members, FallbackMember{
+			Name:     configs[i].Name,
+			Provider: provider,
+			Breaker:  pm.breaker(tenantID, configs[i].Name),
+		})
+	}
+
+	if len(members) == 0 {
+		return nil, fmt.Errorf("no usable LLM provider for tenant %s", tenantID)
+	}
+	if len(members) == 1 {
+		return members[0].Provider, nil
+	}
+
+	return NewFallbackProvider(members, pm.config, pm.logger)
+}
+
+// breaker gets or creates the circuit breaker of a provider. Breakers outlive
+// the fallback providers built on them so error rates carry across requests.
+func (pm *ProviderManager) breaker(tenantID, providerName string) *CircuitBreaker {
+	key := fmt.Sprintf("%s_%s", tenantID, providerName)
+
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	if breaker, exists := pm.breakers[key]; exists {
+		return breaker
+	}
+
+	breaker := NewCircuitBreaker(pm.config)
+	pm.breakers[key] = breaker
+	return breaker
+}
+
+// GetTranscriber gets or creates a transcriber for a tenant
+func (pm *ProviderManager) GetTranscriber(tenantID string, config *domain.LLMProviderConfig) (domain.Transcriber, error) {
+	key := fmt.Sprintf("%s_%s", tenantID, config.Name)
+
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
+	// Return existing transcriber if available
+	if transcriber, exists := pm.transcribers[key]; exists {
+		return transcriber, nil
+	}
+
+	transcriber, err := pm.factory.CreateTranscriber(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcriber %s for tenant %s: %w", config.Name, tenantID, err)
+	}
+
+	pm.transcribers[key] = transcriber
+
+	pm.logger.Info().
+		Str("tenant_id", tenantID).
+		Str("provider_name", config.Name).
+		Str("provider_type", config.Provider).
+		Msg("transcriber created and cached")
+
+	return transcriber, nil
+}
+
 // RemoveProvider removes a cached provider
 func (pm *ProviderManager) RemoveProvider(tenantID, providerName string) {
 	key := fmt.Sprintf("%s_%s", tenantID, providerName)
+
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
 	delete(pm.providers, key)
+	delete(pm.transcribers, key)
+	delete(pm.breakers, key)
 }
 
 // ClearTenant removes all providers for a tenant
 func (pm *ProviderManager) ClearTenant(tenantID string) {
+	pm.mutex.Lock()
+	defer pm.mutex.Unlock()
+
 	for key := range pm.providers {
 		if len(key) > len(tenantID) && key[:len(tenantID)+1] == tenantID+"_" {
 			delete(pm.providers, key)
 		}
 	}
+	for key := range pm.transcribers {
+		if len(key) > len(tenantID) && key[:len(tenantID)+1] == tenantID+"_" {
+			delete(pm.transcribers, key)
+		}
+	}
+	for key := range pm.breakers {
+		if len(key) > len(tenantID) && key[:len(tenantID)+1] == tenantID+"_" {
+			delete(pm.breakers, key)
+		}
+	}
 }
 
 // GetDefaultModels returns default models for each provider type
@@ -141,6 +284,8 @@ func GetDefaultModels(providerType ProviderType) (chatModel, embedModel string)
 		return "claude-3-sonnet-20240229", ""
 	case Bedrock:
 		return "openai.gpt-oss-120b-1:0", "amazon.titan-embed-text-v2:0"
+	case Ollama:
+		return "llama3.1", "" // Common local embedding models do not produce domain.EmbeddingDimensions
 	case Mock:
 		return "mock-chat", "mock-embed"
 	default:
@@ -159,6 +304,8 @@ func GetDefaultBaseURL(providerType ProviderType) string {
 		return "https://api.anthropic.com"
 	case Bedrock:
 		return "" // Uses AWS SDK, no base URL needed
+	case Ollama:
+		return "http://localhost:11434"
 	case Mock:
 		return "http://localhost:8080/mock"
 	default:
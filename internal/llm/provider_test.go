@@ -0,0 +1,110 @@
+package llm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/llm"
+	"personal-assistant/internal/log"
+)
+
+func TestFactory_CreateTranscriber(t *testing.T) {
+	factory := llm.NewFactory(log.Init("error"))
+
+	t.Run("mock transcriber", func(t *testing.T) {
+		transcriber, err := factory.CreateTranscriber(&domain.LLMProviderConfig{
+			Provider: "mock",
+			Name:     "test",
+			Config:   map[string]interface{}{"mock_transcript": "Buy milk"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "mock-test", transcriber.Name())
+
+		transcription, err := transcriber.Transcribe(context.Background(), &domain.TranscriptionRequest{Audio: []byte("audio")})
+		require.NoError(t, err)
+		assert.Equal(t, "Buy milk", transcription.Text)
+	})
+
+	t.Run("openai transcriber", func(t *testing.T) {
+		transcriber, err := factory.CreateTranscriber(&domain.LLMProviderConfig{Provider: "openai", Name: "test", APIKey: "key"})
+		require.NoError(t, err)
+		assert.Equal(t, "openai-test", transcriber.Name())
+	})
+
+	t.Run("unsupported without transcription endpoint", func(t *testing.T) {
+		_, err := factory.CreateTranscriber(&domain.LLMProviderConfig{Provider: "deepseek", Name: "test"})
+		assert.Error(t, err)
+	})
+
+	t.Run("openai-compatible endpoint for other providers", func(t *testing.T) {
+		transcriber, err := factory.CreateTranscriber(&domain.LLMProviderConfig{
+			Provider: "bedrock",
+			Name:     "test",
+			Config:   map[string]interface{}{"transcription_base_url": "http://localhost:9000/v1"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "openai-test", transcriber.Name())
+	})
+}
+
+func TestProviderManager_GetFallbackProvider(t *testing.T) {
+	cfg := &config.LLMFallbackConfig{FailureRate: 0.5, MinRequests: 5, WindowSize: 20, OpenDuration: time.Minute}
+	manager := llm.NewProviderManager(log.Init("error"), cfg, nil)
+
+	t.Run("returns a single provider as it is", func(t *testing.T) {
+		provider, err := manager.GetFallbackProvider("tenant", []domain.LLMProviderConfig{{Provider: "mock", Name: "primary"}})
+		require.NoError(t, err)
+		assert.Equal(t, "mock-primary", provider.Name())
+	})
+
+	t.Run("chains providers in order and skips invalid ones", func(t *testing.T) {
+		provider, err := manager.GetFallbackProvider("tenant", []domain.LLMProviderConfig{
+			{Provider: "mock", Name: "primary"},
+			{Provider: "anthropic", Name: "broken"},
+			{Provider: "mock", Name: "secondary"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "fallback(mock-primary,mock-secondary)", provider.Name())
+
+		resp, err := provider.Chat(context.Background(), &domain.ChatCompletionRequest{
+			Messages: []domain.ChatMessage{{Role: "user", Content: "Hello"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "primary", resp.Provider)
+	})
+
+	t.Run("fails without a usable provider", func(t *testing.T) {
+		_, err := manager.GetFallbackProvider("tenant", []domain.LLMProviderConfig{{Provider: "anthropic", Name: "broken"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestMockProvider_ChatStream(t *testing.T) {
+	provider, err := llm.NewMockProvider(&domain.LLMProviderConfig{Provider: "mock", Name: "test"}, log.Init("error"))
+	require.NoError(t, err)
+
+	var content string
+	var arguments string
+	resp, err := provider.ChatStream(context.Background(), &domain.ChatCompletionRequest{
+		Messages: []domain.ChatMessage{{Role: "user", Content: "search my notes"}},
+		Tools:    []domain.ToolDefinition{{Type: "function", Function: &domain.ToolFunction{Name: "db_search"}}},
+	}, func(delta *domain.ChatDelta) error {
+		content += delta.Content
+		for _, toolCall := range delta.ToolCalls {
+			arguments += toolCall.Arguments
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	message := resp.Choices[0].Message
+	assert.Equal(t, message.Content, content)
+	require.Len(t, message.ToolCalls, 1)
+	assert.JSONEq(t, string(message.ToolCalls[0].Function.Arguments), arguments)
+}
@@ -0,0 +1,163 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+// MeteredProvider implements the LLMProvider interface by recording the token
+// usage of every call of the provider it wraps. Calls whose provider reports
+// no usage, such as embeddings, are recorded with tokens estimated from the
+// text length.
+type MeteredProvider struct {
+	provider   domain.LLMProvider
+	recorder   domain.UsageRecorder
+	tenantID   string
+	name       string // Name of the llm_providers row
+	chatModel  string
+	embedModel string
+	logger     *log.Logger
+}
+
+// NewMeteredProvider creates a provider that records the usage of provider.
+// The result can stream when provider can.
+func NewMeteredProvider(provider domain.LLMProvider, recorder domain.UsageRecorder, tenantID string, config *domain.LLMProviderConfig, logger *log.Logger) domain.LLMProvider {
+	chatModel, embedModel := GetDefaultModels(GetProviderType(config.Provider))
+	if config.ModelChat != "" {
+		chatModel = config.ModelChat
+	}
+	if config.ModelEmbed != "" {
+		embedModel = config.ModelEmbed
+	}
+
+	metered := &MeteredProvider{
+		provider:   provider,
+		recorder:   recorder,
+		tenantID:   tenantID,
+		name:       config.Name,
+		chatModel:  chatModel,
+		embedModel: embedModel,
+		logger:     logger,
+	}
+
+	if streamer, ok := provider.(domain.ChatStreamer); ok {
+		return &meteredStreamer{MeteredProvider: metered, streamer: streamer}
+	}
+	return metered
+}
+
+// Name returns the name of the wrapped provider
+func (p *MeteredProvider) Name() string {
+	return p.provider.Name()
+}
+
+// Chat performs a chat completion and records its usage
+func (p *MeteredProvider) Chat(ctx context.Context, req *domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+	resp, err := p.provider.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	p.recordChat(ctx, req, resp)
+	return resp, nil
+}
+
+// meteredStreamer is a MeteredProvider for a provider that can stream
+type meteredStreamer struct {
+	*MeteredProvider
+	streamer domain.ChatStreamer
+}
+
+// ChatStream performs a streaming chat completion and records its usage
+func (p *meteredStreamer) ChatStream(ctx context.Context, req *domain.ChatCompletionRequest, onDelta func(*domain.ChatDelta) error) (*domain.ChatCompletionResponse, error) {
+	resp, err := p.streamer.ChatStream(ctx, req, onDelta)
+	if err != nil {
+		return nil, err
+	}
+
+	p.recordChat(ctx, req, resp)
+	return resp, nil
+}
+
+// Embed generates embeddings and records their estimated usage
+func (p *MeteredProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings, err := p.provider.Embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(texts) > 0 {
+		tokens := 0
+		for _, text := range texts {
+			tokens += domain.EstimateTokens(text)
+		}
+
+		p.record(ctx, &domain.LLMUsage{
+			Model:        p.embedModel,
+			Operation:    domain.LLMOperationEmbed,
+			PromptTokens: tokens,
+			Estimated:    true,
+		})
+	}
+
+	return embeddings, nil
+}
+
+// recordChat records the usage of a chat completion, estimating it when the
+// provider reported none
+func (p *MeteredProvider) recordChat(ctx context.Context, req *domain.ChatCompletionRequest, resp *domain.ChatCompletionResponse) {
+	// The configured model is recorded rather than the dated snapshot the
+	// provider reports (e.g. gpt-4o-2024-08-06), which llm_model_prices lacks
+	usage := &domain.LLMUsage{
+		Model:     p.chatModel,
+		Operation: domain.LLMOperationChat,
+	}
+
+	if resp.Usage != nil {
+		usage.PromptTokens = resp.Usage.PromptTokens
+		usage.CompletionTokens = resp.Usage.CompletionTokens
+	} else {
+		usage.Estimated = true
+		for _, message := range req.Messages {
+			usage.PromptTokens += domain.EstimateTokens(message.Content)
+		}
+		for _, choice := range resp.Choices {
+			if choice.Message == nil {
+				continue
+			}
+			usage.CompletionTokens += domain.EstimateTokens(choice.Message.Content)
+			for _, toolCall := range choice.Message.ToolCalls {
+				if toolCall.Function != nil {
+					usage.CompletionTokens += domain.EstimateTokens(string(toolCall.Function.Arguments))
+				}
+			}
+		}
+	}
+
+	p.record(ctx, usage)
+}
+
+// record stores a usage row for the tenant and the user being served, if any.
+// Failing to record usage never fails the call.
+func (p *MeteredProvider) record(ctx context.Context, usage *domain.LLMUsage) {
+	usage.TenantID = p.tenantID
+	usage.Provider = p.name
+
+	if userID, ok := ctx.Value(log.UserIDKey).(string); ok {
+		if parsed, err := uuid.Parse(userID); err == nil {
+			usage.UserID = &parsed
+		}
+	}
+
+	if err := p.recorder.RecordLLMUsage(ctx, usage); err != nil {
+		p.logger.WithContext(ctx).Warn().
+			Err(err).
+			Str("provider_name", p.name).
+			Str("operation", usage.Operation).
+			Msg("failed to record LLM usage")
+	}
+}
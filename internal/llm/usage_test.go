@@ -0,0 +1,123 @@
+package llm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/llm"
+	"personal-assistant/internal/log"
+)
+
+// usageRecorder collects recorded usage, failing with err if set
+type usageRecorder struct {
+	usage []domain.LLMUsage
+	err   error
+}
+
+func (r *usageRecorder) RecordLLMUsage(ctx context.Context, usage *domain.LLMUsage) error {
+	r.usage = append(r.usage, *usage)
+	return r.err
+}
+
+// reportingProvider answers with a fixed response that reports token usage
+type reportingProvider struct {
+	scriptedProvider
+}
+
+func (p *reportingProvider) Chat(ctx context.Context, req *domain.ChatCompletionRequest) (*domain.ChatCompletionResponse, error) {
+	return &domain.ChatCompletionResponse{
+		Model:   "gpt-4o-2024-08-06",
+		Choices: []domain.Choice{{Message: &domain.ChatMessage{Role: "assistant", Content: "Hi"}}},
+		Usage:   &domain.TokenUsage{PromptTokens: 42, CompletionTokens: 7, TotalTokens: 49},
+	}, nil
+}
+
+func TestMeteredProvider(t *testing.T) {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), log.UserIDKey, userID.String())
+	config := &domain.LLMProviderConfig{Provider: "openai", Name: "primary", ModelChat: "gpt-4o", ModelEmbed: "text-embedding-3-small"}
+
+	t.Run("records reported chat usage", func(t *testing.T) {
+		recorder := &usageRecorder{}
+		provider := llm.NewMeteredProvider(&reportingProvider{scriptedProvider{name: "openai-primary"}}, recorder, "tenant", config, log.Init("error"))
+		assert.Equal(t, "openai-primary", provider.Name())
+
+		_, err := provider.Chat(ctx, chatRequest())
+		require.NoError(t, err)
+
+		require.Len(t, recorder.usage, 1)
+		usage := recorder.usage[0]
+		assert.Equal(t, "tenant", usage.TenantID)
+		assert.Equal(t, &userID, usage.UserID)
+		assert.Equal(t, "primary", usage.Provider)
+		assert.Equal(t, "gpt-4o", usage.Model) // Not the dated gpt-4o-2024-08-06 the provider reported
+		assert.Equal(t, domain.LLMOperationChat, usage.Operation)
+		assert.Equal(t, 42, usage.PromptTokens)
+		assert.Equal(t, 7, usage.CompletionTokens)
+		assert.False(t, usage.Estimated)
+	})
+
+	t.Run("estimates unreported chat usage", func(t *testing.T) {
+		recorder := &usageRecorder{}
+		provider := llm.NewMeteredProvider(&scriptedProvider{name: "primary"}, recorder, "tenant", config, log.Init("error"))
+
+		_, err := provider.Chat(context.Background(), chatRequest())
+		require.NoError(t, err)
+
+		require.Len(t, recorder.usage, 1)
+		usage := recorder.usage[0]
+		assert.Nil(t, usage.UserID)
+		assert.Equal(t, "gpt-4o", usage.Model)
+		assert.Equal(t, 2, usage.PromptTokens)     // "Hello"
+		assert.Equal(t, 3, usage.CompletionTokens) // "from primary"
+		assert.True(t, usage.Estimated)
+	})
+
+	t.Run("estimates embedding usage", func(t *testing.T) {
+		recorder := &usageRecorder{}
+		provider := llm.NewMeteredProvider(&scriptedProvider{name: "primary"}, recorder, "tenant", config, log.Init("error"))
+
+		_, err := provider.Embed(ctx, []string{"first text", "second"})
+		require.NoError(t, err)
+
+		require.Len(t, recorder.usage, 1)
+		usage := recorder.usage[0]
+		assert.Equal(t, "text-embedding-3-small", usage.Model)
+		assert.Equal(t, domain.LLMOperationEmbed, usage.Operation)
+		assert.Equal(t, 5, usage.PromptTokens)
+		assert.Equal(t, 0, usage.CompletionTokens)
+		assert.True(t, usage.Estimated)
+	})
+
+	t.Run("ignores recording failures", func(t *testing.T) {
+		recorder := &usageRecorder{err: errors.New("database unavailable")}
+		provider := llm.NewMeteredProvider(&scriptedProvider{name: "primary"}, recorder, "tenant", config, log.Init("error"))
+
+		resp, err := provider.Chat(ctx, chatRequest())
+		require.NoError(t, err)
+		assert.Equal(t, "from primary", resp.Choices[0].Message.Content)
+	})
+
+	t.Run("streams only when the provider can", func(t *testing.T) {
+		recorder := &usageRecorder{}
+
+		plain := llm.NewMeteredProvider(&scriptedProvider{name: "primary"}, recorder, "tenant", config, log.Init("error"))
+		_, ok := plain.(domain.ChatStreamer)
+		assert.False(t, ok)
+
+		streaming := llm.NewMeteredProvider(&streamingProvider{scriptedProvider: scriptedProvider{name: "primary"}, chunks: []string{"Hi"}}, recorder, "tenant", config, log.Init("error"))
+		streamer, ok := streaming.(domain.ChatStreamer)
+		require.True(t, ok)
+
+		_, err := streamer.ChatStream(ctx, chatRequest(), func(*domain.ChatDelta) error { return nil })
+		require.NoError(t, err)
+		require.Len(t, recorder.usage, 1)
+		assert.True(t, recorder.usage[0].Estimated)
+	})
+}
@@ -0,0 +1,4 @@
+-- Rollback migration for reminders
+
+-- Drop the reminders table (CASCADE will remove indexes, triggers, and policies)
+DROP TABLE IF EXISTS reminders CASCADE;
@@ -0,0 +1,40 @@
+-- Migration to add persistent reminders delivered by the background scheduler
+
+-- Create reminders table
+CREATE TABLE reminders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(255) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_id UUID REFERENCES memory_chunks(id) ON DELETE SET NULL, -- Optional memory item the reminder refers to
+    text TEXT NOT NULL,
+    channel VARCHAR(20) NOT NULL DEFAULT 'whatsapp' CHECK (channel IN ('whatsapp')),
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled'
+        CHECK (status IN ('scheduled', 'sent', 'failed', 'cancelled')),
+    scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL, -- Next delivery attempt
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 3,
+    last_error TEXT,
+    locked_until TIMESTAMP WITH TIME ZONE, -- Lease held by the replica delivering the reminder
+    sent_at TIMESTAMP WITH TIME ZONE,
+    metadata JSONB DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_reminders_tenant_user ON reminders(tenant_id, user_id);
+CREATE INDEX idx_reminders_due ON reminders(tenant_id, scheduled_at) WHERE status = 'scheduled';
+
+-- Add trigger to update updated_at
+CREATE TRIGGER trigger_reminders_updated_at
+    BEFORE UPDATE ON reminders
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Enable Row Level Security
+ALTER TABLE reminders ENABLE ROW LEVEL SECURITY;
+
+-- Create RLS policy for tenant isolation
+CREATE POLICY reminders_tenant_isolation ON reminders
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant', true));
@@ -0,0 +1,8 @@
+-- Rollback migration for recurring reminders
+
+DROP INDEX IF EXISTS idx_reminders_user_status;
+
+ALTER TABLE reminders
+    DROP COLUMN IF EXISTS recurrence,
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS occurrences;
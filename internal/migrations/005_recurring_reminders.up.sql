@@ -0,0 +1,9 @@
+-- Migration to add recurrence rules to reminders
+
+ALTER TABLE reminders
+    ADD COLUMN recurrence JSONB, -- NULL for one-off reminders
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC', -- IANA zone used to compute occurrences
+    ADD COLUMN occurrences INTEGER NOT NULL DEFAULT 0; -- Number of occurrences already delivered
+
+-- Index for listing a user's reminders
+CREATE INDEX idx_reminders_user_status ON reminders(tenant_id, user_id, status, scheduled_at);
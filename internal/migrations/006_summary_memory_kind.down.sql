@@ -0,0 +1,9 @@
+-- Rollback migration for summary memory kind
+
+DROP INDEX IF EXISTS idx_memory_chunks_user_kind_created;
+
+DELETE FROM memory_chunks WHERE kind = 'summary';
+
+ALTER TABLE memory_chunks
+    DROP CONSTRAINT IF EXISTS memory_chunks_kind_check,
+    ADD CONSTRAINT memory_chunks_kind_check CHECK (kind IN ('note', 'event', 'task', 'msg'));
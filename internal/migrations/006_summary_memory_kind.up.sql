@@ -0,0 +1,8 @@
+-- Migration to allow conversation summaries as memory chunks
+
+ALTER TABLE memory_chunks
+    DROP CONSTRAINT IF EXISTS memory_chunks_kind_check,
+    ADD CONSTRAINT memory_chunks_kind_check CHECK (kind IN ('note', 'event', 'task', 'msg', 'summary'));
+
+-- Index for loading a user's latest summary
+CREATE INDEX IF NOT EXISTS idx_memory_chunks_user_kind_created ON memory_chunks(tenant_id, user_id, kind, created_at DESC);
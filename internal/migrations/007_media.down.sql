@@ -0,0 +1,4 @@
+-- Rollback migration for media
+
+-- Drop the media table (CASCADE will remove indexes and policies)
+DROP TABLE IF EXISTS media CASCADE;
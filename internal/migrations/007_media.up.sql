@@ -0,0 +1,31 @@
+-- Migration to store media, locations and contacts received in messages
+
+-- Create media table
+CREATE TABLE media (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(255) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    message_id UUID NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL
+        CHECK (type IN ('image', 'document', 'audio', 'video', 'location', 'contact')),
+    mime_type VARCHAR(255),
+    file_name VARCHAR(255),
+    caption TEXT,
+    url TEXT, -- Infobip media URL
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    data BYTEA, -- Downloaded content, NULL for locations, contacts and failed downloads
+    metadata JSONB DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_media_tenant_user ON media(tenant_id, user_id, created_at DESC);
+CREATE INDEX idx_media_message ON media(message_id);
+
+-- Enable Row Level Security
+ALTER TABLE media ENABLE ROW LEVEL SECURITY;
+
+-- Create RLS policy for tenant isolation
+CREATE POLICY media_tenant_isolation ON media
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant', true));
@@ -0,0 +1,12 @@
+-- Rollback migration for documents
+
+DELETE FROM memory_chunks WHERE kind = 'document';
+
+ALTER TABLE memory_chunks
+    DROP CONSTRAINT IF EXISTS memory_chunks_kind_check,
+    ADD CONSTRAINT memory_chunks_kind_check CHECK (kind IN ('note', 'event', 'task', 'msg', 'summary'));
+
+DROP INDEX IF EXISTS idx_memory_chunks_document;
+
+-- Drop the documents table (CASCADE will remove indexes, triggers, and policies)
+DROP TABLE IF EXISTS documents CASCADE;
@@ -0,0 +1,45 @@
+-- Migration to add documents ingested into memory from WhatsApp attachments
+
+-- Create documents table
+CREATE TABLE documents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(255) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    media_id UUID REFERENCES media(id) ON DELETE SET NULL, -- Attachment the document was parsed from
+    file_name VARCHAR(255) NOT NULL,
+    mime_type VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'processing'
+        CHECK (status IN ('processing', 'ready', 'failed')),
+    page_count INTEGER NOT NULL DEFAULT 0,
+    chunk_count INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    metadata JSONB DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_documents_tenant_user ON documents(tenant_id, user_id, created_at DESC);
+
+-- Memory chunks reference their source document through metadata
+CREATE INDEX idx_memory_chunks_document ON memory_chunks((metadata->>'document_id'))
+    WHERE metadata ? 'document_id';
+
+-- Allow document chunks as memory chunks
+ALTER TABLE memory_chunks
+    DROP CONSTRAINT IF EXISTS memory_chunks_kind_check,
+    ADD CONSTRAINT memory_chunks_kind_check CHECK (kind IN ('note', 'event', 'task', 'msg', 'summary', 'document'));
+
+-- Add trigger to update updated_at
+CREATE TRIGGER trigger_documents_updated_at
+    BEFORE UPDATE ON documents
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Enable Row Level Security
+ALTER TABLE documents ENABLE ROW LEVEL SECURITY;
+
+-- Create RLS policy for tenant isolation
+CREATE POLICY documents_tenant_isolation ON documents
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant', true));
@@ -0,0 +1,6 @@
+-- Rollback migration for message templates
+
+DROP INDEX IF EXISTS idx_messages_user_direction_timestamp;
+
+-- Drop the message_templates table (CASCADE will remove triggers and policies)
+DROP TABLE IF EXISTS message_templates CASCADE;
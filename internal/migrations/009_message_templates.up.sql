@@ -0,0 +1,33 @@
+-- Migration to add the per-tenant WhatsApp message template catalog
+
+-- Create message_templates table
+CREATE TABLE message_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(255) NOT NULL,
+    purpose VARCHAR(50) NOT NULL, -- Outbound flow using the template, e.g. 'reminder' or 'default'
+    name VARCHAR(255) NOT NULL, -- Template name registered with WhatsApp
+    language VARCHAR(20) NOT NULL DEFAULT 'en',
+    placeholders JSONB NOT NULL DEFAULT '[]', -- Variable names filling {{1}}, {{2}}, ... in order
+    body TEXT,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(tenant_id, purpose)
+);
+
+-- Add trigger to update updated_at
+CREATE TRIGGER trigger_message_templates_updated_at
+    BEFORE UPDATE ON message_templates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Find the last inbound message of a user to check the customer service window
+CREATE INDEX idx_messages_user_direction_timestamp ON messages(tenant_id, user_id, direction, timestamp DESC);
+
+-- Enable Row Level Security
+ALTER TABLE message_templates ENABLE ROW LEVEL SECURITY;
+
+-- Create RLS policy for tenant isolation
+CREATE POLICY message_templates_tenant_isolation ON message_templates
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant', true));
@@ -0,0 +1,8 @@
+-- Rollback migration for message status tracking
+
+-- Drop the message_status_events table (CASCADE will remove indexes and policies)
+DROP TABLE IF EXISTS message_status_events CASCADE;
+
+DROP INDEX IF EXISTS idx_messages_status;
+
+ALTER TABLE messages DROP COLUMN IF EXISTS status;
@@ -0,0 +1,36 @@
+-- Migration to add delivery status tracking for outbound messages
+
+-- Latest delivery status of each outbound message
+ALTER TABLE messages
+    ADD COLUMN status VARCHAR(20)
+        CHECK (status IN ('pending', 'sent', 'delivered', 'seen', 'failed'));
+
+CREATE INDEX idx_messages_status ON messages(tenant_id, status) WHERE status IS NOT NULL;
+
+-- Create message_status_events table with the delivery timeline of each message
+CREATE TABLE message_status_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(255) NOT NULL,
+    message_id UUID NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    external_id VARCHAR(255) NOT NULL, -- Infobip message ID
+    status VARCHAR(20) NOT NULL
+        CHECK (status IN ('pending', 'sent', 'delivered', 'seen', 'failed')),
+    detail VARCHAR(255), -- Infobip status name, e.g. DELIVERED_TO_HANDSET
+    error_code INTEGER,
+    error_message TEXT,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    payload JSONB DEFAULT '{}', -- Raw Infobip report
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_message_status_events_message ON message_status_events(tenant_id, message_id, occurred_at);
+CREATE INDEX idx_message_status_events_status ON message_status_events(tenant_id, status, occurred_at DESC);
+
+-- Enable Row Level Security
+ALTER TABLE message_status_events ENABLE ROW LEVEL SECURITY;
+
+-- Create RLS policy for tenant isolation
+CREATE POLICY message_status_events_tenant_isolation ON message_status_events
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant', true));
@@ -0,0 +1,4 @@
+-- Rollback migration for the inbound job queue
+
+-- Drop the inbound_jobs table (CASCADE will remove indexes and triggers)
+DROP TABLE IF EXISTS inbound_jobs CASCADE;
@@ -0,0 +1,32 @@
+-- Migration to add the durable queue of inbound webhook results
+-- Jobs are enqueued before the tenant is resolved, so the table is global and has no RLS
+
+-- Create inbound jobs table
+CREATE TABLE inbound_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    message_id VARCHAR(255) UNIQUE NOT NULL, -- Infobip message ID, keeps redelivered webhooks from being queued twice
+    sender VARCHAR(50) NOT NULL,
+    recipient VARCHAR(50) NOT NULL, -- WABA number that received the message
+    payload JSONB NOT NULL, -- Webhook result as received
+    status VARCHAR(20) NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'processing', 'done', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    last_error TEXT,
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(), -- Earliest time of the next attempt
+    locked_until TIMESTAMP WITH TIME ZONE, -- Lease held by the worker processing the job
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_inbound_jobs_runnable ON inbound_jobs(run_at) WHERE status = 'queued';
+CREATE INDEX idx_inbound_jobs_leased ON inbound_jobs(locked_until) WHERE status = 'processing';
+CREATE INDEX idx_inbound_jobs_status_created ON inbound_jobs(status, created_at DESC);
+
+-- Add trigger to update updated_at
+CREATE TRIGGER trigger_inbound_jobs_updated_at
+    BEFORE UPDATE ON inbound_jobs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
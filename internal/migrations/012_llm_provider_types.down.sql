@@ -0,0 +1,7 @@
+-- Rollback migration for Bedrock and Ollama LLM providers
+
+DELETE FROM llm_providers WHERE provider = 'ollama';
+
+ALTER TABLE llm_providers
+    DROP CONSTRAINT IF EXISTS llm_providers_provider_check,
+    ADD CONSTRAINT llm_providers_provider_check CHECK (provider IN ('openai', 'deepseek', 'anthropic', 'bedrock', 'mock'));
@@ -0,0 +1,5 @@
+-- Migration to allow Bedrock and Ollama LLM providers
+
+ALTER TABLE llm_providers
+    DROP CONSTRAINT IF EXISTS llm_providers_provider_check,
+    ADD CONSTRAINT llm_providers_provider_check CHECK (provider IN ('openai', 'deepseek', 'anthropic', 'bedrock', 'ollama', 'mock'));
@@ -0,0 +1,5 @@
+-- Rollback migration for LLM usage accounting
+
+-- Drop the tables (CASCADE will remove indexes, policies and triggers)
+DROP TABLE IF EXISTS llm_model_prices CASCADE;
+DROP TABLE IF EXISTS llm_usage CASCADE;
@@ -0,0 +1,58 @@
+-- Migration to add token usage and cost accounting for LLM calls
+
+-- Create llm_usage table with one row per chat completion or embedding call
+CREATE TABLE llm_usage (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(255) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL for calls outside a conversation
+    provider VARCHAR(255) NOT NULL, -- Name of the llm_providers row
+    model VARCHAR(255) NOT NULL,
+    operation VARCHAR(20) NOT NULL CHECK (operation IN ('chat', 'embed')),
+    prompt_tokens INTEGER NOT NULL DEFAULT 0,
+    completion_tokens INTEGER NOT NULL DEFAULT 0,
+    estimated BOOLEAN NOT NULL DEFAULT FALSE, -- Counted from text length because the provider reported no usage
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_llm_usage_tenant_created ON llm_usage(tenant_id, created_at);
+CREATE INDEX idx_llm_usage_user_created ON llm_usage(tenant_id, user_id, created_at);
+
+-- Enable Row Level Security
+ALTER TABLE llm_usage ENABLE ROW LEVEL SECURITY;
+
+-- Create RLS policy for tenant isolation
+CREATE POLICY llm_usage_tenant_isolation ON llm_usage
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant', true));
+
+-- Create llm_model_prices table with the price of each model in USD per million tokens.
+-- Prices are shared by all tenants, so the table has no RLS.
+CREATE TABLE llm_model_prices (
+    model VARCHAR(255) PRIMARY KEY,
+    input_price NUMERIC(12, 6) NOT NULL DEFAULT 0, -- Prompt and embedding tokens
+    output_price NUMERIC(12, 6) NOT NULL DEFAULT 0, -- Completion tokens
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Add trigger to update updated_at
+CREATE TRIGGER trigger_llm_model_prices_updated_at
+    BEFORE UPDATE ON llm_model_prices
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Insert list prices of the default models
+INSERT INTO llm_model_prices (model, input_price, output_price) VALUES
+    ('gpt-3.5-turbo', 0.50, 1.50),
+    ('gpt-4o', 2.50, 10.00),
+    ('gpt-4o-mini', 0.15, 0.60),
+    ('text-embedding-ada-002', 0.10, 0),
+    ('text-embedding-3-small', 0.02, 0),
+    ('text-embedding-3-large', 0.13, 0),
+    ('deepseek-chat', 0.27, 1.10),
+    ('claude-3-sonnet-20240229', 3.00, 15.00),
+    ('claude-3-haiku-20240307', 0.25, 1.25),
+    ('openai.gpt-oss-120b-1:0', 0.15, 0.60),
+    ('amazon.titan-embed-text-v2:0', 0.02, 0)
+ON CONFLICT (model) DO NOTHING;
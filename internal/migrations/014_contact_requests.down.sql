@@ -0,0 +1,4 @@
+-- Rollback migration for contact requests
+
+-- Drop the table (CASCADE will remove indexes, policies and triggers)
+DROP TABLE IF EXISTS contact_requests CASCADE;
@@ -0,0 +1,35 @@
+-- Migration to add access requests from senders outside the contact allowlist
+
+-- Create contact_requests table with one row per unknown sender
+CREATE TABLE contact_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(255) NOT NULL,
+    phone_number VARCHAR(50) NOT NULL,
+    contact_name VARCHAR(255),
+    last_message TEXT, -- Latest message received from the sender
+    message_count INTEGER NOT NULL DEFAULT 1,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'denied')),
+    decided_by VARCHAR(50), -- Phone number of the admin, or 'api'
+    decided_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE(tenant_id, phone_number)
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_contact_requests_tenant_status ON contact_requests(tenant_id, status, created_at);
+
+-- Add trigger to update updated_at
+CREATE TRIGGER trigger_contact_requests_updated_at
+    BEFORE UPDATE ON contact_requests
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Enable Row Level Security
+ALTER TABLE contact_requests ENABLE ROW LEVEL SECURITY;
+
+-- Create RLS policy for tenant isolation
+CREATE POLICY contact_requests_tenant_isolation ON contact_requests
+    FOR ALL
+    USING (tenant_id = current_setting('app.current_tenant', true));
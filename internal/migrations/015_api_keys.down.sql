@@ -0,0 +1,4 @@
+-- Rollback migration for API keys
+
+-- Drop the api_keys table (CASCADE will remove indexes)
+DROP TABLE IF EXISTS api_keys CASCADE;
@@ -0,0 +1,21 @@
+-- Migration to add API keys for the /api/v1 routes
+-- Keys are checked before a tenant is known and global keys belong to no tenant, so the table has no RLS
+
+-- Create api_keys table; only the SHA-256 hash of each key is stored
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    key_prefix VARCHAR(20) NOT NULL, -- First characters of the key, to recognise it in listings
+    key_hash CHAR(64) UNIQUE NOT NULL, -- Hex SHA-256 of the key
+    tenant_id VARCHAR(255), -- NULL for global admin keys
+    scopes TEXT[] NOT NULL DEFAULT ARRAY['read']::TEXT[]
+        CHECK (scopes <@ ARRAY['read', 'write']::TEXT[]),
+    expires_at TIMESTAMP WITH TIME ZONE, -- NULL for keys that do not expire
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_api_keys_tenant ON api_keys(tenant_id, created_at DESC);
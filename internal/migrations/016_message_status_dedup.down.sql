@@ -0,0 +1,3 @@
+-- Rollback migration for idempotent status reports
+
+DROP INDEX IF EXISTS idx_message_status_events_report;
@@ -0,0 +1,14 @@
+-- Migration to make recording a status report idempotent
+-- Infobip redelivers a whole batch of reports when one of them arrives before its message is stored
+
+-- Drop duplicates recorded so far, keeping the first one
+DELETE FROM message_status_events e
+USING message_status_events d
+WHERE e.tenant_id = d.tenant_id
+    AND e.external_id = d.external_id
+    AND e.status = d.status
+    AND e.occurred_at = d.occurred_at
+    AND (e.created_at, e.id) > (d.created_at, d.id);
+
+CREATE UNIQUE INDEX idx_message_status_events_report
+    ON message_status_events(tenant_id, external_id, status, occurred_at);
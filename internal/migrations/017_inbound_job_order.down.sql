@@ -0,0 +1,5 @@
+-- Rollback migration for ordered inbound jobs
+
+DROP INDEX IF EXISTS idx_inbound_jobs_user_pending;
+
+ALTER TABLE inbound_jobs DROP COLUMN IF EXISTS received_at;
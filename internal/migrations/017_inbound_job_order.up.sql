@@ -0,0 +1,11 @@
+-- Migration to claim the inbound jobs of a user in the order Infobip received them
+-- Workers on every replica skip a job while an earlier job of the same user is still pending
+
+ALTER TABLE inbound_jobs
+    ADD COLUMN received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(); -- Infobip receivedAt of the message
+
+UPDATE inbound_jobs SET received_at = created_at;
+
+CREATE INDEX idx_inbound_jobs_user_pending
+    ON inbound_jobs(recipient, sender, received_at)
+    WHERE status IN ('queued', 'processing');
@@ -0,0 +1,3 @@
+-- Rollback migration for the reply lookup index
+
+DROP INDEX IF EXISTS idx_messages_reply_to;
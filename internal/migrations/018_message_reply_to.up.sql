@@ -0,0 +1,6 @@
+-- Migration to find the reply stored for an inbound message
+-- A retried inbound job that was already answered is not answered again
+
+CREATE INDEX idx_messages_reply_to
+    ON messages(tenant_id, (metadata->>'reply_to'))
+    WHERE direction = 'outbound';
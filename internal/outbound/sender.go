@@ -0,0 +1,193 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+// CustomerServiceWindow is how long after the user's last message WhatsApp
+// allows free-form messages. Outside of it only approved templates can be sent.
+const CustomerServiceWindow = 24 * time.Hour
+
+// ErrNoTemplate is returned when the customer service window has closed and the
+// tenant has no template to send instead; WhatsApp would reject free-form text
+var ErrNoTemplate = errors.New("customer service window closed and no message template configured")
+
+// Notification represents a proactive message to a user
+type Notification struct {
+	Purpose   string                      // Template purpose used outside the window, e.g. domain.TemplatePurposeReminder
+	Text      string                      // Free-form text, also available to templates as the "text" variable
+	Variables map[string]string           // Additional template variables
+	Buttons   []domain.InfobipReplyButton // Quick replies sent with the text while the window is open
+}
+
+// Delivery describes how a notification was sent
+type Delivery struct {
+	Message  *domain.InfobipMessage
+	Text     string                  // Text the user received
+	Template *domain.MessageTemplate // Template used, nil for free-form text
+}
+
+// Sender delivers proactive messages, falling back to the tenant's message
+// templates once the customer service window has closed
+type Sender struct {
+	infobipClient domain.InfobipClient
+	logger        *log.Logger
+	now           func() time.Time
+}
+
+// NewSender creates a new outbound sender
+func NewSender(infobipClient domain.InfobipClient, logger *log.Logger) *Sender {
+	return &Sender{
+		infobipClient: infobipClient,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// Send delivers a notification as free-form text while the customer service
+// window is open, and as the template configured for its purpose otherwise.
+// It returns ErrNoTemplate when the window has closed and no template is configured.
+func (s *Sender) Send(ctx context.Context, repo domain.Repository, tenant *domain.Tenant, user *domain.User, notification *Notification) (*Delivery, error) {
+	open, err := s.windowOpen(ctx, repo, tenant.ID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if !open {
+		template, err := s.findTemplate(ctx, repo, tenant.ID, notification.Purpose)
+		if err != nil {
+			return nil, err
+		}
+
+		if template == nil {
+			return nil, fmt.Errorf("%w for purpose %q", ErrNoTemplate, notification.Purpose)
+		}
+
+		return s.sendTemplate(ctx, tenant, user, template, notification)
+	}
+
+	var sent *domain.InfobipMessage
+	if len(notification.Buttons) > 0 {
+		sent, err = s.infobipClient.SendButtons(ctx, tenant.WABANumber, user.Phone, notification.Text, notification.Buttons, domain.EncodeCallbackData(tenant.ID, ""))
+	} else {
+		sent, err = s.infobipClient.SendText(ctx, tenant.WABANumber, user.Phone, notification.Text, domain.EncodeCallbackData(tenant.ID, ""))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Delivery{
+		Message: sent,
+		Text:    notification.Text,
+	}, nil
+}
+
+// windowOpen reports whether the user wrote within the customer service window
+func (s *Sender) windowOpen(ctx context.Context, repo domain.Repository, tenantID string, user *domain.User) (bool, error) {
+	last, err := repo.GetLastInboundMessage(ctx, tenantID, user.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get last inbound message: %w", err)
+	}
+	if last == nil {
+		return false, nil
+	}
+
+	return s.now().Sub(last.Timestamp) < CustomerServiceWindow, nil
+}
+
+// findTemplate returns the template for a purpose, falling back to the tenant's default template
+func (s *Sender) findTemplate(ctx context.Context, repo domain.Repository, tenantID, purpose string) (*domain.MessageTemplate, error) {
+	if purpose != "" && purpose != domain.TemplatePurposeDefault {
+		template, err := repo.GetMessageTemplate(ctx, tenantID, purpose)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get message template: %w", err)
+		}
+		if template != nil {
+			return template, nil
+		}
+	}
+
+	template, err := repo.GetMessageTemplate(ctx, tenantID, domain.TemplatePurposeDefault)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message template: %w", err)
+	}
+
+	return template, nil
+}
+
+// sendTemplate fills the template placeholders and sends it
+func (s *Sender) sendTemplate(ctx context.Context, tenant *domain.Tenant, user *domain.User, template *domain.MessageTemplate, notification *Notification) (*Delivery, error) {
+	values := TemplateValues(template, templateVariables(user, notification))
+
+	sent, err := s.infobipClient.SendTemplate(ctx, tenant.WABANumber, user.Phone, &domain.InfobipTemplate{
+		Name:         template.Name,
+		Language:     template.Language,
+		Placeholders: values,
+	}, domain.EncodeCallbackData(tenant.ID, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info().
+		Str("tenant_id", tenant.ID).
+		Str("user_id", user.ID.String()).
+		Str("template", template.Name).
+		Msg("customer service window closed, sent template message")
+
+	return &Delivery{
+		Message:  sent,
+		Text:     RenderTemplate(template, values, notification.Text),
+		Template: template,
+	}, nil
+}
+
+// templateVariables collects the variables available to templates
+func templateVariables(user *domain.User, notification *Notification) map[string]string {
+	variables := map[string]string{
+		"text":  notification.Text,
+		"phone": user.Phone,
+	}
+	if name, ok := user.Profile["name"].(string); ok {
+		variables["user_name"] = name
+	}
+	for key, value := range notification.Variables {
+		variables[key] = value
+	}
+	return variables
+}
+
+// TemplateValues returns the placeholder values of a template in order.
+// WhatsApp rejects empty placeholders, so missing variables are sent as "-".
+func TemplateValues(template *domain.MessageTemplate, variables map[string]string) []string {
+	values := make([]string, 0, len(template.Placeholders))
+	for _, name := range template.Placeholders {
+		value := strings.TrimSpace(variables[name])
+		if value == "" {
+			value = "-"
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// RenderTemplate returns the text the user receives for a template, or the
+// fallback text when the template body is unknown
+func RenderTemplate(template *domain.MessageTemplate, values []string, fallback string) string {
+	if template.Body == "" {
+		return fallback
+	}
+
+	text := template.Body
+	for i, value := range values {
+		text = strings.ReplaceAll(text, "{{"+strconv.Itoa(i+1)+"}}", value)
+	}
+	return text
+}
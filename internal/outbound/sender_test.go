@@ -0,0 +1,149 @@
+package outbound_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+	"personal-assistant/internal/outbound"
+)
+
+// MockRepository implements the lookups used by the sender
+type MockRepository struct {
+	domain.Repository
+	mock.Mock
+}
+
+func (m *MockRepository) GetLastInboundMessage(ctx context.Context, tenantID string, userID uuid.UUID) (*domain.Message, error) {
+	args := m.Called(ctx, tenantID, userID)
+	message, _ := args.Get(0).(*domain.Message)
+	return message, args.Error(1)
+}
+
+func (m *MockRepository) GetMessageTemplate(ctx context.Context, tenantID, purpose string) (*domain.MessageTemplate, error) {
+	args := m.Called(ctx, tenantID, purpose)
+	template, _ := args.Get(0).(*domain.MessageTemplate)
+	return template, args.Error(1)
+}
+
+// MockInfobipClient implements the sends used by the sender
+type MockInfobipClient struct {
+	domain.InfobipClient
+	mock.Mock
+}
+
+func (m *MockInfobipClient) SendText(ctx context.Context, from, to, text string, messageIDRef ...string) (*domain.InfobipMessage, error) {
+	args := m.Called(ctx, from, to, text)
+	msg, _ := args.Get(0).(*domain.InfobipMessage)
+	return msg, args.Error(1)
+}
+
+func (m *MockInfobipClient) SendTemplate(ctx context.Context, from, to string, template *domain.InfobipTemplate, messageIDRef ...string) (*domain.InfobipMessage, error) {
+	args := m.Called(ctx, from, to, template)
+	msg, _ := args.Get(0).(*domain.InfobipMessage)
+	return msg, args.Error(1)
+}
+
+func (m *MockInfobipClient) SendButtons(ctx context.Context, from, to, text string, buttons []domain.InfobipReplyButton, messageIDRef ...string) (*domain.InfobipMessage, error) {
+	args := m.Called(ctx, from, to, text, buttons)
+	msg, _ := args.Get(0).(*domain.InfobipMessage)
+	return msg, args.Error(1)
+}
+
+func TestSender_Send(t *testing.T) {
+	tenant := &domain.Tenant{ID: "test-tenant", WABANumber: "+15550000000"}
+	user := &domain.User{ID: uuid.New(), TenantID: tenant.ID, Phone: "+15551111111", Profile: map[string]interface{}{"name": "Ana"}}
+	notification := &outbound.Notification{Purpose: domain.TemplatePurposeReminder, Text: "Pay rent"}
+
+	setup := func(lastInbound *domain.Message) (*MockRepository, *MockInfobipClient, *outbound.Sender) {
+		repo := new(MockRepository)
+		client := new(MockInfobipClient)
+		repo.On("GetLastInboundMessage", mock.Anything, tenant.ID, user.ID).Return(lastInbound, nil)
+		return repo, client, outbound.NewSender(client, log.Init("error"))
+	}
+
+	t.Run("sends free-form text while the window is open", func(t *testing.T) {
+		repo, client, sender := setup(&domain.Message{Timestamp: time.Now().Add(-time.Hour)})
+		client.On("SendText", mock.Anything, tenant.WABANumber, user.Phone, "Pay rent").
+			Return(&domain.InfobipMessage{MessageID: "ib-1"}, nil)
+
+		delivery, err := sender.Send(context.Background(), repo, tenant, user, notification)
+		require.NoError(t, err)
+		assert.Equal(t, "ib-1", delivery.Message.MessageID)
+		assert.Equal(t, "Pay rent", delivery.Text)
+		assert.Nil(t, delivery.Template)
+		repo.AssertNotCalled(t, "GetMessageTemplate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("sends buttons while the window is open", func(t *testing.T) {
+		repo, client, sender := setup(&domain.Message{Timestamp: time.Now().Add(-time.Hour)})
+		buttons := []domain.InfobipReplyButton{{ID: "yes", Title: "Yes"}, {ID: "no", Title: "No"}}
+		client.On("SendButtons", mock.Anything, tenant.WABANumber, user.Phone, "Pay rent?", buttons).
+			Return(&domain.InfobipMessage{MessageID: "ib-3"}, nil)
+
+		delivery, err := sender.Send(context.Background(), repo, tenant, user, &outbound.Notification{
+			Purpose: domain.TemplatePurposeReminder,
+			Text:    "Pay rent?",
+			Buttons: buttons,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "ib-3", delivery.Message.MessageID)
+		client.AssertNotCalled(t, "SendText", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("falls back to the default template once the window has closed", func(t *testing.T) {
+		repo, client, sender := setup(&domain.Message{Timestamp: time.Now().Add(-25 * time.Hour)})
+		repo.On("GetMessageTemplate", mock.Anything, tenant.ID, domain.TemplatePurposeReminder).Return(nil, nil)
+		repo.On("GetMessageTemplate", mock.Anything, tenant.ID, domain.TemplatePurposeDefault).Return(&domain.MessageTemplate{
+			Name:         "assistant_update",
+			Language:     "en",
+			Placeholders: []string{"user_name", "text"},
+			Body:         "Hi {{1}}: {{2}}",
+		}, nil)
+		client.On("SendTemplate", mock.Anything, tenant.WABANumber, user.Phone, &domain.InfobipTemplate{
+			Name:         "assistant_update",
+			Language:     "en",
+			Placeholders: []string{"Ana", "Pay rent"},
+		}).Return(&domain.InfobipMessage{MessageID: "ib-2"}, nil)
+
+		delivery, err := sender.Send(context.Background(), repo, tenant, user, notification)
+		require.NoError(t, err)
+		assert.Equal(t, "ib-2", delivery.Message.MessageID)
+		assert.Equal(t, "Hi Ana: Pay rent", delivery.Text)
+		assert.Equal(t, "assistant_update", delivery.Template.Name)
+		client.AssertNotCalled(t, "SendText", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("fails without a template when the user never wrote", func(t *testing.T) {
+		repo, client, sender := setup(nil)
+		repo.On("GetMessageTemplate", mock.Anything, tenant.ID, mock.Anything).Return(nil, nil)
+
+		_, err := sender.Send(context.Background(), repo, tenant, user, notification)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, outbound.ErrNoTemplate))
+		client.AssertNotCalled(t, "SendText", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTemplateValues(t *testing.T) {
+	template := &domain.MessageTemplate{
+		Name:         "reminder_notice",
+		Placeholders: []string{"user_name", "text"},
+		Body:         "Hi {{1}}, you asked me to remind you: {{2}}",
+	}
+
+	values := outbound.TemplateValues(template, map[string]string{"text": "Pay rent"})
+	assert.Equal(t, []string{"-", "Pay rent"}, values)
+	assert.Equal(t, "Hi -, you asked me to remind you: Pay rent", outbound.RenderTemplate(template, values, "Pay rent"))
+
+	template.Body = ""
+	assert.Equal(t, "Pay rent", outbound.RenderTemplate(template, values, "Pay rent"))
+}
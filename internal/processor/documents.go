@@ -0,0 +1,143 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/rag"
+	"personal-assistant/internal/rag/document"
+)
+
+// ingestDocument parses a received document and stores its text in the
+// user's memory. It returns a note for the orchestrator describing the
+// outcome, or an empty string when the attachment is not a supported document.
+func (p *MessageProcessor) ingestDocument(ctx context.Context, repo domain.Repository, tenant *domain.Tenant, user *domain.User, media *domain.Media) string {
+	if media.Type != "document" || len(media.Data) == 0 {
+		return ""
+	}
+
+	format := document.DetectFormat(media.MimeType, media.FileName)
+	if format == "" {
+		return ""
+	}
+
+	logger := p.logger.WithContext(ctx).WithTenant(tenant.ID).WithUser(user.ID.String())
+
+	fileName := media.FileName
+	if fileName == "" {
+		fileName = fmt.Sprintf("document-%s", media.ID.String()[:8])
+	}
+
+	doc := &domain.Document{
+		TenantID: tenant.ID,
+		UserID:   user.ID,
+		MediaID:  &media.ID,
+		FileName: fileName,
+		MimeType: media.MimeType,
+		Metadata: map[string]interface{}{
+			"format":  format,
+			"caption": media.Caption,
+		},
+	}
+
+	if err := repo.CreateDocument(ctx, doc); err != nil {
+		logger.Warn().Err(err).Msg("failed to create document record")
+		return ""
+	}
+
+	chunks, err := p.parseAndIngest(ctx, tenant, doc, media)
+	if err != nil {
+		doc.Status = domain.DocumentStatusFailed
+		doc.LastError = err.Error()
+	} else {
+		doc.Status = domain.DocumentStatusReady
+		doc.ChunkCount = chunks
+	}
+
+	if updateErr := repo.UpdateDocument(ctx, doc); updateErr != nil {
+		logger.Warn().Err(updateErr).Str("document_id", doc.ID.String()).Msg("failed to update document status")
+	}
+
+	if err != nil {
+		logger.Warn().Err(err).Str("document_id", doc.ID.String()).Msg("document ingestion failed")
+	}
+
+	return documentNote(doc)
+}
+
+// documentNote describes the outcome of a document's ingestion to the orchestrator
+func documentNote(doc *domain.Document) string {
+	switch {
+	case doc.Status == domain.DocumentStatusFailed:
+		return fmt.Sprintf("[Document %q could not be read: %s]", doc.FileName, doc.LastError)
+	case doc.Status != domain.DocumentStatusReady:
+		return ""
+	case doc.PageCount > 0:
+		return fmt.Sprintf("[Document %q saved to memory: %d pages, document_id %s]", doc.FileName, doc.PageCount, doc.ID)
+	default:
+		return fmt.Sprintf("[Document %q saved to memory, document_id %s]", doc.FileName, doc.ID)
+	}
+}
+
+// storedDocumentNote returns the note of the document ingested from the media
+// of a stored message, if any
+func (p *MessageProcessor) storedDocumentNote(ctx context.Context, repo domain.Repository, tenantID string, message *domain.Message) string {
+	mediaID, _ := message.Metadata["media_id"].(string)
+	if mediaID == "" {
+		return ""
+	}
+
+	id, err := uuid.Parse(mediaID)
+	if err != nil {
+		return ""
+	}
+
+	doc, err := repo.GetDocumentByMediaID(ctx, tenantID, id)
+	if err != nil {
+		p.logger.WithContext(ctx).Warn().
+			Err(err).
+			Str("media_id", mediaID).
+			Msg("failed to get document of stored message")
+		return ""
+	}
+	if doc == nil {
+		return ""
+	}
+
+	return documentNote(doc)
+}
+
+// parseAndIngest extracts the document text and stores it through the RAG pipeline
+func (p *MessageProcessor) parseAndIngest(ctx context.Context, tenant *domain.Tenant, doc *domain.Document, media *domain.Media) (int, error) {
+	pages, err := document.Parse(media.Data, media.MimeType, media.FileName)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, page := range pages {
+		if page.Number > doc.PageCount {
+			doc.PageCount = page.Number
+		}
+	}
+
+	llmProvider, err := p.tenantManager.GetLLMProvider(tenant.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get LLM provider: %w", err)
+	}
+
+	vectorStore, err := p.tenantManager.GetVectorStore(tenant.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get vector store: %w", err)
+	}
+
+	repo, err := p.tenantManager.GetRepository(tenant.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	pipeline := rag.NewPipeline(llmProvider, vectorStore, repo, p.logger, rag.DefaultPipelineConfig())
+	return pipeline.IngestDocument(ctx, doc, pages)
+}
@@ -0,0 +1,149 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"personal-assistant/internal/domain"
+)
+
+// defaultListButtonText labels the list menu button when the assistant gives none
+const defaultListButtonText = "Options"
+
+// describeInteractiveReply turns a button or list reply into orchestrator text,
+// recording the selected option in the message metadata
+func describeInteractiveReply(incoming *domain.InfobipIncomingMessage) *inboundContent {
+	text := incoming.Title
+	if text == "" {
+		text = incoming.ID
+	}
+
+	metadata := map[string]interface{}{
+		"reply_id":    incoming.ID,
+		"reply_title": incoming.Title,
+	}
+	if incoming.Description != "" {
+		metadata["reply_description"] = incoming.Description
+	}
+
+	return &inboundContent{
+		Text:     text,
+		Metadata: metadata,
+	}
+}
+
+// sendChoices sends a response with options rendered as reply buttons when
+// they fit, as a list menu otherwise, or as numbered text as a last resort.
+// A rejected interactive message is sent again as numbered text.
+func (p *MessageProcessor) sendChoices(ctx context.Context, tenant *domain.Tenant, user *domain.User, originalMessage *domain.Message, text string, choices *domain.ReplyChoices) (*domain.InfobipMessage, error) {
+	if text == "" {
+		text = "Please choose an option:"
+	}
+
+	callbackData := domain.EncodeCallbackData(tenant.ID, originalMessage.MessageID)
+
+	interactive := utf8.RuneCountInString(text) <= domain.InfobipMaxInteractiveText &&
+		len(choices.Options) <= domain.InfobipMaxListRows
+
+	var (
+		sent *domain.InfobipMessage
+		err  error
+	)
+	switch {
+	case !interactive:
+		sent, err = p.infobipClient.SendText(ctx, tenant.WABANumber, user.Phone, numberedChoices(text, choices), callbackData)
+	case fitsButtons(choices):
+		sent, err = p.infobipClient.SendButtons(ctx, tenant.WABANumber, user.Phone, text, replyButtons(choices), callbackData)
+	default:
+		sent, err = p.infobipClient.SendList(ctx, tenant.WABANumber, user.Phone, text, replyList(choices), callbackData)
+	}
+	if err != nil && interactive {
+		p.logger.WithContext(ctx).Warn().
+			Err(err).
+			Int("options", len(choices.Options)).
+			Msg("failed to send interactive choices, falling back to text")
+		sent, err = p.infobipClient.SendText(ctx, tenant.WABANumber, user.Phone, numberedChoices(text, choices), callbackData)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to send choices via Infobip: %w", err)
+	}
+
+	p.logger.WithContext(ctx).Debug().
+		Str("to", user.Phone).
+		Int("options", len(choices.Options)).
+		Msg("choices sent successfully")
+
+	return sent, nil
+}
+
+// fitsButtons reports whether the options can be shown as quick reply buttons
+func fitsButtons(choices *domain.ReplyChoices) bool {
+	if len(choices.Options) > domain.InfobipMaxButtons {
+		return false
+	}
+	for _, option := range choices.Options {
+		if option.Description != "" || utf8.RuneCountInString(option.Title) > domain.InfobipMaxButtonTitle {
+			return false
+		}
+	}
+	return true
+}
+
+// replyButtons converts options into quick reply buttons
+func replyButtons(choices *domain.ReplyChoices) []domain.InfobipReplyButton {
+	buttons := make([]domain.InfobipReplyButton, 0, len(choices.Options))
+	for _, option := range choices.Options {
+		buttons = append(buttons, domain.InfobipReplyButton{
+			ID:    option.ID,
+			Title: option.Title,
+		})
+	}
+	return buttons
+}
+
+// replyList converts options into a single-section list menu
+func replyList(choices *domain.ReplyChoices) *domain.InfobipList {
+	buttonText := choices.ButtonText
+	if buttonText == "" {
+		buttonText = defaultListButtonText
+	}
+
+	rows := make([]domain.InfobipListRow, 0, len(choices.Options))
+	for _, option := range choices.Options {
+		rows = append(rows, domain.InfobipListRow{
+			ID:          option.ID,
+			Title:       truncateRunes(option.Title, domain.InfobipMaxListRowTitle),
+			Description: truncateRunes(option.Description, domain.InfobipMaxListDescription),
+		})
+	}
+
+	return &domain.InfobipList{
+		Title:    truncateRunes(buttonText, domain.InfobipMaxListButtonTitle),
+		Sections: []domain.InfobipListSection{{Rows: rows}},
+	}
+}
+
+// numberedChoices appends the options to the text as a numbered list
+func numberedChoices(text string, choices *domain.ReplyChoices) string {
+	var b strings.Builder
+	b.WriteString(text)
+	b.WriteString("\n")
+	for i, option := range choices.Options {
+		fmt.Fprintf(&b, "\n%d. %s", i+1, option.Title)
+		if option.Description != "" {
+			fmt.Fprintf(&b, " - %s", option.Description)
+		}
+	}
+	return b.String()
+}
+
+// truncateRunes shortens text to at most limit characters, marking the cut with an ellipsis
+func truncateRunes(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,186 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"personal-assistant/internal/domain"
+)
+
+// inboundContent holds the text passed to the orchestrator for an incoming
+// message, extra message metadata and the media record to store alongside it, if any
+type inboundContent struct {
+	Text     string
+	Metadata map[string]interface{}
+	Media    *domain.Media
+}
+
+// extractContent turns an incoming webhook message into orchestrator text,
+// downloading attached media through the Infobip client and transcribing voice notes
+func (p *MessageProcessor) extractContent(ctx context.Context, tenantID string, incoming *domain.InfobipIncomingMessage) *inboundContent {
+	switch incoming.Type {
+	case domain.InfobipMessageTypeText:
+		return &inboundContent{Text: incoming.Text.Text}
+
+	case domain.InfobipMessageTypeButtonReply, domain.InfobipMessageTypeListReply:
+		return describeInteractiveReply(incoming)
+
+	case domain.InfobipMessageTypeLocation:
+		return &inboundContent{
+			Text: describeLocation(incoming),
+			Media: &domain.Media{
+				ID:   uuid.New(),
+				Type: "location",
+				URL:  incoming.URL,
+				Metadata: map[string]interface{}{
+					"latitude":  incoming.Latitude,
+					"longitude": incoming.Longitude,
+					"name":      incoming.Name,
+					"address":   incoming.Address,
+				},
+			},
+		}
+
+	case domain.InfobipMessageTypeContact:
+		return &inboundContent{
+			Text: describeContacts(incoming.Contacts),
+			Media: &domain.Media{
+				ID:   uuid.New(),
+				Type: "contact",
+				Metadata: map[string]interface{}{
+					"contacts": incoming.Contacts,
+				},
+			},
+		}
+	}
+
+	media := &domain.Media{
+		ID:       uuid.New(),
+		Type:     mediaType(incoming.Type),
+		FileName: incoming.FileName,
+		Caption:  incoming.Caption,
+		URL:      incoming.URL,
+		Metadata: map[string]interface{}{
+			"infobip_type": incoming.Type,
+		},
+	}
+
+	content, err := p.infobipClient.DownloadMedia(ctx, incoming.URL)
+	if err != nil {
+		p.logger.WithContext(ctx).Warn().
+			Err(err).
+			Str("message_type", incoming.Type).
+			Msg("failed to download media, continuing with placeholder")
+		media.Metadata["download_error"] = err.Error()
+	} else {
+		media.Data = content.Data
+		media.SizeBytes = int64(len(content.Data))
+		media.MimeType = content.ContentType
+		if media.FileName == "" {
+			media.FileName = content.FileName
+		}
+	}
+
+	// Voice notes are processed as if the user had typed the transcript
+	if transcript, metadata := p.transcribeAudio(ctx, tenantID, media); transcript != "" {
+		return &inboundContent{
+			Text:     transcript,
+			Metadata: metadata,
+			Media:    media,
+		}
+	}
+
+	return &inboundContent{
+		Text:  describeMedia(media),
+		Media: media,
+	}
+}
+
+// mediaType maps an Infobip message type to a media record type
+func mediaType(infobipType string) string {
+	if infobipType == domain.InfobipMessageTypeVoice {
+		return "audio"
+	}
+	return strings.ToLower(infobipType)
+}
+
+// describeMedia builds the placeholder text shown to the orchestrator for a media message
+func describeMedia(media *domain.Media) string {
+	var label string
+	switch media.Type {
+	case "image":
+		label = "Image"
+	case "document":
+		label = "Document"
+	case "audio":
+		label = "Audio message"
+	case "video":
+		label = "Video"
+	default:
+		label = "Attachment"
+	}
+
+	var details []string
+	if media.FileName != "" {
+		details = append(details, media.FileName)
+	}
+	if media.MimeType != "" {
+		details = append(details, media.MimeType)
+	}
+	if len(media.Data) == 0 {
+		details = append(details, "content unavailable")
+	}
+
+	text := fmt.Sprintf("[%s received]", label)
+	if len(details) > 0 {
+		text = fmt.Sprintf("[%s received: %s]", label, strings.Join(details, ", "))
+	}
+	if media.Caption != "" {
+		text += " " + media.Caption
+	}
+
+	return text
+}
+
+// describeLocation builds the placeholder text for a shared location
+func describeLocation(incoming *domain.InfobipIncomingMessage) string {
+	var parts []string
+	if incoming.Name != "" {
+		parts = append(parts, incoming.Name)
+	}
+	if incoming.Address != "" {
+		parts = append(parts, incoming.Address)
+	}
+	parts = append(parts, fmt.Sprintf("%.6f, %.6f", incoming.Latitude, incoming.Longitude))
+
+	return fmt.Sprintf("[Location shared: %s]", strings.Join(parts, " - "))
+}
+
+// describeContacts builds the placeholder text for shared contact cards
+func describeContacts(contacts []domain.InfobipSharedContact) string {
+	var cards []string
+	for _, contact := range contacts {
+		name := contact.Name.FormattedName
+		if name == "" {
+			name = strings.TrimSpace(contact.Name.FirstName + " " + contact.Name.LastName)
+		}
+
+		parts := []string{}
+		if name != "" {
+			parts = append(parts, name)
+		}
+		for _, phone := range contact.Phones {
+			parts = append(parts, phone.Phone)
+		}
+		for _, email := range contact.Emails {
+			parts = append(parts, email.Email)
+		}
+
+		cards = append(cards, strings.Join(parts, ", "))
+	}
+
+	return fmt.Sprintf("[Contact shared: %s]", strings.Join(cards, "; "))
+}
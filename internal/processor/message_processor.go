@@ -2,42 +2,58 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"personal-assistant/internal/agents"
-	"personal-assistant/internal/agents/builtin"
+	"personal-assistant/internal/config"
 	"personal-assistant/internal/domain"
 	"personal-assistant/internal/log"
+	"personal-assistant/internal/outbound"
 	"personal-assistant/internal/rag"
+	"personal-assistant/internal/tools"
 )
 
 // MessageProcessor processes incoming WhatsApp messages
 type MessageProcessor struct {
 	tenantManager domain.TenantManager
 	infobipClient domain.InfobipClient
-	toolRegistry  domain.ToolRegistry
+	sender        *outbound.Sender
+	config        *config.Config
 	logger        *log.Logger
+	lanes         *userLanes
+	jobQueue      domain.JobQueue // Set when messages are processed by queue workers
 }
 
 // NewMessageProcessor creates a new message processor
 func NewMessageProcessor(
 	tenantManager domain.TenantManager,
 	infobipClient domain.InfobipClient,
-	toolRegistry domain.ToolRegistry,
+	cfg *config.Config,
 	logger *log.Logger,
 ) *MessageProcessor {
 	return &MessageProcessor{
 		tenantManager: tenantManager,
 		infobipClient: infobipClient,
-		toolRegistry:  toolRegistry,
+		sender:        outbound.NewSender(infobipClient, logger),
+		config:        cfg,
 		logger:        logger,
+		lanes:         newUserLanes(),
 	}
 }
 
-// ProcessIncoming processes an incoming webhook message
+// SetJobQueue lets the coalescing window see the later messages of a user
+// that the queue holds back until the current one is processed
+func (p *MessageProcessor) SetJobQueue(jobQueue domain.JobQueue) {
+	p.jobQueue = jobQueue
+}
+
+// ProcessIncoming processes an incoming webhook message. Failed results are
+// logged and skipped; use ProcessResult to handle a failure.
 func (p *MessageProcessor) ProcessIncoming(ctx context.Context, webhookMsg *domain.InfobipWebhookMessage) error {
 	if len(webhookMsg.Results) == 0 {
 		return fmt.Errorf("no results in webhook message")
@@ -45,7 +61,7 @@ func (p *MessageProcessor) ProcessIncoming(ctx context.Context, webhookMsg *doma
 
 	// Process each result
 	for _, result := range webhookMsg.Results {
-		if err := p.processWebhookResult(ctx, &result); err != nil {
+		if err := p.ProcessResult(ctx, &result); err != nil {
 			p.logger.WithContext(ctx).Error().
 				Err(err).
 				Str("message_id", result.MessageID).
@@ -58,13 +74,13 @@ func (p *MessageProcessor) ProcessIncoming(ctx context.Context, webhookMsg *doma
 	return nil
 }
 
-// processWebhookResult processes a single webhook result
-func (p *MessageProcessor) processWebhookResult(ctx context.Context, result *domain.InfobipWebhookResult) error {
-	// Extract message text
-	if result.Message.Type != "TEXT" || result.Message.Text.Text == "" {
+// ProcessResult processes a single webhook result, returning the error of a
+// failed attempt so the caller can retry it
+func (p *MessageProcessor) ProcessResult(ctx context.Context, result *domain.InfobipWebhookResult) error {
+	if !result.Message.IsSupported() {
 		p.logger.WithContext(ctx).Debug().
 			Str("message_type", result.Message.Type).
-			Msg("skipping non-text message")
+			Msg("skipping unsupported message")
 		return nil
 	}
 
@@ -81,26 +97,23 @@ func (p *MessageProcessor) processWebhookResult(ctx context.Context, result *dom
 	}
 
 	// Validate sender is allowed to contact this tenant
-	isAllowed, err := repo.IsContactAllowed(ctx, tenant.ID, result.From)
+	contact, admitted, err := p.admitSender(ctx, repo, tenant, result)
 	if err != nil {
-		p.logger.WithContext(ctx).Error().
-			Err(err).
-			Str("tenant_id", tenant.ID).
-			Str("sender", result.From).
-			Msg("failed to check if contact is allowed")
 		return fmt.Errorf("failed to validate sender: %w", err)
 	}
+	if !admitted {
+		return nil
+	}
 
-	if !isAllowed {
-		p.logger.WithContext(ctx).Warn().
-			Str("tenant_id", tenant.ID).
-			Str("sender", result.From).
-			Str("waba_number", result.To).
-			Str("message", result.Message.Text.Text).
-			Msg("unauthorized sender attempted to contact bot - message ignored")
-		return nil // Silently ignore unauthorized senders
+	// Admins answer access requests with the buttons sent to them
+	if phoneNumber, approve, ok := parseRequestDecision(&result.Message); ok {
+		return p.decideRequest(ctx, repo, tenant, contact, result, phoneNumber, approve)
 	}
 
+	// Messages of the same user are stored and answered one at a time, in order
+	lane := p.lanes.acquire(tenant.ID, result.From)
+	defer p.lanes.release(tenant.ID, result.From, lane)
+
 	// Get or create user
 
 	user, err := p.getOrCreateUser(ctx, repo, tenant.ID, result.From, result.Contact.Name)
@@ -108,6 +121,18 @@ func (p *MessageProcessor) processWebhookResult(ctx context.Context, result *dom
 		return fmt.Errorf("failed to get or create user: %w", err)
 	}
 
+	// A retried job finds the message stored by its previous attempt
+	stored, err := repo.GetMessageByID(ctx, tenant.ID, result.MessageID)
+	if err != nil {
+		return fmt.Errorf("failed to check for stored message: %w", err)
+	}
+	if stored != nil {
+		return p.reprocessMessage(ctx, repo, tenant, user, stored)
+	}
+
+	// Extract text, downloading and transcribing media if needed
+	content := p.extractContent(ctx, tenant.ID, &result.Message)
+
 	// Create message record
 	message := &domain.Message{
 		ID:        uuid.New(),
@@ -115,19 +140,81 @@ func (p *MessageProcessor) processWebhookResult(ctx context.Context, result *dom
 		UserID:    user.ID,
 		MessageID: result.MessageID,
 		Direction: "inbound",
-		Text:      result.Message.Text.Text,
+		Text:      content.Text,
 		Timestamp: result.ReceivedAt,
 		Metadata: map[string]interface{}{
 			"integration_type": result.IntegrationType,
 			"contact_name":     result.Contact.Name,
+			"message_type":     result.Message.Type,
 		},
 		CreatedAt: time.Now().UTC(),
 	}
+	if content.Media != nil {
+		message.Metadata["media_id"] = content.Media.ID.String()
+	}
+	for key, value := range content.Metadata {
+		message.Metadata[key] = value
+	}
 
 	if err := repo.CreateMessage(ctx, message); err != nil {
+		// A concurrent delivery of the same webhook stored it first
+		if errors.Is(err, domain.ErrDuplicateMessage) {
+			p.logger.WithContext(ctx).Debug().
+				Str("tenant_id", tenant.ID).
+				Str("message_id", result.MessageID).
+				Msg("skipping duplicate message")
+			return nil
+		}
 		return fmt.Errorf("failed to store incoming message: %w", err)
 	}
 
+	// Store media record
+	noted := false
+	if content.Media != nil {
+		content.Media.TenantID = tenant.ID
+		content.Media.UserID = user.ID
+		content.Media.MessageID = message.ID
+
+		if err := repo.CreateMedia(ctx, content.Media); err != nil {
+			p.logger.WithContext(ctx).Warn().
+				Err(err).
+				Str("media_id", content.Media.ID.String()).
+				Msg("failed to store media")
+		} else if note := p.ingestDocument(ctx, repo, tenant, user, content.Media); note != "" {
+			// Let the orchestrator tell the user what happened to the document
+			processed := *message
+			processed.Text = message.Text + "\n" + note
+			message = &processed
+			noted = true
+		}
+	}
+
+	// Let a burst of messages be answered in a single turn: if the user sends
+	// another message within the window, this one is only part of its history.
+	// Document notes are not stored, so those messages are always answered.
+	seq := lane.next()
+	if p.config.CoalesceWindow > 0 && !noted {
+		superseded, err := lane.superseded(ctx, seq, p.config.CoalesceWindow)
+		if err != nil {
+			return fmt.Errorf("interrupted while waiting for further messages: %w", err)
+		}
+		if !superseded && p.jobQueue != nil {
+			if superseded, err = p.jobQueue.HasPending(ctx, result); err != nil {
+				p.logger.WithContext(ctx).Warn().
+					Err(err).
+					Str("message_id", result.MessageID).
+					Msg("failed to check for further messages, answering this one")
+			}
+		}
+		if superseded {
+			p.logger.WithContext(ctx).Debug().
+				Str("tenant_id", tenant.ID).
+				Str("message_id", message.MessageID).
+				Msg("message coalesced into a later turn")
+			return nil
+		}
+	}
+
 	// Process the message
 	return p.ProcessMessage(ctx, tenant, user, message)
 }
@@ -167,61 +254,173 @@ func (p *MessageProcessor) ProcessMessage(ctx context.Context, tenant *domain.Te
 	// Create RAG pipeline
 	ragPipeline := rag.NewPipeline(llmProvider, vectorStore, repo, logger, rag.DefaultPipelineConfig())
 
-	// Initialize tools for this tenant
-	if err := p.initializeToolsForTenant(ctx, tenant.ID, vectorStore, llmProvider, repo, logger); err != nil {
-		return fmt.Errorf("failed to initialize tools: %w", err)
+	// Get tool registry for tenant
+	toolRegistry, err := p.tenantManager.GetToolRegistry(tenant.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get tool registry: %w", err)
+	}
+
+	// Limit tools to those the sender is permitted to use
+	contact, err := senderContact(ctx, repo, tenant, user.Phone)
+	if err != nil {
+		return err
 	}
 
 	// Create orchestrator
+	orchestratorConfig := agents.DefaultOrchestratorConfig()
 	orchestrator := agents.NewMainOrchestrator(
 		llmProvider,
 		ragPipeline,
-		p.toolRegistry,
+		tools.NewContactRegistry(toolRegistry, contact),
 		nil, // Agent registry can be nil for now
+		repo,
 		logger,
-		agents.DefaultOrchestratorConfig(),
+		orchestratorConfig,
 	)
 
+	// Send long replies paragraph by paragraph while they are generated
+	var sent *domain.InfobipMessage
+	if p.config.StreamReplies {
+		orchestrator.SetReplySink(func(ctx context.Context, text string) error {
+			paragraph, err := p.sendResponse(ctx, tenant, user, message, text)
+			if err != nil {
+				return err
+			}
+			sent = paragraph
+			return nil
+		})
+	}
+
 	// Process message through orchestrator
 	response, err := orchestrator.Route(ctx, tenant, user, message)
-	if err != nil {
+	var partial *agents.PartialReplyError
+	if errors.As(err, &partial) {
+		// Part of the reply reached the user, so the turn is stored as it is
+		// instead of being retried
+		logger.Warn().Err(err).Msg("reply stream failed after part of it was sent")
+		response = &domain.AgentResponse{
+			Text:     partial.Text,
+			Streamed: true,
+			Metadata: map[string]interface{}{
+				"partial_reply": true,
+				"error":         partial.Err.Error(),
+			},
+		}
+	} else if err != nil {
 		return fmt.Errorf("orchestrator failed to process message: %w", err)
 	}
 
-	// Send response back to user
-	if response.Text != "" {
-		err = p.sendResponse(ctx, tenant, user, message, response.Text)
+	// Send response back to user, rendering offered choices as buttons or a list.
+	// A streamed reply was already sent and is stored under its last paragraph.
+	if response.Streamed {
+		logger.Debug().Msg("response streamed to user")
+	} else if response.Choices != nil && len(response.Choices.Options) > 0 {
+		sent, err = p.sendChoices(ctx, tenant, user, message, response.Text, response.Choices)
+		if err != nil {
+			return fmt.Errorf("failed to send response: %w", err)
+		}
+	} else if response.Text != "" {
+		sent, err = p.sendResponse(ctx, tenant, user, message, response.Text)
 		if err != nil {
 			return fmt.Errorf("failed to send response: %w", err)
 		}
 	}
 
-	// Store outbound message
+	// Store outbound message under the Infobip message ID so status reports can find it
+	outboundMessageID := fmt.Sprintf("out_%d", time.Now().UnixNano())
+	if sent != nil && sent.MessageID != "" {
+		outboundMessageID = sent.MessageID
+	}
+
+	// Link the reply to the message it answers, so a retried job does not answer it again
+	if response.Metadata == nil {
+		response.Metadata = make(map[string]interface{})
+	}
+	response.Metadata["reply_to"] = message.MessageID
+
+	// Paragraphs streamed before tool calls are part of the reply the user got
+	replyText := response.Text
+	if response.Preface != "" {
+		replyText = strings.TrimSpace(response.Preface + "\n\n" + response.Text)
+	}
+
 	outboundMessage := &domain.Message{
 		ID:        uuid.New(),
 		TenantID:  tenant.ID,
 		UserID:    user.ID,
-		MessageID: fmt.Sprintf("out_%d", time.Now().UnixNano()),
+		MessageID: outboundMessageID,
 		Direction: "outbound",
-		Text:      response.Text,
+		Text:      replyText,
 		Timestamp: time.Now().UTC(),
 		Metadata:  response.Metadata,
+		Status:    domain.MessageStatusSent,
 		CreatedAt: time.Now().UTC(),
 	}
 
+	// Token usage of all completions of the turn
+	if usage, ok := response.Metadata["token_usage"].(*domain.TokenUsage); ok {
+		outboundMessage.TokenUsage = usage
+	}
+
+	if response.Choices != nil {
+		outboundMessage.Metadata["choices"] = response.Choices
+	}
+
 	if err := repo.CreateMessage(ctx, outboundMessage); err != nil {
 		logger.Warn().Err(err).Msg("failed to store outbound message")
 		// Don't fail the whole process for this
 	}
 
+	// Compress older turns once the history grows past the threshold
+	if orchestratorConfig.SummarizeEnabled && p.config.SummarizeThreshold > 0 {
+		summarizerConfig := agents.DefaultSummarizerConfig()
+		summarizerConfig.Threshold = p.config.SummarizeThreshold
+
+		summarizer := agents.NewSummarizer(llmProvider, vectorStore, repo, logger, summarizerConfig)
+		if _, err := summarizer.MaybeSummarize(ctx, tenant.ID, user.ID); err != nil {
+			logger.Warn().Err(err).Msg("failed to summarize conversation")
+		}
+	}
+
 	logger.Info().
 		Dur("duration", time.Since(start)).
-		Bool("response_sent", response.Text != "").
+		Bool("response_sent", response.Text != "" || response.Choices != nil).
 		Msg("message processing completed")
 
 	return nil
 }
 
+// reprocessMessage answers a message stored by a previous attempt of its job,
+// unless that attempt already stored a reply before it lost the job's lease
+func (p *MessageProcessor) reprocessMessage(ctx context.Context, repo domain.Repository, tenant *domain.Tenant, user *domain.User, message *domain.Message) error {
+	logger := p.logger.WithContext(ctx)
+
+	reply, err := repo.GetReplyMessage(ctx, tenant.ID, message.MessageID)
+	if err != nil {
+		return fmt.Errorf("failed to check for stored reply: %w", err)
+	}
+	if reply != nil {
+		logger.Info().
+			Str("tenant_id", tenant.ID).
+			Str("message_id", message.MessageID).
+			Msg("skipping message that was already answered")
+		return nil
+	}
+
+	// Tell the orchestrator again what happened to an attached document
+	if note := p.storedDocumentNote(ctx, repo, tenant.ID, message); note != "" {
+		processed := *message
+		processed.Text = message.Text + "\n" + note
+		message = &processed
+	}
+
+	logger.Info().
+		Str("tenant_id", tenant.ID).
+		Str("message_id", message.MessageID).
+		Msg("reprocessing stored message")
+	return p.ProcessMessage(ctx, tenant, user, message)
+}
+
 // getOrCreateUser gets an existing user or creates a new one
 func (p *MessageProcessor) getOrCreateUser(ctx context.Context, repo domain.Repository, tenantID, phone, contactName string) (*domain.User, error) {
 	// Try to get existing user
@@ -261,10 +460,11 @@ func (p *MessageProcessor) getOrCreateUser(ctx context.Context, repo domain.Repo
 }
 
 // sendResponse sends a response message back to the user
-func (p *MessageProcessor) sendResponse(ctx context.Context, tenant *domain.Tenant, user *domain.User, originalMessage *domain.Message, responseText string) error {
-	_, err := p.infobipClient.SendText(ctx, tenant.WABANumber, user.Phone, responseText, originalMessage.MessageID)
+func (p *MessageProcessor) sendResponse(ctx context.Context, tenant *domain.Tenant, user *domain.User, originalMessage *domain.Message, responseText string) (*domain.InfobipMessage, error) {
+	callbackData := domain.EncodeCallbackData(tenant.ID, originalMessage.MessageID)
+	sent, err := p.infobipClient.SendText(ctx, tenant.WABANumber, user.Phone, responseText, callbackData)
 	if err != nil {
-		return fmt.Errorf("failed to send response via Infobip: %w", err)
+		return nil, fmt.Errorf("failed to send response via Infobip: %w", err)
 	}
 
 	p.logger.WithContext(ctx).Debug().
@@ -272,48 +472,7 @@ func (p *MessageProcessor) sendResponse(ctx context.Context, tenant *domain.Tena
 		Str("text", log.SanitizeText(responseText)).
 		Msg("response sent successfully")
 
-	return nil
-}
-
-// initializeToolsForTenant initializes tools for a specific tenant
-func (p *MessageProcessor) initializeToolsForTenant(ctx context.Context, tenantID string, vectorStore domain.VectorStore, llmProvider domain.LLMProvider, repo domain.Repository, logger *log.Logger) error {
-	// Register DB tools
-	if err := p.toolRegistry.RegisterTool(builtin.NewDBUpsertTool(vectorStore, llmProvider, logger)); err != nil {
-		logger.Warn().Err(err).Msg("failed to register upsert tool")
-	}
-
-	if err := p.toolRegistry.RegisterTool(builtin.NewDBSearchTool(vectorStore, llmProvider, logger)); err != nil {
-		logger.Warn().Err(err).Msg("failed to register search tool")
-	}
-
-	if err := p.toolRegistry.RegisterTool(builtin.NewDBGetByIDTool(vectorStore, logger)); err != nil {
-		logger.Warn().Err(err).Msg("failed to register get_by_id tool")
-	}
-
-	if err := p.toolRegistry.RegisterTool(builtin.NewDBUpdateItemTool(vectorStore, llmProvider, logger)); err != nil {
-		logger.Warn().Err(err).Msg("failed to register update_item tool")
-	}
-
-	// Register HTTP tools
-	if err := p.toolRegistry.RegisterTool(builtin.NewHTTPCallTool(repo, logger)); err != nil {
-		logger.Warn().Err(err).Msg("failed to register call_api tool")
-	}
-
-	if err := p.toolRegistry.RegisterTool(builtin.NewReminderScheduleTool(logger)); err != nil {
-		logger.Warn().Err(err).Msg("failed to register schedule_reminder tool")
-	}
-
-	// Register example weather tool (optional)
-	if err := p.toolRegistry.RegisterTool(builtin.NewWeatherTool("demo_api_key", logger)); err != nil {
-		logger.Warn().Err(err).Msg("failed to register weather tool")
-	}
-
-	logger.Debug().
-		Str("tenant_id", tenantID).
-		Int("tools_registered", len(p.toolRegistry.ListTools())).
-		Msg("tools initialized for tenant")
-
-	return nil
+	return sent, nil
 }
 
 // Close closes the message processor and its resources
@@ -0,0 +1,144 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+func (m *MockRepository) GetUser(ctx context.Context, tenantID, phone string) (*domain.User, error) {
+	args := m.Called(ctx, tenantID, phone)
+	user, _ := args.Get(0).(*domain.User)
+	return user, args.Error(1)
+}
+
+func (m *MockRepository) GetMessageByID(ctx context.Context, tenantID string, messageID string) (*domain.Message, error) {
+	args := m.Called(ctx, tenantID, messageID)
+	message, _ := args.Get(0).(*domain.Message)
+	return message, args.Error(1)
+}
+
+func (m *MockRepository) GetReplyMessage(ctx context.Context, tenantID string, inboundMessageID string) (*domain.Message, error) {
+	args := m.Called(ctx, tenantID, inboundMessageID)
+	message, _ := args.Get(0).(*domain.Message)
+	return message, args.Error(1)
+}
+
+func (m *MockRepository) GetDocumentByMediaID(ctx context.Context, tenantID string, mediaID uuid.UUID) (*domain.Document, error) {
+	args := m.Called(ctx, tenantID, mediaID)
+	document, _ := args.Get(0).(*domain.Document)
+	return document, args.Error(1)
+}
+
+// MockTenantManager implements the tenant lookups used by the processor
+type MockTenantManager struct {
+	domain.TenantManager
+	mock.Mock
+}
+
+func (m *MockTenantManager) GetTenant(wabaNumber string) (*domain.Tenant, error) {
+	args := m.Called(wabaNumber)
+	tenant, _ := args.Get(0).(*domain.Tenant)
+	return tenant, args.Error(1)
+}
+
+func (m *MockTenantManager) GetRepository(tenantID string) (domain.Repository, error) {
+	args := m.Called(tenantID)
+	repo, _ := args.Get(0).(domain.Repository)
+	return repo, args.Error(1)
+}
+
+func (m *MockTenantManager) GetLLMProvider(tenantID string) (domain.LLMProvider, error) {
+	args := m.Called(tenantID)
+	provider, _ := args.Get(0).(domain.LLMProvider)
+	return provider, args.Error(1)
+}
+
+func TestMessageProcessor_ProcessResult_Retry(t *testing.T) {
+	ctx := context.Background()
+	tenant := &domain.Tenant{ID: "tenant-a", WABANumber: "15550000000"}
+	user := &domain.User{ID: uuid.New(), TenantID: tenant.ID, Phone: "5511999999999"}
+	result := &domain.InfobipWebhookResult{
+		From:      user.Phone,
+		To:        tenant.WABANumber,
+		MessageID: "ib-in-1",
+		Message:   domain.InfobipIncomingMessage{Type: "TEXT", Text: domain.InfobipIncomingText{Text: "Summarize the attached report"}},
+	}
+
+	setup := func(stored *domain.Message) (*MockRepository, *MockTenantManager, *MessageProcessor) {
+		repo := &MockRepository{}
+		repo.On("GetAllowedContact", mock.Anything, tenant.ID, user.Phone).Return(&domain.AllowedContact{PhoneNumber: user.Phone, Enabled: true}, nil)
+		repo.On("GetUser", mock.Anything, tenant.ID, user.Phone).Return(user, nil)
+		repo.On("GetMessageByID", mock.Anything, tenant.ID, result.MessageID).Return(stored, nil)
+
+		manager := &MockTenantManager{}
+		manager.On("GetTenant", tenant.WABANumber).Return(tenant, nil)
+		manager.On("GetRepository", tenant.ID).Return(repo, nil)
+
+		return repo, manager, NewMessageProcessor(manager, nil, &config.Config{}, log.Init("error"))
+	}
+
+	stored := &domain.Message{ID: uuid.New(), TenantID: tenant.ID, UserID: user.ID, MessageID: result.MessageID, Direction: "inbound", Text: result.Message.Text.Text}
+
+	t.Run("does not answer a message whose reply was stored", func(t *testing.T) {
+		repo, manager, processor := setup(stored)
+		repo.On("GetReplyMessage", mock.Anything, tenant.ID, result.MessageID).
+			Return(&domain.Message{MessageID: "ib-out-1", Direction: "outbound"}, nil)
+
+		require.NoError(t, processor.ProcessResult(ctx, result))
+		manager.AssertNotCalled(t, "GetLLMProvider", mock.Anything)
+	})
+
+	t.Run("answers a stored message without a reply again", func(t *testing.T) {
+		repo, manager, processor := setup(stored)
+		repo.On("GetReplyMessage", mock.Anything, tenant.ID, result.MessageID).Return(nil, nil)
+		manager.On("GetLLMProvider", tenant.ID).Return(nil, errors.New("provider unavailable"))
+
+		err := processor.ProcessResult(ctx, result)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "provider unavailable")
+		manager.AssertExpectations(t)
+	})
+}
+
+func TestMessageProcessor_StoredDocumentNote(t *testing.T) {
+	ctx := context.Background()
+	processor := NewMessageProcessor(nil, nil, &config.Config{}, log.Init("error"))
+	mediaID := uuid.New()
+	documentID := uuid.New()
+
+	message := &domain.Message{
+		TenantID: "tenant-a",
+		Metadata: map[string]interface{}{"media_id": mediaID.String()},
+	}
+
+	t.Run("describes the document ingested from the message", func(t *testing.T) {
+		repo := &MockRepository{}
+		repo.On("GetDocumentByMediaID", mock.Anything, "tenant-a", mediaID).Return(&domain.Document{
+			ID:        documentID,
+			FileName:  "report.pdf",
+			Status:    domain.DocumentStatusReady,
+			PageCount: 3,
+		}, nil)
+
+		note := processor.storedDocumentNote(ctx, repo, "tenant-a", message)
+		assert.Equal(t, `[Document "report.pdf" saved to memory: 3 pages, document_id `+documentID.String()+`]`, note)
+	})
+
+	t.Run("has no note for messages without media", func(t *testing.T) {
+		repo := &MockRepository{}
+
+		note := processor.storedDocumentNote(ctx, repo, "tenant-a", &domain.Message{TenantID: "tenant-a"})
+		assert.Empty(t, note)
+		repo.AssertNotCalled(t, "GetDocumentByMediaID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
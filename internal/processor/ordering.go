@@ -0,0 +1,122 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// userLane serializes the messages of a single user within this process. The
+// lane is held while a message is stored and answered, and is handed to
+// waiting messages first come, first served, so replies follow the order in
+// which messages reached the processor. Across replicas the job queue claims
+// the messages of a user one at a time in the order Infobip received them.
+type userLane struct {
+	lanes   *userLanes
+	held    bool
+	waiters []chan struct{} // Messages waiting for the lane, in arrival order
+	latest  uint64          // Sequence number of the most recently stored message
+	refs    int             // Messages holding or waiting for the lane
+}
+
+// userLanes hands out one lane per (tenant, user) pair. Lanes only exist
+// while messages of the user are in flight.
+type userLanes struct {
+	mutex sync.Mutex
+	lanes map[string]*userLane
+}
+
+// newUserLanes creates an empty set of lanes
+func newUserLanes() *userLanes {
+	return &userLanes{
+		lanes: make(map[string]*userLane),
+	}
+}
+
+// acquire waits for the lane of a user and holds it
+func (l *userLanes) acquire(tenantID, sender string) *userLane {
+	key := tenantID + "|" + sender
+
+	l.mutex.Lock()
+	lane, exists := l.lanes[key]
+	if !exists {
+		lane = &userLane{lanes: l}
+		l.lanes[key] = lane
+	}
+	lane.refs++
+	l.mutex.Unlock()
+
+	lane.lock()
+	return lane
+}
+
+// release gives up a lane acquired for a user
+func (l *userLanes) release(tenantID, sender string, lane *userLane) {
+	lane.unlock()
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	lane.refs--
+	if lane.refs == 0 {
+		delete(l.lanes, tenantID+"|"+sender)
+	}
+}
+
+// lock waits until the lane is handed over, after the messages waiting before
+func (lane *userLane) lock() {
+	lane.lanes.mutex.Lock()
+	if !lane.held {
+		lane.held = true
+		lane.lanes.mutex.Unlock()
+		return
+	}
+	turn := make(chan struct{})
+	lane.waiters = append(lane.waiters, turn)
+	lane.lanes.mutex.Unlock()
+
+	<-turn
+}
+
+// unlock hands the lane to the message waiting longest, if any
+func (lane *userLane) unlock() {
+	lane.lanes.mutex.Lock()
+	defer lane.lanes.mutex.Unlock()
+
+	if len(lane.waiters) == 0 {
+		lane.held = false
+		return
+	}
+	close(lane.waiters[0])
+	lane.waiters = lane.waiters[1:]
+}
+
+// next records a newly stored message and returns its sequence number.
+// The lane must be held.
+func (lane *userLane) next() uint64 {
+	lane.latest++
+	return lane.latest
+}
+
+// superseded waits for the coalescing window with the lane released, so
+// further messages of the burst can be stored, and reports whether a newer
+// message arrived meanwhile. The lane is held again when it returns.
+func (lane *userLane) superseded(ctx context.Context, seq uint64, window time.Duration) (bool, error) {
+	lane.unlock()
+
+	timer := time.NewTimer(window)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timer.C:
+	}
+
+	lane.lock()
+	if err != nil {
+		return false, err
+	}
+	return lane.latest != seq, nil
+}
@@ -0,0 +1,121 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForWaiters blocks until n messages are waiting for the lane
+func waitForWaiters(t *testing.T, lanes *userLanes, lane *userLane, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		lanes.mutex.Lock()
+		defer lanes.mutex.Unlock()
+		return len(lane.waiters) == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestUserLanes(t *testing.T) {
+	t.Run("hands the lane over in arrival order", func(t *testing.T) {
+		lanes := newUserLanes()
+		lane := lanes.acquire("tenant-1", "5511999999999")
+
+		var (
+			mutex sync.Mutex
+			order []int
+			wg    sync.WaitGroup
+		)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				waiting := lanes.acquire("tenant-1", "5511999999999")
+				mutex.Lock()
+				order = append(order, i)
+				mutex.Unlock()
+				lanes.release("tenant-1", "5511999999999", waiting)
+			}(i)
+			waitForWaiters(t, lanes, lane, i+1)
+		}
+
+		lanes.release("tenant-1", "5511999999999", lane)
+		wg.Wait()
+
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, order)
+		assert.Empty(t, lanes.lanes)
+	})
+
+	t.Run("does not block other users", func(t *testing.T) {
+		lanes := newUserLanes()
+		lane := lanes.acquire("tenant-1", "5511999999999")
+		defer lanes.release("tenant-1", "5511999999999", lane)
+
+		acquired := make(chan struct{})
+		go func() {
+			other := lanes.acquire("tenant-1", "5511888888888")
+			lanes.release("tenant-1", "5511888888888", other)
+			close(acquired)
+		}()
+
+		select {
+		case <-acquired:
+		case <-time.After(time.Second):
+			t.Fatal("lane of another user was blocked")
+		}
+	})
+}
+
+func TestUserLane_Superseded(t *testing.T) {
+	ctx := context.Background()
+	window := 50 * time.Millisecond
+
+	t.Run("answers a message without a later one", func(t *testing.T) {
+		lanes := newUserLanes()
+		lane := lanes.acquire("tenant-1", "5511999999999")
+		defer lanes.release("tenant-1", "5511999999999", lane)
+
+		superseded, err := lane.superseded(ctx, lane.next(), window)
+		require.NoError(t, err)
+		assert.False(t, superseded)
+	})
+
+	t.Run("coalesces a message followed within the window", func(t *testing.T) {
+		lanes := newUserLanes()
+		lane := lanes.acquire("tenant-1", "5511999999999")
+		defer lanes.release("tenant-1", "5511999999999", lane)
+
+		stored := make(chan struct{})
+		go func() {
+			later := lanes.acquire("tenant-1", "5511999999999")
+			later.next()
+			lanes.release("tenant-1", "5511999999999", later)
+			close(stored)
+		}()
+		waitForWaiters(t, lanes, lane, 1)
+
+		superseded, err := lane.superseded(ctx, lane.next(), window)
+		require.NoError(t, err)
+		assert.True(t, superseded)
+		<-stored
+	})
+
+	t.Run("holds the lane again when the context is cancelled", func(t *testing.T) {
+		lanes := newUserLanes()
+		lane := lanes.acquire("tenant-1", "5511999999999")
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := lane.superseded(cancelled, lane.next(), time.Minute)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.True(t, lane.held)
+
+		lanes.release("tenant-1", "5511999999999", lane)
+		assert.Empty(t, lanes.lanes)
+	})
+}
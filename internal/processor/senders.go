@@ -0,0 +1,256 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"personal-assistant/internal/allowlist"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/outbound"
+)
+
+const (
+	// contactRequestReplyPrefix starts the IDs of the approve and deny buttons sent to admins
+	contactRequestReplyPrefix = "contact_request:"
+	// maxRequestPreview is the length of the sender's message quoted to admins
+	maxRequestPreview = 600
+)
+
+// admitSender checks the sender against the tenant's contact allowlist and
+// applies the tenant's unknown sender action to senders outside it. It returns
+// the sender's contact, nil when the allowlist is not required, and whether
+// the message should be processed.
+func (p *MessageProcessor) admitSender(ctx context.Context, repo domain.Repository, tenant *domain.Tenant, result *domain.InfobipWebhookResult) (*domain.AllowedContact, bool, error) {
+	logger := p.logger.WithContext(ctx)
+
+	contact, err := repo.GetAllowedContact(ctx, tenant.ID, result.From)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("tenant_id", tenant.ID).
+			Str("sender", result.From).
+			Msg("failed to check if contact is allowed")
+		return nil, false, err
+	}
+
+	if contact != nil {
+		if !contact.Enabled {
+			// Disabled contacts were blocked on purpose, whatever the policy
+			logger.Warn().
+				Str("tenant_id", tenant.ID).
+				Str("sender", result.From).
+				Msg("disabled contact attempted to contact bot - message ignored")
+		}
+		return contact, contact.Enabled, nil
+	}
+
+	policy, err := allowlist.LoadPolicy(ctx, repo, tenant)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !policy.RequireAllowlist {
+		return nil, true, nil
+	}
+
+	switch policy.UnknownSenderAction {
+	case domain.UnknownSenderAutoAdd:
+		contact, err := allowlist.AddContact(ctx, repo, tenant.ID, result.From, result.Contact.Name, policy.DefaultPermissions, "Added automatically on first message")
+		if err != nil {
+			return nil, false, err
+		}
+
+		logger.Info().
+			Str("tenant_id", tenant.ID).
+			Str("sender", result.From).
+			Strs("permissions", contact.Permissions).
+			Msg("unknown sender added to allowed contacts")
+		return contact, true, nil
+
+	case domain.UnknownSenderNotifyAdmin:
+		request, notify, err := allowlist.RecordRequest(ctx, repo, tenant.ID, result.From, result.Contact.Name, requestPreview(&result.Message))
+		if err != nil {
+			return nil, false, err
+		}
+
+		if notify {
+			p.notifyAdmins(ctx, repo, tenant, request)
+		}
+
+		logger.Info().
+			Str("tenant_id", tenant.ID).
+			Str("sender", result.From).
+			Str("status", request.Status).
+			Msg("unknown sender awaiting admin approval - message ignored")
+		return nil, false, nil
+
+	default:
+		logger.Warn().
+			Str("tenant_id", tenant.ID).
+			Str("sender", result.From).
+			Str("waba_number", result.To).
+			Str("message", result.Message.Text.Text).
+			Msg("unauthorized sender attempted to contact bot - message ignored")
+		return nil, false, nil
+	}
+}
+
+// senderContact returns the allowed contact of a sender. Senders outside the
+// allowlist, admitted because it is not required, get the tenant's default
+// contact permissions.
+func senderContact(ctx context.Context, repo domain.Repository, tenant *domain.Tenant, phoneNumber string) (*domain.AllowedContact, error) {
+	contact, err := repo.GetAllowedContact(ctx, tenant.ID, phoneNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allowed contact: %w", err)
+	}
+	if contact != nil {
+		return contact, nil
+	}
+
+	policy, err := allowlist.LoadPolicy(ctx, repo, tenant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sender policy: %w", err)
+	}
+
+	return &domain.AllowedContact{
+		TenantID:    tenant.ID,
+		PhoneNumber: phoneNumber,
+		Permissions: policy.DefaultPermissions,
+	}, nil
+}
+
+// notifyAdmins asks the tenant's admin contacts to approve or deny an access
+// request. Admins outside the customer service window get the tenant's
+// contact_request template instead of the buttons; admins that cannot be
+// reached can still decide through the API.
+func (p *MessageProcessor) notifyAdmins(ctx context.Context, repo domain.Repository, tenant *domain.Tenant, request *domain.ContactRequest) {
+	logger := p.logger.WithContext(ctx)
+
+	admins, err := allowlist.Admins(ctx, repo, tenant.ID)
+	if err != nil {
+		logger.Warn().Err(err).Str("tenant_id", tenant.ID).Msg("failed to get admin contacts")
+		return
+	}
+
+	if len(admins) == 0 {
+		logger.Warn().
+			Str("tenant_id", tenant.ID).
+			Str("sender", request.PhoneNumber).
+			Msg("no admin contact to approve access request")
+		return
+	}
+
+	sender := request.PhoneNumber
+	if request.ContactName != "" {
+		sender = fmt.Sprintf("%s (%s)", request.ContactName, request.PhoneNumber)
+	}
+
+	notification := &outbound.Notification{
+		Purpose: domain.TemplatePurposeContactRequest,
+		Text:    fmt.Sprintf("%s is not on the contact list and wrote:\n\n%s\n\nAllow them to chat with the assistant?", sender, request.LastMessage),
+		Variables: map[string]string{
+			"sender":  sender,
+			"message": request.LastMessage,
+		},
+		Buttons: []domain.InfobipReplyButton{
+			{ID: contactRequestReplyPrefix + "approve:" + request.PhoneNumber, Title: "Approve"},
+			{ID: contactRequestReplyPrefix + "deny:" + request.PhoneNumber, Title: "Deny"},
+		},
+	}
+
+	for _, admin := range admins {
+		// Admins who never wrote have no user, so their window is closed
+		user, err := repo.GetUser(ctx, tenant.ID, admin.PhoneNumber)
+		if err != nil {
+			logger.Warn().Err(err).Str("tenant_id", tenant.ID).Str("admin", admin.PhoneNumber).Msg("failed to get admin user")
+			continue
+		}
+		if user == nil {
+			user = &domain.User{TenantID: tenant.ID, Phone: admin.PhoneNumber}
+		}
+
+		if _, err := p.sender.Send(ctx, repo, tenant, user, notification); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("tenant_id", tenant.ID).
+				Str("admin", admin.PhoneNumber).
+				Msg("failed to notify admin of access request")
+		}
+	}
+}
+
+// decideRequest applies an admin's answer to an access request and confirms it to them
+func (p *MessageProcessor) decideRequest(ctx context.Context, repo domain.Repository, tenant *domain.Tenant, contact *domain.AllowedContact, result *domain.InfobipWebhookResult, phoneNumber string, approve bool) error {
+	var reply string
+	if contact == nil || !contact.HasPermission(domain.PermissionAdmin) {
+		reply = "Only admin contacts can approve or deny access requests."
+	} else {
+		request, err := allowlist.Decide(ctx, repo, tenant, phoneNumber, approve, result.From)
+		switch {
+		case errors.Is(err, allowlist.ErrRequestNotFound):
+			reply = fmt.Sprintf("There is no access request from %s.", phoneNumber)
+		case errors.Is(err, allowlist.ErrRequestDecided):
+			reply = fmt.Sprintf("The request from %s was already %s.", phoneNumber, request.Status)
+		case err != nil:
+			return fmt.Errorf("failed to decide contact request: %w", err)
+		case request.Status == domain.ContactRequestApproved:
+			reply = fmt.Sprintf("%s can now chat with the assistant.", phoneNumber)
+		default:
+			reply = fmt.Sprintf("%s will not be able to chat with the assistant.", phoneNumber)
+		}
+
+		if err == nil {
+			p.logger.WithContext(ctx).Info().
+				Str("tenant_id", tenant.ID).
+				Str("sender", phoneNumber).
+				Str("admin", result.From).
+				Str("status", request.Status).
+				Msg("access request decided")
+		}
+	}
+
+	callbackData := domain.EncodeCallbackData(tenant.ID, result.MessageID)
+	if _, err := p.infobipClient.SendText(ctx, tenant.WABANumber, result.From, reply, callbackData); err != nil {
+		return fmt.Errorf("failed to confirm contact request decision via Infobip: %w", err)
+	}
+
+	return nil
+}
+
+// parseRequestDecision returns the sender and decision of a tap on the
+// approve or deny button of an access request
+func parseRequestDecision(message *domain.InfobipIncomingMessage) (string, bool, bool) {
+	if !message.IsInteractiveReply() || !strings.HasPrefix(message.ID, contactRequestReplyPrefix) {
+		return "", false, false
+	}
+
+	action, phoneNumber, found := strings.Cut(strings.TrimPrefix(message.ID, contactRequestReplyPrefix), ":")
+	if !found || phoneNumber == "" {
+		return "", false, false
+	}
+
+	switch action {
+	case "approve":
+		return phoneNumber, true, true
+	case "deny":
+		return phoneNumber, false, true
+	default:
+		return "", false, false
+	}
+}
+
+// requestPreview returns the part of a message quoted to admins
+func requestPreview(message *domain.InfobipIncomingMessage) string {
+	text := message.Text.Text
+	if text == "" {
+		text = fmt.Sprintf("[%s message]", strings.ToLower(message.Type))
+	}
+
+	if utf8.RuneCountInString(text) > maxRequestPreview {
+		text = string([]rune(text)[:maxRequestPreview]) + "…"
+	}
+	return text
+}
@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/allowlist"
+	"personal-assistant/internal/domain"
+)
+
+// MockRepository implements the subset of domain.Repository used to look up senders
+type MockRepository struct {
+	domain.Repository
+	mock.Mock
+}
+
+func (m *MockRepository) GetAllowedContact(ctx context.Context, tenantID, phoneNumber string) (*domain.AllowedContact, error) {
+	args := m.Called(ctx, tenantID, phoneNumber)
+	contact, _ := args.Get(0).(*domain.AllowedContact)
+	return contact, args.Error(1)
+}
+
+func (m *MockRepository) GetSystemConfig(ctx context.Context, key string) (*domain.SystemConfig, error) {
+	args := m.Called(ctx, key)
+	config, _ := args.Get(0).(*domain.SystemConfig)
+	return config, args.Error(1)
+}
+
+func TestSenderContact(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns the allowed contact", func(t *testing.T) {
+		repo := &MockRepository{}
+		allowed := &domain.AllowedContact{PhoneNumber: "5511999999999", Permissions: []string{domain.PermissionAdmin}, Enabled: true}
+		repo.On("GetAllowedContact", ctx, "tenant-a", "5511999999999").Return(allowed, nil)
+
+		contact, err := senderContact(ctx, repo, &domain.Tenant{ID: "tenant-a"}, "5511999999999")
+		require.NoError(t, err)
+		assert.Equal(t, allowed, contact)
+		repo.AssertNotCalled(t, "GetSystemConfig", mock.Anything, mock.Anything)
+	})
+
+	t.Run("gives senders outside the allowlist the default permissions", func(t *testing.T) {
+		repo := &MockRepository{}
+		repo.On("GetAllowedContact", ctx, "tenant-a", "5511999999999").Return(nil, nil)
+		repo.On("GetSystemConfig", ctx, mock.Anything).Return(nil, nil)
+
+		tenant := &domain.Tenant{
+			ID: "tenant-a",
+			Config: map[string]interface{}{
+				allowlist.RequireAllowlistKey:   false,
+				allowlist.DefaultPermissionsKey: []interface{}{domain.PermissionChat, domain.PermissionSchedule},
+			},
+		}
+
+		contact, err := senderContact(ctx, repo, tenant, "5511999999999")
+		require.NoError(t, err)
+		assert.Equal(t, "5511999999999", contact.PhoneNumber)
+		assert.Equal(t, []string{domain.PermissionChat, domain.PermissionSchedule}, contact.Permissions)
+	})
+}
@@ -0,0 +1,161 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"personal-assistant/internal/domain"
+)
+
+// statusReportGrace is how long after a message was sent a report for it may
+// arrive before the outbound message row is stored. Within it the report is
+// redelivered by Infobip; later reports for unknown messages are dropped.
+const statusReportGrace = 2 * time.Minute
+
+// ProcessStatus records delivery and seen reports of outbound messages. It
+// returns domain.ErrMessageNotStored when a recent report could not be matched
+// to its message yet, so the batch can be redelivered; reports already
+// recorded are ignored on redelivery.
+func (p *MessageProcessor) ProcessStatus(ctx context.Context, webhook *domain.InfobipStatusWebhook) error {
+	if len(webhook.Results) == 0 {
+		return fmt.Errorf("no results in status webhook")
+	}
+
+	notStored := 0
+	for i := range webhook.Results {
+		if err := p.processStatusReport(ctx, &webhook.Results[i]); err != nil {
+			if errors.Is(err, domain.ErrMessageNotStored) {
+				notStored++
+				continue
+			}
+			p.logger.WithContext(ctx).Error().
+				Err(err).
+				Str("message_id", webhook.Results[i].MessageID).
+				Msg("failed to process status report")
+			// Continue processing other reports
+			continue
+		}
+	}
+
+	if notStored > 0 {
+		return fmt.Errorf("%w: %d of %d reports", domain.ErrMessageNotStored, notStored, len(webhook.Results))
+	}
+
+	return nil
+}
+
+// processStatusReport appends a single report to the timeline of the message it belongs to
+func (p *MessageProcessor) processStatusReport(ctx context.Context, report *domain.InfobipStatusReport) error {
+	logger := p.logger.WithContext(ctx)
+
+	status := report.MessageStatus()
+	if status == "" || report.MessageID == "" {
+		logger.Debug().
+			Str("message_id", report.MessageID).
+			Msg("skipping status report without a known status")
+		return nil
+	}
+
+	tenant, err := p.statusReportTenant(report)
+	if err != nil {
+		return err
+	}
+
+	repo, err := p.tenantManager.GetRepository(tenant.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get repository for tenant %s: %w", tenant.ID, err)
+	}
+
+	message, err := repo.GetMessageByID(ctx, tenant.ID, report.MessageID)
+	if err != nil {
+		return fmt.Errorf("failed to get message: %w", err)
+	}
+	if message == nil {
+		// Reports can arrive before the outbound message row is stored
+		if sentAt := report.SentAt; sentAt != nil && time.Since(sentAt.Time) < statusReportGrace {
+			logger.Debug().
+				Str("tenant_id", tenant.ID).
+				Str("message_id", report.MessageID).
+				Str("status", status).
+				Msg("status report arrived before its message was stored, asking for redelivery")
+			return domain.ErrMessageNotStored
+		}
+
+		logger.Warn().
+			Str("tenant_id", tenant.ID).
+			Str("message_id", report.MessageID).
+			Str("status", status).
+			Msg("status report for unknown message")
+		return nil
+	}
+
+	event := &domain.MessageStatusEvent{
+		TenantID:   tenant.ID,
+		MessageID:  message.ID,
+		ExternalID: report.MessageID,
+		Status:     status,
+		OccurredAt: report.OccurredAt(),
+		Payload:    reportPayload(report),
+	}
+	if report.Status != nil {
+		event.Detail = report.Status.Name
+	}
+	if report.Error != nil && report.Error.ID != 0 {
+		event.ErrorCode = report.Error.ID
+		event.ErrorMessage = report.Error.Description
+		if event.ErrorMessage == "" {
+			event.ErrorMessage = report.Error.Name
+		}
+	}
+
+	if err := repo.RecordMessageStatus(ctx, event); err != nil {
+		return fmt.Errorf("failed to record message status: %w", err)
+	}
+
+	logger.Debug().
+		Str("tenant_id", tenant.ID).
+		Str("message_id", report.MessageID).
+		Str("status", status).
+		Msg("message status updated")
+
+	return nil
+}
+
+// statusReportTenant resolves the tenant that sent the reported message, from
+// the callback data attached when sending or from the sender number
+func (p *MessageProcessor) statusReportTenant(report *domain.InfobipStatusReport) (*domain.Tenant, error) {
+	if tenantID, _ := domain.ParseCallbackData(report.CallbackData); tenantID != "" {
+		tenant, err := p.tenantManager.GetTenantByID(tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tenant %s: %w", tenantID, err)
+		}
+		return tenant, nil
+	}
+
+	if sender := report.SenderNumber(); sender != "" {
+		tenant, err := p.tenantManager.GetTenant(sender)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tenant for WABA number %s: %w", sender, err)
+		}
+		return tenant, nil
+	}
+
+	return nil, fmt.Errorf("cannot resolve tenant of status report %s", report.MessageID)
+}
+
+// reportPayload converts a report into the raw payload stored with the event
+func reportPayload(report *domain.InfobipStatusReport) map[string]interface{} {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil
+	}
+	return payload
+}
@@ -0,0 +1,75 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"personal-assistant/internal/domain"
+)
+
+// transcribeAudio converts a downloaded voice note into text using the
+// tenant's transcriber. It returns the transcript and the message metadata
+// describing it, or an empty string when the audio could not be transcribed.
+func (p *MessageProcessor) transcribeAudio(ctx context.Context, tenantID string, media *domain.Media) (string, map[string]interface{}) {
+	if media.Type != "audio" || len(media.Data) == 0 {
+		return "", nil
+	}
+
+	logger := p.logger.WithContext(ctx).WithTenant(tenantID)
+
+	transcription, provider, err := p.transcribe(ctx, tenantID, media)
+	if err != nil {
+		logger.Warn().
+			Err(err).
+			Str("media_id", media.ID.String()).
+			Msg("failed to transcribe audio, continuing with placeholder")
+		media.Metadata["transcription_error"] = err.Error()
+		return "", nil
+	}
+
+	media.Metadata["transcript"] = transcription.Text
+	media.Metadata["transcription_provider"] = provider
+
+	metadata := map[string]interface{}{
+		"audio":                  true,
+		"transcribed":            true,
+		"transcription_provider": provider,
+	}
+	if transcription.Language != "" {
+		metadata["transcription_language"] = transcription.Language
+	}
+	if transcription.Duration > 0 {
+		metadata["audio_duration"] = transcription.Duration
+	}
+
+	logger.Debug().
+		Str("media_id", media.ID.String()).
+		Str("provider", provider).
+		Int("transcript_length", len(transcription.Text)).
+		Msg("audio transcribed")
+
+	return transcription.Text, metadata
+}
+
+// transcribe sends the audio to the tenant's transcriber
+func (p *MessageProcessor) transcribe(ctx context.Context, tenantID string, media *domain.Media) (*domain.Transcription, string, error) {
+	transcriber, err := p.tenantManager.GetTranscriber(tenantID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	transcription, err := transcriber.Transcribe(ctx, &domain.TranscriptionRequest{
+		Audio:    media.Data,
+		FileName: media.FileName,
+		MimeType: media.MimeType,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if transcription.Text == "" {
+		return nil, "", fmt.Errorf("transcript is empty")
+	}
+
+	return transcription, transcriber.Name(), nil
+}
@@ -0,0 +1,249 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"personal-assistant/internal/domain"
+)
+
+// MemoryQueue implements the JobQueue interface in process memory. Jobs are
+// lost on restart, so it is meant for tests and single-instance development.
+type MemoryQueue struct {
+	jobs       map[uuid.UUID]*domain.InboundJob
+	messageIDs map[string]uuid.UUID
+
+	mutex sync.Mutex
+}
+
+// NewMemoryQueue creates a new in-memory job queue
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{
+		jobs:       make(map[uuid.UUID]*domain.InboundJob),
+		messageIDs: make(map[string]uuid.UUID),
+	}
+}
+
+// Close is a no-op for the in-memory queue
+func (q *MemoryQueue) Close() error {
+	return nil
+}
+
+// Enqueue adds a job, ignoring results whose message ID is already queued
+func (q *MemoryQueue) Enqueue(ctx context.Context, job *domain.InboundJob) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	prepareJob(job)
+	if _, exists := q.messageIDs[job.MessageID]; exists {
+		return nil
+	}
+
+	stored := *job
+	q.jobs[job.ID] = &stored
+	q.messageIDs[job.MessageID] = job.ID
+
+	return nil
+}
+
+// Claim leases up to limit runnable jobs, including jobs whose lease expired.
+// The jobs of a user are claimed one at a time in the order Infobip received them.
+func (q *MemoryQueue) Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.InboundJob, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	now := time.Now().UTC()
+	var runnable []*domain.InboundJob
+	for _, job := range q.jobs {
+		queued := job.Status == domain.JobStatusQueued && !job.RunAt.After(now)
+		expired := job.Status == domain.JobStatusProcessing && job.LockedUntil != nil && job.LockedUntil.Before(now)
+		if (queued || expired) && !q.blocked(job, now) {
+			runnable = append(runnable, job)
+		}
+	}
+
+	sort.Slice(runnable, func(i, j int) bool {
+		if !runnable[i].RunAt.Equal(runnable[j].RunAt) {
+			return runnable[i].RunAt.Before(runnable[j].RunAt)
+		}
+		return runnable[i].CreatedAt.Before(runnable[j].CreatedAt)
+	})
+	if len(runnable) > limit {
+		runnable = runnable[:limit]
+	}
+
+	lockedUntil := now.Add(lease)
+	claimed := make([]domain.InboundJob, 0, len(runnable))
+	for _, job := range runnable {
+		job.Status = domain.JobStatusProcessing
+		job.LockedUntil = &lockedUntil
+		job.Attempts++
+		job.UpdatedAt = now
+		claimed = append(claimed, *job)
+	}
+
+	return claimed, nil
+}
+
+// HasPending reports whether a later message of the same sender to the same
+// WABA number is queued
+func (q *MemoryQueue) HasPending(ctx context.Context, result *domain.InfobipWebhookResult) (bool, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	jobID, exists := q.messageIDs[result.MessageID]
+	if !exists {
+		return false, nil
+	}
+	current := q.jobs[jobID]
+
+	for _, job := range q.jobs {
+		if job.ID != current.ID && job.To == current.To && job.From == current.From &&
+			job.Status == domain.JobStatusQueued && !job.ReceivedAt.Before(current.ReceivedAt) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Complete marks a claimed job as done
+func (q *MemoryQueue) Complete(ctx context.Context, job *domain.InboundJob) error {
+	return q.release(job, func(job *domain.InboundJob, now time.Time) {
+		job.Status = domain.JobStatusDone
+		job.LockedUntil = nil
+		job.CompletedAt = &now
+		job.LastError = ""
+	})
+}
+
+// Retry releases a claimed job to run again at runAt
+func (q *MemoryQueue) Retry(ctx context.Context, job *domain.InboundJob, runAt time.Time, lastError string) error {
+	return q.release(job, func(job *domain.InboundJob, now time.Time) {
+		job.Status = domain.JobStatusQueued
+		job.LockedUntil = nil
+		job.RunAt = runAt
+		job.LastError = lastError
+	})
+}
+
+// DeadLetter marks a job as dead after its last failed attempt
+func (q *MemoryQueue) DeadLetter(ctx context.Context, job *domain.InboundJob, lastError string) error {
+	return q.release(job, func(job *domain.InboundJob, now time.Time) {
+		job.Status = domain.JobStatusDead
+		job.LockedUntil = nil
+		job.LastError = lastError
+	})
+}
+
+// Requeue moves a dead job back to the queue with its attempts reset
+func (q *MemoryQueue) Requeue(ctx context.Context, jobID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	job, exists := q.jobs[jobID]
+	if !exists || job.Status != domain.JobStatusDead {
+		return fmt.Errorf("job not found")
+	}
+
+	now := time.Now().UTC()
+	job.Status = domain.JobStatusQueued
+	job.Attempts = 0
+	job.RunAt = now
+	job.LockedUntil = nil
+	job.UpdatedAt = now
+
+	return nil
+}
+
+// GetJob retrieves a job by ID
+func (q *MemoryQueue) GetJob(ctx context.Context, jobID uuid.UUID) (*domain.InboundJob, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	job, exists := q.jobs[jobID]
+	if !exists {
+		return nil, nil
+	}
+
+	found := *job
+	return &found, nil
+}
+
+// ListJobs lists jobs newest first; an empty status returns jobs in any status
+func (q *MemoryQueue) ListJobs(ctx context.Context, status string, limit int) ([]domain.InboundJob, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var jobs []domain.InboundJob
+	for _, job := range q.jobs {
+		if status == "" || job.Status == status {
+			jobs = append(jobs, *job)
+		}
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+
+	return jobs, nil
+}
+
+// blocked reports whether an earlier job of the same user is queued or
+// processing, or another of its jobs is leased. The mutex must be held.
+func (q *MemoryQueue) blocked(job *domain.InboundJob, now time.Time) bool {
+	for _, other := range q.jobs {
+		if other.ID == job.ID || other.To != job.To || other.From != job.From {
+			continue
+		}
+		pending := other.Status == domain.JobStatusQueued || other.Status == domain.JobStatusProcessing
+		if pending && claimedBefore(other, job) {
+			return true
+		}
+		if other.Status == domain.JobStatusProcessing && other.LockedUntil != nil && !other.LockedUntil.Before(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// claimedBefore reports whether job a is due before job b of the same user
+func claimedBefore(a, b *domain.InboundJob) bool {
+	if !a.ReceivedAt.Equal(b.ReceivedAt) {
+		return a.ReceivedAt.Before(b.ReceivedAt)
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.MessageID < b.MessageID
+}
+
+// release applies a state transition to a claimed job while it still holds
+// the lease returned by Claim, returning domain.ErrJobLeaseLost otherwise
+func (q *MemoryQueue) release(claimed *domain.InboundJob, apply func(job *domain.InboundJob, now time.Time)) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	job, exists := q.jobs[claimed.ID]
+	if !exists {
+		return fmt.Errorf("job not found")
+	}
+	if job.Status != domain.JobStatusProcessing || job.LockedUntil == nil || claimed.LockedUntil == nil ||
+		!job.LockedUntil.Equal(*claimed.LockedUntil) {
+		return domain.ErrJobLeaseLost
+	}
+
+	now := time.Now().UTC()
+	apply(job, now)
+	job.UpdatedAt = now
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package queue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/queue"
+)
+
+func newJob(messageID string) *domain.InboundJob {
+	return &domain.InboundJob{
+		Result: domain.InfobipWebhookResult{
+			MessageID: messageID,
+			From:      "5511999999999",
+			To:        "5511888888888",
+			Message: domain.InfobipIncomingMessage{
+				Type: domain.InfobipMessageTypeText,
+				Text: domain.InfobipIncomingText{Text: "hello"},
+			},
+		},
+	}
+}
+
+func TestMemoryQueue(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ignores redelivered messages", func(t *testing.T) {
+		q := queue.NewMemoryQueue()
+		require.NoError(t, q.Enqueue(ctx, newJob("msg-1")))
+		require.NoError(t, q.Enqueue(ctx, newJob("msg-1")))
+
+		jobs, err := q.ListJobs(ctx, "", 10)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, "msg-1", jobs[0].MessageID)
+		assert.Equal(t, "5511888888888", jobs[0].To)
+		assert.Equal(t, domain.JobStatusQueued, jobs[0].Status)
+		assert.Equal(t, domain.DefaultJobMaxAttempts, jobs[0].MaxAttempts)
+	})
+
+	t.Run("claims each job once while leased", func(t *testing.T) {
+		q := queue.NewMemoryQueue()
+		require.NoError(t, q.Enqueue(ctx, newJob("msg-1")))
+
+		claimed, err := q.Claim(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, domain.JobStatusProcessing, claimed[0].Status)
+		assert.Equal(t, 1, claimed[0].Attempts)
+
+		again, err := q.Claim(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, again)
+	})
+
+	t.Run("reclaims jobs whose lease expired", func(t *testing.T) {
+		q := queue.NewMemoryQueue()
+		require.NoError(t, q.Enqueue(ctx, newJob("msg-1")))
+
+		_, err := q.Claim(ctx, 10, -time.Second)
+		require.NoError(t, err)
+
+		reclaimed, err := q.Claim(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, reclaimed, 1)
+		assert.Equal(t, 2, reclaimed[0].Attempts)
+	})
+
+	t.Run("keeps the outcome of the latest claim", func(t *testing.T) {
+		q := queue.NewMemoryQueue()
+		require.NoError(t, q.Enqueue(ctx, newJob("msg-1")))
+
+		stale, err := q.Claim(ctx, 1, -time.Second)
+		require.NoError(t, err)
+		require.Len(t, stale, 1)
+
+		reclaimed, err := q.Claim(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, reclaimed, 1)
+
+		assert.ErrorIs(t, q.Complete(ctx, &stale[0]), domain.ErrJobLeaseLost)
+		require.NoError(t, q.Retry(ctx, &reclaimed[0], time.Now().Add(time.Hour), "llm timeout"))
+		assert.ErrorIs(t, q.DeadLetter(ctx, &reclaimed[0], "late"), domain.ErrJobLeaseLost)
+
+		job, err := q.GetJob(ctx, stale[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusQueued, job.Status)
+		assert.Equal(t, "llm timeout", job.LastError)
+	})
+
+	t.Run("claims the jobs of a user in the order they were received", func(t *testing.T) {
+		q := queue.NewMemoryQueue()
+		received := time.Now().UTC().Add(-time.Minute)
+
+		second := newJob("msg-2")
+		second.Result.ReceivedAt = received.Add(time.Second)
+		require.NoError(t, q.Enqueue(ctx, second))
+
+		first := newJob("msg-1")
+		first.Result.ReceivedAt = received
+		require.NoError(t, q.Enqueue(ctx, first))
+
+		other := newJob("msg-3")
+		other.Result.From = "5511777777777"
+		require.NoError(t, q.Enqueue(ctx, other))
+
+		claimed, err := q.Claim(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, claimed, 2)
+		claimedIDs := []string{claimed[0].MessageID, claimed[1].MessageID}
+		assert.ElementsMatch(t, []string{"msg-1", "msg-3"}, claimedIDs)
+
+		// A later message waits for the earlier one and answers the coalescing check
+		none, err := q.Claim(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+
+		pending, err := q.HasPending(ctx, &first.Result)
+		require.NoError(t, err)
+		assert.True(t, pending)
+		pending, err = q.HasPending(ctx, &other.Result)
+		require.NoError(t, err)
+		assert.False(t, pending)
+
+		for i := range claimed {
+			if claimed[i].MessageID == "msg-1" {
+				require.NoError(t, q.Complete(ctx, &claimed[i]))
+			}
+		}
+
+		next, err := q.Claim(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, next, 1)
+		assert.Equal(t, "msg-2", next[0].MessageID)
+	})
+
+	t.Run("holds later jobs of a user while an earlier one waits to be retried", func(t *testing.T) {
+		q := queue.NewMemoryQueue()
+		require.NoError(t, q.Enqueue(ctx, newJob("msg-1")))
+
+		claimed, err := q.Claim(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, q.Retry(ctx, &claimed[0], time.Now().Add(time.Hour), "llm timeout"))
+		require.NoError(t, q.Enqueue(ctx, newJob("msg-2")))
+
+		none, err := q.Claim(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
+	t.Run("holds a late earlier job while a later one is leased", func(t *testing.T) {
+		q := queue.NewMemoryQueue()
+		later := newJob("msg-2")
+		later.Result.ReceivedAt = time.Now().UTC()
+		require.NoError(t, q.Enqueue(ctx, later))
+
+		claimed, err := q.Claim(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+
+		earlier := newJob("msg-1")
+		earlier.Result.ReceivedAt = later.Result.ReceivedAt.Add(-time.Second)
+		require.NoError(t, q.Enqueue(ctx, earlier))
+
+		none, err := q.Claim(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
+	t.Run("holds retried jobs until run time", func(t *testing.T) {
+		q := queue.NewMemoryQueue()
+		require.NoError(t, q.Enqueue(ctx, newJob("msg-1")))
+
+		claimed, err := q.Claim(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, q.Retry(ctx, &claimed[0], time.Now().Add(time.Hour), "llm timeout"))
+
+		none, err := q.Claim(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+
+		job, err := q.GetJob(ctx, claimed[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusQueued, job.Status)
+		assert.Equal(t, "llm timeout", job.LastError)
+	})
+
+	t.Run("requeues only dead jobs", func(t *testing.T) {
+		q := queue.NewMemoryQueue()
+		require.NoError(t, q.Enqueue(ctx, newJob("msg-1")))
+
+		claimed, err := q.Claim(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		assert.Error(t, q.Requeue(ctx, claimed[0].ID))
+
+		require.NoError(t, q.DeadLetter(ctx, &claimed[0], "tenant not found"))
+		dead, err := q.ListJobs(ctx, domain.JobStatusDead, 10)
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+
+		require.NoError(t, q.Requeue(ctx, claimed[0].ID))
+		job, err := q.GetJob(ctx, claimed[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusQueued, job.Status)
+		assert.Equal(t, 0, job.Attempts)
+	})
+}
@@ -0,0 +1,326 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+const jobColumns = `id, message_id, sender, recipient, payload, received_at, status, attempts, max_attempts,
+		COALESCE(last_error, ''), run_at, locked_until, completed_at, created_at, updated_at`
+
+// PostgresQueue implements the JobQueue interface on the inbound_jobs table.
+// Claiming uses row-level locks with SKIP LOCKED, so several server replicas
+// can run workers against the same queue.
+type PostgresQueue struct {
+	db     *pgxpool.Pool
+	logger *log.Logger
+}
+
+// NewPostgresQueue creates a new PostgreSQL job queue
+func NewPostgresQueue(db *pgxpool.Pool, logger *log.Logger) *PostgresQueue {
+	return &PostgresQueue{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Close closes the database connection
+func (q *PostgresQueue) Close() error {
+	q.db.Close()
+	return nil
+}
+
+// Enqueue adds a job, ignoring results whose message ID is already queued
+func (q *PostgresQueue) Enqueue(ctx context.Context, job *domain.InboundJob) error {
+	query := `
+		INSERT INTO inbound_jobs (id, message_id, sender, recipient, payload, received_at, status,
+			attempts, max_attempts, run_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (message_id) DO NOTHING
+	`
+
+	prepareJob(job)
+
+	payloadJSON, err := json.Marshal(job.Result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	result, err := q.db.Exec(ctx, query,
+		job.ID, job.MessageID, job.From, job.To, payloadJSON, job.ReceivedAt, job.Status,
+		job.Attempts, job.MaxAttempts, job.RunAt, job.CreatedAt, job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		q.logger.WithContext(ctx).Debug().
+			Str("message_id", job.MessageID).
+			Msg("job already queued")
+		return nil
+	}
+
+	q.logger.WithContext(ctx).Debug().
+		Str("job_id", job.ID.String()).
+		Str("message_id", job.MessageID).
+		Msg("job created")
+
+	return nil
+}
+
+// Claim atomically leases up to limit runnable jobs. Rows locked by another
+// replica are skipped, and a job whose lease has expired (e.g. the worker
+// crashed) becomes claimable again. The jobs of a user are claimed one at a
+// time in the order Infobip received them: a job waits while an earlier job
+// of the user is queued or processing, or another of its jobs is leased.
+// A replica claiming concurrently still sees the earlier job as queued, so
+// the order holds across replicas.
+func (q *PostgresQueue) Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.InboundJob, error) {
+	query := `
+		UPDATE inbound_jobs
+		SET status = 'processing', locked_until = $2, attempts = attempts + 1, updated_at = NOW()
+		WHERE id IN (
+			SELECT j.id FROM inbound_jobs j
+			WHERE ((j.status = 'queued' AND j.run_at <= NOW())
+				OR (j.status = 'processing' AND j.locked_until < NOW()))
+				AND NOT EXISTS (
+					SELECT 1 FROM inbound_jobs e
+					WHERE e.recipient = j.recipient
+						AND e.sender = j.sender
+						AND e.id <> j.id
+						AND ((e.status IN ('queued', 'processing')
+								AND (e.received_at, e.created_at, e.message_id) < (j.received_at, j.created_at, j.message_id))
+							OR (e.status = 'processing' AND e.locked_until >= NOW()))
+				)
+			ORDER BY j.run_at, j.created_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	lockedUntil := time.Now().UTC().Add(lease)
+	rows, err := q.db.Query(ctx, query, limit, lockedUntil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim jobs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanJobs(rows)
+}
+
+// HasPending reports whether a later message of the same sender to the same
+// WABA number is queued
+func (q *PostgresQueue) HasPending(ctx context.Context, result *domain.InfobipWebhookResult) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM inbound_jobs
+			WHERE recipient = $1 AND sender = $2 AND message_id <> $3 AND status = 'queued'
+				AND received_at >= (SELECT received_at FROM inbound_jobs WHERE message_id = $3)
+		)
+	`
+
+	var pending bool
+	if err := q.db.QueryRow(ctx, query, result.To, result.From, result.MessageID).Scan(&pending); err != nil {
+		return false, fmt.Errorf("failed to check pending jobs: %w", err)
+	}
+
+	return pending, nil
+}
+
+// Complete marks a claimed job as done
+func (q *PostgresQueue) Complete(ctx context.Context, job *domain.InboundJob) error {
+	query := `
+		UPDATE inbound_jobs
+		SET status = 'done', locked_until = NULL, completed_at = NOW(), last_error = NULL
+		WHERE id = $1 AND status = 'processing' AND locked_until = $2
+	`
+
+	return q.release(ctx, query, "complete", job)
+}
+
+// Retry releases a claimed job to run again at runAt
+func (q *PostgresQueue) Retry(ctx context.Context, job *domain.InboundJob, runAt time.Time, lastError string) error {
+	query := `
+		UPDATE inbound_jobs
+		SET status = 'queued', locked_until = NULL, run_at = $3, last_error = NULLIF($4, '')
+		WHERE id = $1 AND status = 'processing' AND locked_until = $2
+	`
+
+	return q.release(ctx, query, "retry", job, runAt, lastError)
+}
+
+// DeadLetter marks a job as dead after its last failed attempt
+func (q *PostgresQueue) DeadLetter(ctx context.Context, job *domain.InboundJob, lastError string) error {
+	query := `
+		UPDATE inbound_jobs
+		SET status = 'dead', locked_until = NULL, last_error = NULLIF($3, '')
+		WHERE id = $1 AND status = 'processing' AND locked_until = $2
+	`
+
+	return q.release(ctx, query, "dead-letter", job, lastError)
+}
+
+// Requeue moves a dead job back to the queue with its attempts reset
+func (q *PostgresQueue) Requeue(ctx context.Context, jobID uuid.UUID) error {
+	query := `
+		UPDATE inbound_jobs
+		SET status = 'queued', attempts = 0, run_at = NOW(), locked_until = NULL
+		WHERE id = $1 AND status = 'dead'
+	`
+
+	return q.update(ctx, query, "requeue", jobID)
+}
+
+// GetJob retrieves a job by ID
+func (q *PostgresQueue) GetJob(ctx context.Context, jobID uuid.UUID) (*domain.InboundJob, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM inbound_jobs
+		WHERE id = $1
+	`
+
+	job, err := scanJob(q.db.QueryRow(ctx, query, jobID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return job, nil
+}
+
+// ListJobs lists jobs newest first; an empty status returns jobs in any status
+func (q *PostgresQueue) ListJobs(ctx context.Context, status string, limit int) ([]domain.InboundJob, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM inbound_jobs
+		WHERE $1::text = '' OR status = $1::text
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := q.db.Query(ctx, query, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanJobs(rows)
+}
+
+// update runs a single-job state transition and reports missing jobs
+func (q *PostgresQueue) update(ctx context.Context, query, action string, jobID uuid.UUID, args ...interface{}) error {
+	result, err := q.db.Exec(ctx, query, append([]interface{}{jobID}, args...)...)
+	if err != nil {
+		return fmt.Errorf("failed to %s job: %w", action, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("job not found")
+	}
+
+	return nil
+}
+
+// release records the outcome of a claimed job while it still holds the
+// lease returned by Claim, returning domain.ErrJobLeaseLost otherwise
+func (q *PostgresQueue) release(ctx context.Context, query, action string, job *domain.InboundJob, args ...interface{}) error {
+	if job.LockedUntil == nil {
+		return domain.ErrJobLeaseLost
+	}
+
+	result, err := q.db.Exec(ctx, query, append([]interface{}{job.ID, *job.LockedUntil}, args...)...)
+	if err != nil {
+		return fmt.Errorf("failed to %s job: %w", action, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrJobLeaseLost
+	}
+
+	return nil
+}
+
+// scanJobs scans all job rows selected with jobColumns
+func scanJobs(rows pgx.Rows) ([]domain.InboundJob, error) {
+	var jobs []domain.InboundJob
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// scanJob scans a single job row selected with jobColumns
+func scanJob(row pgx.Row) (*domain.InboundJob, error) {
+	var job domain.InboundJob
+	var payloadJSON []byte
+
+	err := row.Scan(
+		&job.ID, &job.MessageID, &job.From, &job.To, &payloadJSON, &job.ReceivedAt, &job.Status,
+		&job.Attempts, &job.MaxAttempts, &job.LastError, &job.RunAt, &job.LockedUntil,
+		&job.CompletedAt, &job.CreatedAt, &job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(payloadJSON, &job.Result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	return &job, nil
+}
+
+// prepareJob fills in the defaults of a new job
+func prepareJob(job *domain.InboundJob) {
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	if job.MessageID == "" {
+		job.MessageID = job.Result.MessageID
+	}
+	if job.From == "" {
+		job.From = job.Result.From
+	}
+	if job.To == "" {
+		job.To = job.Result.To
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = domain.DefaultJobMaxAttempts
+	}
+
+	now := time.Now().UTC()
+	if job.ReceivedAt.IsZero() {
+		job.ReceivedAt = job.Result.ReceivedAt.UTC()
+	}
+	if job.ReceivedAt.IsZero() {
+		job.ReceivedAt = now
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = now
+	}
+	job.Status = domain.JobStatusQueued
+	job.Attempts = 0
+	job.CreatedAt = now
+	job.UpdatedAt = now
+}
@@ -0,0 +1,203 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+)
+
+// WorkerPool processes queued inbound jobs with a fixed number of workers.
+// Failed jobs are retried with exponential backoff and dead-lettered once
+// they run out of attempts.
+type WorkerPool struct {
+	queue     domain.JobQueue
+	processor domain.MessageProcessor
+	config    *config.QueueConfig
+	logger    *log.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWorkerPool creates a new worker pool
+func NewWorkerPool(queue domain.JobQueue, processor domain.MessageProcessor, cfg *config.QueueConfig, logger *log.Logger) *WorkerPool {
+	return &WorkerPool{
+		queue:     queue,
+		processor: processor,
+		config:    cfg,
+		logger:    logger.WithComponent("job_worker"),
+	}
+}
+
+// Start launches the workers in background goroutines
+func (w *WorkerPool) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	workers := w.config.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	for i := 0; i < workers; i++ {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			w.run(ctx)
+		}()
+	}
+
+	w.logger.Info().
+		Int("workers", workers).
+		Dur("poll_interval", w.config.PollInterval).
+		Msg("job workers started")
+}
+
+// Stop stops the workers and waits for in-flight jobs to finish
+func (w *WorkerPool) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+
+	w.logger.Info().Msg("job workers stopped")
+}
+
+// run processes jobs until the context is cancelled, polling while the queue is empty
+func (w *WorkerPool) run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain the queue before waiting for the next poll
+		for ctx.Err() == nil && w.ProcessNext(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessNext claims and processes a single job, reporting whether one was found
+func (w *WorkerPool) ProcessNext(ctx context.Context) bool {
+	jobs, err := w.queue.Claim(ctx, 1, w.config.LeaseTimeout)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Error().Err(err).Msg("failed to claim jobs")
+		}
+		return false
+	}
+	if len(jobs) == 0 {
+		return false
+	}
+
+	// A claimed job runs to completion even when the pool is stopping
+	w.processJob(context.WithoutCancel(ctx), &jobs[0])
+	return true
+}
+
+// processJob hands a claimed job to the message processor and records the outcome
+func (w *WorkerPool) processJob(ctx context.Context, job *domain.InboundJob) {
+	logger := w.logger.WithContext(ctx)
+
+	// Jobs claimed again after their lease expired may have crashed the previous worker
+	if job.Attempts > job.MaxAttempts {
+		w.deadLetter(ctx, job, fmt.Errorf("lease expired after %d attempts", job.MaxAttempts))
+		return
+	}
+
+	start := time.Now()
+	err := w.processor.ProcessResult(ctx, &job.Result)
+	duration := time.Since(start)
+
+	if err == nil {
+		if err := w.queue.Complete(ctx, job); err != nil {
+			w.logReleaseError(ctx, job, err, "failed to complete job")
+			return
+		}
+
+		logger.Info().
+			Str("job_id", job.ID.String()).
+			Str("message_id", job.MessageID).
+			Int("attempt", job.Attempts).
+			Dur("duration", duration).
+			Msg("job processed successfully")
+		return
+	}
+
+	if job.Attempts >= job.MaxAttempts {
+		w.deadLetter(ctx, job, err)
+		return
+	}
+
+	runAt := time.Now().UTC().Add(w.retryDelay(job.Attempts))
+	if retryErr := w.queue.Retry(ctx, job, runAt, err.Error()); retryErr != nil {
+		w.logReleaseError(ctx, job, retryErr, "failed to reschedule job")
+		return
+	}
+
+	logger.Warn().
+		Err(err).
+		Str("job_id", job.ID.String()).
+		Str("message_id", job.MessageID).
+		Int("attempt", job.Attempts).
+		Time("retry_at", runAt).
+		Msg("job processing failed, retrying")
+}
+
+// deadLetter moves a job that ran out of attempts to the dead state
+func (w *WorkerPool) deadLetter(ctx context.Context, job *domain.InboundJob, cause error) {
+	logger := w.logger.WithContext(ctx)
+
+	if err := w.queue.DeadLetter(ctx, job, cause.Error()); err != nil {
+		w.logReleaseError(ctx, job, err, "failed to dead-letter job")
+		return
+	}
+
+	logger.Error().
+		Err(cause).
+		Str("job_id", job.ID.String()).
+		Str("message_id", job.MessageID).
+		Int("attempts", job.Attempts).
+		Msg("job moved to dead letter")
+}
+
+// logReleaseError logs a failure to record a job outcome. A lost lease means
+// another worker claimed the job again and owns its outcome now.
+func (w *WorkerPool) logReleaseError(ctx context.Context, job *domain.InboundJob, err error, msg string) {
+	logger := w.logger.WithContext(ctx)
+
+	if errors.Is(err, domain.ErrJobLeaseLost) {
+		logger.Warn().
+			Str("job_id", job.ID.String()).
+			Int("attempt", job.Attempts).
+			Msg("job lease expired before its outcome was recorded")
+		return
+	}
+
+	logger.Error().Err(err).Str("job_id", job.ID.String()).Msg(msg)
+}
+
+// retryDelay returns the exponential backoff delay after the given attempt
+func (w *WorkerPool) retryDelay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	if attempt > 16 {
+		attempt = 16
+	}
+
+	delay := w.config.RetryBackoff * time.Duration(1<<uint(attempt-1))
+	if w.config.MaxBackoff > 0 && delay > w.config.MaxBackoff {
+		delay = w.config.MaxBackoff
+	}
+	return delay
+}
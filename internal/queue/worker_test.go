@@ -0,0 +1,151 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+	"personal-assistant/internal/processor"
+	"personal-assistant/internal/queue"
+)
+
+// MockMessageProcessor is a mock implementation of domain.MessageProcessor
+type MockMessageProcessor struct {
+	domain.MessageProcessor
+	mock.Mock
+}
+
+func (m *MockMessageProcessor) ProcessResult(ctx context.Context, result *domain.InfobipWebhookResult) error {
+	args := m.Called(ctx, result)
+	return args.Error(0)
+}
+
+// MockTenantManager implements the subset of domain.TenantManager used by the processor
+type MockTenantManager struct {
+	domain.TenantManager
+	mock.Mock
+}
+
+func (m *MockTenantManager) GetTenant(wabaNumber string) (*domain.Tenant, error) {
+	args := m.Called(wabaNumber)
+	tenant, _ := args.Get(0).(*domain.Tenant)
+	return tenant, args.Error(1)
+}
+
+func TestWorkerPool(t *testing.T) {
+	ctx := context.Background()
+	logger := log.Init("error")
+	cfg := &config.QueueConfig{
+		Workers:      1,
+		PollInterval: 10 * time.Millisecond,
+		LeaseTimeout: time.Minute,
+		RetryBackoff: time.Second,
+		MaxBackoff:   time.Minute,
+	}
+
+	t.Run("completes processed jobs", func(t *testing.T) {
+		q := queue.NewMemoryQueue()
+		processor := &MockMessageProcessor{}
+		require.NoError(t, q.Enqueue(ctx, newJob("msg-1")))
+
+		processor.On("ProcessResult", mock.Anything, mock.MatchedBy(func(result *domain.InfobipWebhookResult) bool {
+			return result.MessageID == "msg-1"
+		})).Return(nil)
+
+		pool := queue.NewWorkerPool(q, processor, cfg, logger)
+		assert.True(t, pool.ProcessNext(ctx))
+		assert.False(t, pool.ProcessNext(ctx))
+
+		done, err := q.ListJobs(ctx, domain.JobStatusDone, 10)
+		require.NoError(t, err)
+		require.Len(t, done, 1)
+		assert.NotNil(t, done[0].CompletedAt)
+		processor.AssertExpectations(t)
+	})
+
+	t.Run("retries failed jobs with backoff", func(t *testing.T) {
+		q := queue.NewMemoryQueue()
+		processor := &MockMessageProcessor{}
+		job := newJob("msg-1")
+		require.NoError(t, q.Enqueue(ctx, job))
+
+		processor.On("ProcessResult", mock.Anything, mock.Anything).Return(errors.New("llm timeout"))
+
+		pool := queue.NewWorkerPool(q, processor, cfg, logger)
+		before := time.Now()
+		assert.True(t, pool.ProcessNext(ctx))
+
+		retried, err := q.GetJob(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusQueued, retried.Status)
+		assert.Equal(t, 1, retried.Attempts)
+		assert.Equal(t, "llm timeout", retried.LastError)
+		assert.WithinDuration(t, before.Add(time.Second), retried.RunAt, 500*time.Millisecond)
+	})
+
+	t.Run("dead-letters jobs after max attempts", func(t *testing.T) {
+		q := queue.NewMemoryQueue()
+		processor := &MockMessageProcessor{}
+		job := newJob("msg-1")
+		job.MaxAttempts = 1
+		require.NoError(t, q.Enqueue(ctx, job))
+
+		processor.On("ProcessResult", mock.Anything, mock.Anything).Return(errors.New("tenant not found"))
+
+		pool := queue.NewWorkerPool(q, processor, cfg, logger)
+		assert.True(t, pool.ProcessNext(ctx))
+
+		dead, err := q.GetJob(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusDead, dead.Status)
+		assert.Equal(t, "tenant not found", dead.LastError)
+	})
+
+	t.Run("retries jobs the message processor fails", func(t *testing.T) {
+		q := queue.NewMemoryQueue()
+		tenantManager := &MockTenantManager{}
+		job := newJob("msg-1")
+		require.NoError(t, q.Enqueue(ctx, job))
+
+		tenantManager.On("GetTenant", "5511888888888").Return(nil, errors.New("database unavailable"))
+
+		messageProcessor := processor.NewMessageProcessor(tenantManager, nil, &config.Config{}, logger)
+		pool := queue.NewWorkerPool(q, messageProcessor, cfg, logger)
+		assert.True(t, pool.ProcessNext(ctx))
+
+		retried, err := q.GetJob(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusQueued, retried.Status)
+		assert.Contains(t, retried.LastError, "database unavailable")
+		tenantManager.AssertExpectations(t)
+	})
+
+	t.Run("processes queued jobs in the background", func(t *testing.T) {
+		q := queue.NewMemoryQueue()
+		processor := &MockMessageProcessor{}
+		processed := make(chan struct{})
+		processor.On("ProcessResult", mock.Anything, mock.Anything).
+			Run(func(mock.Arguments) { close(processed) }).
+			Return(nil)
+
+		pool := queue.NewWorkerPool(q, processor, cfg, logger)
+		pool.Start(ctx)
+		defer pool.Stop()
+
+		require.NoError(t, q.Enqueue(ctx, newJob("msg-1")))
+
+		select {
+		case <-processed:
+		case <-time.After(time.Second):
+			t.Fatal("job was not processed")
+		}
+	})
+}
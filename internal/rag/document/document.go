@@ -0,0 +1,79 @@
+package document
+
+import (
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"personal-assistant/internal/domain"
+)
+
+// Supported document formats
+const (
+	FormatPDF      = "pdf"
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+)
+
+// DetectFormat returns the document format for a MIME type or file name, or
+// an empty string when the document cannot be parsed
+func DetectFormat(mimeType, fileName string) string {
+	if mediaType, _, err := mime.ParseMediaType(mimeType); err == nil {
+		switch mediaType {
+		case "application/pdf":
+			return FormatPDF
+		case "text/markdown", "text/x-markdown":
+			return FormatMarkdown
+		case "text/plain":
+			if isMarkdownFile(fileName) {
+				return FormatMarkdown
+			}
+			return FormatText
+		}
+	}
+
+	// Infobip often reports attachments as application/octet-stream
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".pdf":
+		return FormatPDF
+	case ".md", ".markdown":
+		return FormatMarkdown
+	case ".txt", ".text":
+		return FormatText
+	}
+
+	return ""
+}
+
+// Parse extracts the text of a document page by page
+func Parse(data []byte, mimeType, fileName string) ([]domain.DocumentPage, error) {
+	switch DetectFormat(mimeType, fileName) {
+	case FormatPDF:
+		return parsePDF(data)
+	case FormatText, FormatMarkdown:
+		return parseText(data)
+	default:
+		return nil, fmt.Errorf("unsupported document type: %s", mimeType)
+	}
+}
+
+// parseText returns plain text and Markdown documents as a single page
+func parseText(data []byte) ([]domain.DocumentPage, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("document is not valid UTF-8 text")
+	}
+
+	text := strings.TrimSpace(strings.TrimPrefix(string(data), "\ufeff"))
+	if text == "" {
+		return nil, fmt.Errorf("document is empty")
+	}
+
+	return []domain.DocumentPage{{Number: 0, Text: text}}, nil
+}
+
+func isMarkdownFile(fileName string) bool {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	return ext == ".md" || ext == ".markdown"
+}
@@ -0,0 +1,159 @@
+package document_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/rag/document"
+)
+
+// buildPDF assembles a minimal PDF whose pages draw the given content streams
+func buildPDF(t testing.TB, compress bool, contents ...string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	buf.WriteString("1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+
+	kids := ""
+	for i := range contents {
+		kids += fmt.Sprintf("%d 0 R ", 3+i*2)
+	}
+	buf.WriteString(fmt.Sprintf("2 0 obj\n<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", kids, len(contents)))
+
+	for i, content := range contents {
+		pageNum := 3 + i*2
+		buf.WriteString(fmt.Sprintf("%d 0 obj\n<< /Type /Page /Parent 2 0 R /Contents %d 0 R >>\nendobj\n", pageNum, pageNum+1))
+
+		data := []byte(content)
+		filter := ""
+		if compress {
+			var compressed bytes.Buffer
+			w := zlib.NewWriter(&compressed)
+			_, err := w.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			data = compressed.Bytes()
+			filter = " /Filter /FlateDecode"
+		}
+		buf.WriteString(fmt.Sprintf("%d 0 obj\n<< /Length %d%s >>\nstream\n", pageNum+1, len(data), filter))
+		buf.Write(data)
+		buf.WriteString("\nendstream\nendobj\n")
+	}
+
+	buf.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return buf.Bytes()
+}
+
+func TestParsePDF(t *testing.T) {
+	t.Run("extracts text per page", func(t *testing.T) {
+		pdf := buildPDF(t, false,
+			"BT /F1 12 Tf 72 720 Td (Invoice #42) Tj 0 -14 Td (Total: \\(USD\\) 120.00) Tj ET",
+			"BT /F1 12 Tf 72 720 Td [(Pay)-300(by)-300(March 31)] TJ ET",
+		)
+
+		pages, err := document.Parse(pdf, "application/pdf", "invoice.pdf")
+		require.NoError(t, err)
+		require.Len(t, pages, 2)
+
+		assert.Equal(t, 1, pages[0].Number)
+		assert.Equal(t, "Invoice #42\nTotal: (USD) 120.00", pages[0].Text)
+		assert.Equal(t, 2, pages[1].Number)
+		assert.Equal(t, "Pay by March 31", pages[1].Text)
+	})
+
+	t.Run("decodes compressed streams and hex strings", func(t *testing.T) {
+		pdf := buildPDF(t, true, "BT 72 720 Td <48656c6c6f> Tj ( world) Tj ET")
+
+		pages, err := document.Parse(pdf, "application/octet-stream", "notes.PDF")
+		require.NoError(t, err)
+		require.Len(t, pages, 1)
+		assert.Equal(t, "Hello world", pages[0].Text)
+	})
+
+	t.Run("skips object stream entries outside the stream", func(t *testing.T) {
+		pdf := buildPDF(t, false, "BT 72 720 Td (Hello) Tj ET")
+		for _, header := range []string{"5 -1000", "5 9223372036854775807"} {
+			content := header + "\n<< /Type /Page >>"
+			malformed := append(bytes.Clone(pdf), fmt.Sprintf(
+				"9 0 obj\n<< /Type /ObjStm /N 1 /First %d /Length %d >>\nstream\n%s\nendstream\nendobj\n",
+				len(header)+1, len(content), content)...)
+
+			pages, err := document.Parse(malformed, "application/pdf", "objstm.pdf")
+			require.NoError(t, err)
+			require.Len(t, pages, 1)
+			assert.Equal(t, "Hello", pages[0].Text)
+		}
+	})
+
+	t.Run("rejects PDFs decoding to more than the size budget", func(t *testing.T) {
+		// Every page draws the same 10MB content stream
+		var buf bytes.Buffer
+		buf.WriteString("%PDF-1.4\n")
+		buf.WriteString("1 0 obj\n<< /Type /Pages /Kids [")
+		for i := 0; i < 20; i++ {
+			buf.WriteString(fmt.Sprintf("%d 0 R ", 3+i))
+		}
+		buf.WriteString("] >>\nendobj\n")
+
+		var compressed bytes.Buffer
+		w := zlib.NewWriter(&compressed)
+		_, err := w.Write(append([]byte("BT (Hello) Tj ET\n"), bytes.Repeat([]byte(" "), 10<<20)...))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		buf.WriteString(fmt.Sprintf("2 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", compressed.Len()))
+		buf.Write(compressed.Bytes())
+		buf.WriteString("\nendstream\nendobj\n")
+
+		for i := 0; i < 20; i++ {
+			buf.WriteString(fmt.Sprintf("%d 0 obj\n<< /Type /Page /Parent 1 0 R /Contents 2 0 R >>\nendobj\n", 3+i))
+		}
+
+		_, err = document.Parse(buf.Bytes(), "application/pdf", "bomb.pdf")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "once decoded")
+	})
+
+	t.Run("rejects PDFs without text", func(t *testing.T) {
+		pdf := buildPDF(t, false, "q 100 0 0 100 0 0 cm /Im1 Do Q")
+
+		_, err := document.Parse(pdf, "application/pdf", "scan.pdf")
+		assert.Error(t, err)
+	})
+}
+
+// FuzzParsePDF checks that the PDF parser, which runs on documents sent by
+// users, never panics
+func FuzzParsePDF(f *testing.F) {
+	f.Add(buildPDF(f, false, "BT /F1 12 Tf 72 720 Td (Invoice #42) Tj 0 -14 Td [(Pay)-300(by)] TJ ET"))
+	f.Add(buildPDF(f, true, "BT 72 720 Td <48656c6c6f> Tj ( world) Tj ET"))
+	f.Add([]byte("%PDF-1.4\n1 0 obj\n<< /Type /ObjStm /N 1 /First 8 /Length 26 >>\nstream\n5 -1000\n<< /Type /Page >>\nendstream\nendobj\n"))
+	f.Add([]byte("%PDF-1.4\n1 0 obj\n<< /Length 999 >>\nstream\n(unterminated\n2 0 obj\n3 0 obj stream"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		document.Parse(data, "application/pdf", "fuzz.pdf")
+	})
+}
+
+func TestParseText(t *testing.T) {
+	pages, err := document.Parse([]byte("\ufeff# Shopping\n\n- milk\n"), "text/plain", "list.md")
+	require.NoError(t, err)
+	require.Len(t, pages, 1)
+	assert.Equal(t, 0, pages[0].Number)
+	assert.Equal(t, "# Shopping\n\n- milk", pages[0].Text)
+
+	_, err = document.Parse([]byte("   \n"), "text/plain", "empty.txt")
+	assert.Error(t, err)
+}
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, document.FormatPDF, document.DetectFormat("application/pdf", ""))
+	assert.Equal(t, document.FormatMarkdown, document.DetectFormat("text/plain; charset=utf-8", "README.md"))
+	assert.Equal(t, document.FormatText, document.DetectFormat("", "notes.txt"))
+	assert.Equal(t, "", document.DetectFormat("image/jpeg", "photo.jpg"))
+}
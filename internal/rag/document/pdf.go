@@ -0,0 +1,653 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"personal-assistant/internal/domain"
+)
+
+// The PDF support below is a small text extractor rather than a full PDF
+// implementation. It understands uncompressed and Flate-compressed content
+// streams, compressed object streams and the page tree, and decodes text
+// drawn with simple (single-byte or UTF-16) font encodings. Scanned PDFs and
+// fonts that rely on custom CMaps produce little or no text.
+
+const (
+	// maxPDFStreamSize caps the decoded size of a single stream
+	maxPDFStreamSize = 50 << 20
+	// maxPDFDecodedSize caps the decoded size of all streams of a file, which
+	// bounds the work done for content streams shared by many pages
+	maxPDFDecodedSize = 100 << 20
+)
+
+var (
+	pdfObjectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	pdfEndObject    = []byte("endobj")
+	pdfReference    = regexp.MustCompile(`(\d+)\s+\d+\s+R\b`)
+	pdfTypePage     = regexp.MustCompile(`/Type\s*/Page\b`)
+	pdfTypePages    = regexp.MustCompile(`/Type\s*/Pages\b`)
+	pdfTypeObjStm   = regexp.MustCompile(`/Type\s*/ObjStm\b`)
+	pdfLength       = regexp.MustCompile(`/Length\s+(\d+)(\s+\d+\s+R)?`)
+	pdfCount        = regexp.MustCompile(`/N\s+(\d+)`)
+	pdfFirst        = regexp.MustCompile(`/First\s+(\d+)`)
+)
+
+// errPDFTooLarge is returned once the decoded streams exceed maxPDFDecodedSize
+var errPDFTooLarge = fmt.Errorf("PDF content exceeds %d bytes once decoded", maxPDFDecodedSize)
+
+// pdfObject is an indirect object with its dictionary and raw stream data
+type pdfObject struct {
+	body   string
+	stream []byte
+}
+
+// pdfFile holds the indirect objects of a parsed PDF
+type pdfFile struct {
+	objects map[int]*pdfObject
+	// decoded counts the bytes returned by decodeStream, up to maxPDFDecodedSize
+	decoded int
+}
+
+// parsePDF extracts the text of each page of a PDF
+func parsePDF(data []byte) ([]domain.DocumentPage, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\r\n\t "), []byte("%PDF")) {
+		return nil, fmt.Errorf("not a PDF document")
+	}
+	if bytes.Contains(data, []byte("/Encrypt")) {
+		return nil, fmt.Errorf("encrypted PDF documents are not supported")
+	}
+
+	file := &pdfFile{objects: make(map[int]*pdfObject)}
+	file.readObjects(data)
+	file.expandObjectStreams()
+
+	var pages []domain.DocumentPage
+	hasText := false
+	for i, pageNum := range file.pageOrder() {
+		text := extractPDFText(file.pageContent(pageNum))
+		if text != "" {
+			hasText = true
+		}
+		pages = append(pages, domain.DocumentPage{Number: i + 1, Text: text})
+	}
+
+	if file.decoded > maxPDFDecodedSize {
+		return nil, errPDFTooLarge
+	}
+
+	if !hasText {
+		return nil, fmt.Errorf("no extractable text found in PDF")
+	}
+
+	return pages, nil
+}
+
+// readObjects scans the file for indirect objects. Headers found inside an
+// object already read, e.g. in its stream data, are skipped, which keeps the
+// scan linear on files with many headers and missing endobj markers.
+func (f *pdfFile) readObjects(data []byte) {
+	var endObjs []int
+	for offset := 0; ; {
+		idx := bytes.Index(data[offset:], pdfEndObject)
+		if idx < 0 {
+			break
+		}
+		endObjs = append(endObjs, offset+idx)
+		offset += idx + len(pdfEndObject)
+	}
+
+	headers := pdfObjectHeader.FindAllSubmatchIndex(data, -1)
+	consumed := 0
+	for i, loc := range headers {
+		if loc[0] < consumed {
+			continue
+		}
+
+		num, err := strconv.Atoi(string(data[loc[2]:loc[3]]))
+		if err != nil {
+			continue
+		}
+
+		// The object ends at the next endobj, or at the next header when it has none
+		for len(endObjs) > 0 && endObjs[0] < loc[1] {
+			endObjs = endObjs[1:]
+		}
+		end := len(data)
+		if len(endObjs) > 0 {
+			end = endObjs[0]
+		} else if i+1 < len(headers) {
+			end = max(headers[i+1][0], loc[1])
+		}
+		rest := data[loc[1]:end]
+		consumed = end
+
+		obj := &pdfObject{}
+		streamStart := bytes.Index(rest, []byte("stream"))
+		if streamStart < 0 {
+			obj.body = string(rest)
+			f.objects[num] = obj
+			continue
+		}
+
+		obj.body = string(rest[:streamStart])
+		streamOffset := loc[1] + streamStart + len("stream")
+		streamData := data[streamOffset:]
+		if bytes.HasPrefix(streamData, []byte("\r\n")) {
+			streamData = streamData[2:]
+			streamOffset += 2
+		} else if bytes.HasPrefix(streamData, []byte("\n")) {
+			streamData = streamData[1:]
+			streamOffset++
+		}
+
+		// Prefer a direct /Length followed by endstream, which may run past an
+		// endobj inside the stream data; other lengths fall back to the endstream marker
+		length := -1
+		if m := pdfLength.FindStringSubmatch(obj.body); m != nil && m[2] == "" {
+			length, _ = strconv.Atoi(m[1])
+		}
+		if length < 0 || length > len(streamData) ||
+			!bytes.HasPrefix(bytes.TrimLeft(streamData[length:], "\r\n "), []byte("endstream")) {
+			length = bytes.Index(streamData, []byte("endstream"))
+			if length < 0 {
+				length = len(streamData)
+			}
+		}
+		obj.stream = streamData[:length]
+		consumed = max(consumed, streamOffset+length)
+
+		// An object can appear several times in incrementally updated files; the last one wins
+		f.objects[num] = obj
+	}
+}
+
+// expandObjectStreams adds the objects stored inside compressed object streams
+func (f *pdfFile) expandObjectStreams() {
+	for _, obj := range f.objects {
+		if obj.stream == nil || !pdfTypeObjStm.MatchString(obj.body) {
+			continue
+		}
+
+		data, err := f.decodeStream(obj)
+		if err != nil {
+			continue
+		}
+
+		count := pdfIntEntry(obj.body, pdfCount)
+		first := pdfIntEntry(obj.body, pdfFirst)
+		if count <= 0 || first <= 0 || first > len(data) {
+			continue
+		}
+
+		header := strings.Fields(string(data[:first]))
+		type entry struct{ num, offset int }
+		var entries []entry
+		for i := 0; i+1 < len(header) && len(entries) < count; i += 2 {
+			num, err1 := strconv.Atoi(header[i])
+			offset, err2 := strconv.Atoi(header[i+1])
+			if err1 != nil || err2 != nil || offset < 0 {
+				break
+			}
+			entries = append(entries, entry{num, offset})
+		}
+
+		for i, e := range entries {
+			start := first + e.offset
+			end := len(data)
+			if i+1 < len(entries) {
+				end = first + entries[i+1].offset
+			}
+			// Offsets are relative to First; sums that overflow end up below it
+			if start < first || start > end || end > len(data) {
+				continue
+			}
+			if _, exists := f.objects[e.num]; !exists {
+				f.objects[e.num] = &pdfObject{body: string(data[start:end])}
+			}
+		}
+	}
+}
+
+// pageOrder returns the page object numbers in reading order
+func (f *pdfFile) pageOrder() []int {
+	var pages []int
+	visited := make(map[int]bool)
+
+	var walk func(num int)
+	walk = func(num int) {
+		obj, ok := f.objects[num]
+		if !ok || visited[num] {
+			return
+		}
+		visited[num] = true
+
+		if pdfTypePages.MatchString(obj.body) {
+			for _, kid := range pdfRefArray(obj.body, "Kids") {
+				walk(kid)
+			}
+			return
+		}
+		if pdfTypePage.MatchString(obj.body) {
+			pages = append(pages, num)
+		}
+	}
+
+	// Start from page tree roots (Pages objects without a parent)
+	var roots []int
+	for num, obj := range f.objects {
+		if pdfTypePages.MatchString(obj.body) && !strings.Contains(obj.body, "/Parent") {
+			roots = append(roots, num)
+		}
+	}
+	sort.Ints(roots)
+	for _, root := range roots {
+		walk(root)
+	}
+
+	if len(pages) > 0 {
+		return pages
+	}
+
+	// Broken page tree: fall back to object order
+	for num, obj := range f.objects {
+		if pdfTypePage.MatchString(obj.body) {
+			pages = append(pages, num)
+		}
+	}
+	sort.Ints(pages)
+	return pages
+}
+
+// pageContent returns the decoded content streams of a page
+func (f *pdfFile) pageContent(pageNum int) []byte {
+	page := f.objects[pageNum]
+	if page == nil {
+		return nil
+	}
+
+	refs := pdfRefArray(page.body, "Contents")
+	if len(refs) == 1 {
+		// The contents entry may point to an array object
+		if obj, ok := f.objects[refs[0]]; ok && obj.stream == nil && strings.HasPrefix(strings.TrimSpace(obj.body), "[") {
+			refs = nil
+			for _, m := range pdfReference.FindAllStringSubmatch(obj.body, -1) {
+				num, _ := strconv.Atoi(m[1])
+				refs = append(refs, num)
+			}
+		}
+	}
+
+	var content bytes.Buffer
+	for _, ref := range refs {
+		obj, ok := f.objects[ref]
+		if !ok || obj.stream == nil {
+			continue
+		}
+		data, err := f.decodeStream(obj)
+		if err != nil {
+			continue
+		}
+		content.Write(data)
+		content.WriteByte('\n')
+	}
+
+	return content.Bytes()
+}
+
+// decodeStream decodes a stream, supporting no filter or FlateDecode. Decoded
+// bytes count towards maxPDFDecodedSize, after which errPDFTooLarge is returned.
+func (f *pdfFile) decodeStream(obj *pdfObject) ([]byte, error) {
+	budget := maxPDFDecodedSize - f.decoded
+	if budget <= 0 {
+		return nil, errPDFTooLarge
+	}
+
+	if !strings.Contains(obj.body, "/Filter") {
+		if len(obj.stream) > budget {
+			f.decoded = maxPDFDecodedSize + 1
+			return nil, errPDFTooLarge
+		}
+		f.decoded += len(obj.stream)
+		return obj.stream, nil
+	}
+	if !strings.Contains(obj.body, "/FlateDecode") || strings.Count(obj.body, "Decode") > 1 {
+		return nil, fmt.Errorf("unsupported stream filter")
+	}
+
+	reader, err := zlib.NewReader(bytes.NewReader(obj.stream))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// Read one byte past the budget to tell a stream that fits from one that does not
+	limit := min(maxPDFStreamSize, budget+1)
+
+	// Truncated streams are common; keep whatever was decoded
+	data, err := io.ReadAll(io.LimitReader(reader, int64(limit)))
+	f.decoded += len(data)
+	if f.decoded > maxPDFDecodedSize {
+		return nil, errPDFTooLarge
+	}
+	if err != nil && len(data) == 0 {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// pdfIntEntry reads an integer dictionary entry matched by pattern
+func pdfIntEntry(body string, pattern *regexp.Regexp) int {
+	m := pattern.FindStringSubmatch(body)
+	if m == nil {
+		return 0
+	}
+	value, _ := strconv.Atoi(m[1])
+	return value
+}
+
+// pdfRefArray reads a dictionary entry holding a reference or an array of references
+func pdfRefArray(body, key string) []int {
+	idx := strings.Index(body, "/"+key)
+	if idx < 0 {
+		return nil
+	}
+	value := strings.TrimSpace(body[idx+len(key)+1:])
+
+	if strings.HasPrefix(value, "[") {
+		if end := strings.Index(value, "]"); end >= 0 {
+			value = value[:end]
+		}
+	} else if m := pdfReference.FindStringIndex(value); m != nil && m[0] == 0 {
+		value = value[:m[1]]
+	} else {
+		return nil
+	}
+
+	var refs []int
+	for _, m := range pdfReference.FindAllStringSubmatch(value, -1) {
+		num, _ := strconv.Atoi(m[1])
+		refs = append(refs, num)
+	}
+	return refs
+}
+
+// pdfToken is an operand in a content stream
+type pdfToken struct {
+	str   []byte
+	num   float64
+	isStr bool
+	isNum bool
+	array []pdfToken
+	isArr bool
+}
+
+// extractPDFText interprets the text operators of a content stream
+func extractPDFText(content []byte) string {
+	var out strings.Builder
+	var operands []pdfToken
+	var arrays [][]pdfToken
+
+	push := func(tok pdfToken) {
+		if len(arrays) > 0 {
+			arrays[len(arrays)-1] = append(arrays[len(arrays)-1], tok)
+			return
+		}
+		operands = append(operands, tok)
+	}
+	newline := func() {
+		if out.Len() > 0 && !strings.HasSuffix(out.String(), "\n") {
+			out.WriteByte('\n')
+		}
+	}
+	space := func() {
+		if s := out.String(); len(s) > 0 && !strings.HasSuffix(s, " ") && !strings.HasSuffix(s, "\n") {
+			out.WriteByte(' ')
+		}
+	}
+
+	i := 0
+	for i < len(content) {
+		c := content[i]
+		switch {
+		case isPDFWhitespace(c):
+			i++
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case c == '(':
+			str, next := readPDFLiteral(content, i)
+			push(pdfToken{str: str, isStr: true})
+			i = next
+		case c == '<' && i+1 < len(content) && content[i+1] == '<':
+			i += 2
+		case c == '>' && i+1 < len(content) && content[i+1] == '>':
+			i += 2
+		case c == '<':
+			str, next := readPDFHex(content, i)
+			push(pdfToken{str: str, isStr: true})
+			i = next
+		case c == '[':
+			arrays = append(arrays, nil)
+			i++
+		case c == ']':
+			if len(arrays) > 0 {
+				arr := arrays[len(arrays)-1]
+				arrays = arrays[:len(arrays)-1]
+				push(pdfToken{array: arr, isArr: true})
+			}
+			i++
+		case c == '/':
+			start := i
+			i++
+			for i < len(content) && !isPDFWhitespace(content[i]) && !isPDFDelimiter(content[i]) {
+				i++
+			}
+			push(pdfToken{str: content[start:i]})
+		case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+			start := i
+			i++
+			for i < len(content) && (content[i] == '.' || (content[i] >= '0' && content[i] <= '9')) {
+				i++
+			}
+			num, _ := strconv.ParseFloat(string(content[start:i]), 64)
+			push(pdfToken{num: num, isNum: true})
+		default:
+			start := i
+			for i < len(content) && !isPDFWhitespace(content[i]) && !isPDFDelimiter(content[i]) {
+				i++
+			}
+			if i == start {
+				i++
+				continue
+			}
+			op := string(content[start:i])
+
+			switch op {
+			case "Tj":
+				if tok, ok := lastOperand(operands); ok && tok.isStr {
+					out.WriteString(decodePDFString(tok.str))
+				}
+			case "'", "\"":
+				newline()
+				if tok, ok := lastOperand(operands); ok && tok.isStr {
+					out.WriteString(decodePDFString(tok.str))
+				}
+			case "TJ":
+				if tok, ok := lastOperand(operands); ok && tok.isArr {
+					for _, part := range tok.array {
+						if part.isStr {
+							out.WriteString(decodePDFString(part.str))
+						} else if part.isNum && part.num < -200 {
+							space()
+						}
+					}
+				}
+			case "Td", "TD":
+				if len(operands) >= 2 && operands[len(operands)-1].isNum && operands[len(operands)-1].num != 0 {
+					newline()
+				} else {
+					space()
+				}
+			case "T*":
+				newline()
+			case "Tm", "ET":
+				newline()
+			case "ID":
+				// Skip inline image data
+				if end := bytes.Index(content[i:], []byte("EI")); end >= 0 {
+					i += end + 2
+				} else {
+					i = len(content)
+				}
+			}
+			operands = operands[:0]
+		}
+	}
+
+	return normalizePDFText(out.String())
+}
+
+func lastOperand(operands []pdfToken) (pdfToken, bool) {
+	if len(operands) == 0 {
+		return pdfToken{}, false
+	}
+	return operands[len(operands)-1], true
+}
+
+// readPDFLiteral reads a (literal) string starting at content[start]
+func readPDFLiteral(content []byte, start int) ([]byte, int) {
+	var out []byte
+	depth := 0
+	i := start
+	for i < len(content) {
+		c := content[i]
+		switch {
+		case c == '\\' && i+1 < len(content):
+			i++
+			switch e := content[i]; e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				if i+1 < len(content) && content[i+1] == '\n' {
+					i++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					value := 0
+					n := 0
+					for n < 3 && i < len(content) && content[i] >= '0' && content[i] <= '7' {
+						value = value*8 + int(content[i]-'0')
+						i++
+						n++
+					}
+					i--
+					out = append(out, byte(value))
+				} else {
+					out = append(out, e)
+				}
+			}
+		case c == '(':
+			if depth > 0 {
+				out = append(out, c)
+			}
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return out, i + 1
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+		i++
+	}
+	return out, i
+}
+
+// readPDFHex reads a <hex> string starting at content[start]
+func readPDFHex(content []byte, start int) ([]byte, int) {
+	end := bytes.IndexByte(content[start:], '>')
+	if end < 0 {
+		return nil, len(content)
+	}
+
+	var digits []byte
+	for _, c := range content[start+1 : start+end] {
+		if !isPDFWhitespace(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	out := make([]byte, 0, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		value, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			break
+		}
+		out = append(out, byte(value))
+	}
+	return out, start + end + 1
+}
+
+// decodePDFString decodes UTF-16BE strings (with BOM) and treats everything else as Latin-1
+func decodePDFString(data []byte) string {
+	if len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF {
+		units := make([]uint16, 0, (len(data)-2)/2)
+		for i := 2; i+1 < len(data); i += 2 {
+			units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+
+	runes := make([]rune, 0, len(data))
+	for _, b := range data {
+		if b < 0x20 && b != '\n' && b != '\t' {
+			continue
+		}
+		runes = append(runes, rune(b))
+	}
+	return string(runes)
+}
+
+// normalizePDFText collapses repeated spaces and blank lines
+func normalizePDFText(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isPDFWhitespace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
@@ -236,6 +236,88 @@ func (p *Pipeline) GetContext(ctx context.Context, memories []domain.MemoryHit,
 	return context
 }
 
+// documentEmbedBatchSize limits the number of chunks embedded per provider call
+const documentEmbedBatchSize = 64
+
+// IngestDocument chunks, embeds and stores the pages of a document as memory
+// chunks tagged with the document ID, name and page number
+func (p *Pipeline) IngestDocument(ctx context.Context, document *domain.Document, pages []domain.DocumentPage) (int, error) {
+	start := time.Now()
+	
+	logger := p.logger.WithContext(ctx).WithTenant(document.TenantID).WithUser(document.UserID.String())
+	
+	var items []*domain.MemoryItem
+	for _, page := range pages {
+		if strings.TrimSpace(page.Text) == "" {
+			continue
+		}
+		
+		metadata := map[string]interface{}{
+			"document_id":   document.ID.String(),
+			"document_name": document.FileName,
+			"source":        "document",
+			"stored_at":     time.Now().UTC().Format(time.RFC3339),
+		}
+		if page.Number > 0 {
+			metadata["page"] = page.Number
+		}
+		
+		items = append(items, p.chunkMemoryItem(&domain.MemoryItem{
+			Kind:     "document",
+			Text:     page.Text,
+			Metadata: metadata,
+		})...)
+	}
+	
+	if len(items) == 0 {
+		return 0, fmt.Errorf("document has no text to ingest")
+	}
+	
+	// Generate embeddings in batches
+	for i := 0; i < len(items); i += documentEmbedBatchSize {
+		end := i + documentEmbedBatchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		
+		texts := make([]string, 0, end-i)
+		for _, item := range items[i:end] {
+			texts = append(texts, item.Text)
+		}
+		
+		embeddings, err := p.llmProvider.Embed(ctx, texts)
+		if err != nil {
+			return 0, fmt.Errorf("failed to generate chunk embeddings: %w", err)
+		}
+		if len(embeddings) != len(texts) {
+			return 0, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embeddings))
+		}
+		
+		for j, item := range items[i:end] {
+			item.Metadata["embedding"] = embeddings[j]
+		}
+	}
+	
+	memItems := make([]domain.MemoryItem, len(items))
+	for i, item := range items {
+		memItems[i] = *item
+	}
+	
+	ids, err := p.vectorStore.Upsert(ctx, document.TenantID, document.UserID, memItems)
+	if err != nil {
+		return 0, fmt.Errorf("failed to store document chunks: %w", err)
+	}
+	
+	logger.Info().
+		Str("document_id", document.ID.String()).
+		Int("pages", len(pages)).
+		Int("chunks", len(ids)).
+		Dur("duration", time.Since(start)).
+		Msg("document ingested successfully")
+	
+	return len(ids), nil
+}
+
 // chunkMemoryItem splits a large memory item into smaller chunks
 func (p *Pipeline) chunkMemoryItem(item *domain.MemoryItem) []*domain.MemoryItem {
 	text := item.Text
@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"personal-assistant/internal/domain"
+)
+
+const contactRequestColumns = `id, tenant_id, phone_number, COALESCE(contact_name, ''), COALESCE(last_message, ''),
+	message_count, status, COALESCE(decided_by, ''), decided_at, created_at, updated_at`
+
+// scanContactRequest scans a row selected with contactRequestColumns
+func scanContactRequest(row pgx.Row) (*domain.ContactRequest, error) {
+	var request domain.ContactRequest
+	err := row.Scan(
+		&request.ID, &request.TenantID, &request.PhoneNumber, &request.ContactName, &request.LastMessage,
+		&request.MessageCount, &request.Status, &request.DecidedBy, &request.DecidedAt,
+		&request.CreatedAt, &request.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetContactRequest retrieves the access request of a sender
+func (r *PostgresRepository) GetContactRequest(ctx context.Context, tenantID, phoneNumber string) (*domain.ContactRequest, error) {
+	query := `
+		SELECT ` + contactRequestColumns + `
+		FROM contact_requests
+		WHERE tenant_id = $1 AND phone_number = $2
+	`
+
+	request, err := scanContactRequest(r.db.QueryRow(ctx, query, tenantID, phoneNumber))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get contact request: %w", err)
+	}
+
+	return request, nil
+}
+
+// ListContactRequests lists a tenant's access requests oldest first; an empty
+// status returns requests in any status
+func (r *PostgresRepository) ListContactRequests(ctx context.Context, tenantID, status string) ([]domain.ContactRequest, error) {
+	query := `
+		SELECT ` + contactRequestColumns + `
+		FROM contact_requests
+		WHERE tenant_id = $1 AND ($2::text = '' OR status = $2::text)
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contact requests: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []domain.ContactRequest
+	for rows.Next() {
+		request, err := scanContactRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan contact request: %w", err)
+		}
+		requests = append(requests, *request)
+	}
+
+	return requests, rows.Err()
+}
+
+// CreateContactRequest creates a new access request
+func (r *PostgresRepository) CreateContactRequest(ctx context.Context, request *domain.ContactRequest) error {
+	if request.ID == uuid.Nil {
+		request.ID = uuid.New()
+	}
+	if request.Status == "" {
+		request.Status = domain.ContactRequestPending
+	}
+	if request.MessageCount == 0 {
+		request.MessageCount = 1
+	}
+	now := time.Now().UTC()
+	request.CreatedAt = now
+	request.UpdatedAt = now
+
+	query := `
+		INSERT INTO contact_requests (id, tenant_id, phone_number, contact_name, last_message,
+			message_count, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		request.ID, request.TenantID, request.PhoneNumber, request.ContactName, request.LastMessage,
+		request.MessageCount, request.Status, request.CreatedAt, request.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create contact request: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateContactRequest updates the message, status and decision of an access request
+func (r *PostgresRepository) UpdateContactRequest(ctx context.Context, request *domain.ContactRequest) error {
+	request.UpdatedAt = time.Now().UTC()
+
+	query := `
+		UPDATE contact_requests
+		SET contact_name = $3, last_message = $4, message_count = $5, status = $6,
+			decided_by = NULLIF($7, ''), decided_at = $8, updated_at = $9
+		WHERE tenant_id = $1 AND id = $2
+	`
+
+	result, err := r.db.Exec(ctx, query,
+		request.TenantID, request.ID, request.ContactName, request.LastMessage, request.MessageCount,
+		request.Status, request.DecidedBy, request.DecidedAt, request.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update contact request: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("contact request not found")
+	}
+
+	return nil
+}
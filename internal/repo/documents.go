@@ -0,0 +1,214 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"personal-assistant/internal/domain"
+)
+
+const documentColumns = `id, tenant_id, user_id, media_id, file_name, COALESCE(mime_type, ''), status,
+		page_count, chunk_count, COALESCE(last_error, ''), metadata, created_at, updated_at`
+
+// CreateDocument creates a new document record
+func (r *PostgresRepository) CreateDocument(ctx context.Context, document *domain.Document) error {
+	query := `
+		INSERT INTO documents (id, tenant_id, user_id, media_id, file_name, mime_type, status,
+			page_count, chunk_count, metadata, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	if document.ID == uuid.Nil {
+		document.ID = uuid.New()
+	}
+	if document.Status == "" {
+		document.Status = domain.DocumentStatusProcessing
+	}
+	document.CreatedAt = time.Now().UTC()
+	document.UpdatedAt = document.CreatedAt
+
+	metadataJSON, err := json.Marshal(document.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx, query,
+		document.ID, document.TenantID, document.UserID, document.MediaID,
+		document.FileName, document.MimeType, document.Status,
+		document.PageCount, document.ChunkCount, metadataJSON,
+		document.CreatedAt, document.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create document: %w", err)
+	}
+
+	r.logger.WithContext(ctx).Debug().
+		Str("document_id", document.ID.String()).
+		Str("tenant_id", document.TenantID).
+		Str("file_name", document.FileName).
+		Msg("document created")
+
+	return nil
+}
+
+// UpdateDocument updates the ingestion status of a document
+func (r *PostgresRepository) UpdateDocument(ctx context.Context, document *domain.Document) error {
+	query := `
+		UPDATE documents
+		SET status = $3, page_count = $4, chunk_count = $5, last_error = NULLIF($6, ''), metadata = $7
+		WHERE tenant_id = $1 AND id = $2
+	`
+
+	metadataJSON, err := json.Marshal(document.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	result, err := r.db.Exec(ctx, query,
+		document.TenantID, document.ID, document.Status, document.PageCount,
+		document.ChunkCount, document.LastError, metadataJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update document: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("document not found")
+	}
+
+	return nil
+}
+
+// GetDocument retrieves a document by ID
+func (r *PostgresRepository) GetDocument(ctx context.Context, tenantID string, documentID uuid.UUID) (*domain.Document, error) {
+	query := `
+		SELECT ` + documentColumns + `
+		FROM documents
+		WHERE tenant_id = $1 AND id = $2
+	`
+
+	document, err := scanDocument(r.db.QueryRow(ctx, query, tenantID, documentID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+
+	return document, nil
+}
+
+// GetDocumentByMediaID retrieves the document parsed from a media attachment
+func (r *PostgresRepository) GetDocumentByMediaID(ctx context.Context, tenantID string, mediaID uuid.UUID) (*domain.Document, error) {
+	query := `
+		SELECT ` + documentColumns + `
+		FROM documents
+		WHERE tenant_id = $1 AND media_id = $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	document, err := scanDocument(r.db.QueryRow(ctx, query, tenantID, mediaID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get document by media: %w", err)
+	}
+
+	return document, nil
+}
+
+// ListDocuments lists a user's documents, most recent first
+func (r *PostgresRepository) ListDocuments(ctx context.Context, tenantID string, userID uuid.UUID, limit int) ([]domain.Document, error) {
+	query := `
+		SELECT ` + documentColumns + `
+		FROM documents
+		WHERE tenant_id = $1 AND user_id = $2
+		ORDER BY created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query documents: %w", err)
+	}
+	defer rows.Close()
+
+	var documents []domain.Document
+	for rows.Next() {
+		document, err := scanDocument(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan document: %w", err)
+		}
+		documents = append(documents, *document)
+	}
+
+	return documents, rows.Err()
+}
+
+// DeleteDocument deletes a document together with its memory chunks
+func (r *PostgresRepository) DeleteDocument(ctx context.Context, tenantID string, documentID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM memory_chunks
+		WHERE tenant_id = $1 AND metadata->>'document_id' = $2
+	`, tenantID, documentID.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete document chunks: %w", err)
+	}
+
+	result, err := tx.Exec(ctx, `DELETE FROM documents WHERE tenant_id = $1 AND id = $2`, tenantID, documentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("document not found")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.logger.WithContext(ctx).Debug().
+		Str("document_id", documentID.String()).
+		Str("tenant_id", tenantID).
+		Msg("document deleted")
+
+	return nil
+}
+
+// scanDocument scans a document row selected with documentColumns
+func scanDocument(row pgx.Row) (*domain.Document, error) {
+	var document domain.Document
+	var metadataJSON []byte
+
+	err := row.Scan(
+		&document.ID, &document.TenantID, &document.UserID, &document.MediaID,
+		&document.FileName, &document.MimeType, &document.Status,
+		&document.PageCount, &document.ChunkCount, &document.LastError,
+		&metadataJSON, &document.CreatedAt, &document.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &document.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+	}
+
+	return &document, nil
+}
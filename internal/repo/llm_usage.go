@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"personal-assistant/internal/domain"
+)
+
+// RecordLLMUsage stores the token usage of a single LLM call
+func (r *PostgresRepository) RecordLLMUsage(ctx context.Context, usage *domain.LLMUsage) error {
+	if usage.ID == uuid.Nil {
+		usage.ID = uuid.New()
+	}
+	if usage.CreatedAt.IsZero() {
+		usage.CreatedAt = time.Now().UTC()
+	}
+
+	query := `
+		INSERT INTO llm_usage (id, tenant_id, user_id, provider, model, operation,
+			prompt_tokens, completion_tokens, estimated, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.Exec(ctx, query,
+		usage.ID, usage.TenantID, usage.UserID, usage.Provider, usage.Model, usage.Operation,
+		usage.PromptTokens, usage.CompletionTokens, usage.Estimated, usage.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create LLM usage: %w", err)
+	}
+
+	return nil
+}
+
+// GetDailyLLMUsage aggregates the token usage of a tenant between from and to
+// (exclusive) by UTC day, user, provider and model, priced with llm_model_prices.
+// A model without a price of its own takes the price of the longest listed
+// model it is a dated snapshot of, e.g. gpt-4o-2024-08-06 that of gpt-4o.
+// A non-nil userID restricts the result to that user.
+func (r *PostgresRepository) GetDailyLLMUsage(ctx context.Context, tenantID string, userID *uuid.UUID, from, to time.Time) ([]domain.DailyLLMUsage, error) {
+	query := `
+		SELECT date_trunc('day', u.created_at AT TIME ZONE 'UTC') AS day, u.user_id, u.provider, u.model,
+			COUNT(*),
+			COALESCE(SUM(u.prompt_tokens) FILTER (WHERE u.operation = 'chat'), 0),
+			COALESCE(SUM(u.completion_tokens), 0),
+			COALESCE(SUM(u.prompt_tokens) FILTER (WHERE u.operation = 'embed'), 0),
+			COALESCE(SUM(u.prompt_tokens * p.input_price + u.completion_tokens * p.output_price) / 1000000, 0)::float8,
+			BOOL_AND(p.model IS NOT NULL)
+		FROM llm_usage u
+		LEFT JOIN LATERAL (
+			SELECT model, input_price, output_price FROM llm_model_prices
+			WHERE model = u.model OR left(u.model, length(model) + 1) = model || '-'
+			ORDER BY length(model) DESC
+			LIMIT 1
+		) p ON TRUE
+		WHERE u.tenant_id = $1 AND u.created_at >= $2 AND u.created_at < $3
+			AND ($4::uuid IS NULL OR u.user_id = $4)
+		GROUP BY day, u.user_id, u.provider, u.model
+		ORDER BY day, u.user_id, u.provider, u.model
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID, from, to, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query LLM usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []domain.DailyLLMUsage
+	for rows.Next() {
+		var day domain.DailyLLMUsage
+
+		err := rows.Scan(
+			&day.Day, &day.UserID, &day.Provider, &day.Model,
+			&day.Calls, &day.PromptTokens, &day.CompletionTokens, &day.EmbeddingTokens,
+			&day.Cost, &day.Priced,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan LLM usage: %w", err)
+		}
+
+		day.Day = day.Day.UTC()
+		usage = append(usage, day)
+	}
+
+	return usage, rows.Err()
+}
@@ -0,0 +1,84 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"personal-assistant/internal/domain"
+)
+
+// CreateMedia stores a media record attached to a message
+func (r *PostgresRepository) CreateMedia(ctx context.Context, media *domain.Media) error {
+	query := `
+		INSERT INTO media (id, tenant_id, user_id, message_id, type, mime_type, file_name,
+			caption, url, size_bytes, data, metadata, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	if media.ID == uuid.Nil {
+		media.ID = uuid.New()
+	}
+	media.CreatedAt = time.Now().UTC()
+
+	metadataJSON, err := json.Marshal(media.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx, query,
+		media.ID, media.TenantID, media.UserID, media.MessageID, media.Type,
+		media.MimeType, media.FileName, media.Caption, media.URL, media.SizeBytes,
+		media.Data, metadataJSON, media.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create media: %w", err)
+	}
+
+	r.logger.WithContext(ctx).Debug().
+		Str("media_id", media.ID.String()).
+		Str("tenant_id", media.TenantID).
+		Str("type", media.Type).
+		Int64("size_bytes", media.SizeBytes).
+		Msg("media created")
+
+	return nil
+}
+
+// GetMedia retrieves a media record including its content
+func (r *PostgresRepository) GetMedia(ctx context.Context, tenantID string, mediaID uuid.UUID) (*domain.Media, error) {
+	query := `
+		SELECT id, tenant_id, user_id, message_id, type, COALESCE(mime_type, ''), COALESCE(file_name, ''),
+			COALESCE(caption, ''), COALESCE(url, ''), size_bytes, data, metadata, created_at
+		FROM media
+		WHERE tenant_id = $1 AND id = $2
+	`
+
+	var media domain.Media
+	var metadataJSON []byte
+
+	err := r.db.QueryRow(ctx, query, tenantID, mediaID).Scan(
+		&media.ID, &media.TenantID, &media.UserID, &media.MessageID, &media.Type,
+		&media.MimeType, &media.FileName, &media.Caption, &media.URL, &media.SizeBytes,
+		&media.Data, &metadataJSON, &media.CreatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get media: %w", err)
+	}
+
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &media.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+	}
+
+	return &media, nil
+}
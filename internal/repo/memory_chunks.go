@@ -0,0 +1,47 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"personal-assistant/internal/domain"
+)
+
+// GetLatestMemoryChunk retrieves the most recently created memory chunk of a kind for a user.
+// The embedding is not loaded.
+func (r *PostgresRepository) GetLatestMemoryChunk(ctx context.Context, tenantID string, userID uuid.UUID, kind string) (*domain.MemoryChunk, error) {
+	query := `
+		SELECT id, tenant_id, user_id, kind, text, metadata, created_at, updated_at
+		FROM memory_chunks
+		WHERE tenant_id = $1 AND user_id = $2 AND kind = $3
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var chunk domain.MemoryChunk
+	var metadataJSON []byte
+
+	err := r.db.QueryRow(ctx, query, tenantID, userID, kind).Scan(
+		&chunk.ID, &chunk.TenantID, &chunk.UserID, &chunk.Kind, &chunk.Text,
+		&metadataJSON, &chunk.CreatedAt, &chunk.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest memory chunk: %w", err)
+	}
+
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &chunk.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+	}
+
+	return &chunk, nil
+}
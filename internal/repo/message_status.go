@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"personal-assistant/internal/domain"
+)
+
+// messageStatusOrder ranks delivery statuses so out-of-order reports never move a
+// message back, e.g. a delivery report arriving after the seen report
+const messageStatusOrder = `ARRAY['pending', 'sent', 'delivered', 'seen', 'failed']::varchar[]`
+
+// RecordMessageStatus appends a status event to the delivery timeline of a
+// message and advances the message's current status. A report that was
+// already recorded, e.g. from a redelivered webhook, is ignored.
+func (r *PostgresRepository) RecordMessageStatus(ctx context.Context, event *domain.MessageStatusEvent) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	event.CreatedAt = time.Now().UTC()
+
+	payloadJSON, err := json.Marshal(event.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		INSERT INTO message_status_events (id, tenant_id, message_id, external_id, status, detail,
+			error_code, error_message, occurred_at, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, 0), NULLIF($8, ''), $9, $10, $11)
+		ON CONFLICT (tenant_id, external_id, status, occurred_at) DO NOTHING
+	`,
+		event.ID, event.TenantID, event.MessageID, event.ExternalID, event.Status, event.Detail,
+		event.ErrorCode, event.ErrorMessage, event.OccurredAt, payloadJSON, event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create message status event: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE messages
+		SET status = $3
+		WHERE tenant_id = $1 AND id = $2
+			AND (status IS NULL OR
+				array_position(`+messageStatusOrder+`, status) <= array_position(`+messageStatusOrder+`, $3::varchar))
+	`, event.TenantID, event.MessageID, event.Status)
+	if err != nil {
+		return fmt.Errorf("failed to update message status: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.logger.WithContext(ctx).Debug().
+		Str("message_id", event.MessageID.String()).
+		Str("tenant_id", event.TenantID).
+		Str("status", event.Status).
+		Msg("message status recorded")
+
+	return nil
+}
+
+// GetMessageStatusTimeline retrieves the delivery timeline of a message in chronological order
+func (r *PostgresRepository) GetMessageStatusTimeline(ctx context.Context, tenantID string, messageID uuid.UUID) ([]domain.MessageStatusEvent, error) {
+	query := `
+		SELECT id, tenant_id, message_id, external_id, status, COALESCE(detail, ''),
+			COALESCE(error_code, 0), COALESCE(error_message, ''), occurred_at, payload, created_at
+		FROM message_status_events
+		WHERE tenant_id = $1 AND message_id = $2
+		ORDER BY occurred_at, created_at
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query message status events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []domain.MessageStatusEvent
+	for rows.Next() {
+		var event domain.MessageStatusEvent
+		var payloadJSON []byte
+
+		err := rows.Scan(
+			&event.ID, &event.TenantID, &event.MessageID, &event.ExternalID, &event.Status, &event.Detail,
+			&event.ErrorCode, &event.ErrorMessage, &event.OccurredAt, &payloadJSON, &event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message status event: %w", err)
+		}
+
+		if len(payloadJSON) > 0 {
+			if err := json.Unmarshal(payloadJSON, &event.Payload); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal payload: %w", err)
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"personal-assistant/internal/domain"
+)
+
+const messageTemplateColumns = `id, tenant_id, purpose, name, language, placeholders, COALESCE(body, ''),
+		enabled, created_at, updated_at`
+
+// GetMessageTemplate retrieves the enabled template configured for a purpose
+func (r *PostgresRepository) GetMessageTemplate(ctx context.Context, tenantID, purpose string) (*domain.MessageTemplate, error) {
+	query := `
+		SELECT ` + messageTemplateColumns + `
+		FROM message_templates
+		WHERE tenant_id = $1 AND purpose = $2 AND enabled = true
+	`
+
+	template, err := scanMessageTemplate(r.db.QueryRow(ctx, query, tenantID, purpose))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get message template: %w", err)
+	}
+
+	return template, nil
+}
+
+// ListMessageTemplates retrieves all templates of a tenant
+func (r *PostgresRepository) ListMessageTemplates(ctx context.Context, tenantID string) ([]domain.MessageTemplate, error) {
+	query := `
+		SELECT ` + messageTemplateColumns + `
+		FROM message_templates
+		WHERE tenant_id = $1
+		ORDER BY purpose
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query message templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []domain.MessageTemplate
+	for rows.Next() {
+		template, err := scanMessageTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message template: %w", err)
+		}
+		templates = append(templates, *template)
+	}
+
+	return templates, rows.Err()
+}
+
+// UpsertMessageTemplate creates or replaces the template configured for a purpose
+func (r *PostgresRepository) UpsertMessageTemplate(ctx context.Context, template *domain.MessageTemplate) error {
+	query := `
+		INSERT INTO message_templates (id, tenant_id, purpose, name, language, placeholders, body, enabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10)
+		ON CONFLICT (tenant_id, purpose) DO UPDATE SET
+			name = EXCLUDED.name,
+			language = EXCLUDED.language,
+			placeholders = EXCLUDED.placeholders,
+			body = EXCLUDED.body,
+			enabled = EXCLUDED.enabled
+		RETURNING id, created_at, updated_at
+	`
+
+	if template.ID == uuid.Nil {
+		template.ID = uuid.New()
+	}
+	if template.Placeholders == nil {
+		template.Placeholders = []string{}
+	}
+	now := time.Now().UTC()
+
+	placeholdersJSON, err := json.Marshal(template.Placeholders)
+	if err != nil {
+		return fmt.Errorf("failed to marshal placeholders: %w", err)
+	}
+
+	err = r.db.QueryRow(ctx, query,
+		template.ID, template.TenantID, template.Purpose, template.Name, template.Language,
+		placeholdersJSON, template.Body, template.Enabled, now, now,
+	).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert message template: %w", err)
+	}
+
+	r.logger.WithContext(ctx).Debug().
+		Str("tenant_id", template.TenantID).
+		Str("purpose", template.Purpose).
+		Str("template", template.Name).
+		Msg("message template saved")
+
+	return nil
+}
+
+// scanMessageTemplate scans a message template row
+func scanMessageTemplate(row pgx.Row) (*domain.MessageTemplate, error) {
+	var template domain.MessageTemplate
+	var placeholdersJSON []byte
+
+	err := row.Scan(
+		&template.ID, &template.TenantID, &template.Purpose, &template.Name, &template.Language,
+		&placeholdersJSON, &template.Body, &template.Enabled, &template.CreatedAt, &template.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(placeholdersJSON, &template.Placeholders); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal placeholders: %w", err)
+	}
+
+	return &template, nil
+}
@@ -0,0 +1,271 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"personal-assistant/internal/domain"
+)
+
+const reminderColumns = `id, tenant_id, user_id, item_id, text, channel, status, scheduled_at,
+		attempts, max_attempts, COALESCE(last_error, ''), locked_until, sent_at, recurrence, timezone, occurrences,
+		metadata, created_at, updated_at`
+
+// CreateReminder creates a new reminder
+func (r *PostgresRepository) CreateReminder(ctx context.Context, reminder *domain.Reminder) error {
+	query := `
+		INSERT INTO reminders (id, tenant_id, user_id, item_id, text, channel, status, scheduled_at,
+			attempts, max_attempts, recurrence, timezone, occurrences, metadata, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`
+
+	if reminder.ID == uuid.Nil {
+		reminder.ID = uuid.New()
+	}
+	if reminder.Status == "" {
+		reminder.Status = domain.ReminderStatusScheduled
+	}
+	if reminder.Channel == "" {
+		reminder.Channel = "whatsapp"
+	}
+	if reminder.MaxAttempts <= 0 {
+		reminder.MaxAttempts = domain.DefaultReminderMaxAttempts
+	}
+	if reminder.TimeZone == "" {
+		reminder.TimeZone = "UTC"
+	}
+	reminder.CreatedAt = time.Now().UTC()
+	reminder.UpdatedAt = reminder.CreatedAt
+
+	recurrenceJSON, metadataJSON, err := marshalReminderJSON(reminder)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query,
+		reminder.ID, reminder.TenantID, reminder.UserID, reminder.ItemID,
+		reminder.Text, reminder.Channel, reminder.Status, reminder.ScheduledAt,
+		reminder.Attempts, reminder.MaxAttempts, recurrenceJSON, reminder.TimeZone,
+		reminder.Occurrences, metadataJSON, reminder.CreatedAt, reminder.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create reminder: %w", err)
+	}
+
+	r.logger.WithContext(ctx).Debug().
+		Str("reminder_id", reminder.ID.String()).
+		Str("tenant_id", reminder.TenantID).
+		Time("scheduled_at", reminder.ScheduledAt).
+		Msg("reminder created")
+
+	return nil
+}
+
+// GetReminder retrieves a reminder by ID
+func (r *PostgresRepository) GetReminder(ctx context.Context, tenantID string, reminderID uuid.UUID) (*domain.Reminder, error) {
+	query := `
+		SELECT ` + reminderColumns + `
+		FROM reminders
+		WHERE tenant_id = $1 AND id = $2
+	`
+
+	reminder, err := scanReminder(r.db.QueryRow(ctx, query, tenantID, reminderID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get reminder: %w", err)
+	}
+
+	return reminder, nil
+}
+
+// UpdateReminder updates the mutable fields of a reminder and releases its lease
+func (r *PostgresRepository) UpdateReminder(ctx context.Context, reminder *domain.Reminder) error {
+	updated, err := r.updateReminder(ctx, reminder, nil)
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		return fmt.Errorf("reminder not found")
+	}
+
+	return nil
+}
+
+// ReleaseReminder stores the outcome of a delivery and releases the lease taken
+// by ClaimDueReminders. It returns domain.ErrReminderLeaseLost, leaving the row
+// untouched, when the reminder no longer holds that lease because it was
+// cancelled or snoozed during delivery, or claimed again after the lease expired.
+func (r *PostgresRepository) ReleaseReminder(ctx context.Context, reminder *domain.Reminder, lease time.Time) error {
+	updated, err := r.updateReminder(ctx, reminder, &lease)
+	if err != nil {
+		return err
+	}
+
+	if !updated {
+		return domain.ErrReminderLeaseLost
+	}
+
+	return nil
+}
+
+// updateReminder writes the mutable fields of a reminder, only while it holds
+// the given lease when one is passed, and reports whether a row was updated
+func (r *PostgresRepository) updateReminder(ctx context.Context, reminder *domain.Reminder, lease *time.Time) (bool, error) {
+	query := `
+		UPDATE reminders
+		SET text = $1, status = $2, scheduled_at = $3, attempts = $4, max_attempts = $5,
+			last_error = NULLIF($6, ''), locked_until = $7, sent_at = $8, recurrence = $9,
+			timezone = $10, occurrences = $11, metadata = $12, updated_at = $13
+		WHERE tenant_id = $14 AND id = $15
+			AND ($16::timestamptz IS NULL OR (status = 'scheduled' AND locked_until = $16::timestamptz))
+	`
+
+	recurrenceJSON, metadataJSON, err := marshalReminderJSON(reminder)
+	if err != nil {
+		return false, err
+	}
+
+	reminder.UpdatedAt = time.Now().UTC()
+	result, err := r.db.Exec(ctx, query,
+		reminder.Text, reminder.Status, reminder.ScheduledAt, reminder.Attempts, reminder.MaxAttempts,
+		reminder.LastError, reminder.LockedUntil, reminder.SentAt, recurrenceJSON,
+		reminder.TimeZone, reminder.Occurrences, metadataJSON, reminder.UpdatedAt,
+		reminder.TenantID, reminder.ID, lease,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update reminder: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// ListReminders retrieves a user's reminders ordered by next delivery time.
+// An empty status returns reminders in any status.
+func (r *PostgresRepository) ListReminders(ctx context.Context, tenantID string, userID uuid.UUID, status string, limit int) ([]domain.Reminder, error) {
+	query := `
+		SELECT ` + reminderColumns + `
+		FROM reminders
+		WHERE tenant_id = $1 AND user_id = $2 AND ($3::text = '' OR status = $3::text)
+		ORDER BY scheduled_at
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID, userID, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []domain.Reminder
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		reminders = append(reminders, *reminder)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reminders: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// ClaimDueReminders atomically leases up to limit due reminders for delivery.
+// Rows locked by another replica are skipped, and a reminder whose lease has
+// expired (e.g. the claiming process crashed) becomes claimable again.
+func (r *PostgresRepository) ClaimDueReminders(ctx context.Context, tenantID string, limit int, lease time.Duration) ([]domain.Reminder, error) {
+	query := `
+		UPDATE reminders
+		SET locked_until = $3, attempts = attempts + 1, updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM reminders
+			WHERE tenant_id = $1 AND status = 'scheduled' AND scheduled_at <= NOW()
+				AND (locked_until IS NULL OR locked_until < NOW())
+			ORDER BY scheduled_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + reminderColumns
+
+	lockedUntil := time.Now().UTC().Add(lease)
+	rows, err := r.db.Query(ctx, query, tenantID, limit, lockedUntil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []domain.Reminder
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		reminders = append(reminders, *reminder)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reminders: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// scanReminder scans a single reminder row selected with reminderColumns
+func scanReminder(row pgx.Row) (*domain.Reminder, error) {
+	var reminder domain.Reminder
+	var recurrenceJSON, metadataJSON []byte
+
+	err := row.Scan(
+		&reminder.ID, &reminder.TenantID, &reminder.UserID, &reminder.ItemID,
+		&reminder.Text, &reminder.Channel, &reminder.Status, &reminder.ScheduledAt,
+		&reminder.Attempts, &reminder.MaxAttempts, &reminder.LastError,
+		&reminder.LockedUntil, &reminder.SentAt, &recurrenceJSON, &reminder.TimeZone,
+		&reminder.Occurrences, &metadataJSON, &reminder.CreatedAt, &reminder.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recurrenceJSON) > 0 {
+		if err := json.Unmarshal(recurrenceJSON, &reminder.Recurrence); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal recurrence: %w", err)
+		}
+	}
+
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &reminder.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+	}
+
+	return &reminder, nil
+}
+
+// marshalReminderJSON marshals the JSONB columns of a reminder
+func marshalReminderJSON(reminder *domain.Reminder) ([]byte, []byte, error) {
+	var recurrenceJSON []byte
+	if reminder.Recurrence != nil {
+		var err error
+		recurrenceJSON, err = json.Marshal(reminder.Recurrence)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal recurrence: %w", err)
+		}
+	}
+
+	metadataJSON, err := json.Marshal(reminder.Metadata)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	return recurrenceJSON, metadataJSON, nil
+}
@@ -3,11 +3,13 @@ package repo
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"personal-assistant/internal/domain"
@@ -148,8 +150,8 @@ func (r *PostgresRepository) UpdateUser(ctx context.Context, user *domain.User)
 // CreateMessage creates a new message
 func (r *PostgresRepository) CreateMessage(ctx context.Context, message *domain.Message) error {
 	query := `
-		INSERT INTO messages (id, tenant_id, user_id, message_id, direction, text, timestamp, token_usage, metadata, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO messages (id, tenant_id, user_id, message_id, direction, text, timestamp, token_usage, metadata, created_at, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''))
 	`
 
 	var tokenUsageJSON []byte
@@ -169,9 +171,13 @@ func (r *PostgresRepository) CreateMessage(ctx context.Context, message *domain.
 	_, err = r.db.Exec(ctx, query, 
 		message.ID, message.TenantID, message.UserID, message.MessageID,
 		message.Direction, message.Text, message.Timestamp, 
-		tokenUsageJSON, metadataJSON, message.CreatedAt,
+		tokenUsageJSON, metadataJSON, message.CreatedAt, message.Status,
 	)
 	if err != nil {
+		// The (tenant_id, message_id) constraint rejects redelivered webhooks
+		if isUniqueViolation(err) {
+			return fmt.Errorf("failed to create message %s: %w", message.MessageID, domain.ErrDuplicateMessage)
+		}
 		return fmt.Errorf("failed to create message: %w", err)
 	}
 
@@ -187,7 +193,7 @@ func (r *PostgresRepository) CreateMessage(ctx context.Context, message *domain.
 // GetMessages retrieves messages for a user
 func (r *PostgresRepository) GetMessages(ctx context.Context, tenantID string, userID uuid.UUID, limit int) ([]domain.Message, error) {
 	query := `
-		SELECT id, tenant_id, user_id, message_id, direction, text, timestamp, token_usage, metadata, created_at
+		SELECT id, tenant_id, user_id, message_id, direction, text, timestamp, token_usage, metadata, created_at, COALESCE(status, '')
 		FROM messages
 		WHERE tenant_id = $1 AND user_id = $2
 		ORDER BY timestamp DESC
@@ -208,7 +214,53 @@ func (r *PostgresRepository) GetMessages(ctx context.Context, tenantID string, u
 		err := rows.Scan(
 			&message.ID, &message.TenantID, &message.UserID, &message.MessageID,
 			&message.Direction, &message.Text, &message.Timestamp,
-			&tokenUsageJSON, &metadataJSON, &message.CreatedAt,
+			&tokenUsageJSON, &metadataJSON, &message.CreatedAt, &message.Status,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+
+		if len(tokenUsageJSON) > 0 {
+			if err := json.Unmarshal(tokenUsageJSON, &message.TokenUsage); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal token usage: %w", err)
+			}
+		}
+
+		if err := json.Unmarshal(metadataJSON, &message.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+// GetMessagesSince retrieves a user's messages newer than the given time in chronological order
+func (r *PostgresRepository) GetMessagesSince(ctx context.Context, tenantID string, userID uuid.UUID, since time.Time, limit int) ([]domain.Message, error) {
+	query := `
+		SELECT id, tenant_id, user_id, message_id, direction, text, timestamp, token_usage, metadata, created_at, COALESCE(status, '')
+		FROM messages
+		WHERE tenant_id = $1 AND user_id = $2 AND timestamp > $3
+		ORDER BY timestamp ASC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(ctx, query, tenantID, userID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []domain.Message
+	for rows.Next() {
+		var message domain.Message
+		var tokenUsageJSON, metadataJSON []byte
+
+		err := rows.Scan(
+			&message.ID, &message.TenantID, &message.UserID, &message.MessageID,
+			&message.Direction, &message.Text, &message.Timestamp,
+			&tokenUsageJSON, &metadataJSON, &message.CreatedAt, &message.Status,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan message: %w", err)
@@ -233,7 +285,7 @@ func (r *PostgresRepository) GetMessages(ctx context.Context, tenantID string, u
 // GetMessageByID retrieves a message by its Infobip message ID
 func (r *PostgresRepository) GetMessageByID(ctx context.Context, tenantID string, messageID string) (*domain.Message, error) {
 	query := `
-		SELECT id, tenant_id, user_id, message_id, direction, text, timestamp, token_usage, metadata, created_at
+		SELECT id, tenant_id, user_id, message_id, direction, text, timestamp, token_usage, metadata, created_at, COALESCE(status, '')
 		FROM messages
 		WHERE tenant_id = $1 AND message_id = $2
 	`
@@ -244,7 +296,7 @@ func (r *PostgresRepository) GetMessageByID(ctx context.Context, tenantID string
 	err := r.db.QueryRow(ctx, query, tenantID, messageID).Scan(
 		&message.ID, &message.TenantID, &message.UserID, &message.MessageID,
 		&message.Direction, &message.Text, &message.Timestamp,
-		&tokenUsageJSON, &metadataJSON, &message.CreatedAt,
+		&tokenUsageJSON, &metadataJSON, &message.CreatedAt, &message.Status,
 	)
 
 	if err != nil {
@@ -267,6 +319,85 @@ func (r *PostgresRepository) GetMessageByID(ctx context.Context, tenantID string
 	return &message, nil
 }
 
+// GetLastInboundMessage retrieves the most recent message received from a user
+func (r *PostgresRepository) GetLastInboundMessage(ctx context.Context, tenantID string, userID uuid.UUID) (*domain.Message, error) {
+	query := `
+		SELECT id, tenant_id, user_id, message_id, direction, text, timestamp, token_usage, metadata, created_at, COALESCE(status, '')
+		FROM messages
+		WHERE tenant_id = $1 AND user_id = $2 AND direction = 'inbound'
+		ORDER BY timestamp DESC
+		LIMIT 1
+	`
+
+	var message domain.Message
+	var tokenUsageJSON, metadataJSON []byte
+
+	err := r.db.QueryRow(ctx, query, tenantID, userID).Scan(
+		&message.ID, &message.TenantID, &message.UserID, &message.MessageID,
+		&message.Direction, &message.Text, &message.Timestamp,
+		&tokenUsageJSON, &metadataJSON, &message.CreatedAt, &message.Status,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get last inbound message: %w", err)
+	}
+
+	if len(tokenUsageJSON) > 0 {
+		if err := json.Unmarshal(tokenUsageJSON, &message.TokenUsage); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal token usage: %w", err)
+		}
+	}
+
+	if err := json.Unmarshal(metadataJSON, &message.Metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+
+	return &message, nil
+}
+
+// GetReplyMessage retrieves the reply stored for an inbound message, identified
+// by its Infobip message ID
+func (r *PostgresRepository) GetReplyMessage(ctx context.Context, tenantID string, inboundMessageID string) (*domain.Message, error) {
+	query := `
+		SELECT id, tenant_id, user_id, message_id, direction, text, timestamp, token_usage, metadata, created_at, COALESCE(status, '')
+		FROM messages
+		WHERE tenant_id = $1 AND direction = 'outbound' AND metadata->>'reply_to' = $2
+		ORDER BY timestamp DESC
+		LIMIT 1
+	`
+
+	var message domain.Message
+	var tokenUsageJSON, metadataJSON []byte
+
+	err := r.db.QueryRow(ctx, query, tenantID, inboundMessageID).Scan(
+		&message.ID, &message.TenantID, &message.UserID, &message.MessageID,
+		&message.Direction, &message.Text, &message.Timestamp,
+		&tokenUsageJSON, &metadataJSON, &message.CreatedAt, &message.Status,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get reply message: %w", err)
+	}
+
+	if len(tokenUsageJSON) > 0 {
+		if err := json.Unmarshal(tokenUsageJSON, &message.TokenUsage); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal token usage: %w", err)
+		}
+	}
+
+	if err := json.Unmarshal(metadataJSON, &message.Metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+
+	return &message, nil
+}
+
 // GetAgents retrieves all agents
 func (r *PostgresRepository) GetAgents(ctx context.Context) ([]domain.AgentConfig, error) {
 	query := `
@@ -1148,4 +1279,10 @@ func (r *PostgresRepository) IsContactAllowed(ctx context.Context, tenantID, pho
 	}
 
 	return exists, nil
-}
\ No newline at end of file
+}
+
+// isUniqueViolation reports whether an error was caused by a unique constraint
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -51,6 +51,96 @@ func (m *MockRepository) GetMessageByID(ctx context.Context, tenantID string, me
 	return args.Get(0).(*domain.Message), args.Error(1)
 }
 
+func (m *MockRepository) GetMessagesSince(ctx context.Context, tenantID string, userID uuid.UUID, since time.Time, limit int) ([]domain.Message, error) {
+	args := m.Called(ctx, tenantID, userID, since, limit)
+	return args.Get(0).([]domain.Message), args.Error(1)
+}
+
+func (m *MockRepository) GetLastInboundMessage(ctx context.Context, tenantID string, userID uuid.UUID) (*domain.Message, error) {
+	args := m.Called(ctx, tenantID, userID)
+	return args.Get(0).(*domain.Message), args.Error(1)
+}
+
+func (m *MockRepository) GetReplyMessage(ctx context.Context, tenantID string, inboundMessageID string) (*domain.Message, error) {
+	args := m.Called(ctx, tenantID, inboundMessageID)
+	return args.Get(0).(*domain.Message), args.Error(1)
+}
+
+// Message status operations
+func (m *MockRepository) RecordMessageStatus(ctx context.Context, event *domain.MessageStatusEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetMessageStatusTimeline(ctx context.Context, tenantID string, messageID uuid.UUID) ([]domain.MessageStatusEvent, error) {
+	args := m.Called(ctx, tenantID, messageID)
+	return args.Get(0).([]domain.MessageStatusEvent), args.Error(1)
+}
+
+// Memory chunk operations
+func (m *MockRepository) GetLatestMemoryChunk(ctx context.Context, tenantID string, userID uuid.UUID, kind string) (*domain.MemoryChunk, error) {
+	args := m.Called(ctx, tenantID, userID, kind)
+	return args.Get(0).(*domain.MemoryChunk), args.Error(1)
+}
+
+// Media operations
+func (m *MockRepository) CreateMedia(ctx context.Context, media *domain.Media) error {
+	args := m.Called(ctx, media)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetMedia(ctx context.Context, tenantID string, mediaID uuid.UUID) (*domain.Media, error) {
+	args := m.Called(ctx, tenantID, mediaID)
+	return args.Get(0).(*domain.Media), args.Error(1)
+}
+
+// Document operations
+func (m *MockRepository) CreateDocument(ctx context.Context, document *domain.Document) error {
+	args := m.Called(ctx, document)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateDocument(ctx context.Context, document *domain.Document) error {
+	args := m.Called(ctx, document)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetDocument(ctx context.Context, tenantID string, documentID uuid.UUID) (*domain.Document, error) {
+	args := m.Called(ctx, tenantID, documentID)
+	return args.Get(0).(*domain.Document), args.Error(1)
+}
+
+func (m *MockRepository) GetDocumentByMediaID(ctx context.Context, tenantID string, mediaID uuid.UUID) (*domain.Document, error) {
+	args := m.Called(ctx, tenantID, mediaID)
+	return args.Get(0).(*domain.Document), args.Error(1)
+}
+
+func (m *MockRepository) ListDocuments(ctx context.Context, tenantID string, userID uuid.UUID, limit int) ([]domain.Document, error) {
+	args := m.Called(ctx, tenantID, userID, limit)
+	return args.Get(0).([]domain.Document), args.Error(1)
+}
+
+func (m *MockRepository) DeleteDocument(ctx context.Context, tenantID string, documentID uuid.UUID) error {
+	args := m.Called(ctx, tenantID, documentID)
+	return args.Error(0)
+}
+
+// Message template operations
+func (m *MockRepository) GetMessageTemplate(ctx context.Context, tenantID, purpose string) (*domain.MessageTemplate, error) {
+	args := m.Called(ctx, tenantID, purpose)
+	return args.Get(0).(*domain.MessageTemplate), args.Error(1)
+}
+
+func (m *MockRepository) ListMessageTemplates(ctx context.Context, tenantID string) ([]domain.MessageTemplate, error) {
+	args := m.Called(ctx, tenantID)
+	return args.Get(0).([]domain.MessageTemplate), args.Error(1)
+}
+
+func (m *MockRepository) UpsertMessageTemplate(ctx context.Context, template *domain.MessageTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
 func (m *MockRepository) GetAgents(ctx context.Context) ([]domain.AgentConfig, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]domain.AgentConfig), args.Error(1)
@@ -116,6 +206,36 @@ func (m *MockRepository) UpdateLLMProvider(ctx context.Context, config *domain.L
 	return args.Error(0)
 }
 
+func (m *MockRepository) GetContactRequest(ctx context.Context, tenantID, phoneNumber string) (*domain.ContactRequest, error) {
+	args := m.Called(ctx, tenantID, phoneNumber)
+	return args.Get(0).(*domain.ContactRequest), args.Error(1)
+}
+
+func (m *MockRepository) ListContactRequests(ctx context.Context, tenantID, status string) ([]domain.ContactRequest, error) {
+	args := m.Called(ctx, tenantID, status)
+	return args.Get(0).([]domain.ContactRequest), args.Error(1)
+}
+
+func (m *MockRepository) CreateContactRequest(ctx context.Context, request *domain.ContactRequest) error {
+	args := m.Called(ctx, request)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateContactRequest(ctx context.Context, request *domain.ContactRequest) error {
+	args := m.Called(ctx, request)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RecordLLMUsage(ctx context.Context, usage *domain.LLMUsage) error {
+	args := m.Called(ctx, usage)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetDailyLLMUsage(ctx context.Context, tenantID string, userID *uuid.UUID, from, to time.Time) ([]domain.DailyLLMUsage, error) {
+	args := m.Called(ctx, tenantID, userID, from, to)
+	return args.Get(0).([]domain.DailyLLMUsage), args.Error(1)
+}
+
 func (m *MockRepository) Ping(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
@@ -194,6 +314,37 @@ func (m *MockRepository) IsContactAllowed(ctx context.Context, tenantID, phoneNu
 	return args.Bool(0), args.Error(1)
 }
 
+// Reminder operations
+func (m *MockRepository) CreateReminder(ctx context.Context, reminder *domain.Reminder) error {
+	args := m.Called(ctx, reminder)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetReminder(ctx context.Context, tenantID string, reminderID uuid.UUID) (*domain.Reminder, error) {
+	args := m.Called(ctx, tenantID, reminderID)
+	return args.Get(0).(*domain.Reminder), args.Error(1)
+}
+
+func (m *MockRepository) UpdateReminder(ctx context.Context, reminder *domain.Reminder) error {
+	args := m.Called(ctx, reminder)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ReleaseReminder(ctx context.Context, reminder *domain.Reminder, lease time.Time) error {
+	args := m.Called(ctx, reminder, lease)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListReminders(ctx context.Context, tenantID string, userID uuid.UUID, status string, limit int) ([]domain.Reminder, error) {
+	args := m.Called(ctx, tenantID, userID, status, limit)
+	return args.Get(0).([]domain.Reminder), args.Error(1)
+}
+
+func (m *MockRepository) ClaimDueReminders(ctx context.Context, tenantID string, limit int, lease time.Duration) ([]domain.Reminder, error) {
+	args := m.Called(ctx, tenantID, limit, lease)
+	return args.Get(0).([]domain.Reminder), args.Error(1)
+}
+
 // Test Repository Interface Compliance
 func TestRepositoryInterface(t *testing.T) {
 	t.Run("mock repository implements interface", func(t *testing.T) {
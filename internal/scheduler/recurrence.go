@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"personal-assistant/internal/domain"
+)
+
+// maxRecurrenceSearchDays bounds the search for the next occurrence per unit of interval
+const maxRecurrenceSearchDays = 4 * 366
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// LoadLocation resolves an IANA time zone name, defaulting to UTC when empty
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// ValidateRecurrence checks that a recurrence rule is well formed
+func ValidateRecurrence(rule *domain.RecurrenceRule) error {
+	switch rule.Frequency {
+	case domain.RecurrenceDaily, domain.RecurrenceWeekly, domain.RecurrenceMonthly:
+	default:
+		return fmt.Errorf("unsupported frequency: %s", rule.Frequency)
+	}
+
+	if rule.Interval < 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if rule.Count < 0 {
+		return fmt.Errorf("count must be positive")
+	}
+
+	for _, code := range rule.ByWeekday {
+		if _, ok := weekdayCodes[strings.ToUpper(code)]; !ok {
+			return fmt.Errorf("invalid weekday: %s", code)
+		}
+	}
+
+	for _, day := range rule.ByMonthDay {
+		if day == 0 || day > 31 || day < -1 {
+			return fmt.Errorf("invalid month day: %d", day)
+		}
+	}
+
+	if rule.Until != nil && rule.Until.Before(rule.DTStart) {
+		return fmt.Errorf("until must be after the first occurrence")
+	}
+
+	return nil
+}
+
+// NextOccurrence returns the first occurrence of the rule strictly after the
+// given instant, computed in loc. It returns false once the series has ended
+// because of Until; Count is tracked by the caller through Reminder.Occurrences.
+func NextOccurrence(rule *domain.RecurrenceRule, loc *time.Location, after time.Time) (time.Time, bool) {
+	interval := rule.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	start := rule.DTStart.In(loc)
+	startDate := civilDate(start)
+
+	day := civilDate(after.In(loc))
+	if day.Before(startDate) {
+		day = startDate
+	}
+
+	for i := 0; i < maxRecurrenceSearchDays*interval; i++ {
+		if matchesRule(rule, interval, start, startDate, day) {
+			candidate := time.Date(day.Year(), day.Month(), day.Day(),
+				start.Hour(), start.Minute(), start.Second(), 0, loc)
+
+			if candidate.After(after) && !candidate.Before(rule.DTStart) {
+				if rule.Until != nil && candidate.After(*rule.Until) {
+					return time.Time{}, false
+				}
+				return candidate, true
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return time.Time{}, false
+}
+
+// matchesRule reports whether the calendar day belongs to the series
+func matchesRule(rule *domain.RecurrenceRule, interval int, start, startDate, day time.Time) bool {
+	switch rule.Frequency {
+	case domain.RecurrenceDaily:
+		return daysBetween(startDate, day)%interval == 0
+
+	case domain.RecurrenceWeekly:
+		weeks := daysBetween(weekStart(startDate), weekStart(day)) / 7
+		if weeks%interval != 0 {
+			return false
+		}
+		if len(rule.ByWeekday) == 0 {
+			return day.Weekday() == start.Weekday()
+		}
+		for _, code := range rule.ByWeekday {
+			if weekdayCodes[strings.ToUpper(code)] == day.Weekday() {
+				return true
+			}
+		}
+		return false
+
+	case domain.RecurrenceMonthly:
+		months := (day.Year()-startDate.Year())*12 + int(day.Month()-startDate.Month())
+		if months%interval != 0 {
+			return false
+		}
+		if len(rule.ByMonthDay) == 0 {
+			return day.Day() == start.Day()
+		}
+		lastDay := civilDate(time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC)).Day()
+		for _, monthDay := range rule.ByMonthDay {
+			if monthDay == day.Day() || (monthDay == -1 && day.Day() == lastDay) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return false
+}
+
+// civilDate returns midnight UTC of the calendar day of t, so that day
+// arithmetic is not affected by DST transitions
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// weekStart returns the Monday on or before the given civil date
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// daysBetween returns the number of whole days between two civil dates
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
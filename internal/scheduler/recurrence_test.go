@@ -0,0 +1,105 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/scheduler"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	t.Run("daily with interval", func(t *testing.T) {
+		rule := &domain.RecurrenceRule{
+			Frequency: domain.RecurrenceDaily,
+			Interval:  2,
+			DTStart:   time.Date(2024, 3, 1, 8, 30, 0, 0, saoPaulo),
+		}
+
+		next, ok := scheduler.NextOccurrence(rule, saoPaulo, time.Date(2024, 3, 1, 9, 0, 0, 0, saoPaulo))
+		require.True(t, ok)
+		assert.Equal(t, time.Date(2024, 3, 3, 8, 30, 0, 0, saoPaulo), next)
+	})
+
+	t.Run("every monday and wednesday", func(t *testing.T) {
+		rule := &domain.RecurrenceRule{
+			Frequency: domain.RecurrenceWeekly,
+			ByWeekday: []string{"MO", "WE"},
+			DTStart:   time.Date(2024, 3, 4, 9, 0, 0, 0, saoPaulo), // Monday
+		}
+
+		next, ok := scheduler.NextOccurrence(rule, saoPaulo, time.Date(2024, 3, 4, 9, 0, 0, 0, saoPaulo))
+		require.True(t, ok)
+		assert.Equal(t, time.Date(2024, 3, 6, 9, 0, 0, 0, saoPaulo), next)
+
+		next, ok = scheduler.NextOccurrence(rule, saoPaulo, next)
+		require.True(t, ok)
+		assert.Equal(t, time.Date(2024, 3, 11, 9, 0, 0, 0, saoPaulo), next)
+	})
+
+	t.Run("every other week", func(t *testing.T) {
+		rule := &domain.RecurrenceRule{
+			Frequency: domain.RecurrenceWeekly,
+			Interval:  2,
+			DTStart:   time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC), // Tuesday
+		}
+
+		next, ok := scheduler.NextOccurrence(rule, time.UTC, time.Date(2024, 3, 5, 18, 0, 0, 0, time.UTC))
+		require.True(t, ok)
+		assert.Equal(t, time.Date(2024, 3, 19, 18, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("first and last day of the month", func(t *testing.T) {
+		rule := &domain.RecurrenceRule{
+			Frequency:  domain.RecurrenceMonthly,
+			ByMonthDay: []int{1, -1},
+			DTStart:    time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		}
+
+		next, ok := scheduler.NextOccurrence(rule, time.UTC, time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC))
+		require.True(t, ok)
+		assert.Equal(t, time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC), next)
+	})
+
+	t.Run("keeps wall clock time across DST", func(t *testing.T) {
+		rule := &domain.RecurrenceRule{
+			Frequency: domain.RecurrenceDaily,
+			DTStart:   time.Date(2024, 3, 9, 9, 0, 0, 0, newYork),
+		}
+
+		next, ok := scheduler.NextOccurrence(rule, newYork, time.Date(2024, 3, 9, 9, 0, 0, 0, newYork))
+		require.True(t, ok)
+		assert.Equal(t, 9, next.In(newYork).Hour())
+		assert.Equal(t, 10, next.In(newYork).Day())
+	})
+
+	t.Run("stops after until", func(t *testing.T) {
+		until := time.Date(2024, 3, 2, 23, 0, 0, 0, time.UTC)
+		rule := &domain.RecurrenceRule{
+			Frequency: domain.RecurrenceDaily,
+			Until:     &until,
+			DTStart:   time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+		}
+
+		_, ok := scheduler.NextOccurrence(rule, time.UTC, time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC))
+		assert.False(t, ok)
+	})
+}
+
+func TestValidateRecurrence(t *testing.T) {
+	start := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, scheduler.ValidateRecurrence(&domain.RecurrenceRule{Frequency: "weekly", ByWeekday: []string{"mo"}, DTStart: start}))
+	assert.Error(t, scheduler.ValidateRecurrence(&domain.RecurrenceRule{Frequency: "yearly", DTStart: start}))
+	assert.Error(t, scheduler.ValidateRecurrence(&domain.RecurrenceRule{Frequency: "weekly", ByWeekday: []string{"XX"}, DTStart: start}))
+	assert.Error(t, scheduler.ValidateRecurrence(&domain.RecurrenceRule{Frequency: "monthly", ByMonthDay: []int{32}, DTStart: start}))
+}
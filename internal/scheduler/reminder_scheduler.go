@@ -0,0 +1,278 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+	"personal-assistant/internal/outbound"
+)
+
+// ReminderScheduler periodically claims due reminders for every tenant and
+// delivers them over WhatsApp. Claiming is done with row-level leases in the
+// database, so several server replicas can run the scheduler concurrently.
+type ReminderScheduler struct {
+	tenantManager domain.TenantManager
+	sender        *outbound.Sender
+	config        *config.SchedulerConfig
+	logger        *log.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewReminderScheduler creates a new reminder scheduler
+func NewReminderScheduler(
+	tenantManager domain.TenantManager,
+	infobipClient domain.InfobipClient,
+	cfg *config.SchedulerConfig,
+	logger *log.Logger,
+) *ReminderScheduler {
+	return &ReminderScheduler{
+		tenantManager: tenantManager,
+		sender:        outbound.NewSender(infobipClient, logger),
+		config:        cfg,
+		logger:        logger.WithComponent("reminder_scheduler"),
+	}
+}
+
+// Start launches the polling loop in a background goroutine
+func (s *ReminderScheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.config.PollInterval)
+		defer ticker.Stop()
+
+		s.logger.Info().
+			Dur("poll_interval", s.config.PollInterval).
+			Int("batch_size", s.config.BatchSize).
+			Msg("reminder scheduler started")
+
+		for {
+			s.RunOnce(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the polling loop and waits for in-flight deliveries to finish
+func (s *ReminderScheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info().Msg("reminder scheduler stopped")
+}
+
+// RunOnce processes due reminders for all tenants a single time
+func (s *ReminderScheduler) RunOnce(ctx context.Context) {
+	tenants, err := s.tenantManager.ListTenants()
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list tenants")
+		return
+	}
+
+	for i := range tenants {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err := s.processTenant(ctx, &tenants[i]); err != nil {
+			s.logger.Error().
+				Err(err).
+				Str("tenant_id", tenants[i].ID).
+				Msg("failed to process reminders for tenant")
+		}
+	}
+}
+
+// processTenant claims and delivers the due reminders of a single tenant
+func (s *ReminderScheduler) processTenant(ctx context.Context, tenant *domain.Tenant) error {
+	ctx = context.WithValue(ctx, log.TenantIDKey, tenant.ID)
+
+	repo, err := s.tenantManager.GetRepository(tenant.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	reminders, err := repo.ClaimDueReminders(ctx, tenant.ID, s.config.BatchSize, s.config.LeaseTimeout)
+	if err != nil {
+		return err
+	}
+
+	for i := range reminders {
+		s.processReminder(ctx, repo, tenant, &reminders[i])
+	}
+
+	return nil
+}
+
+// processReminder delivers a claimed reminder and records the outcome
+func (s *ReminderScheduler) processReminder(ctx context.Context, repo domain.Repository, tenant *domain.Tenant, reminder *domain.Reminder) {
+	logger := s.logger.WithContext(ctx).WithTenant(tenant.ID).WithUser(reminder.UserID.String())
+
+	var lease time.Time
+	if reminder.LockedUntil != nil {
+		lease = *reminder.LockedUntil
+	}
+
+	sent, err := s.deliver(ctx, repo, tenant, reminder)
+	now := time.Now().UTC()
+	reminder.LockedUntil = nil
+
+	if err == nil {
+		reminder.Status = domain.ReminderStatusSent
+		reminder.SentAt = &now
+		reminder.LastError = ""
+		s.advanceRecurrence(reminder, now)
+	} else {
+		reminder.LastError = err.Error()
+		if reminder.Attempts >= reminder.MaxAttempts {
+			reminder.Status = domain.ReminderStatusFailed
+			s.advanceRecurrence(reminder, now)
+		} else {
+			reminder.ScheduledAt = now.Add(s.retryDelay(reminder.Attempts))
+		}
+
+		logger.Warn().
+			Err(err).
+			Str("reminder_id", reminder.ID.String()).
+			Int("attempt", reminder.Attempts).
+			Str("status", reminder.Status).
+			Msg("reminder delivery failed")
+	}
+
+	// A reminder cancelled or snoozed during delivery keeps the user's change
+	if err := repo.ReleaseReminder(ctx, reminder, lease); err != nil {
+		if errors.Is(err, domain.ErrReminderLeaseLost) {
+			logger.Info().
+				Str("reminder_id", reminder.ID.String()).
+				Msg("reminder changed during delivery, keeping its new state")
+		} else {
+			logger.Error().
+				Err(err).
+				Str("reminder_id", reminder.ID.String()).
+				Msg("failed to update reminder status")
+			return
+		}
+	}
+
+	if sent != nil {
+		s.storeOutboundMessage(ctx, repo, reminder, sent)
+
+		logger.Info().
+			Str("reminder_id", reminder.ID.String()).
+			Str("message_id", sent.Message.MessageID).
+			Msg("reminder delivered")
+	}
+}
+
+// advanceRecurrence moves a finished occurrence of a recurring reminder to
+// the next one, leaving the reminder in its final status when the series ends
+func (s *ReminderScheduler) advanceRecurrence(reminder *domain.Reminder, now time.Time) {
+	if reminder.Recurrence == nil {
+		return
+	}
+
+	reminder.Occurrences++
+	if reminder.Recurrence.Count > 0 && reminder.Occurrences >= reminder.Recurrence.Count {
+		return
+	}
+
+	loc, err := LoadLocation(reminder.TimeZone)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("reminder_id", reminder.ID.String()).Msg("falling back to UTC for recurrence")
+		loc = time.UTC
+	}
+
+	// Occurrences missed while the reminder was retrying or the server was down are skipped
+	next, ok := NextOccurrence(reminder.Recurrence, loc, now)
+	if !ok {
+		return
+	}
+
+	reminder.Status = domain.ReminderStatusScheduled
+	reminder.ScheduledAt = next
+	reminder.Attempts = 0
+}
+
+// deliver sends the reminder text to the user from the tenant's WABA number,
+// using the tenant's reminder template once the customer service window has closed
+func (s *ReminderScheduler) deliver(ctx context.Context, repo domain.Repository, tenant *domain.Tenant, reminder *domain.Reminder) (*outbound.Delivery, error) {
+	user, err := repo.GetUserByID(ctx, tenant.ID, reminder.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found: %s", reminder.UserID)
+	}
+
+	sent, err := s.sender.Send(ctx, repo, tenant, user, &outbound.Notification{
+		Purpose: domain.TemplatePurposeReminder,
+		Text:    reminder.Text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send reminder via Infobip: %w", err)
+	}
+
+	return sent, nil
+}
+
+// storeOutboundMessage records the delivered reminder in the conversation history
+func (s *ReminderScheduler) storeOutboundMessage(ctx context.Context, repo domain.Repository, reminder *domain.Reminder, sent *outbound.Delivery) {
+	messageID := sent.Message.MessageID
+	if messageID == "" {
+		messageID = fmt.Sprintf("out_%d", time.Now().UnixNano())
+	}
+
+	message := &domain.Message{
+		ID:        uuid.New(),
+		TenantID:  reminder.TenantID,
+		UserID:    reminder.UserID,
+		MessageID: messageID,
+		Direction: "outbound",
+		Text:      sent.Text,
+		Timestamp: time.Now().UTC(),
+		Metadata: map[string]interface{}{
+			"source":      "reminder",
+			"reminder_id": reminder.ID.String(),
+		},
+		Status:    domain.MessageStatusSent,
+		CreatedAt: time.Now().UTC(),
+	}
+	if sent.Template != nil {
+		message.Metadata["template"] = sent.Template.Name
+	}
+
+	if err := repo.CreateMessage(ctx, message); err != nil {
+		s.logger.WithContext(ctx).Warn().
+			Err(err).
+			Str("reminder_id", reminder.ID.String()).
+			Msg("failed to store reminder message")
+	}
+}
+
+// retryDelay returns the exponential backoff delay after the given attempt
+func (s *ReminderScheduler) retryDelay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	return s.config.RetryBackoff * time.Duration(1<<uint(attempt-1))
+}
@@ -0,0 +1,306 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"personal-assistant/internal/config"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+	"personal-assistant/internal/scheduler"
+)
+
+// MockRepository implements the reminder-related subset of domain.Repository
+type MockRepository struct {
+	domain.Repository
+	mock.Mock
+}
+
+func (m *MockRepository) ClaimDueReminders(ctx context.Context, tenantID string, limit int, lease time.Duration) ([]domain.Reminder, error) {
+	args := m.Called(ctx, tenantID, limit, lease)
+	return args.Get(0).([]domain.Reminder), args.Error(1)
+}
+
+func (m *MockRepository) ReleaseReminder(ctx context.Context, reminder *domain.Reminder, lease time.Time) error {
+	args := m.Called(ctx, reminder, lease)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetUserByID(ctx context.Context, tenantID string, userID uuid.UUID) (*domain.User, error) {
+	args := m.Called(ctx, tenantID, userID)
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockRepository) GetLastInboundMessage(ctx context.Context, tenantID string, userID uuid.UUID) (*domain.Message, error) {
+	args := m.Called(ctx, tenantID, userID)
+	return args.Get(0).(*domain.Message), args.Error(1)
+}
+
+func (m *MockRepository) GetMessageTemplate(ctx context.Context, tenantID, purpose string) (*domain.MessageTemplate, error) {
+	args := m.Called(ctx, tenantID, purpose)
+	return args.Get(0).(*domain.MessageTemplate), args.Error(1)
+}
+
+func (m *MockRepository) CreateMessage(ctx context.Context, message *domain.Message) error {
+	args := m.Called(ctx, message)
+	return args.Error(0)
+}
+
+// MockTenantManager implements the subset of domain.TenantManager used by the scheduler
+type MockTenantManager struct {
+	domain.TenantManager
+	mock.Mock
+}
+
+func (m *MockTenantManager) ListTenants() ([]domain.Tenant, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Tenant), args.Error(1)
+}
+
+func (m *MockTenantManager) GetRepository(tenantID string) (domain.Repository, error) {
+	args := m.Called(tenantID)
+	return args.Get(0).(domain.Repository), args.Error(1)
+}
+
+// MockInfobipClient implements domain.InfobipClient for testing
+type MockInfobipClient struct {
+	mock.Mock
+}
+
+func (m *MockInfobipClient) SendText(ctx context.Context, from, to, text string, messageIDRef ...string) (*domain.InfobipMessage, error) {
+	args := m.Called(ctx, from, to, text)
+	msg, _ := args.Get(0).(*domain.InfobipMessage)
+	return msg, args.Error(1)
+}
+
+func (m *MockInfobipClient) SendButtons(ctx context.Context, from, to, text string, buttons []domain.InfobipReplyButton, messageIDRef ...string) (*domain.InfobipMessage, error) {
+	args := m.Called(ctx, from, to, text, buttons)
+	msg, _ := args.Get(0).(*domain.InfobipMessage)
+	return msg, args.Error(1)
+}
+
+func (m *MockInfobipClient) SendList(ctx context.Context, from, to, text string, list *domain.InfobipList, messageIDRef ...string) (*domain.InfobipMessage, error) {
+	args := m.Called(ctx, from, to, text, list)
+	msg, _ := args.Get(0).(*domain.InfobipMessage)
+	return msg, args.Error(1)
+}
+
+func (m *MockInfobipClient) SendTemplate(ctx context.Context, from, to string, template *domain.InfobipTemplate, messageIDRef ...string) (*domain.InfobipMessage, error) {
+	args := m.Called(ctx, from, to, template)
+	msg, _ := args.Get(0).(*domain.InfobipMessage)
+	return msg, args.Error(1)
+}
+
+func (m *MockInfobipClient) SendMessage(ctx context.Context, message *domain.InfobipMessage) (*domain.InfobipMessage, error) {
+	args := m.Called(ctx, message)
+	msg, _ := args.Get(0).(*domain.InfobipMessage)
+	return msg, args.Error(1)
+}
+
+func (m *MockInfobipClient) DownloadMedia(ctx context.Context, url string) (*domain.MediaContent, error) {
+	args := m.Called(ctx, url)
+	content, _ := args.Get(0).(*domain.MediaContent)
+	return content, args.Error(1)
+}
+
+func newTestScheduler(tm domain.TenantManager, client domain.InfobipClient) *scheduler.ReminderScheduler {
+	cfg := &config.SchedulerConfig{
+		PollInterval: time.Second,
+		BatchSize:    10,
+		LeaseTimeout: time.Minute,
+		RetryBackoff: time.Minute,
+	}
+	return scheduler.NewReminderScheduler(tm, client, cfg, log.Init("error"))
+}
+
+func TestReminderScheduler(t *testing.T) {
+	tenant := domain.Tenant{ID: "test-tenant", WABANumber: "+15550000000"}
+	user := &domain.User{ID: uuid.New(), TenantID: tenant.ID, Phone: "+15551111111"}
+
+	// Users wrote recently unless a test moves their last message out of the customer service window
+	lastInbound := time.Now().Add(-time.Hour)
+
+	// lease is the locked_until set on the reminders returned by ClaimDueReminders
+	lease := time.Now().UTC().Add(time.Minute).Truncate(time.Microsecond)
+
+	setup := func(reminder domain.Reminder) (*MockTenantManager, *MockRepository, *MockInfobipClient) {
+		reminder.LockedUntil = &lease
+		repo := new(MockRepository)
+		tm := new(MockTenantManager)
+		client := new(MockInfobipClient)
+
+		tm.On("ListTenants").Return([]domain.Tenant{tenant}, nil)
+		tm.On("GetRepository", tenant.ID).Return(repo, nil)
+		repo.On("ClaimDueReminders", mock.Anything, tenant.ID, 10, time.Minute).Return([]domain.Reminder{reminder}, nil)
+		repo.On("GetUserByID", mock.Anything, tenant.ID, user.ID).Return(user, nil)
+		repo.On("GetLastInboundMessage", mock.Anything, tenant.ID, user.ID).
+			Return(&domain.Message{Direction: "inbound", Timestamp: lastInbound}, nil)
+
+		return tm, repo, client
+	}
+
+	t.Run("marks reminder sent after delivery", func(t *testing.T) {
+		reminder := domain.Reminder{
+			ID: uuid.New(), TenantID: tenant.ID, UserID: user.ID, Text: "Pay rent",
+			Status: domain.ReminderStatusScheduled, Attempts: 1, MaxAttempts: 3,
+		}
+		tm, repo, client := setup(reminder)
+
+		client.On("SendText", mock.Anything, tenant.WABANumber, user.Phone, "Pay rent").
+			Return(&domain.InfobipMessage{MessageID: "ib-123"}, nil)
+		repo.On("ReleaseReminder", mock.Anything, mock.MatchedBy(func(r *domain.Reminder) bool {
+			return r.Status == domain.ReminderStatusSent && r.SentAt != nil && r.LockedUntil == nil
+		}), lease).Return(nil)
+		repo.On("CreateMessage", mock.Anything, mock.MatchedBy(func(m *domain.Message) bool {
+			return m.MessageID == "ib-123" && m.Direction == "outbound"
+		})).Return(nil)
+
+		newTestScheduler(tm, client).RunOnce(context.Background())
+
+		repo.AssertExpectations(t)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("reschedules failed delivery with backoff", func(t *testing.T) {
+		reminder := domain.Reminder{
+			ID: uuid.New(), TenantID: tenant.ID, UserID: user.ID, Text: "Call mom",
+			Status: domain.ReminderStatusScheduled, Attempts: 2, MaxAttempts: 3,
+		}
+		tm, repo, client := setup(reminder)
+
+		client.On("SendText", mock.Anything, tenant.WABANumber, user.Phone, "Call mom").
+			Return(nil, errors.New("timeout"))
+
+		var updated *domain.Reminder
+		repo.On("ReleaseReminder", mock.Anything, mock.Anything, lease).
+			Run(func(args mock.Arguments) { updated = args.Get(1).(*domain.Reminder) }).
+			Return(nil)
+
+		start := time.Now()
+		newTestScheduler(tm, client).RunOnce(context.Background())
+
+		if assert.NotNil(t, updated) {
+			assert.Equal(t, domain.ReminderStatusScheduled, updated.Status)
+			assert.Contains(t, updated.LastError, "timeout")
+			assert.WithinDuration(t, start.Add(2*time.Minute), updated.ScheduledAt, 5*time.Second)
+		}
+		repo.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("reschedules recurring reminder after delivery", func(t *testing.T) {
+		reminder := domain.Reminder{
+			ID: uuid.New(), TenantID: tenant.ID, UserID: user.ID, Text: "Standup",
+			Status: domain.ReminderStatusScheduled, Attempts: 1, MaxAttempts: 3, TimeZone: "UTC",
+			Recurrence: &domain.RecurrenceRule{
+				Frequency: domain.RecurrenceDaily,
+				DTStart:   time.Now().UTC().Add(-time.Hour),
+			},
+		}
+		tm, repo, client := setup(reminder)
+
+		client.On("SendText", mock.Anything, tenant.WABANumber, user.Phone, "Standup").
+			Return(&domain.InfobipMessage{MessageID: "ib-456"}, nil)
+		repo.On("CreateMessage", mock.Anything, mock.Anything).Return(nil)
+
+		var updated *domain.Reminder
+		repo.On("ReleaseReminder", mock.Anything, mock.Anything, lease).
+			Run(func(args mock.Arguments) { updated = args.Get(1).(*domain.Reminder) }).
+			Return(nil)
+
+		newTestScheduler(tm, client).RunOnce(context.Background())
+
+		if assert.NotNil(t, updated) {
+			assert.Equal(t, domain.ReminderStatusScheduled, updated.Status)
+			assert.Equal(t, 1, updated.Occurrences)
+			assert.Equal(t, 0, updated.Attempts)
+			assert.WithinDuration(t, reminder.Recurrence.DTStart.Add(24*time.Hour), updated.ScheduledAt, time.Second)
+		}
+	})
+
+	t.Run("sends reminder template outside the customer service window", func(t *testing.T) {
+		reminder := domain.Reminder{
+			ID: uuid.New(), TenantID: tenant.ID, UserID: user.ID, Text: "Renew passport",
+			Status: domain.ReminderStatusScheduled, Attempts: 1, MaxAttempts: 3,
+		}
+		lastInbound = time.Now().Add(-25 * time.Hour)
+		defer func() { lastInbound = time.Now().Add(-time.Hour) }()
+		tm, repo, client := setup(reminder)
+
+		repo.On("GetMessageTemplate", mock.Anything, tenant.ID, domain.TemplatePurposeReminder).
+			Return((*domain.MessageTemplate)(nil), nil)
+		repo.On("GetMessageTemplate", mock.Anything, tenant.ID, domain.TemplatePurposeDefault).
+			Return(&domain.MessageTemplate{
+				Name:         "assistant_update",
+				Language:     "en",
+				Placeholders: []string{"text"},
+				Body:         "Update from your assistant: {{1}}",
+			}, nil)
+
+		client.On("SendTemplate", mock.Anything, tenant.WABANumber, user.Phone, &domain.InfobipTemplate{
+			Name:         "assistant_update",
+			Language:     "en",
+			Placeholders: []string{"Renew passport"},
+		}).Return(&domain.InfobipMessage{MessageID: "ib-789"}, nil)
+		repo.On("ReleaseReminder", mock.Anything, mock.MatchedBy(func(r *domain.Reminder) bool {
+			return r.Status == domain.ReminderStatusSent
+		}), lease).Return(nil)
+		repo.On("CreateMessage", mock.Anything, mock.MatchedBy(func(m *domain.Message) bool {
+			return m.MessageID == "ib-789" &&
+				m.Text == "Update from your assistant: Renew passport" &&
+				m.Metadata["template"] == "assistant_update"
+		})).Return(nil)
+
+		newTestScheduler(tm, client).RunOnce(context.Background())
+
+		repo.AssertExpectations(t)
+		client.AssertExpectations(t)
+		client.AssertNotCalled(t, "SendText", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("marks reminder failed after max attempts", func(t *testing.T) {
+		reminder := domain.Reminder{
+			ID: uuid.New(), TenantID: tenant.ID, UserID: user.ID, Text: "Water plants",
+			Status: domain.ReminderStatusScheduled, Attempts: 3, MaxAttempts: 3,
+		}
+		tm, repo, client := setup(reminder)
+
+		client.On("SendText", mock.Anything, tenant.WABANumber, user.Phone, "Water plants").
+			Return(nil, errors.New("invalid destination"))
+		repo.On("ReleaseReminder", mock.Anything, mock.MatchedBy(func(r *domain.Reminder) bool {
+			return r.Status == domain.ReminderStatusFailed
+		}), lease).Return(nil)
+
+		newTestScheduler(tm, client).RunOnce(context.Background())
+
+		repo.AssertExpectations(t)
+	})
+	t.Run("keeps a reminder cancelled during delivery", func(t *testing.T) {
+		reminder := domain.Reminder{
+			ID: uuid.New(), TenantID: tenant.ID, UserID: user.ID, Text: "Gym",
+			Status: domain.ReminderStatusScheduled, Attempts: 1, MaxAttempts: 3, TimeZone: "UTC",
+			Recurrence: &domain.RecurrenceRule{
+				Frequency: domain.RecurrenceDaily,
+				DTStart:   time.Now().UTC().Add(-time.Hour),
+			},
+		}
+		tm, repo, client := setup(reminder)
+
+		client.On("SendText", mock.Anything, tenant.WABANumber, user.Phone, "Gym").
+			Return(&domain.InfobipMessage{MessageID: "ib-999"}, nil)
+		repo.On("ReleaseReminder", mock.Anything, mock.Anything, lease).Return(domain.ErrReminderLeaseLost)
+		repo.On("CreateMessage", mock.Anything, mock.MatchedBy(func(m *domain.Message) bool {
+			return m.MessageID == "ib-999"
+		})).Return(nil)
+
+		newTestScheduler(tm, client).RunOnce(context.Background())
+
+		// The delivered message is still recorded in the conversation history
+		repo.AssertExpectations(t)
+	})
+}
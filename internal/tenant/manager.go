@@ -24,11 +24,12 @@ type Manager struct {
 	logger        *log.Logger
 
 	// Caches
-	tenants      map[string]*domain.Tenant       // WABA number -> Tenant
-	tenantsByID  map[string]*domain.Tenant       // Tenant ID -> Tenant
-	repositories map[string]domain.Repository    // Tenant ID -> Repository
-	vectorStores map[string]domain.VectorStore   // Tenant ID -> VectorStore
-	llmProviders map[string]*llm.ProviderManager // Tenant ID -> LLM Provider Manager
+	tenants        map[string]*domain.Tenant       // WABA number -> Tenant
+	tenantsByID    map[string]*domain.Tenant       // Tenant ID -> Tenant
+	repositories   map[string]domain.Repository    // Tenant ID -> Repository
+	vectorStores   map[string]domain.VectorStore   // Tenant ID -> VectorStore
+	llmProviders   map[string]*llm.ProviderManager // Tenant ID -> LLM Provider Manager
+	toolRegistries map[string]domain.ToolRegistry  // Tenant ID -> Tool Registry
 
 	mutex sync.RWMutex
 }
@@ -42,14 +43,15 @@ func newYAMLManager(cfg *config.Config, logger *log.Logger) (*Manager, error) {
 	}
 
 	manager := &Manager{
-		config:        cfg,
-		tenantsConfig: tenantsConfig,
-		logger:        logger,
-		tenants:       make(map[string]*domain.Tenant),
-		tenantsByID:   make(map[string]*domain.Tenant),
-		repositories:  make(map[string]domain.Repository),
-		vectorStores:  make(map[string]domain.VectorStore),
-		llmProviders:  make(map[string]*llm.ProviderManager),
+		config:         cfg,
+		tenantsConfig:  tenantsConfig,
+		logger:         logger,
+		tenants:        make(map[string]*domain.Tenant),
+		tenantsByID:    make(map[string]*domain.Tenant),
+		repositories:   make(map[string]domain.Repository),
+		vectorStores:   make(map[string]domain.VectorStore),
+		llmProviders:   make(map[string]*llm.ProviderManager),
+		toolRegistries: make(map[string]domain.ToolRegistry),
 	}
 
 	// Initialize tenants
@@ -213,46 +215,174 @@ func (m *Manager) GetVectorStore(tenantID string) (domain.VectorStore, error) {
 	return store, nil
 }
 
-// GetLLMProvider returns the default LLM provider for a tenant
+// GetLLMProvider returns the LLM provider for a tenant, failing over between
+// the tenant's enabled providers with the default one first
 func (m *Manager) GetLLMProvider(tenantID string) (domain.LLMProvider, error) {
+	providerManager, err := m.providerManager(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	providerConfigs, err := m.enabledLLMProviders(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get or create the provider chain
+	provider, err := providerManager.GetFallbackProvider(tenantID, providerConfigs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LLM provider: %w", err)
+	}
+
+	return provider, nil
+}
+
+// GetTranscriber returns a speech-to-text transcriber backed by the tenant's default LLM provider
+func (m *Manager) GetTranscriber(tenantID string) (domain.Transcriber, error) {
+	providerManager, providerConfig, err := m.defaultLLMProvider(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	transcriber, err := providerManager.GetTranscriber(tenantID, providerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transcriber: %w", err)
+	}
+
+	return transcriber, nil
+}
+
+// GetToolRegistry returns the tool registry of a tenant, building it once
+// with the tenant's own vector store, LLM provider and repository
+func (m *Manager) GetToolRegistry(tenantID string) (domain.ToolRegistry, error) {
 	m.mutex.RLock()
-	providerManager, exists := m.llmProviders[tenantID]
+	registry, exists := m.toolRegistries[tenantID]
 	m.mutex.RUnlock()
 
+	if exists {
+		return registry, nil
+	}
+
+	// Resolve the tenant's resources before taking the lock, as they take it themselves
+	tenant, err := m.GetTenantByID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	vectorStore, err := m.GetVectorStore(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vector store: %w", err)
+	}
+
+	llmProvider, err := m.GetLLMProvider(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LLM provider: %w", err)
+	}
+
+	repo, err := m.GetRepository(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Check again in case another goroutine created it
+	if registry, exists := m.toolRegistries[tenantID]; exists {
+		return registry, nil
+	}
+
+	tenantRegistry, err := newToolRegistry(tenant, vectorStore, llmProvider, repo, m.logger.WithTenant(tenantID))
+	if err != nil {
+		return nil, err
+	}
+	m.toolRegistries[tenantID] = tenantRegistry
+
+	m.logger.Info().
+		Str("tenant_id", tenantID).
+		Int("tools_enabled", len(tenantRegistry.GetToolsForTenant(tenantID))).
+		Msg("tool registry created for tenant")
+
+	return tenantRegistry, nil
+}
+
+// providerManager returns the tenant's provider manager, creating it if needed
+func (m *Manager) providerManager(tenantID string) (*llm.ProviderManager, error) {
+	// Token usage is recorded in the tenant's database
+	repo, err := m.GetRepository(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	providerManager, exists := m.llmProviders[tenantID]
 	if !exists {
-		// Create provider manager if it doesn't exist
-		m.mutex.Lock()
-		providerManager = llm.NewProviderManager(m.logger.WithTenant(tenantID))
+		providerManager = llm.NewProviderManager(m.logger.WithTenant(tenantID), &m.config.LLMFallback, repo)
 		m.llmProviders[tenantID] = providerManager
-		m.mutex.Unlock()
 	}
 
-	// Get repository to fetch LLM provider config
+	return providerManager, nil
+}
+
+// enabledLLMProviders returns the tenant's enabled LLM provider configs, default first
+func (m *Manager) enabledLLMProviders(tenantID string) ([]domain.LLMProviderConfig, error) {
+	// Get repository to fetch LLM provider configs
 	repo, err := m.GetRepository(tenantID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get repository: %w", err)
 	}
 
-	// Get default LLM provider config for tenant
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	providerConfig, err := repo.GetDefaultLLMProvider(ctx, tenantID)
+	providerConfigs, err := repo.GetLLMProviders(ctx, tenantID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get default LLM provider config: %w", err)
+		return nil, fmt.Errorf("failed to get LLM provider configs: %w", err)
 	}
 
-	if providerConfig == nil {
-		return nil, fmt.Errorf("no default LLM provider configured for tenant: %s", tenantID)
+	var enabled []domain.LLMProviderConfig
+	for _, providerConfig := range providerConfigs {
+		if providerConfig.Enabled {
+			enabled = append(enabled, providerConfig)
+		}
+	}
+
+	if len(enabled) == 0 {
+		return nil, fmt.Errorf("no enabled LLM provider configured for tenant: %s", tenantID)
 	}
 
-	// Get or create the provider
-	provider, err := providerManager.GetProvider(tenantID, providerConfig)
+	return enabled, nil
+}
+
+// defaultLLMProvider returns the tenant's provider manager and default LLM provider config
+func (m *Manager) defaultLLMProvider(tenantID string) (*llm.ProviderManager, *domain.LLMProviderConfig, error) {
+	providerManager, err := m.providerManager(tenantID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get LLM provider: %w", err)
+		return nil, nil, err
 	}
 
-	return provider, nil
+	// Get repository to fetch LLM provider config
+	repo, err := m.GetRepository(tenantID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	// Get default LLM provider config for tenant
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	providerConfig, err := repo.GetDefaultLLMProvider(ctx, tenantID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get default LLM provider config: %w", err)
+	}
+
+	if providerConfig == nil {
+		return nil, nil, fmt.Errorf("no default LLM provider configured for tenant: %s", tenantID)
+	}
+
+	return providerManager, providerConfig, nil
 }
 
 // initializeTenants initializes all tenants from configuration
@@ -328,6 +458,11 @@ func (m *Manager) generateTenantID(wabaNumber string) string {
 	return fmt.Sprintf("tenant_%x", hash[:8]) // Use first 8 bytes of hash
 }
 
+// GetConfigRepository returns ErrTenantConfigReadOnly, as tenants are configured in the YAML file
+func (m *Manager) GetConfigRepository() (domain.Repository, error) {
+	return nil, domain.ErrTenantConfigReadOnly
+}
+
 // ReloadTenants reloads tenant configurations from file
 func (m *Manager) ReloadTenants() error {
 	m.logger.Info().Msg("reloading tenant configurations")
@@ -341,9 +476,54 @@ func (m *Manager) ReloadTenants() error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	if err := m.replaceTenants(tenantsConfig); err != nil {
+		return err
+	}
+
+	// Rebuild tool registries, as enabled agents and tools may have changed
+	m.toolRegistries = make(map[string]domain.ToolRegistry)
+
+	// Drop cached LLM providers, as provider settings may have changed
+	for tenantID, providerManager := range m.llmProviders {
+		providerManager.ClearTenant(tenantID)
+	}
+
+	m.logger.Info().Int("tenants_loaded", len(m.tenants)).Msg("tenant configurations reloaded")
+
+	return nil
+}
+
+// ReloadTenant reloads tenant configurations from file after a change to a
+// single tenant, dropping only the resources built for that tenant
+func (m *Manager) ReloadTenant(tenantID string) error {
+	m.logger.Info().Str("tenant_id", tenantID).Msg("reloading tenant configuration")
+
+	tenantsConfig, err := m.config.LoadTenants()
+	if err != nil {
+		return fmt.Errorf("failed to load tenant configurations: %w", err)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if err := m.replaceTenants(tenantsConfig); err != nil {
+		return err
+	}
+
+	m.clearTenantResources(tenantID)
+
+	m.logger.Info().Str("tenant_id", tenantID).Msg("tenant configuration reloaded")
+
+	return nil
+}
+
+// replaceTenants replaces the loaded tenants with the given configuration and
+// cleans up the resources of removed tenants. The mutex must be held.
+func (m *Manager) replaceTenants(tenantsConfig *config.TenantsConfig) error {
 	// Clear existing tenants
 	oldTenants := m.tenants
 	oldTenantsById := m.tenantsByID
+	oldTenantsConfig := m.tenantsConfig
 
 	m.tenants = make(map[string]*domain.Tenant)
 	m.tenantsByID = make(map[string]*domain.Tenant)
@@ -354,17 +534,26 @@ func (m *Manager) ReloadTenants() error {
 		// Restore old state on error
 		m.tenants = oldTenants
 		m.tenantsByID = oldTenantsById
+		m.tenantsConfig = oldTenantsConfig
 		return fmt.Errorf("failed to reinitialize tenants: %w", err)
 	}
 
 	// Clean up resources for removed tenants
 	m.cleanupRemovedTenants(oldTenantsById)
 
-	m.logger.Info().Int("tenants_loaded", len(m.tenants)).Msg("tenant configurations reloaded")
-
 	return nil
 }
 
+// clearTenantResources drops the tool registry and cached LLM providers of a
+// tenant so they are rebuilt from its new configuration. The mutex must be held.
+func (m *Manager) clearTenantResources(tenantID string) {
+	delete(m.toolRegistries, tenantID)
+
+	if providerManager, exists := m.llmProviders[tenantID]; exists {
+		providerManager.ClearTenant(tenantID)
+	}
+}
+
 // cleanupRemovedTenants cleans up resources for tenants that were removed
 func (m *Manager) cleanupRemovedTenants(oldTenants map[string]*domain.Tenant) {
 	for tenantID := range oldTenants {
@@ -418,6 +607,7 @@ func (m *Manager) Close() error {
 	m.repositories = make(map[string]domain.Repository)
 	m.vectorStores = make(map[string]domain.VectorStore)
 	m.llmProviders = make(map[string]*llm.ProviderManager)
+	m.toolRegistries = make(map[string]domain.ToolRegistry)
 
 	if len(errors) > 0 {
 		return fmt.Errorf("errors occurred while closing tenant resources: %v", errors)
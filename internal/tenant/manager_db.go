@@ -24,11 +24,12 @@ type DatabaseManager struct {
 	globalRepo domain.Repository
 
 	// Caches
-	tenants      map[string]*domain.Tenant       // WABA number -> Tenant
-	tenantsByID  map[string]*domain.Tenant       // Tenant ID -> Tenant
-	repositories map[string]domain.Repository    // Tenant ID -> Repository
-	vectorStores map[string]domain.VectorStore   // Tenant ID -> VectorStore
-	llmProviders map[string]*llm.ProviderManager // Tenant ID -> LLM Provider Manager
+	tenants        map[string]*domain.Tenant       // WABA number -> Tenant
+	tenantsByID    map[string]*domain.Tenant       // Tenant ID -> Tenant
+	repositories   map[string]domain.Repository    // Tenant ID -> Repository
+	vectorStores   map[string]domain.VectorStore   // Tenant ID -> VectorStore
+	llmProviders   map[string]*llm.ProviderManager // Tenant ID -> LLM Provider Manager
+	toolRegistries map[string]domain.ToolRegistry  // Tenant ID -> Tool Registry
 
 	mutex sync.RWMutex
 }
@@ -51,15 +52,16 @@ func newDatabaseManager(cfg *config.Config, logger *log.Logger) (*DatabaseManage
 	globalRepo := repoImpl.NewPostgresRepository(db, logger)
 
 	manager := &DatabaseManager{
-		config:       cfg,
-		logger:       logger,
-		globalDB:     db,
-		globalRepo:   globalRepo,
-		tenants:      make(map[string]*domain.Tenant),
-		tenantsByID:  make(map[string]*domain.Tenant),
-		repositories: make(map[string]domain.Repository),
-		vectorStores: make(map[string]domain.VectorStore),
-		llmProviders: make(map[string]*llm.ProviderManager),
+		config:         cfg,
+		logger:         logger,
+		globalDB:       db,
+		globalRepo:     globalRepo,
+		tenants:        make(map[string]*domain.Tenant),
+		tenantsByID:    make(map[string]*domain.Tenant),
+		repositories:   make(map[string]domain.Repository),
+		vectorStores:   make(map[string]domain.VectorStore),
+		llmProviders:   make(map[string]*llm.ProviderManager),
+		toolRegistries: make(map[string]domain.ToolRegistry),
 	}
 
 	// Initialize tenants from database
@@ -217,21 +219,120 @@ func (m *DatabaseManager) GetVectorStore(tenantID string) (domain.VectorStore, e
 	return store, nil
 }
 
-// GetLLMProvider returns the default LLM provider for a tenant
+// GetLLMProvider returns the LLM provider for a tenant, failing over between
+// the tenant's enabled providers with the default one first
 func (m *DatabaseManager) GetLLMProvider(tenantID string) (domain.LLMProvider, error) {
+	providerManager, err := m.providerManager(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	providerConfigs, err := m.enabledLLMProviders(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get or create the provider chain
+	provider, err := providerManager.GetFallbackProvider(tenantID, providerConfigs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LLM provider: %w", err)
+	}
+
+	return provider, nil
+}
+
+// GetTranscriber returns a speech-to-text transcriber backed by the tenant's default LLM provider
+func (m *DatabaseManager) GetTranscriber(tenantID string) (domain.Transcriber, error) {
+	providerManager, providerConfig, err := m.defaultLLMProvider(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	transcriber, err := providerManager.GetTranscriber(tenantID, providerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transcriber: %w", err)
+	}
+
+	return transcriber, nil
+}
+
+// GetToolRegistry returns the tool registry of a tenant, building it once
+// with the tenant's own vector store, LLM provider and repository
+func (m *DatabaseManager) GetToolRegistry(tenantID string) (domain.ToolRegistry, error) {
 	m.mutex.RLock()
-	providerManager, exists := m.llmProviders[tenantID]
+	registry, exists := m.toolRegistries[tenantID]
 	m.mutex.RUnlock()
 
+	if exists {
+		return registry, nil
+	}
+
+	// Resolve the tenant's resources before taking the lock, as they take it themselves
+	tenant, err := m.GetTenantByID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	vectorStore, err := m.GetVectorStore(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vector store: %w", err)
+	}
+
+	llmProvider, err := m.GetLLMProvider(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LLM provider: %w", err)
+	}
+
+	repo, err := m.GetRepository(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Check again in case another goroutine created it
+	if registry, exists := m.toolRegistries[tenantID]; exists {
+		return registry, nil
+	}
+
+	tenantRegistry, err := newToolRegistry(tenant, vectorStore, llmProvider, repo, m.logger.WithTenant(tenantID))
+	if err != nil {
+		return nil, err
+	}
+	m.toolRegistries[tenantID] = tenantRegistry
+
+	m.logger.Info().
+		Str("tenant_id", tenantID).
+		Int("tools_enabled", len(tenantRegistry.GetToolsForTenant(tenantID))).
+		Msg("tool registry created for tenant")
+
+	return tenantRegistry, nil
+}
+
+// providerManager returns the tenant's provider manager, creating it if needed
+func (m *DatabaseManager) providerManager(tenantID string) (*llm.ProviderManager, error) {
+	// Token usage is recorded in the tenant's database
+	repo, err := m.GetRepository(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	providerManager, exists := m.llmProviders[tenantID]
 	if !exists {
-		// Create provider manager if it doesn't exist
-		m.mutex.Lock()
-		providerManager = llm.NewProviderManager(m.logger.WithTenant(tenantID))
+		providerManager = llm.NewProviderManager(m.logger.WithTenant(tenantID), &m.config.LLMFallback, repo)
 		m.llmProviders[tenantID] = providerManager
-		m.mutex.Unlock()
 	}
 
-	// Get default LLM provider config for tenant from database
+	return providerManager, nil
+}
+
+// enabledLLMProviders returns the tenant's enabled LLM provider configs, default first
+func (m *DatabaseManager) enabledLLMProviders(tenantID string) ([]domain.LLMProviderConfig, error) {
+	// Get LLM provider configs for tenant from database
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -240,22 +341,51 @@ func (m *DatabaseManager) GetLLMProvider(tenantID string) (domain.LLMProvider, e
 		return nil, fmt.Errorf("failed to set tenant context: %w", err)
 	}
 
-	providerConfig, err := m.globalRepo.GetDefaultLLMProvider(ctx, tenantID)
+	providerConfigs, err := m.globalRepo.GetLLMProviders(ctx, tenantID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get default LLM provider config: %w", err)
+		return nil, fmt.Errorf("failed to get LLM provider configs: %w", err)
 	}
 
-	if providerConfig == nil {
-		return nil, fmt.Errorf("no default LLM provider configured for tenant: %s", tenantID)
+	var enabled []domain.LLMProviderConfig
+	for _, providerConfig := range providerConfigs {
+		if providerConfig.Enabled {
+			enabled = append(enabled, providerConfig)
+		}
 	}
 
-	// Get or create the provider
-	provider, err := providerManager.GetProvider(tenantID, providerConfig)
+	if len(enabled) == 0 {
+		return nil, fmt.Errorf("no enabled LLM provider configured for tenant: %s", tenantID)
+	}
+
+	return enabled, nil
+}
+
+// defaultLLMProvider returns the tenant's provider manager and default LLM provider config
+func (m *DatabaseManager) defaultLLMProvider(tenantID string) (*llm.ProviderManager, *domain.LLMProviderConfig, error) {
+	providerManager, err := m.providerManager(tenantID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get LLM provider: %w", err)
+		return nil, nil, err
 	}
 
-	return provider, nil
+	// Get default LLM provider config for tenant from database
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Set tenant context for RLS
+	if err := m.setTenantContext(ctx, tenantID); err != nil {
+		return nil, nil, fmt.Errorf("failed to set tenant context: %w", err)
+	}
+
+	providerConfig, err := m.globalRepo.GetDefaultLLMProvider(ctx, tenantID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get default LLM provider config: %w", err)
+	}
+
+	if providerConfig == nil {
+		return nil, nil, fmt.Errorf("no default LLM provider configured for tenant: %s", tenantID)
+	}
+
+	return providerManager, providerConfig, nil
 }
 
 // initializeTenants initializes all tenants from database
@@ -311,6 +441,11 @@ func (m *DatabaseManager) convertConfigToTenant(config *domain.TenantConfig) *do
 	}
 }
 
+// GetConfigRepository returns the global repository holding tenant configurations
+func (m *DatabaseManager) GetConfigRepository() (domain.Repository, error) {
+	return m.globalRepo, nil
+}
+
 // ReloadTenants reloads tenant configurations from database
 func (m *DatabaseManager) ReloadTenants() error {
 	m.logger.Info().Msg("reloading tenant configurations from database")
@@ -318,6 +453,45 @@ func (m *DatabaseManager) ReloadTenants() error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	if err := m.replaceTenants(); err != nil {
+		return err
+	}
+
+	// Rebuild tool registries, as enabled agents and tools may have changed
+	m.toolRegistries = make(map[string]domain.ToolRegistry)
+
+	// Drop cached LLM providers, as provider settings may have changed
+	for tenantID, providerManager := range m.llmProviders {
+		providerManager.ClearTenant(tenantID)
+	}
+
+	m.logger.Info().Int("tenants_loaded", len(m.tenants)).Msg("tenant configurations reloaded from database")
+
+	return nil
+}
+
+// ReloadTenant reloads tenant configurations from database after a change to
+// a single tenant, dropping only the resources built for that tenant
+func (m *DatabaseManager) ReloadTenant(tenantID string) error {
+	m.logger.Info().Str("tenant_id", tenantID).Msg("reloading tenant configuration from database")
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if err := m.replaceTenants(); err != nil {
+		return err
+	}
+
+	m.clearTenantResources(tenantID)
+
+	m.logger.Info().Str("tenant_id", tenantID).Msg("tenant configuration reloaded from database")
+
+	return nil
+}
+
+// replaceTenants replaces the loaded tenants with the configurations stored in
+// the database and cleans up the resources of removed tenants. The mutex must be held.
+func (m *DatabaseManager) replaceTenants() error {
 	// Clear existing tenants
 	oldTenants := m.tenants
 	oldTenantsById := m.tenantsByID
@@ -336,11 +510,19 @@ func (m *DatabaseManager) ReloadTenants() error {
 	// Clean up resources for removed tenants
 	m.cleanupRemovedTenants(oldTenantsById)
 
-	m.logger.Info().Int("tenants_loaded", len(m.tenants)).Msg("tenant configurations reloaded from database")
-
 	return nil
 }
 
+// clearTenantResources drops the tool registry and cached LLM providers of a
+// tenant so they are rebuilt from its new configuration. The mutex must be held.
+func (m *DatabaseManager) clearTenantResources(tenantID string) {
+	delete(m.toolRegistries, tenantID)
+
+	if providerManager, exists := m.llmProviders[tenantID]; exists {
+		providerManager.ClearTenant(tenantID)
+	}
+}
+
 // cleanupRemovedTenants cleans up resources for tenants that were removed
 func (m *DatabaseManager) cleanupRemovedTenants(oldTenants map[string]*domain.Tenant) {
 	for tenantID := range oldTenants {
@@ -405,6 +587,7 @@ func (m *DatabaseManager) Close() error {
 	m.repositories = make(map[string]domain.Repository)
 	m.vectorStores = make(map[string]domain.VectorStore)
 	m.llmProviders = make(map[string]*llm.ProviderManager)
+	m.toolRegistries = make(map[string]domain.ToolRegistry)
 
 	if len(errors) > 0 {
 		return fmt.Errorf("errors occurred while closing tenant resources: %v", errors)
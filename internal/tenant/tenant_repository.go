@@ -2,6 +2,7 @@ package tenant
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -75,9 +76,135 @@ func (r *TenantRepository) GetMessageByID(ctx context.Context, tenantID string,
 	if err := r.setTenantContext(ctx); err != nil {
 		return nil, err
 	}
+
 	return r.repo.GetMessageByID(ctx, tenantID, messageID)
 }
 
+func (r *TenantRepository) GetReplyMessage(ctx context.Context, tenantID string, inboundMessageID string) (*domain.Message, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.repo.GetReplyMessage(ctx, tenantID, inboundMessageID)
+}
+
+func (r *TenantRepository) GetMessagesSince(ctx context.Context, tenantID string, userID uuid.UUID, since time.Time, limit int) ([]domain.Message, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.GetMessagesSince(ctx, tenantID, userID, since, limit)
+}
+
+func (r *TenantRepository) GetLastInboundMessage(ctx context.Context, tenantID string, userID uuid.UUID) (*domain.Message, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.GetLastInboundMessage(ctx, tenantID, userID)
+}
+
+// Message status operations
+func (r *TenantRepository) RecordMessageStatus(ctx context.Context, event *domain.MessageStatusEvent) error {
+	if err := r.setTenantContext(ctx); err != nil {
+		return err
+	}
+	return r.repo.RecordMessageStatus(ctx, event)
+}
+
+func (r *TenantRepository) GetMessageStatusTimeline(ctx context.Context, tenantID string, messageID uuid.UUID) ([]domain.MessageStatusEvent, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.GetMessageStatusTimeline(ctx, tenantID, messageID)
+}
+
+// Memory chunk operations
+func (r *TenantRepository) GetLatestMemoryChunk(ctx context.Context, tenantID string, userID uuid.UUID, kind string) (*domain.MemoryChunk, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.GetLatestMemoryChunk(ctx, tenantID, userID, kind)
+}
+
+// Media operations
+func (r *TenantRepository) CreateMedia(ctx context.Context, media *domain.Media) error {
+	if err := r.setTenantContext(ctx); err != nil {
+		return err
+	}
+	return r.repo.CreateMedia(ctx, media)
+}
+
+func (r *TenantRepository) GetMedia(ctx context.Context, tenantID string, mediaID uuid.UUID) (*domain.Media, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.GetMedia(ctx, tenantID, mediaID)
+}
+
+// Document operations
+func (r *TenantRepository) CreateDocument(ctx context.Context, document *domain.Document) error {
+	if err := r.setTenantContext(ctx); err != nil {
+		return err
+	}
+	return r.repo.CreateDocument(ctx, document)
+}
+
+func (r *TenantRepository) UpdateDocument(ctx context.Context, document *domain.Document) error {
+	if err := r.setTenantContext(ctx); err != nil {
+		return err
+	}
+	return r.repo.UpdateDocument(ctx, document)
+}
+
+func (r *TenantRepository) GetDocument(ctx context.Context, tenantID string, documentID uuid.UUID) (*domain.Document, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.GetDocument(ctx, tenantID, documentID)
+}
+
+func (r *TenantRepository) GetDocumentByMediaID(ctx context.Context, tenantID string, mediaID uuid.UUID) (*domain.Document, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.GetDocumentByMediaID(ctx, tenantID, mediaID)
+}
+
+func (r *TenantRepository) ListDocuments(ctx context.Context, tenantID string, userID uuid.UUID, limit int) ([]domain.Document, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.ListDocuments(ctx, tenantID, userID, limit)
+}
+
+func (r *TenantRepository) DeleteDocument(ctx context.Context, tenantID string, documentID uuid.UUID) error {
+	if err := r.setTenantContext(ctx); err != nil {
+		return err
+	}
+	return r.repo.DeleteDocument(ctx, tenantID, documentID)
+}
+
+// Message template operations
+func (r *TenantRepository) GetMessageTemplate(ctx context.Context, tenantID, purpose string) (*domain.MessageTemplate, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.GetMessageTemplate(ctx, tenantID, purpose)
+}
+
+func (r *TenantRepository) ListMessageTemplates(ctx context.Context, tenantID string) ([]domain.MessageTemplate, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.ListMessageTemplates(ctx, tenantID)
+}
+
+func (r *TenantRepository) UpsertMessageTemplate(ctx context.Context, template *domain.MessageTemplate) error {
+	if err := r.setTenantContext(ctx); err != nil {
+		return err
+	}
+	return r.repo.UpsertMessageTemplate(ctx, template)
+}
+
 // Agent operations (global - no tenant context needed)
 func (r *TenantRepository) GetAgents(ctx context.Context) ([]domain.AgentConfig, error) {
 	return r.repo.GetAgents(ctx)
@@ -160,6 +287,21 @@ func (r *TenantRepository) UpdateLLMProvider(ctx context.Context, config *domain
 	return r.repo.UpdateLLMProvider(ctx, config)
 }
 
+// LLM usage operations
+func (r *TenantRepository) RecordLLMUsage(ctx context.Context, usage *domain.LLMUsage) error {
+	if err := r.setTenantContext(ctx); err != nil {
+		return err
+	}
+	return r.repo.RecordLLMUsage(ctx, usage)
+}
+
+func (r *TenantRepository) GetDailyLLMUsage(ctx context.Context, tenantID string, userID *uuid.UUID, from, to time.Time) ([]domain.DailyLLMUsage, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.GetDailyLLMUsage(ctx, tenantID, userID, from, to)
+}
+
 // Tenant configuration operations (admin level - clear context first)
 func (r *TenantRepository) GetTenantsConfig(ctx context.Context) ([]domain.TenantConfig, error) {
 	// Clear tenant context for admin operations
@@ -243,6 +385,78 @@ func (r *TenantRepository) IsContactAllowed(ctx context.Context, tenantID, phone
 	return r.repo.IsContactAllowed(ctx, tenantID, phoneNumber)
 }
 
+// Contact request operations
+func (r *TenantRepository) GetContactRequest(ctx context.Context, tenantID, phoneNumber string) (*domain.ContactRequest, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.GetContactRequest(ctx, tenantID, phoneNumber)
+}
+
+func (r *TenantRepository) ListContactRequests(ctx context.Context, tenantID, status string) ([]domain.ContactRequest, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.ListContactRequests(ctx, tenantID, status)
+}
+
+func (r *TenantRepository) CreateContactRequest(ctx context.Context, request *domain.ContactRequest) error {
+	if err := r.setTenantContext(ctx); err != nil {
+		return err
+	}
+	return r.repo.CreateContactRequest(ctx, request)
+}
+
+func (r *TenantRepository) UpdateContactRequest(ctx context.Context, request *domain.ContactRequest) error {
+	if err := r.setTenantContext(ctx); err != nil {
+		return err
+	}
+	return r.repo.UpdateContactRequest(ctx, request)
+}
+
+// Reminder operations
+func (r *TenantRepository) CreateReminder(ctx context.Context, reminder *domain.Reminder) error {
+	if err := r.setTenantContext(ctx); err != nil {
+		return err
+	}
+	return r.repo.CreateReminder(ctx, reminder)
+}
+
+func (r *TenantRepository) GetReminder(ctx context.Context, tenantID string, reminderID uuid.UUID) (*domain.Reminder, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.GetReminder(ctx, tenantID, reminderID)
+}
+
+func (r *TenantRepository) UpdateReminder(ctx context.Context, reminder *domain.Reminder) error {
+	if err := r.setTenantContext(ctx); err != nil {
+		return err
+	}
+	return r.repo.UpdateReminder(ctx, reminder)
+}
+
+func (r *TenantRepository) ReleaseReminder(ctx context.Context, reminder *domain.Reminder, lease time.Time) error {
+	if err := r.setTenantContext(ctx); err != nil {
+		return err
+	}
+	return r.repo.ReleaseReminder(ctx, reminder, lease)
+}
+
+func (r *TenantRepository) ListReminders(ctx context.Context, tenantID string, userID uuid.UUID, status string, limit int) ([]domain.Reminder, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.ListReminders(ctx, tenantID, userID, status, limit)
+}
+
+func (r *TenantRepository) ClaimDueReminders(ctx context.Context, tenantID string, limit int, lease time.Duration) ([]domain.Reminder, error) {
+	if err := r.setTenantContext(ctx); err != nil {
+		return nil, err
+	}
+	return r.repo.ClaimDueReminders(ctx, tenantID, limit, lease)
+}
+
 // Utility operations
 func (r *TenantRepository) Ping(ctx context.Context) error {
 	return r.repo.Ping(ctx)
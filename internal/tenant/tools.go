@@ -0,0 +1,85 @@
+package tenant
+
+import (
+	"fmt"
+
+	"personal-assistant/internal/agents/builtin"
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/log"
+	"personal-assistant/internal/tools"
+)
+
+// enabledToolsKey is the tenant config key that limits the tenant's tools to
+// the listed names, on top of the tools of its enabled agents
+const enabledToolsKey = "enabled_tools"
+
+// newToolRegistry creates the tool registry of a tenant. Every built-in tool
+// is registered with the tenant's own resources; the tenant's enabled agents
+// and enabled_tools config decide which of them can be used.
+func newToolRegistry(tenant *domain.Tenant, vectorStore domain.VectorStore, llmProvider domain.LLMProvider, repo domain.Repository, logger *log.Logger) (*tools.Registry, error) {
+	// Built-in tools by the agent that provides them
+	toolsByAgent := map[string][]domain.Tool{
+		"db_agent": {
+			builtin.NewDBUpsertTool(vectorStore, llmProvider, logger),
+			builtin.NewDBSearchTool(vectorStore, llmProvider, logger),
+			builtin.NewDBGetByIDTool(vectorStore, logger),
+			builtin.NewDBUpdateItemTool(vectorStore, llmProvider, logger),
+			builtin.NewDocumentListTool(repo, logger),
+			builtin.NewDocumentForgetTool(repo, logger),
+		},
+		"http_agent": {
+			builtin.NewHTTPCallTool(repo, logger),
+			builtin.NewWeatherTool("demo_api_key", logger),
+		},
+		"orchestrator": {
+			builtin.NewReminderScheduleTool(repo, logger),
+			builtin.NewReminderListTool(repo, logger),
+			builtin.NewReminderCancelTool(repo, logger),
+			builtin.NewReminderSnoozeTool(repo, logger),
+			builtin.NewChoiceTool(logger),
+		},
+	}
+
+	enabledAgents := make(map[string]bool, len(tenant.EnabledAgents))
+	for _, agent := range tenant.EnabledAgents {
+		enabledAgents[agent] = true
+	}
+
+	enabled := make(map[string]bool)
+	registry := tools.NewTenantRegistry(tenant.ID, func(toolName string) bool {
+		return enabled[toolName]
+	})
+
+	allowed, restricted := enabledTools(tenant)
+	for agent, agentTools := range toolsByAgent {
+		for _, tool := range agentTools {
+			if err := registry.RegisterTool(tool); err != nil {
+				return nil, fmt.Errorf("failed to register tool for tenant %s: %w", tenant.ID, err)
+			}
+			enabled[tool.Name()] = enabledAgents[agent] && (!restricted || allowed[tool.Name()])
+		}
+	}
+
+	return registry, nil
+}
+
+// enabledTools returns the tool names listed in the tenant's enabled_tools
+// config, and whether the list is set at all
+func enabledTools(tenant *domain.Tenant) (map[string]bool, bool) {
+	allowed := make(map[string]bool)
+	switch names := tenant.Config[enabledToolsKey].(type) {
+	case []string:
+		for _, name := range names {
+			allowed[name] = true
+		}
+	case []interface{}:
+		for _, name := range names {
+			if str, ok := name.(string); ok {
+				allowed[str] = true
+			}
+		}
+	default:
+		return nil, false
+	}
+	return allowed, true
+}
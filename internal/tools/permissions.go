@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"personal-assistant/internal/domain"
+)
+
+// toolPermissions maps tools to the contact permission they require.
+// Tools that are not listed require the chat permission.
+var toolPermissions = map[string]string{
+	"schedule_reminder": domain.PermissionSchedule,
+	"list_reminders":    domain.PermissionSchedule,
+	"cancel_reminder":   domain.PermissionSchedule,
+	"snooze_reminder":   domain.PermissionSchedule,
+	"call_api":          domain.PermissionAPI,
+	"get_weather":       domain.PermissionAPI,
+}
+
+// RequiredPermission returns the contact permission needed to use a tool
+func RequiredPermission(toolName string) string {
+	if permission, exists := toolPermissions[toolName]; exists {
+		return permission
+	}
+	return domain.PermissionChat
+}
+
+// ContactRegistry limits a tool registry to the tools a contact is permitted to use
+type ContactRegistry struct {
+	domain.ToolRegistry
+	contact *domain.AllowedContact
+}
+
+// NewContactRegistry creates a view of registry for the contact sending the message
+func NewContactRegistry(registry domain.ToolRegistry, contact *domain.AllowedContact) *ContactRegistry {
+	return &ContactRegistry{
+		ToolRegistry: registry,
+		contact:      contact,
+	}
+}
+
+// GetTool retrieves a tool by name if the contact may use it
+func (r *ContactRegistry) GetTool(name string) (domain.Tool, error) {
+	if err := r.checkPermission(name); err != nil {
+		return nil, err
+	}
+	return r.ToolRegistry.GetTool(name)
+}
+
+// ListTools returns the registered tools the contact may use
+func (r *ContactRegistry) ListTools() []domain.Tool {
+	return r.permitted(r.ToolRegistry.ListTools())
+}
+
+// GetToolsForTenant returns the tenant's tools the contact may use
+func (r *ContactRegistry) GetToolsForTenant(tenantID string) []domain.Tool {
+	return r.permitted(r.ToolRegistry.GetToolsForTenant(tenantID))
+}
+
+// ExecuteToolCall executes a tool call, rejecting tools the contact may not use
+func (r *ContactRegistry) ExecuteToolCall(ctx context.Context, toolCall *domain.ToolCall) (*domain.ToolInvocationResult, error) {
+	if toolCall.Function != nil {
+		if err := r.checkPermission(toolCall.Function.Name); err != nil {
+			return &domain.ToolInvocationResult{
+				ToolName: toolCall.Function.Name,
+				Success:  false,
+				Error:    err.Error(),
+			}, nil
+		}
+	}
+
+	return r.ToolRegistry.ExecuteToolCall(ctx, toolCall)
+}
+
+// permitted filters tools down to those the contact may use
+func (r *ContactRegistry) permitted(tools []domain.Tool) []domain.Tool {
+	allowed := make([]domain.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if r.contact.HasPermission(RequiredPermission(tool.Name())) {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
+
+// checkPermission returns an error explaining why the contact may not use a tool
+func (r *ContactRegistry) checkPermission(toolName string) error {
+	permission := RequiredPermission(toolName)
+	if !r.contact.HasPermission(permission) {
+		return fmt.Errorf("this contact is not permitted to use %s: it requires the %q permission", toolName, permission)
+	}
+	return nil
+}
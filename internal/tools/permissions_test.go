@@ -0,0 +1,86 @@
+package tools_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"personal-assistant/internal/domain"
+	"personal-assistant/internal/tools"
+)
+
+func newPermissionRegistry(t *testing.T) *tools.Registry {
+	registry := tools.NewRegistry()
+	for _, name := range []string{"search", "schedule_reminder", "call_api"} {
+		mockTool := new(MockTool)
+		mockTool.On("Name").Return(name)
+		mockTool.On("Schema").Return(&domain.JSONSchema{Type: "object"})
+		mockTool.On("Invoke", context.Background(), map[string]interface{}{}).Return("done", nil).Maybe()
+		require.NoError(t, registry.RegisterTool(mockTool))
+	}
+	return registry
+}
+
+func toolNames(tools []domain.Tool) []string {
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name()
+	}
+	return names
+}
+
+func TestContactRegistry(t *testing.T) {
+	t.Run("offers only permitted tools", func(t *testing.T) {
+		contact := &domain.AllowedContact{Permissions: domain.DefaultContactPermissions}
+		registry := tools.NewContactRegistry(newPermissionRegistry(t), contact)
+
+		names := toolNames(registry.GetToolsForTenant("tenant-a"))
+		assert.ElementsMatch(t, []string{"search", "schedule_reminder"}, names)
+	})
+
+	t.Run("admin may use every tool", func(t *testing.T) {
+		contact := &domain.AllowedContact{Permissions: []string{domain.PermissionAdmin}}
+		registry := tools.NewContactRegistry(newPermissionRegistry(t), contact)
+
+		assert.Len(t, registry.GetToolsForTenant("tenant-a"), 3)
+	})
+
+	t.Run("rejects tool calls without the permission", func(t *testing.T) {
+		contact := &domain.AllowedContact{Permissions: []string{domain.PermissionChat}}
+		registry := tools.NewContactRegistry(newPermissionRegistry(t), contact)
+
+		result, err := registry.ExecuteToolCall(context.Background(), &domain.ToolCall{
+			ID:       "call-123",
+			Type:     "function",
+			Function: &domain.FunctionCall{Name: "schedule_reminder", Arguments: []byte(`{}`)},
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Error, `requires the "schedule" permission`)
+
+		_, err = registry.GetTool("call_api")
+		assert.Error(t, err)
+	})
+
+	t.Run("executes permitted tool calls", func(t *testing.T) {
+		contact := &domain.AllowedContact{Permissions: []string{domain.PermissionChat}}
+		registry := tools.NewContactRegistry(newPermissionRegistry(t), contact)
+
+		result, err := registry.ExecuteToolCall(context.Background(), &domain.ToolCall{
+			ID:       "call-123",
+			Type:     "function",
+			Function: &domain.FunctionCall{Name: "search", Arguments: []byte(`{}`)},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, "done", result.Result)
+	})
+}
+
+func TestRequiredPermission(t *testing.T) {
+	assert.Equal(t, domain.PermissionSchedule, tools.RequiredPermission("snooze_reminder"))
+	assert.Equal(t, domain.PermissionAPI, tools.RequiredPermission("call_api"))
+	assert.Equal(t, domain.PermissionChat, tools.RequiredPermission("upsert_item"))
+}
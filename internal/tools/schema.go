@@ -4,14 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 
 	"personal-assistant/internal/domain"
 )
 
 // Registry manages all available tools
 type Registry struct {
-	tools   map[string]domain.Tool
-	schemas map[string]*domain.JSONSchema
+	tools    map[string]domain.Tool
+	schemas  map[string]*domain.JSONSchema
+	tenantID string                     // Tenant the registry belongs to, empty when shared
+	enabled  func(toolName string) bool // Reports whether a tool may be used, nil allows all
+	mutex    sync.RWMutex
 }
 
 // NewRegistry creates a new tool registry
@@ -22,8 +26,21 @@ func NewRegistry() *Registry {
 	}
 }
 
+// NewTenantRegistry creates a tool registry for a single tenant. Tools for
+// which enabled returns false stay registered but are neither offered to the
+// LLM nor executed.
+func NewTenantRegistry(tenantID string, enabled func(toolName string) bool) *Registry {
+	registry := NewRegistry()
+	registry.tenantID = tenantID
+	registry.enabled = enabled
+	return registry
+}
+
 // RegisterTool registers a new tool
 func (r *Registry) RegisterTool(tool domain.Tool) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
 	name := tool.Name()
 	if _, exists := r.tools[name]; exists {
 		return fmt.Errorf("tool %s already registered", name)
@@ -36,15 +53,24 @@ func (r *Registry) RegisterTool(tool domain.Tool) error {
 
 // GetTool retrieves a tool by name
 func (r *Registry) GetTool(name string) (domain.Tool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
 	tool, exists := r.tools[name]
 	if !exists {
 		return nil, fmt.Errorf("tool %s not found", name)
 	}
+	if !r.isEnabled(name) {
+		return nil, fmt.Errorf("tool %s is not enabled", name)
+	}
 	return tool, nil
 }
 
 // ListTools returns all registered tools
 func (r *Registry) ListTools() []domain.Tool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
 	tools := make([]domain.Tool, 0, len(r.tools))
 	for _, tool := range r.tools {
 		tools = append(tools, tool)
@@ -52,15 +78,35 @@ func (r *Registry) ListTools() []domain.Tool {
 	return tools
 }
 
-// GetToolsForTenant returns tools available for a specific tenant
+// GetToolsForTenant returns the enabled tools of a tenant. A tenant registry
+// returns no tools for other tenants.
 func (r *Registry) GetToolsForTenant(tenantID string) []domain.Tool {
-	// For now, return all tools. In production, you might want to
-	// filter based on tenant permissions or enabled tools
-	return r.ListTools()
+	if r.tenantID != "" && r.tenantID != tenantID {
+		return nil
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	tools := make([]domain.Tool, 0, len(r.tools))
+	for name, tool := range r.tools {
+		if r.isEnabled(name) {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// isEnabled reports whether a tool may be used
+func (r *Registry) isEnabled(toolName string) bool {
+	return r.enabled == nil || r.enabled(toolName)
 }
 
 // GetSchema returns the JSON schema for a tool
 func (r *Registry) GetSchema(toolName string) (*domain.JSONSchema, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
 	schema, exists := r.schemas[toolName]
 	if !exists {
 		return nil, fmt.Errorf("schema for tool %s not found", toolName)
@@ -154,25 +200,31 @@ func validateFieldType(fieldName string, value interface{}, schema domain.JSONSc
 
 // InvokeTool invokes a tool with the given input
 func (r *Registry) InvokeTool(ctx context.Context, toolName string, input map[string]interface{}) (interface{}, error) {
-	// Validate input
-	if err := r.ValidateInput(toolName, input); err != nil {
-		return nil, fmt.Errorf("validation failed for tool %s: %w", toolName, err)
-	}
-	
 	// Get tool
 	tool, err := r.GetTool(toolName)
 	if err != nil {
 		return nil, err
 	}
 	
+	// Validate input
+	if err := r.ValidateInput(toolName, input); err != nil {
+		return nil, fmt.Errorf("validation failed for tool %s: %w", toolName, err)
+	}
+	
 	// Invoke tool
 	return tool.Invoke(ctx, input)
 }
 
 // ConvertToLLMTools converts registered tools to LLM tool format
 func (r *Registry) ConvertToLLMTools() []domain.ToolDefinition {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
 	llmTools := make([]domain.ToolDefinition, 0, len(r.tools))
-	for _, tool := range r.tools {
+	for name, tool := range r.tools {
+		if !r.isEnabled(name) {
+			continue
+		}
 		llmTool := domain.ToolDefinition{
 			Type: "function",
 			Function: &domain.ToolFunction{
@@ -2,6 +2,8 @@ package tools_test
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 
 	"personal-assistant/internal/domain"
@@ -240,4 +242,73 @@ func TestConvertToLLMTools(t *testing.T) {
 		assert.Equal(t, "test-tool", tool.Function.Name)
 		assert.NotNil(t, tool.Function.Parameters)
 	})
-}
\ No newline at end of file
+}
+
+func TestTenantRegistry(t *testing.T) {
+	newTool := func(name string) *MockTool {
+		mockTool := new(MockTool)
+		mockTool.On("Name").Return(name)
+		mockTool.On("Schema").Return(&domain.JSONSchema{Type: "object"})
+		return mockTool
+	}
+
+	newTenantRegistry := func(t *testing.T) *tools.Registry {
+		registry := tools.NewTenantRegistry("tenant-a", func(toolName string) bool {
+			return toolName == "search"
+		})
+		assert.NoError(t, registry.RegisterTool(newTool("search")))
+		assert.NoError(t, registry.RegisterTool(newTool("call_api")))
+		return registry
+	}
+
+	t.Run("offers only enabled tools", func(t *testing.T) {
+		registry := newTenantRegistry(t)
+
+		tenantTools := registry.GetToolsForTenant("tenant-a")
+		assert.Len(t, tenantTools, 1)
+		assert.Equal(t, "search", tenantTools[0].Name())
+
+		llmTools := registry.ConvertToLLMTools()
+		assert.Len(t, llmTools, 1)
+		assert.Equal(t, "search", llmTools[0].Function.Name)
+	})
+
+	t.Run("offers no tools to other tenants", func(t *testing.T) {
+		registry := newTenantRegistry(t)
+
+		assert.Empty(t, registry.GetToolsForTenant("tenant-b"))
+	})
+
+	t.Run("does not execute disabled tools", func(t *testing.T) {
+		registry := newTenantRegistry(t)
+
+		result, err := registry.ExecuteToolCall(context.Background(), &domain.ToolCall{
+			ID:   "call-123",
+			Type: "function",
+			Function: &domain.FunctionCall{
+				Name:      "call_api",
+				Arguments: []byte(`{}`),
+			},
+		})
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Error, "tool call_api is not enabled")
+	})
+
+	t.Run("registers tools concurrently", func(t *testing.T) {
+		registry := tools.NewRegistry()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				assert.NoError(t, registry.RegisterTool(newTool(fmt.Sprintf("tool-%d", i))))
+				registry.GetToolsForTenant("tenant-a")
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Len(t, registry.ListTools(), 20)
+	})
+}